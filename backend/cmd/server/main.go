@@ -3,9 +3,13 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
+	"educ-retro/internal/auth"
 	"educ-retro/internal/database"
 	"educ-retro/internal/handlers"
+	"educ-retro/internal/issuetracker"
+	"educ-retro/internal/notifications"
 	"educ-retro/internal/repositories"
 	"educ-retro/internal/services"
 
@@ -59,20 +63,77 @@ func main() {
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(database.DB)
 	retroRepo := repositories.NewRetrospectiveRepository(database.DB)
+	webhookRepo := repositories.NewWebhookRepository(database.DB)
+	scheduleRepo := repositories.NewScheduleRepository(database.DB)
+	shareLinkRepo := repositories.NewShareLinkRepository(database.DB)
+
+	// Initialize Realtime service
+	realtimeService := services.NewRealtimeService()
 
 	// Initialize services
 	userService := services.NewUserService(userRepo)
 	templateService := services.NewTemplateService()
-	retrospectiveService := services.NewRetrospectiveService(retroRepo)
+	retrospectiveService := services.NewRetrospectiveService(retroRepo, realtimeService)
+	adminService := services.NewAdminService(userRepo, retroRepo)
 
-	// Initialize Realtime service
-	realtimeService := services.NewRealtimeService()
+	// Reject suspended accounts and load the system role on every authenticated request
+	auth.SetUserStatusLookup(userService.GetAccountStatus)
+
+	// Action item due date reminders
+	if os.Getenv("REMINDERS_ENABLED") != "false" {
+		notifier, err := notifications.NotifierFromEnv()
+		if err != nil {
+			log.Fatal("Failed to configure reminder notifier:", err)
+		}
+
+		reminderService := services.NewReminderService(retroRepo, notifier, durationFromEnv("REMINDER_DUE_SOON", 48*time.Hour))
+		reminderService.Start(durationFromEnv("REMINDER_INTERVAL", time.Hour))
+		defer reminderService.Stop()
+		log.Printf("Action item reminders enabled (%s notifier)", notifier.Name())
+	}
+
+	// Team webhooks receive the retrospective events sent to browsers
+	webhookService := services.NewWebhookService(webhookRepo, retroRepo)
+	realtimeService.AddListener(webhookService.HandleEvent)
+	webhookService.Start(durationFromEnv("WEBHOOK_RETRY_INTERVAL", 30*time.Second))
+	defer webhookService.Stop()
+
+	// Retrospective start, end and new action items posted to the chat channel of each team
+	chatService := services.NewChatService(webhookRepo, retroRepo, userRepo, os.Getenv("APP_URL"))
+	realtimeService.AddListener(chatService.HandleEvent)
+
+	// Export of action items to GitHub Issues or Jira, with status synced back
+	issueTracker, err := issuetracker.ProviderFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure issue tracker:", err)
+	}
+	issueTrackerService := services.NewIssueTrackerService(retroRepo, issueTracker, realtimeService, os.Getenv("APP_URL"))
+	if issueTracker != nil {
+		issueTrackerService.Start(durationFromEnv("ISSUE_SYNC_INTERVAL", 15*time.Minute))
+		defer issueTrackerService.Stop()
+		log.Printf("Issue tracker enabled (%s)", issueTracker.Name())
+	}
+
+	// Recurring retrospectives and auto-start at the scheduled time
+	scheduleService := services.NewScheduleService(scheduleRepo, retroRepo, realtimeService, durationFromEnv("SCHEDULE_LEAD_TIME", 72*time.Hour))
+	scheduleService.Start(durationFromEnv("SCHEDULER_INTERVAL", time.Minute))
+	defer scheduleService.Stop()
+
+	calendarService := services.NewCalendarService(userRepo, retroRepo, os.Getenv("APP_URL"))
+	shareService := services.NewShareService(retroRepo, shareLinkRepo, os.Getenv("APP_URL"))
 
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userService)
 	templateHandler := handlers.NewTemplateHandler(templateService)
 	retrospectiveHandler := handlers.NewRetrospectiveHandler(retrospectiveService, realtimeService)
 	sseHandler := handlers.NewSSEHandler(realtimeService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	actionItemHandler := handlers.NewActionItemHandler(retrospectiveService, realtimeService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	issueTrackerHandler := handlers.NewIssueTrackerHandler(issueTrackerService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	shareHandler := handlers.NewShareHandler(shareService)
 
 	// Setup router
 	r := gin.Default()
@@ -100,7 +161,21 @@ func main() {
 		userHandler.SetupRoutes(v1)
 		templateHandler.SetupRoutes(v1)
 		retrospectiveHandler.SetupRoutes(v1)
+		actionItemHandler.SetupRoutes(v1)
+		webhookHandler.SetupRoutes(v1)
+		scheduleHandler.SetupRoutes(v1)
+		issueTrackerHandler.SetupRoutes(v1)
+		calendarHandler.SetupRoutes(v1)
+		shareHandler.SetupRoutes(v1)
 		sseHandler.SetupRoutes(v1)
+		adminHandler.SetupRoutes(v1)
+
+		// Single sign-on is only enabled when an identity provider is configured
+		if oidcConfig, ok := auth.OIDCConfigFromEnv(); ok {
+			oidcHandler := handlers.NewOIDCHandler(userService, auth.NewOIDCProvider(oidcConfig), os.Getenv("OIDC_POST_LOGIN_REDIRECT_URL"))
+			oidcHandler.SetupRoutes(v1)
+			log.Printf("OIDC single sign-on enabled for issuer %s", oidcConfig.IssuerURL)
+		}
 	}
 
 	// Health check
@@ -121,3 +196,19 @@ func main() {
 	log.Printf("Swagger documentation available at: http://localhost:%s/swagger/index.html", port)
 	log.Fatal(r.Run(":" + port))
 }
+
+// durationFromEnv parses a duration such as "30m" or "48h" from the environment
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return duration
+}
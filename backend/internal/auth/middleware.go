@@ -1,12 +1,54 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// UserStatusLookup returns the current system role of a user and whether the
+// account is suspended. It is read on every request so that role changes and
+// suspensions apply to tokens that were already issued.
+type UserStatusLookup func(userID uuid.UUID) (role string, suspended bool, err error)
+
+var userStatusLookup UserStatusLookup
+
+// SetUserStatusLookup enables the role and suspension checks of Authenticate
+func SetUserStatusLookup(lookup UserStatusLookup) {
+	userStatusLookup = lookup
+}
+
+// ErrAccountSuspended is returned by Authenticate for suspended accounts
+var ErrAccountSuspended = errors.New("account suspended")
+
+// Authenticate validates the token and, once SetUserStatusLookup was called,
+// checks that the account still exists and is not suspended. It returns the
+// claims and the system role of the user, empty without a lookup. Every way
+// of authenticating a request goes through it, so that suspensions apply to
+// tokens that were already issued.
+func Authenticate(tokenString string) (*Claims, string, error) {
+	claims, err := ValidateToken(tokenString)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if userStatusLookup == nil {
+		return claims, "", nil
+	}
+	role, suspended, err := userStatusLookup(claims.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if suspended {
+		return nil, "", ErrAccountSuspended
+	}
+
+	return claims, role, nil
+}
+
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -23,13 +65,22 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := ValidateToken(tokenString)
+		claims, role, err := Authenticate(tokenString)
+		if err == ErrAccountSuspended {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		if role != "" {
+			c.Set("user_role", role)
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
 		c.Set("user_name", claims.Name)
@@ -51,7 +102,8 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := ValidateToken(tokenString)
+		// Suspended users are served as anonymous ones
+		claims, _, err := Authenticate(tokenString)
 		if err != nil {
 			c.Next()
 			return
@@ -63,3 +115,20 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireRole only lets through users whose system role is one of roles.
+// It must be used after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("user_role")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		c.Abort()
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -1050,3 +1051,99 @@ func TestAuthMiddleware_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddleware_UserStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtSecret = []byte("test-secret")
+	defer func() {
+		jwtSecret = []byte(os.Getenv("JWT_SECRET"))
+		SetUserStatusLookup(nil)
+	}()
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	suspendedID := uuid.New()
+
+	SetUserStatusLookup(func(id uuid.UUID) (string, bool, error) {
+		switch id {
+		case adminID:
+			return "admin", false, nil
+		case userID:
+			return "user", false, nil
+		case suspendedID:
+			return "admin", true, nil
+		}
+		return "", false, errors.New("user not found")
+	})
+
+	tests := []struct {
+		name           string
+		userID         uuid.UUID
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "admin", userID: adminID, expectedStatus: http.StatusOK},
+		{name: "regular user", userID: userID, expectedStatus: http.StatusForbidden, expectedError: "insufficient permissions"},
+		{name: "suspended account", userID: suspendedID, expectedStatus: http.StatusForbidden, expectedError: "account suspended"},
+		{name: "deleted account", userID: uuid.New(), expectedStatus: http.StatusUnauthorized, expectedError: "Invalid token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+
+			r.GET("/admin", AuthMiddleware(), RequireRole("admin"), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			token, err := GenerateToken(tt.userID, "test@example.com", "Test User")
+			require.NoError(t, err)
+
+			req, _ := http.NewRequest("GET", "/admin", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+			}
+		})
+	}
+}
+
+func TestOptionalAuthMiddleware_SuspendedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtSecret = []byte("test-secret")
+	defer func() {
+		jwtSecret = []byte(os.Getenv("JWT_SECRET"))
+		SetUserStatusLookup(nil)
+	}()
+
+	SetUserStatusLookup(func(id uuid.UUID) (string, bool, error) {
+		return "user", true, nil
+	})
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+
+	r.GET("/test", OptionalAuthMiddleware(), func(c *gin.Context) {
+		_, exists := c.Get("user_id")
+		c.JSON(http.StatusOK, gin.H{"authenticated": exists})
+	})
+
+	token, err := GenerateToken(uuid.New(), "test@example.com", "Test User")
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+
+	// Served, but not as the suspended user
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]bool
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response["authenticated"])
+}
@@ -0,0 +1,391 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// oidcStateTTL is how long a login attempt may take between the redirect to
+// the identity provider and the callback
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCConfig holds the client registration at the identity provider
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDCConfigFromEnv reads the OIDC client configuration from the environment.
+// It returns false when single sign-on is not configured.
+func OIDCConfigFromEnv() (OIDCConfig, bool) {
+	config := OIDCConfig{
+		IssuerURL:    os.Getenv("OIDC_ISSUER_URL"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+
+	if config.IssuerURL == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return config, false
+	}
+
+	if scopes := os.Getenv("OIDC_SCOPES"); scopes != "" {
+		config.Scopes = strings.Fields(scopes)
+	}
+
+	return config, true
+}
+
+// OIDCIdentity is the verified identity returned by the provider in the ID token
+type OIDCIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcState struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+type idTokenClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     *bool  `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Nonce             string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// OIDCProvider implements the OpenID Connect authorization code flow against
+// a single identity provider
+type OIDCProvider struct {
+	config     OIDCConfig
+	httpClient *http.Client
+
+	discovery   *oidcDiscovery
+	keys        map[string]*rsa.PublicKey
+	discoveryMu sync.Mutex
+
+	states   map[string]oidcState
+	statesMu sync.Mutex
+}
+
+func NewOIDCProvider(config OIDCConfig) *OIDCProvider {
+	return &OIDCProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		keys:       make(map[string]*rsa.PublicKey),
+		states:     make(map[string]oidcState),
+	}
+}
+
+// AuthCodeURL starts a login attempt and returns the provider URL the user
+// must be redirected to
+func (p *OIDCProvider) AuthCodeURL() (string, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	p.statesMu.Lock()
+	now := time.Now()
+	for key, s := range p.states {
+		if now.After(s.expiresAt) {
+			delete(p.states, key)
+		}
+	}
+	p.states[state] = oidcState{nonce: nonce, expiresAt: now.Add(oidcStateTTL)}
+	p.statesMu.Unlock()
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", strings.Join(p.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// HandleCallback validates the state returned by the provider, exchanges the
+// authorization code and verifies the resulting ID token
+func (p *OIDCProvider) HandleCallback(code, state string) (*OIDCIdentity, error) {
+	p.statesMu.Lock()
+	saved, ok := p.states[state]
+	delete(p.states, state)
+	p.statesMu.Unlock()
+
+	if !ok || time.Now().After(saved.expiresAt) {
+		return nil, errors.New("invalid oidc state")
+	}
+
+	if code == "" {
+		return nil, errors.New("authorization code required")
+	}
+
+	rawIDToken, err := p.exchangeCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.verifyIDToken(rawIDToken, saved.nonce)
+}
+
+func (p *OIDCProvider) exchangeCode(code string) (string, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %d: %s %s", resp.StatusCode, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+
+	if tokenResponse.IDToken == "" {
+		return "", errors.New("token response does not contain an id_token")
+	}
+
+	return tokenResponse.IDToken, nil
+}
+
+func (p *OIDCProvider) verifyIDToken(rawIDToken, nonce string) (*OIDCIdentity, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+
+	identity := &OIDCIdentity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(strings.TrimSpace(claims.Email)),
+		EmailVerified: claims.EmailVerified != nil && *claims.EmailVerified,
+		Name:          claims.Name,
+	}
+
+	if identity.Subject == "" {
+		return nil, errors.New("invalid id_token: missing subject")
+	}
+
+	if identity.Name == "" {
+		identity.Name = claims.PreferredUsername
+	}
+	if identity.Name == "" {
+		identity.Name = strings.Split(identity.Email, "@")[0]
+	}
+
+	return identity, nil
+}
+
+func (p *OIDCProvider) getDiscovery() (*oidcDiscovery, error) {
+	p.discoveryMu.Lock()
+	defer p.discoveryMu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.config.IssuerURL, "/") + "/.well-known/openid-configuration"
+	resp, err := p.httpClient.Get(wellKnown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch oidc discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery returned %d", resp.StatusCode)
+	}
+
+	discovery := &oidcDiscovery{}
+	if err := json.NewDecoder(resp.Body).Decode(discovery); err != nil {
+		return nil, fmt.Errorf("failed to decode oidc discovery document: %w", err)
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.config.IssuerURL, "/") {
+		return nil, fmt.Errorf("oidc issuer mismatch: expected %s, got %s", p.config.IssuerURL, discovery.Issuer)
+	}
+
+	p.discovery = discovery
+	return discovery, nil
+}
+
+// getKey returns the signing key for kid, refreshing the JWKS once when the
+// key is unknown (the provider may have rotated its keys)
+func (p *OIDCProvider) getKey(kid string) (*rsa.PublicKey, error) {
+	p.discoveryMu.Lock()
+	key, ok := p.keys[kid]
+	p.discoveryMu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := p.refreshKeys(); err != nil {
+		return nil, err
+	}
+
+	p.discoveryMu.Lock()
+	defer p.discoveryMu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	// Providers with a single key may omit kid from the token header
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (p *OIDCProvider) refreshKeys() error {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.httpClient.Get(discovery.JWKSURI)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	p.discoveryMu.Lock()
+	p.keys = keys
+	p.discoveryMu.Unlock()
+
+	return nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
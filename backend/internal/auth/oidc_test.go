@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIdP is a minimal OpenID Connect provider serving discovery, JWKS and
+// token endpoints
+type mockIdP struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+	// claims returned in the next id_token; "nonce" is filled from the auth request
+	claims jwt.MapClaims
+	nonce  string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{key: key, clientID: "retro-client"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "test-key",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != idp.clientID || secret != "secret" || r.FormValue("code") != "valid-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"aud":   idp.clientID,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": idp.nonce,
+		}
+		for k, v := range idp.claims {
+			claims[k] = v
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test-key"
+		signed, err := token.SignedString(idp.key)
+		require.NoError(t, err)
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access",
+			"id_token":     signed,
+		})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *mockIdP) provider() *OIDCProvider {
+	return NewOIDCProvider(OIDCConfig{
+		IssuerURL:    idp.server.URL,
+		ClientID:     idp.clientID,
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+}
+
+// startLogin simulates the browser redirect and returns the state parameter
+func (idp *mockIdP) startLogin(t *testing.T, provider *OIDCProvider) string {
+	authURL, err := provider.AuthCodeURL()
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, idp.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "code", parsed.Query().Get("response_type"))
+	assert.Equal(t, idp.clientID, parsed.Query().Get("client_id"))
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+
+	idp.nonce = parsed.Query().Get("nonce")
+	return parsed.Query().Get("state")
+}
+
+func TestOIDCProvider_LoginFlow(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := idp.provider()
+	idp.claims = jwt.MapClaims{
+		"sub":            "user-123",
+		"email":          "Maria@Example.com",
+		"email_verified": true,
+		"name":           "Maria Souza",
+	}
+
+	state := idp.startLogin(t, provider)
+
+	identity, err := provider.HandleCallback("valid-code", state)
+
+	require.NoError(t, err)
+	assert.Equal(t, idp.server.URL, identity.Issuer)
+	assert.Equal(t, "user-123", identity.Subject)
+	assert.Equal(t, "maria@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Maria Souza", identity.Name)
+
+	// State is single use
+	_, err = provider.HandleCallback("valid-code", state)
+	assert.EqualError(t, err, "invalid oidc state")
+}
+
+func TestOIDCProvider_NameFallback(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := idp.provider()
+	idp.claims = jwt.MapClaims{
+		"sub":   "user-456",
+		"email": "joao@example.com",
+	}
+
+	state := idp.startLogin(t, provider)
+	identity, err := provider.HandleCallback("valid-code", state)
+
+	require.NoError(t, err)
+	assert.Equal(t, "joao", identity.Name)
+	// Without the email_verified claim the email is not taken as verified
+	assert.False(t, identity.EmailVerified)
+}
+
+func TestOIDCProvider_Errors(t *testing.T) {
+	t.Run("unknown state", func(t *testing.T) {
+		idp := newMockIdP(t)
+		provider := idp.provider()
+
+		_, err := provider.HandleCallback("valid-code", "forged-state")
+		assert.EqualError(t, err, "invalid oidc state")
+	})
+
+	t.Run("rejected code", func(t *testing.T) {
+		idp := newMockIdP(t)
+		provider := idp.provider()
+		idp.claims = jwt.MapClaims{"sub": "user"}
+
+		state := idp.startLogin(t, provider)
+		_, err := provider.HandleCallback("bad-code", state)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		idp := newMockIdP(t)
+		provider := idp.provider()
+		idp.claims = jwt.MapClaims{"sub": "user"}
+
+		state := idp.startLogin(t, provider)
+		idp.nonce = "replayed"
+		_, err := provider.HandleCallback("valid-code", state)
+
+		assert.EqualError(t, err, "invalid id_token: nonce mismatch")
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		idp := newMockIdP(t)
+		provider := idp.provider()
+		idp.claims = jwt.MapClaims{"sub": "user", "aud": "another-client"}
+
+		state := idp.startLogin(t, provider)
+		_, err := provider.HandleCallback("valid-code", state)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid id_token")
+	})
+
+	t.Run("token signed by another key", func(t *testing.T) {
+		idp := newMockIdP(t)
+		provider := idp.provider()
+		idp.claims = jwt.MapClaims{"sub": "user"}
+
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		idp.key = otherKey
+
+		state := idp.startLogin(t, provider)
+		_, err = provider.HandleCallback("valid-code", state)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid id_token")
+	})
+}
+
+func TestOIDCConfigFromEnv(t *testing.T) {
+	for _, key := range []string{"OIDC_ISSUER_URL", "OIDC_CLIENT_ID", "OIDC_CLIENT_SECRET", "OIDC_REDIRECT_URL", "OIDC_SCOPES"} {
+		original, set := os.LookupEnv(key)
+		if set {
+			defer os.Setenv(key, original)
+		} else {
+			defer os.Unsetenv(key)
+		}
+		os.Unsetenv(key)
+	}
+
+	_, ok := OIDCConfigFromEnv()
+	assert.False(t, ok)
+
+	os.Setenv("OIDC_ISSUER_URL", "https://idp.example.com")
+	os.Setenv("OIDC_CLIENT_ID", "client")
+	os.Setenv("OIDC_REDIRECT_URL", "http://localhost:8080/api/v1/auth/oidc/callback")
+	os.Setenv("OIDC_SCOPES", "openid email")
+
+	config, ok := OIDCConfigFromEnv()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"openid", "email"}, config.Scopes)
+}
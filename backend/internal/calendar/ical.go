@@ -0,0 +1,104 @@
+// Package calendar renders iCalendar (RFC 5545) documents for retrospectives
+// and action item due dates.
+package calendar
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Calendar is a VCALENDAR with its events
+type Calendar struct {
+	// Name is shown by calendar apps for subscribed feeds
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT. All-day events only use the date of Start and last one day.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	// Stamp is when the event was last changed
+	Stamp time.Time
+}
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// Render returns the calendar as an .ics document
+func (c *Calendar) Render() []byte {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//Educ Retro//Retrospectives//PT")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+event.UID)
+		writeLine(&b, "DTSTAMP:"+event.Stamp.UTC().Format(dateTimeFormat))
+		if event.AllDay {
+			writeLine(&b, "DTSTART;VALUE=DATE:"+event.Start.Format(dateFormat))
+			writeLine(&b, "DTEND;VALUE=DATE:"+event.Start.AddDate(0, 0, 1).Format(dateFormat))
+		} else {
+			writeLine(&b, "DTSTART:"+event.Start.UTC().Format(dateTimeFormat))
+			writeLine(&b, "DTEND:"+event.End.UTC().Format(dateTimeFormat))
+		}
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.URL != "" {
+			writeLine(&b, "URL:"+event.URL)
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	return []byte(b.String())
+}
+
+// escapeText escapes a TEXT value
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// writeLine writes a content line folded at 75 octets, without splitting
+// UTF-8 characters, and terminated by CRLF
+func writeLine(b *strings.Builder, line string) {
+	// Continuation lines start with a space, which counts toward the limit
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,64 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendar_Render(t *testing.T) {
+	stamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cal := &Calendar{
+		Name: "Retrospectivas",
+		Events: []Event{
+			{
+				UID:         "retrospective-1@educ-retro",
+				Summary:     "Retrospectiva: Sprint 12, time A",
+				Description: "Linha 1\nLinha 2; fim",
+				URL:         "http://app.test/retrospectives/1",
+				Start:       time.Date(2024, 5, 10, 14, 0, 0, 0, time.FixedZone("BRT", -3*3600)),
+				End:         time.Date(2024, 5, 10, 15, 0, 0, 0, time.FixedZone("BRT", -3*3600)),
+				Stamp:       stamp,
+			},
+			{
+				UID:     "action-item-2@educ-retro",
+				Summary: "Prazo: Automatizar deploy",
+				Start:   time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+				Stamp:   stamp,
+			},
+		},
+	}
+
+	ics := string(cal.Render())
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "X-WR-CALNAME:Retrospectivas\r\n")
+	assert.Contains(t, ics, "DTSTART:20240510T170000Z\r\nDTEND:20240510T180000Z\r\n")
+	assert.Contains(t, ics, "SUMMARY:Retrospectiva: Sprint 12\\, time A\r\n")
+	assert.Contains(t, ics, "DESCRIPTION:Linha 1\\nLinha 2\\; fim\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20240520\r\nDTEND;VALUE=DATE:20240521\r\n")
+	assert.Contains(t, ics, "DTSTAMP:20240501T120000Z\r\n")
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+}
+
+func TestWriteLine_Folds(t *testing.T) {
+	var b strings.Builder
+	line := "SUMMARY:" + strings.Repeat("ação ", 40)
+	writeLine(&b, line)
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	assert.Greater(t, len(lines), 1)
+	unfolded := lines[0]
+	for i, l := range lines {
+		assert.LessOrEqual(t, len(l), 75)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(l, " "))
+			unfolded += l[1:]
+		}
+	}
+	assert.Equal(t, line, unfolded)
+}
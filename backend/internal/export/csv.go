@@ -0,0 +1,102 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type csvExporter struct{}
+
+func (csvExporter) ContentType() string { return "text/csv; charset=utf-8" }
+func (csvExporter) Extension() string   { return "csv" }
+
+func (csvExporter) Export(report *Report) ([]byte, error) {
+	return CSV(report)
+}
+
+var csvHeader = []string{"Tipo", "Categoria", "Conteúdo", "Votos", "Grupos", "Responsável", "Prazo", "Status", "Notas", "Resultado"}
+
+// CSV renders the items and action items of the report as one table for
+// spreadsheets, a row per item or action item, with the discussion notes of
+// the items and a row per group with notes
+func CSV(report *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	// The byte order mark makes spreadsheets read the file as UTF-8
+	buf.WriteString("\ufeff")
+
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	groupNames := map[uuid.UUID][]string{}
+	for _, group := range report.Retrospective.Groups {
+		for _, itemID := range report.GroupItems[group.ID] {
+			groupNames[itemID] = append(groupNames[itemID], group.Name)
+		}
+	}
+
+	for _, category := range report.ItemsByCategory() {
+		for _, item := range category.Items {
+			notes, outcome := csvNote(report.ItemNote(item.ID))
+			err := writer.Write(csvRow("Item", category.Category.Name, item.Content, strconv.Itoa(item.Votes),
+				strings.Join(groupNames[item.ID], "; "), "", "", "", notes, outcome))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, group := range report.Retrospective.Groups {
+		note := report.GroupNote(group.ID)
+		if note == nil {
+			continue
+		}
+		err := writer.Write(csvRow("Grupo", "", group.Name, strconv.Itoa(group.Votes), "", "", "", "", note.Content, note.Outcome))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, actionItem := range report.Retrospective.ActionItems {
+		dueDate := ""
+		if actionItem.DueDate != nil {
+			dueDate = actionItem.DueDate.Format("2006-01-02")
+		}
+		err := writer.Write(csvRow("Action item", "", actionItem.Title, "", "",
+			report.UserName(actionItem.AssignedTo), dueDate, ActionItemStatusLabel(actionItem.Status), "", ""))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvNote returns the content and outcome of the note, or empty cells
+func csvNote(note *models.DiscussionNote) (string, string) {
+	if note == nil {
+		return "", ""
+	}
+	return note.Content, note.Outcome
+}
+
+// csvRow builds a row, quoting the cells a spreadsheet would run as formulas
+func csvRow(cells ...string) []string {
+	for i, cell := range cells {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cells[i] = "'" + cell
+		}
+	}
+	return cells
+}
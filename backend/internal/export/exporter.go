@@ -0,0 +1,55 @@
+package export
+
+import (
+	"sort"
+	"strings"
+)
+
+// Exporter renders a report in one document format
+type Exporter interface {
+	// ContentType is the MIME type of the document
+	ContentType() string
+	// Extension is the file extension of the document, without the dot
+	Extension() string
+	Export(report *Report) ([]byte, error)
+}
+
+var exporters = map[string]Exporter{}
+
+// Register makes an exporter available under the format name. Registering
+// the same format twice replaces the previous exporter.
+func Register(format string, exporter Exporter) {
+	exporters[strings.ToLower(format)] = exporter
+}
+
+// Lookup returns the exporter of the format, case insensitively
+func Lookup(format string) (Exporter, bool) {
+	exporter, ok := exporters[strings.ToLower(format)]
+	return exporter, ok
+}
+
+// Formats lists the registered format names, sorted
+func Formats() []string {
+	formats := make([]string, 0, len(exporters))
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func init() {
+	Register("pdf", pdfExporter{})
+	Register("markdown", markdownExporter{})
+	Register("csv", csvExporter{})
+	Register("json", jsonExporter{})
+}
+
+type pdfExporter struct{}
+
+func (pdfExporter) ContentType() string { return "application/pdf" }
+func (pdfExporter) Extension() string   { return "pdf" }
+
+func (pdfExporter) Export(report *Report) ([]byte, error) {
+	return PDF(report)
+}
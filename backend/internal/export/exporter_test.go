@@ -0,0 +1,94 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	assert.Equal(t, []string{"csv", "json", "markdown", "pdf"}, Formats())
+
+	exporter, ok := Lookup("Markdown")
+	require.True(t, ok)
+	assert.Equal(t, "md", exporter.Extension())
+
+	_, ok = Lookup("docx")
+	assert.False(t, ok)
+}
+
+func TestMarkdown(t *testing.T) {
+	report := testReport()
+	report.Retrospective.Items[0].Content = "Deploy *manual*\nàs sextas"
+
+	content := string(Markdown(report))
+
+	assert.True(t, strings.HasPrefix(content, "# Retrospectiva de março — ação e reflexão\n"))
+	assert.Contains(t, content, "**Status:** Encerrada · **Template:** Deu certo / Melhorar")
+	assert.Contains(t, content, "### Melhorar (2)")
+	// Markdown in the items is escaped and their lines stay in the list entry
+	assert.Contains(t, content, "- Deploy \\*manual\\*  \n  às sextas (1 voto)")
+	assert.Contains(t, content, "  - **Notas:** O deploy leva 2 horas  \n  e falha às vezes\n  - **Resultado:** Automatizar o deploy\n")
+	assert.Contains(t, content, "### Automação (3 votos)\n\n**Resultado:** Investir em automação\n\n")
+	assert.Contains(t, content, "- [ ] **Automatizar o deploy** — Responsável: João · Prazo: 15/03/2024 · Status: Em progresso")
+	assert.Contains(t, content, "- [ ] **Revisar critérios de aceitação** — Responsável: sem responsável · Prazo: sem prazo")
+	assert.Contains(t, content, "_Gerado em 01/03/2024 14:30_")
+}
+
+func TestCSV(t *testing.T) {
+	report := testReport()
+	report.Retrospective.Items[1].Content = "=HYPERLINK(\"http://evil\")"
+
+	content, err := CSV(report)
+	require.NoError(t, err)
+
+	require.True(t, bytes.HasPrefix(content, []byte("\ufeff")))
+	rows, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\ufeff")))).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, rows, 8)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{"Item", "Deu certo", "'=HYPERLINK(\"http://evil\")", "2", "Automação", "", "", "", "", ""}, rows[1])
+	assert.Equal(t, "4", rows[2][3])
+	assert.Equal(t, []string{"O deploy leva 2 horas\ne falha às vezes", "Automatizar o deploy"}, rows[3][8:])
+	assert.Equal(t, []string{"Grupo", "", "Automação", "3", "", "", "", "", "", "Investir em automação"}, rows[5])
+	assert.Equal(t, []string{"Action item", "", "Automatizar o deploy", "", "", "João", "2024-03-15", "Em progresso", "", ""}, rows[6])
+}
+
+func TestJSON(t *testing.T) {
+	report := testReport()
+
+	content, err := JSON(report)
+	require.NoError(t, err)
+
+	var document Document
+	require.NoError(t, json.Unmarshal(content, &document))
+	assert.Equal(t, report.Retrospective.Title, document.Retrospective.Title)
+	assert.Len(t, document.Retrospective.Items, 4)
+	assert.Equal(t, "went_well_to_improve", document.Template.ID)
+	assert.Equal(t, report.Categories, document.Template.Categories)
+	assert.Equal(t, report.GroupItems, document.GroupItems)
+	assert.Equal(t, "João", document.UserNames[*report.Retrospective.ActionItems[0].AssignedTo])
+	require.Len(t, document.Retrospective.Notes, 3)
+	assert.Equal(t, "Automatizar o deploy", document.Retrospective.Notes[0].Outcome)
+}
+
+func TestJSON_EmptyReport(t *testing.T) {
+	report := testReport()
+	report.Categories = nil
+	report.GroupItems = nil
+	report.UserNames = nil
+
+	content, err := JSON(report)
+	require.NoError(t, err)
+
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &document))
+	assert.Equal(t, map[string]interface{}{}, document["group_items"])
+	assert.Equal(t, []interface{}{}, document["template"].(map[string]interface{})["categories"])
+}
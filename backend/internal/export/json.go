@@ -0,0 +1,63 @@
+package export
+
+import (
+	"encoding/json"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type jsonExporter struct{}
+
+func (jsonExporter) ContentType() string { return "application/json; charset=utf-8" }
+func (jsonExporter) Extension() string   { return "json" }
+
+func (jsonExporter) Export(report *Report) ([]byte, error) {
+	return JSON(report)
+}
+
+// Document is the JSON export of a retrospective: everything shown on its
+// page, with the template categories, the items of each group and the
+// names of the assignees
+type Document struct {
+	Retrospective *models.RetrospectiveWithDetails `json:"retrospective"`
+	Template      DocumentTemplate                 `json:"template"`
+	GroupItems    map[uuid.UUID][]uuid.UUID        `json:"group_items"`
+	UserNames     map[uuid.UUID]string             `json:"user_names"`
+	GeneratedAt   time.Time                        `json:"generated_at"`
+}
+
+// DocumentTemplate is the template of an exported retrospective
+type DocumentTemplate struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Categories []Category `json:"categories"`
+}
+
+// JSON renders the report as an indented Document
+func JSON(report *Report) ([]byte, error) {
+	document := Document{
+		Retrospective: report.Retrospective,
+		Template: DocumentTemplate{
+			ID:         string(report.Retrospective.Template),
+			Name:       report.TemplateName,
+			Categories: report.Categories,
+		},
+		GroupItems:  report.GroupItems,
+		UserNames:   report.UserNames,
+		GeneratedAt: report.GeneratedAt,
+	}
+	if document.Template.Categories == nil {
+		document.Template.Categories = []Category{}
+	}
+	if document.GroupItems == nil {
+		document.GroupItems = map[uuid.UUID][]uuid.UUID{}
+	}
+	if document.UserNames == nil {
+		document.UserNames = map[uuid.UUID]string{}
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
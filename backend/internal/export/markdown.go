@@ -0,0 +1,126 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type markdownExporter struct{}
+
+func (markdownExporter) ContentType() string { return "text/markdown; charset=utf-8" }
+func (markdownExporter) Extension() string   { return "md" }
+
+func (markdownExporter) Export(report *Report) ([]byte, error) {
+	return Markdown(report), nil
+}
+
+// Markdown renders the report as a Markdown document, to paste into wikis
+func Markdown(report *Report) []byte {
+	var buf bytes.Buffer
+	retrospective := report.Retrospective
+
+	fmt.Fprintf(&buf, "# %s\n\n", markdownEscape(retrospective.Title))
+	if retrospective.Description != nil && *retrospective.Description != "" {
+		fmt.Fprintf(&buf, "%s\n\n", markdownEscape(*retrospective.Description))
+	}
+
+	details := []string{
+		"**Status:** " + StatusLabel(retrospective.Status),
+		"**Template:** " + markdownEscape(report.TemplateName),
+	}
+	if retrospective.StartedAt != nil {
+		details = append(details, "**Início:** "+retrospective.StartedAt.Format("02/01/2006 15:04"))
+	}
+	if retrospective.EndedAt != nil {
+		details = append(details, "**Término:** "+retrospective.EndedAt.Format("02/01/2006 15:04"))
+	}
+	details = append(details, fmt.Sprintf("**Participantes:** %d", len(retrospective.Participants)))
+	fmt.Fprintf(&buf, "%s\n", strings.Join(details, " · "))
+
+	if categories := report.ItemsByCategory(); len(categories) > 0 {
+		buf.WriteString("\n## Itens\n")
+		for _, category := range categories {
+			fmt.Fprintf(&buf, "\n### %s (%d)\n\n", markdownEscape(category.Category.Name), len(category.Items))
+			for _, item := range category.Items {
+				fmt.Fprintf(&buf, "- %s (%s)\n", markdownListText(item.Content), votesLabel(item.Votes))
+				if note := report.ItemNote(item.ID); note != nil {
+					if note.Content != "" {
+						fmt.Fprintf(&buf, "  - **Notas:** %s\n", markdownListText(note.Content))
+					}
+					if note.Outcome != "" {
+						fmt.Fprintf(&buf, "  - **Resultado:** %s\n", markdownListText(note.Outcome))
+					}
+				}
+			}
+		}
+	}
+
+	if len(retrospective.Groups) > 0 {
+		buf.WriteString("\n## Grupos\n")
+		for _, group := range retrospective.Groups {
+			fmt.Fprintf(&buf, "\n### %s (%s)\n\n", markdownEscape(group.Name), votesLabel(group.Votes))
+			if group.Description != nil && *group.Description != "" {
+				fmt.Fprintf(&buf, "%s\n\n", markdownEscape(*group.Description))
+			}
+			if note := report.GroupNote(group.ID); note != nil {
+				if note.Content != "" {
+					fmt.Fprintf(&buf, "**Notas:** %s\n\n", markdownEscape(note.Content))
+				}
+				if note.Outcome != "" {
+					fmt.Fprintf(&buf, "**Resultado:** %s\n\n", markdownEscape(note.Outcome))
+				}
+			}
+			for _, itemID := range report.GroupItems[group.ID] {
+				if item := report.Item(itemID); item != nil {
+					fmt.Fprintf(&buf, "- %s (%s)\n", markdownListText(item.Content), markdownEscape(report.CategoryName(item.Category)))
+				}
+			}
+		}
+	}
+
+	if len(retrospective.ActionItems) > 0 {
+		buf.WriteString("\n## Action items\n\n")
+		for _, actionItem := range retrospective.ActionItems {
+			check := " "
+			if actionItem.Status == "done" {
+				check = "x"
+			}
+
+			assignee := report.UserName(actionItem.AssignedTo)
+			if assignee == "" {
+				assignee = "sem responsável"
+			}
+			dueDate := "sem prazo"
+			if actionItem.DueDate != nil {
+				dueDate = actionItem.DueDate.Format("02/01/2006")
+			}
+
+			fmt.Fprintf(&buf, "- [%s] **%s** — Responsável: %s · Prazo: %s · Status: %s\n", check,
+				markdownListText(actionItem.Title), markdownEscape(assignee), dueDate, ActionItemStatusLabel(actionItem.Status))
+			if actionItem.Description != nil && *actionItem.Description != "" {
+				fmt.Fprintf(&buf, "  %s\n", markdownListText(*actionItem.Description))
+			}
+		}
+	}
+
+	fmt.Fprintf(&buf, "\n_Gerado em %s_\n", report.GeneratedAt.Format("02/01/2006 15:04"))
+	return buf.Bytes()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "#", `\#`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+// markdownEscape keeps user text from being read as Markdown syntax
+func markdownEscape(text string) string {
+	return markdownEscaper.Replace(strings.TrimSpace(text))
+}
+
+// markdownListText escapes text for a list entry, indenting its other lines
+// so they stay in the entry
+func markdownListText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(markdownEscape(text), "\r\n", "\n"), "\n")
+	return strings.Join(lines, "  \n  ")
+}
@@ -0,0 +1,310 @@
+package export
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"educ-retro/internal/models"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// DejaVu Sans covers the accented characters the core PDF fonts mangle
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	fontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	fontBold []byte
+)
+
+const (
+	pdfFont       = "DejaVu"
+	pdfMargin     = 15.0
+	pdfLineHeight = 5.0
+	pdfStripe     = 1.5 // width of the category color bar next to items
+)
+
+// PDF renders the report as an A4 document: items per category in the
+// template colors, groups with their items, action items with assignees
+// and due dates, and numbered pages.
+func PDF(report *Report) ([]byte, error) {
+	// The first pass counts the pages for the "page X of Y" footer. The
+	// {nb} alias of gofpdf is not used because digits missing from the
+	// rest of the text would be missing from the embedded font subset.
+	pages, err := renderPDF(report, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := renderPDF(report, pages, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderPDF(report *Report, totalPages int, buf *bytes.Buffer) (int, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFont, "", fontRegular)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", fontBold)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin+5)
+	pdf.SetTitle(report.Retrospective.Title, true)
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin)
+		pdf.SetFont(pdfFont, "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 5, "Gerado em "+report.GeneratedAt.Format("02/01/2006 15:04"), "", 0, "L", false, 0, "")
+		pdf.SetX(pdfMargin)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Página %d de %d", pdf.PageNo(), totalPages), "", 0, "R", false, 0, "")
+	})
+
+	pdf.AddPage()
+	writeHeader(pdf, report)
+	writeItems(pdf, report)
+	writeGroups(pdf, report)
+	writeActionItems(pdf, report)
+
+	if buf == nil {
+		pdf.Close()
+		return pdf.PageNo(), pdf.Error()
+	}
+	return pdf.PageNo(), pdf.Output(buf)
+}
+
+func writeHeader(pdf *gofpdf.Fpdf, report *Report) {
+	retrospective := report.Retrospective
+
+	pdf.SetFont(pdfFont, "B", 18)
+	pdf.SetTextColor(33, 33, 33)
+	multiCell(pdf, 0, 8, retrospective.Title, "", "L", false)
+	pdf.Ln(1)
+
+	if retrospective.Description != nil && *retrospective.Description != "" {
+		pdf.SetFont(pdfFont, "", 10)
+		pdf.SetTextColor(90, 90, 90)
+		multiCell(pdf, 0, pdfLineHeight, *retrospective.Description, "", "L", false)
+		pdf.Ln(1)
+	}
+
+	details := []string{
+		"Status: " + StatusLabel(retrospective.Status),
+		"Template: " + report.TemplateName,
+	}
+	if retrospective.StartedAt != nil {
+		details = append(details, "Início: "+retrospective.StartedAt.Format("02/01/2006 15:04"))
+	}
+	if retrospective.EndedAt != nil {
+		details = append(details, "Término: "+retrospective.EndedAt.Format("02/01/2006 15:04"))
+	}
+	details = append(details, fmt.Sprintf("Participantes: %d", len(retrospective.Participants)))
+
+	pdf.SetFont(pdfFont, "", 9)
+	pdf.SetTextColor(90, 90, 90)
+	multiCell(pdf, 0, pdfLineHeight, strings.Join(details, "   ·   "), "", "L", false)
+	pdf.Ln(4)
+}
+
+func writeSection(pdf *gofpdf.Fpdf, title string) {
+	// Keep the title together with the first lines of the section
+	ensureSpace(pdf, 20)
+	pdf.SetFont(pdfFont, "B", 13)
+	pdf.SetTextColor(33, 33, 33)
+	pdf.CellFormat(0, 8, title, "B", 1, "L", false, 0, "")
+	pdf.Ln(2)
+}
+
+func writeItems(pdf *gofpdf.Fpdf, report *Report) {
+	categories := report.ItemsByCategory()
+	if len(categories) == 0 {
+		return
+	}
+
+	writeSection(pdf, "Itens")
+	for _, category := range categories {
+		r, g, b := hexColor(category.Category.Color)
+
+		ensureSpace(pdf, 7+pdfLineHeight*2)
+		pdf.SetFillColor(r, g, b)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.SetFont(pdfFont, "B", 10)
+		pdf.CellFormat(0, 7, pdfText(fmt.Sprintf(" %s (%d)", category.Category.Name, len(category.Items))), "", 1, "L", true, 0, "")
+		pdf.Ln(1)
+
+		pdf.SetTextColor(33, 33, 33)
+		pdf.SetFont(pdfFont, "", 10)
+		for _, item := range category.Items {
+			writeStripedText(pdf, fmt.Sprintf("%s  (%s)", item.Content, votesLabel(item.Votes)), r, g, b)
+			writeNote(pdf, report.ItemNote(item.ID), pdfStripe+3)
+		}
+		pdf.Ln(3)
+	}
+}
+
+func writeGroups(pdf *gofpdf.Fpdf, report *Report) {
+	if len(report.Retrospective.Groups) == 0 {
+		return
+	}
+
+	writeSection(pdf, "Grupos")
+	for _, group := range report.Retrospective.Groups {
+		ensureSpace(pdf, pdfLineHeight*3)
+		pdf.SetFont(pdfFont, "B", 10)
+		pdf.SetTextColor(33, 33, 33)
+		multiCell(pdf, 0, pdfLineHeight+1, fmt.Sprintf("%s  (%s)", group.Name, votesLabel(group.Votes)), "", "L", false)
+
+		pdf.SetFont(pdfFont, "", 9)
+		if group.Description != nil && *group.Description != "" {
+			pdf.SetTextColor(90, 90, 90)
+			multiCell(pdf, 0, pdfLineHeight, *group.Description, "", "L", false)
+		}
+		writeNote(pdf, report.GroupNote(group.ID), 0)
+
+		pdf.SetTextColor(33, 33, 33)
+		for _, itemID := range report.GroupItems[group.ID] {
+			item := report.Item(itemID)
+			if item == nil {
+				continue
+			}
+			r, g, b := hexColor(categoryColor(report, item.Category))
+			writeStripedText(pdf, fmt.Sprintf("%s  (%s)", item.Content, report.CategoryName(item.Category)), r, g, b)
+		}
+		pdf.Ln(3)
+	}
+}
+
+func writeActionItems(pdf *gofpdf.Fpdf, report *Report) {
+	if len(report.Retrospective.ActionItems) == 0 {
+		return
+	}
+
+	writeSection(pdf, "Action items")
+	for _, actionItem := range report.Retrospective.ActionItems {
+		ensureSpace(pdf, pdfLineHeight*3)
+		pdf.SetFont(pdfFont, "B", 10)
+		pdf.SetTextColor(33, 33, 33)
+		multiCell(pdf, 0, pdfLineHeight+1, actionItem.Title, "", "L", false)
+
+		if actionItem.Description != nil && *actionItem.Description != "" {
+			pdf.SetFont(pdfFont, "", 9)
+			pdf.SetTextColor(90, 90, 90)
+			multiCell(pdf, 0, pdfLineHeight, *actionItem.Description, "", "L", false)
+		}
+
+		assignee := report.UserName(actionItem.AssignedTo)
+		if assignee == "" {
+			assignee = "sem responsável"
+		}
+		dueDate := "sem prazo"
+		if actionItem.DueDate != nil {
+			dueDate = actionItem.DueDate.Format("02/01/2006")
+		}
+
+		pdf.SetFont(pdfFont, "", 9)
+		pdf.SetTextColor(90, 90, 90)
+		multiCell(pdf, 0, pdfLineHeight, fmt.Sprintf("Responsável: %s   ·   Prazo: %s   ·   Status: %s",
+			assignee, dueDate, ActionItemStatusLabel(actionItem.Status)), "", "L", false)
+		pdf.Ln(2)
+	}
+}
+
+// writeNote writes the notes and outcome of a discussion note, if any,
+// indented from the left margin
+func writeNote(pdf *gofpdf.Fpdf, note *models.DiscussionNote, indent float64) {
+	if note == nil {
+		return
+	}
+
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	lines := []string{}
+	if note.Content != "" {
+		lines = append(lines, "Notas: "+note.Content)
+	}
+	if note.Outcome != "" {
+		lines = append(lines, "Resultado: "+note.Outcome)
+	}
+
+	pdf.SetFont(pdfFont, "", 9)
+	pdf.SetTextColor(90, 90, 90)
+	for _, line := range lines {
+		pdf.SetX(left + indent)
+		multiCell(pdf, pageWidth-left-right-indent, pdfLineHeight, line, "", "L", false)
+	}
+	pdf.Ln(1)
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.SetTextColor(33, 33, 33)
+}
+
+// writeStripedText writes wrapped text with a colored bar on its left. The
+// page is broken before the text so the bar and the text stay together.
+func writeStripedText(pdf *gofpdf.Fpdf, text string, r, g, b int) {
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	textWidth := pageWidth - left - right - pdfStripe - 3
+
+	text = pdfText(text)
+	height := float64(len(pdf.SplitText(text, textWidth))) * pdfLineHeight
+	ensureSpace(pdf, height)
+
+	y := pdf.GetY()
+	pdf.SetFillColor(r, g, b)
+	pdf.Rect(left, y, pdfStripe, height, "F")
+	pdf.SetXY(left+pdfStripe+3, y)
+	multiCell(pdf, textWidth, pdfLineHeight, text, "", "L", false)
+	pdf.Ln(1)
+}
+
+// multiCell writes wrapped text
+func multiCell(pdf *gofpdf.Fpdf, w, h float64, text, border, align string, fill bool) {
+	pdf.MultiCell(w, h, pdfText(text), border, align, fill)
+}
+
+// pdfText drops the characters outside the Basic Multilingual Plane, like
+// emoji: gofpdf has no widths for them and the font has no glyphs
+func pdfText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0xFFFF {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// ensureSpace starts a new page when less than height is left on this one
+func ensureSpace(pdf *gofpdf.Fpdf, height float64) {
+	_, pageHeight := pdf.GetPageSize()
+	_, bottom := pdf.GetAutoPageBreak()
+	if pdf.GetY()+height > pageHeight-bottom {
+		pdf.AddPage()
+	}
+}
+
+func categoryColor(report *Report, id string) string {
+	for _, category := range report.Categories {
+		if category.ID == id {
+			return category.Color
+		}
+	}
+	return ""
+}
+
+// hexColor parses a #RRGGBB color, falling back to gray
+func hexColor(color string) (int, int, int) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(color, "#")) != 6 {
+		return 117, 117, 117
+	}
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff)
+}
+
+func votesLabel(votes int) string {
+	if votes == 1 {
+		return "1 voto"
+	}
+	return fmt.Sprintf("%d votos", votes)
+}
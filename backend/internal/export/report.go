@@ -0,0 +1,153 @@
+// Package export renders a retrospective as a document to download.
+package export
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Report is everything an export shows about a retrospective
+type Report struct {
+	Retrospective *models.RetrospectiveWithDetails
+	TemplateName  string
+	// Categories of the template, in display order
+	Categories []Category
+	// GroupItems lists the items of each group
+	GroupItems map[uuid.UUID][]uuid.UUID
+	// UserNames resolves assignees to names
+	UserNames   map[uuid.UUID]string
+	GeneratedAt time.Time
+}
+
+// Category is a column of the retrospective template
+type Category struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"` // hex, e.g. #4CAF50
+}
+
+// CategoryName returns the display name of a category, or its ID when it
+// is not part of the template
+func (r *Report) CategoryName(id string) string {
+	for _, category := range r.Categories {
+		if category.ID == id {
+			return category.Name
+		}
+	}
+	return id
+}
+
+// UserName returns the name of the user, or "" when unknown or nil
+func (r *Report) UserName(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return r.UserNames[*id]
+}
+
+// ItemsByCategory returns the items of each category, most voted first.
+// Categories of the template come first, in order, followed by any other
+// category found in the items.
+func (r *Report) ItemsByCategory() []CategoryItems {
+	categories := append([]Category{}, r.Categories...)
+	known := map[string]bool{}
+	for _, category := range r.Categories {
+		known[category.ID] = true
+	}
+
+	byCategory := map[string][]models.RetrospectiveItem{}
+	for _, item := range r.Retrospective.Items {
+		if _, seen := byCategory[item.Category]; !seen && !known[item.Category] {
+			categories = append(categories, Category{ID: item.Category, Name: item.Category})
+		}
+		byCategory[item.Category] = append(byCategory[item.Category], item)
+	}
+
+	result := []CategoryItems{}
+	for _, category := range categories {
+		items := byCategory[category.ID]
+		if len(items) == 0 {
+			continue
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Votes > items[j].Votes
+		})
+		result = append(result, CategoryItems{Category: category, Items: items})
+	}
+
+	return result
+}
+
+// CategoryItems are the items of one category
+type CategoryItems struct {
+	Category Category
+	Items    []models.RetrospectiveItem
+}
+
+// Item returns the item with the ID, or nil
+func (r *Report) Item(id uuid.UUID) *models.RetrospectiveItem {
+	for i := range r.Retrospective.Items {
+		if r.Retrospective.Items[i].ID == id {
+			return &r.Retrospective.Items[i]
+		}
+	}
+	return nil
+}
+
+// ItemNote returns the discussion note of the item, or nil when it has none
+// or it is blank
+func (r *Report) ItemNote(id uuid.UUID) *models.DiscussionNote {
+	for i, note := range r.Retrospective.Notes {
+		if note.ItemID != nil && *note.ItemID == id {
+			return nonBlankNote(&r.Retrospective.Notes[i])
+		}
+	}
+	return nil
+}
+
+// GroupNote returns the discussion note of the group, or nil when it has none
+// or it is blank
+func (r *Report) GroupNote(id uuid.UUID) *models.DiscussionNote {
+	for i, note := range r.Retrospective.Notes {
+		if note.GroupID != nil && *note.GroupID == id {
+			return nonBlankNote(&r.Retrospective.Notes[i])
+		}
+	}
+	return nil
+}
+
+func nonBlankNote(note *models.DiscussionNote) *models.DiscussionNote {
+	if strings.TrimSpace(note.Content) == "" && strings.TrimSpace(note.Outcome) == "" {
+		return nil
+	}
+	return note
+}
+
+// StatusLabel translates a retrospective status
+func StatusLabel(status models.RetrospectiveStatus) string {
+	switch status {
+	case models.RetroStatusPlanned:
+		return "Planejada"
+	case models.RetroStatusClosed:
+		return "Encerrada"
+	default:
+		return "Em andamento"
+	}
+}
+
+// ActionItemStatusLabel translates an action item status
+func ActionItemStatusLabel(status string) string {
+	switch status {
+	case "done":
+		return "Concluído"
+	case "in_progress":
+		return "Em progresso"
+	default:
+		return "Pendente"
+	}
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() *Report {
+	description := "Revisão da sprint: o que não funcionou e qual ação tomar"
+	assignee := uuid.New()
+	dueDate := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	groupID := uuid.New()
+	items := []models.RetrospectiveItem{
+		{ID: uuid.New(), Category: "improve", Content: "Deploy manual demora demais", Votes: 1},
+		{ID: uuid.New(), Category: "went_well", Content: "Integração contínua estável 🎉", Votes: 2},
+		{ID: uuid.New(), Category: "improve", Content: strings.Repeat("Comunicação entre os times precisa melhorar. ", 30), Votes: 4},
+		{ID: uuid.New(), Category: "legacy", Content: "Categoria que não está no template", Votes: 0},
+	}
+
+	return &Report{
+		Retrospective: &models.RetrospectiveWithDetails{
+			Retrospective: models.Retrospective{
+				ID:          uuid.New(),
+				Title:       "Retrospectiva de março — ação e reflexão",
+				Description: &description,
+				Template:    models.TemplateWentWellToImprove,
+				Status:      models.RetroStatusClosed,
+			},
+			Items: items,
+			Groups: []models.RetrospectiveGroup{
+				{ID: groupID, Name: "Automação", Votes: 3},
+			},
+			ActionItems: []models.ActionItem{
+				{Title: "Automatizar o deploy", Status: "in_progress", AssignedTo: &assignee, DueDate: &dueDate},
+				{Title: "Revisar critérios de aceitação", Status: "todo"},
+			},
+			Notes: []models.DiscussionNote{
+				{ItemID: &items[0].ID, Content: "O deploy leva 2 horas\ne falha às vezes", Outcome: "Automatizar o deploy", UpdatedBy: &assignee},
+				{ItemID: &items[3].ID, Content: "  "},
+				{GroupID: &groupID, Outcome: "Investir em automação"},
+			},
+		},
+		TemplateName: "Deu certo / Melhorar",
+		Categories: []Category{
+			{ID: "went_well", Name: "Deu certo", Color: "#4CAF50"},
+			{ID: "improve", Name: "Melhorar", Color: "#FF9800"},
+		},
+		GroupItems:  map[uuid.UUID][]uuid.UUID{groupID: {items[0].ID, items[1].ID}},
+		UserNames:   map[uuid.UUID]string{assignee: "João"},
+		GeneratedAt: time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestReport_ItemsByCategory(t *testing.T) {
+	report := testReport()
+
+	categories := report.ItemsByCategory()
+
+	require.Len(t, categories, 3)
+	assert.Equal(t, "went_well", categories[0].Category.ID)
+	assert.Equal(t, "improve", categories[1].Category.ID)
+	assert.Equal(t, 4, categories[1].Items[0].Votes)
+	assert.Equal(t, 1, categories[1].Items[1].Votes)
+	// Categories missing from the template come last, named by their ID
+	assert.Equal(t, Category{ID: "legacy", Name: "legacy"}, categories[2].Category)
+}
+
+func TestReport_Names(t *testing.T) {
+	report := testReport()
+
+	assert.Equal(t, "Melhorar", report.CategoryName("improve"))
+	assert.Equal(t, "legacy", report.CategoryName("legacy"))
+	assert.Equal(t, "João", report.UserName(report.Retrospective.ActionItems[0].AssignedTo))
+	assert.Equal(t, "", report.UserName(nil))
+	assert.Nil(t, report.Item(uuid.New()))
+}
+
+func TestReport_Notes(t *testing.T) {
+	report := testReport()
+	items := report.Retrospective.Items
+
+	require.NotNil(t, report.ItemNote(items[0].ID))
+	assert.Equal(t, "Automatizar o deploy", report.ItemNote(items[0].ID).Outcome)
+	// Blank notes are left out
+	assert.Nil(t, report.ItemNote(items[3].ID))
+	assert.Nil(t, report.ItemNote(items[1].ID))
+	require.NotNil(t, report.GroupNote(report.Retrospective.Groups[0].ID))
+	assert.Nil(t, report.GroupNote(items[0].ID))
+}
+
+func TestPDF(t *testing.T) {
+	report := testReport()
+	// Enough items for several pages
+	for i := 0; i < 80; i++ {
+		report.Retrospective.Items = append(report.Retrospective.Items, models.RetrospectiveItem{
+			ID: uuid.New(), Category: "went_well", Content: "Reunião diária mais curta e objetiva",
+		})
+	}
+
+	content, err := PDF(report)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "%PDF"))
+}
+
+func TestPDF_EmptyRetrospective(t *testing.T) {
+	report := &Report{
+		Retrospective: &models.RetrospectiveWithDetails{
+			Retrospective: models.Retrospective{Title: "Sem itens", Status: models.RetroStatusPlanned},
+		},
+		GeneratedAt: time.Now(),
+	}
+
+	content, err := PDF(report)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "%PDF"))
+}
+
+func TestHexColor(t *testing.T) {
+	r, g, b := hexColor("#4CAF50")
+	assert.Equal(t, []int{76, 175, 80}, []int{r, g, b})
+
+	r, g, b = hexColor("green")
+	assert.Equal(t, []int{117, 117, 117}, []int{r, g, b})
+}
@@ -0,0 +1,107 @@
+package export
+
+import "time"
+
+// Summary is the read-only view of a retrospective behind a public link.
+// It shows what the exports show, by name and without user IDs.
+type Summary struct {
+	Title        string              `json:"title"`
+	Description  string              `json:"description,omitempty"`
+	Template     string              `json:"template"`
+	Status       string              `json:"status"`
+	StartedAt    *time.Time          `json:"started_at,omitempty"`
+	EndedAt      *time.Time          `json:"ended_at,omitempty"`
+	Participants int                 `json:"participants"`
+	Categories   []SummaryCategory   `json:"categories"`
+	Groups       []SummaryGroup      `json:"groups"`
+	ActionItems  []SummaryActionItem `json:"action_items"`
+	GeneratedAt  time.Time           `json:"generated_at"`
+}
+
+type SummaryCategory struct {
+	Name  string        `json:"name"`
+	Color string        `json:"color,omitempty"`
+	Items []SummaryItem `json:"items"`
+}
+
+type SummaryItem struct {
+	Content string `json:"content"`
+	Votes   int    `json:"votes"`
+	Notes   string `json:"notes,omitempty"`
+	Outcome string `json:"outcome,omitempty"`
+}
+
+type SummaryGroup struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Votes       int      `json:"votes"`
+	Items       []string `json:"items"`
+	Notes       string   `json:"notes,omitempty"`
+	Outcome     string   `json:"outcome,omitempty"`
+}
+
+type SummaryActionItem struct {
+	Title    string     `json:"title"`
+	Status   string     `json:"status"`
+	Assignee string     `json:"assignee,omitempty"`
+	DueDate  *time.Time `json:"due_date,omitempty"`
+}
+
+// NewSummary builds the summary of the report
+func NewSummary(report *Report) *Summary {
+	retrospective := report.Retrospective
+	summary := &Summary{
+		Title:        retrospective.Title,
+		Template:     report.TemplateName,
+		Status:       StatusLabel(retrospective.Status),
+		StartedAt:    retrospective.StartedAt,
+		EndedAt:      retrospective.EndedAt,
+		Participants: len(retrospective.Participants),
+		Categories:   []SummaryCategory{},
+		Groups:       []SummaryGroup{},
+		ActionItems:  []SummaryActionItem{},
+		GeneratedAt:  report.GeneratedAt,
+	}
+	if retrospective.Description != nil {
+		summary.Description = *retrospective.Description
+	}
+
+	for _, category := range report.ItemsByCategory() {
+		summaryCategory := SummaryCategory{Name: category.Category.Name, Color: category.Category.Color}
+		for _, item := range category.Items {
+			summaryItem := SummaryItem{Content: item.Content, Votes: item.Votes}
+			if note := report.ItemNote(item.ID); note != nil {
+				summaryItem.Notes, summaryItem.Outcome = note.Content, note.Outcome
+			}
+			summaryCategory.Items = append(summaryCategory.Items, summaryItem)
+		}
+		summary.Categories = append(summary.Categories, summaryCategory)
+	}
+
+	for _, group := range retrospective.Groups {
+		summaryGroup := SummaryGroup{Name: group.Name, Votes: group.Votes, Items: []string{}}
+		if group.Description != nil {
+			summaryGroup.Description = *group.Description
+		}
+		if note := report.GroupNote(group.ID); note != nil {
+			summaryGroup.Notes, summaryGroup.Outcome = note.Content, note.Outcome
+		}
+		for _, itemID := range report.GroupItems[group.ID] {
+			if item := report.Item(itemID); item != nil {
+				summaryGroup.Items = append(summaryGroup.Items, item.Content)
+			}
+		}
+		summary.Groups = append(summary.Groups, summaryGroup)
+	}
+
+	for _, actionItem := range retrospective.ActionItems {
+		summary.ActionItems = append(summary.ActionItems, SummaryActionItem{
+			Title:    actionItem.Title,
+			Status:   ActionItemStatusLabel(actionItem.Status),
+			Assignee: report.UserName(actionItem.AssignedTo),
+			DueDate:  actionItem.DueDate,
+		})
+	}
+
+	return summary
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSummary(t *testing.T) {
+	report := testReport()
+
+	summary := NewSummary(report)
+
+	assert.Equal(t, "Retrospectiva de março — ação e reflexão", summary.Title)
+	assert.Equal(t, "Deu certo / Melhorar", summary.Template)
+	assert.Equal(t, "Encerrada", summary.Status)
+	require.Len(t, summary.Categories, 3)
+	assert.Equal(t, "Melhorar", summary.Categories[1].Name)
+	assert.Equal(t, 4, summary.Categories[1].Items[0].Votes)
+	require.Len(t, summary.Groups, 1)
+	assert.Equal(t, []string{"Deploy manual demora demais", "Integração contínua estável 🎉"}, summary.Groups[0].Items)
+	assert.Equal(t, "Investir em automação", summary.Groups[0].Outcome)
+	assert.Equal(t, "Automatizar o deploy", summary.Categories[1].Items[1].Outcome)
+	require.Len(t, summary.ActionItems, 2)
+	assert.Equal(t, "João", summary.ActionItems[0].Assignee)
+	assert.Equal(t, "Em progresso", summary.ActionItems[0].Status)
+
+	// The public summary names people instead of showing their IDs
+	content, err := json.Marshal(summary)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), report.Retrospective.ActionItems[0].AssignedTo.String())
+	assert.NotContains(t, string(content), report.Retrospective.ID.String())
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ActionItemHandler struct {
+	retrospectiveService *services.RetrospectiveService
+	realtimeService      *services.RealtimeService
+}
+
+func NewActionItemHandler(retrospectiveService *services.RetrospectiveService, realtimeService *services.RealtimeService) *ActionItemHandler {
+	return &ActionItemHandler{
+		retrospectiveService: retrospectiveService,
+		realtimeService:      realtimeService,
+	}
+}
+
+// ListActionItems godoc
+// @Summary List action items
+// @Description List action items across the retrospectives the user created, joined or that belong to one of their teams
+// @Tags Action Items
+// @Produce json
+// @Security BearerAuth
+// @Param assigned_to query string false "Assignee ID, or \"me\""
+// @Param status query string false "Comma separated statuses (todo, in_progress, done)"
+// @Param due_from query string false "Due on or after (YYYY-MM-DD)"
+// @Param due_to query string false "Due on or before (YYYY-MM-DD)"
+// @Param team_id query string false "Team ID"
+// @Param retrospective_id query string false "Retrospective ID"
+// @Param search query string false "Search in title and description"
+// @Param sort query string false "Sort field (due_date, created_at, updated_at, status, title)" default(due_date)
+// @Param order query string false "Sort order (asc, desc)" default(asc)
+// @Param limit query int false "Page size (1-100)" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.ActionItemList "Action items"
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /action-items [get]
+func (h *ActionItemHandler) ListActionItems(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	filter := models.ActionItemFilter{
+		Search:    c.Query("search"),
+		SortBy:    c.Query("sort"),
+		SortOrder: c.Query("order"),
+	}
+
+	if assignedTo := c.Query("assigned_to"); assignedTo != "" {
+		if assignedTo == "me" {
+			id := userID.(uuid.UUID)
+			filter.AssignedTo = &id
+		} else {
+			id, err := uuid.Parse(assignedTo)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid assigned_to"})
+				return
+			}
+			filter.AssignedTo = &id
+		}
+	}
+
+	if status := c.Query("status"); status != "" {
+		filter.Status = strings.Split(status, ",")
+	}
+
+	if dueFrom := c.Query("due_from"); dueFrom != "" {
+		date, err := time.Parse("2006-01-02", dueFrom)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid due_from format"})
+			return
+		}
+		filter.DueFrom = &date
+	}
+
+	if dueTo := c.Query("due_to"); dueTo != "" {
+		date, err := time.Parse("2006-01-02", dueTo)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid due_to format"})
+			return
+		}
+		// Include the whole day
+		date = date.AddDate(0, 0, 1).Add(-time.Microsecond)
+		filter.DueTo = &date
+	}
+
+	if teamID := c.Query("team_id"); teamID != "" {
+		id, err := uuid.Parse(teamID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team_id"})
+			return
+		}
+		filter.TeamID = &id
+	}
+
+	if retrospectiveID := c.Query("retrospective_id"); retrospectiveID != "" {
+		id, err := uuid.Parse(retrospectiveID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid retrospective_id"})
+			return
+		}
+		filter.RetrospectiveID = &id
+	}
+
+	var err error
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+	if offset := c.Query("offset"); offset != "" {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+			return
+		}
+	}
+
+	actionItems, err := h.retrospectiveService.ListActionItems(userID.(uuid.UUID), filter)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") || strings.HasPrefix(err.Error(), "limit ") ||
+			strings.HasPrefix(err.Error(), "offset ") || strings.HasPrefix(err.Error(), "due_to ") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, actionItems)
+}
+
+// actionItemErrorStatus maps action item history and comment errors to HTTP statuses
+func actionItemErrorStatus(err error) int {
+	switch err.Error() {
+	case "action item not found", "comment not found", "parent comment not found":
+		return http.StatusNotFound
+	case "access denied":
+		return http.StatusForbidden
+	case "content is required", "invalid parent_id":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// broadcast sends an SSE event to the retrospective of the action item and to
+// the retrospectives it was carried into
+func (h *ActionItemHandler) broadcast(actionItemID uuid.UUID, event string, data map[string]interface{}) {
+	if h.realtimeService == nil {
+		return
+	}
+
+	actionItem, err := h.retrospectiveService.GetActionItemByID(actionItemID)
+	if err != nil {
+		return
+	}
+
+	carriedInto, _ := h.retrospectiveService.GetCarryoverRetrospectiveIDs(actionItemID)
+	h.realtimeService.BroadcastToRetrospectives(actionItem.RetrospectiveID, carriedInto, event, data)
+}
+
+// GetActionItemHistory godoc
+// @Summary Get action item history
+// @Description List the status changes, reassignments and due date moves of an action item, oldest first
+// @Tags Action Items
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Action item ID"
+// @Success 200 {array} models.ActionItemEvent "History"
+// @Failure 400 {object} map[string]string "Invalid action item ID"
+// @Failure 404 {object} map[string]string "Action item not found"
+// @Router /action-items/{id}/history [get]
+func (h *ActionItemHandler) GetActionItemHistory(c *gin.Context) {
+	actionItemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	events, err := h.retrospectiveService.GetActionItemHistory(actionItemID)
+	if err != nil {
+		c.JSON(actionItemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// GetActionItemComments godoc
+// @Summary List action item comments
+// @Description List the comment threads of an action item, oldest first, with replies nested
+// @Tags Action Items
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Action item ID"
+// @Success 200 {array} models.ActionItemComment "Comments"
+// @Failure 400 {object} map[string]string "Invalid action item ID"
+// @Failure 404 {object} map[string]string "Action item not found"
+// @Router /action-items/{id}/comments [get]
+func (h *ActionItemHandler) GetActionItemComments(c *gin.Context) {
+	actionItemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	comments, err := h.retrospectiveService.GetActionItemComments(actionItemID)
+	if err != nil {
+		c.JSON(actionItemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// AddActionItemComment godoc
+// @Summary Comment on an action item
+// @Description Add a comment to an action item, or a reply when parent_id is given
+// @Tags Action Items
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Action item ID"
+// @Param request body models.ActionItemCommentCreateRequest true "Comment"
+// @Success 201 {object} models.ActionItemComment "Comment created"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Action item or parent comment not found"
+// @Router /action-items/{id}/comments [post]
+func (h *ActionItemHandler) AddActionItemComment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	actionItemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	var req models.ActionItemCommentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.retrospectiveService.AddActionItemComment(actionItemID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(actionItemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send real-time update via SSE
+	h.broadcast(actionItemID, "action_item_comment_added", map[string]interface{}{
+		"comment": comment,
+	})
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// UpdateActionItemComment godoc
+// @Summary Edit an action item comment
+// @Description Edit a comment. Only its author can edit it.
+// @Tags Action Items
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Action item ID"
+// @Param commentId path string true "Comment ID"
+// @Param request body models.ActionItemCommentUpdateRequest true "Comment"
+// @Success 200 {object} models.ActionItemComment "Comment updated"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Comment not found"
+// @Router /action-items/{id}/comments/{commentId} [put]
+func (h *ActionItemHandler) UpdateActionItemComment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	actionItemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var req models.ActionItemCommentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.retrospectiveService.UpdateActionItemComment(actionItemID, commentID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(actionItemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send real-time update via SSE
+	h.broadcast(actionItemID, "action_item_comment_updated", map[string]interface{}{
+		"comment": comment,
+	})
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteActionItemComment godoc
+// @Summary Delete an action item comment
+// @Description Delete a comment and its replies. The author and whoever manages the action item can delete it.
+// @Tags Action Items
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Action item ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} map[string]string "Comment deleted"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Comment not found"
+// @Router /action-items/{id}/comments/{commentId} [delete]
+func (h *ActionItemHandler) DeleteActionItemComment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	actionItemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	err = h.retrospectiveService.DeleteActionItemComment(actionItemID, commentID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(actionItemErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send real-time update via SSE
+	h.broadcast(actionItemID, "action_item_comment_deleted", map[string]interface{}{
+		"action_item_id": actionItemID,
+		"comment_id":     commentID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+func (h *ActionItemHandler) SetupRoutes(r *gin.RouterGroup) {
+	actionItems := r.Group("/action-items")
+	actionItems.Use(authMiddleware)
+	{
+		actionItems.GET("", h.ListActionItems)
+		actionItems.GET("/:id/history", h.GetActionItemHistory)
+		actionItems.GET("/:id/comments", h.GetActionItemComments)
+		actionItems.POST("/:id/comments", h.AddActionItemComment)
+		actionItems.PUT("/:id/comments/:commentId", h.UpdateActionItemComment)
+		actionItems.DELETE("/:id/comments/:commentId", h.DeleteActionItemComment)
+	}
+}
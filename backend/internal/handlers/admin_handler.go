@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"educ-retro/internal/auth"
+	"educ-retro/internal/models"
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AdminHandler struct {
+	adminService *services.AdminService
+}
+
+func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description List all users, optionally filtered by name or email (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Search by name or email"
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.AdminUserList "Users"
+// @Failure 400 {object} map[string]string "Invalid pagination"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+
+	users, err := h.adminService.ListUsers(c.Query("search"), limit, offset)
+	if err != nil {
+		if err.Error() == "limit must be between 1 and 100" || err.Error() == "offset must not be negative" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// SuspendUser godoc
+// @Summary Suspend user
+// @Description Suspend a user account; the user can no longer log in or use existing tokens (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User "Suspended user"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	h.setUserSuspended(c, true)
+}
+
+// ReactivateUser godoc
+// @Summary Reactivate user
+// @Description Lift the suspension of a user account (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User "Reactivated user"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /admin/users/{id}/reactivate [post]
+func (h *AdminHandler) ReactivateUser(c *gin.Context) {
+	h.setUserSuspended(c, false)
+}
+
+func (h *AdminHandler) setUserSuspended(c *gin.Context, suspended bool) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	user, err := h.adminService.SetUserSuspended(adminID.(uuid.UUID), userID, suspended)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "cannot suspend your own account":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateUserRole godoc
+// @Summary Change user role
+// @Description Grant or revoke the admin role (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param role body models.UserRoleUpdateRequest true "New role"
+// @Success 200 {object} models.User "Updated user"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req models.UserRoleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.adminService.UpdateUserRole(adminID.(uuid.UUID), userID, req.Role)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "invalid role. Must be one of: admin, user", "cannot change your own role":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// GetOrphanedRetrospectives godoc
+// @Summary List orphaned retrospectives
+// @Description List retrospectives whose creator deleted their account (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Retrospective "Orphaned retrospectives"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Router /admin/retrospectives/orphaned [get]
+func (h *AdminHandler) GetOrphanedRetrospectives(c *gin.Context) {
+	retrospectives, err := h.adminService.GetOrphanedRetrospectives()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, retrospectives)
+}
+
+// TransferRetrospective godoc
+// @Summary Transfer retrospective ownership
+// @Description Make another user the owner of a retrospective (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param transfer body models.RetrospectiveTransferRequest true "New owner"
+// @Success 200 {object} models.Retrospective "Updated retrospective"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Failure 404 {object} map[string]string "Retrospective or user not found"
+// @Router /admin/retrospectives/{id}/transfer [post]
+func (h *AdminHandler) TransferRetrospective(c *gin.Context) {
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid retrospective ID"})
+		return
+	}
+
+	var req models.RetrospectiveTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	retrospective, err := h.adminService.TransferRetrospective(retrospectiveID, req.NewOwnerID)
+	if err != nil {
+		switch err.Error() {
+		case "retrospective not found", "new owner not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "new owner is suspended":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, retrospective)
+}
+
+// GetSystemStats godoc
+// @Summary Get system statistics
+// @Description Get system-wide counters of users, retrospectives and action items (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SystemStats "System statistics"
+// @Failure 403 {object} map[string]string "Insufficient permissions"
+// @Router /admin/stats [get]
+func (h *AdminHandler) GetSystemStats(c *gin.Context) {
+	stats, err := h.adminService.GetSystemStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *AdminHandler) SetupRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin")
+	admin.Use(authMiddleware, auth.RequireRole(string(models.UserRoleAdmin)))
+	{
+		admin.GET("/users", h.ListUsers)
+		admin.POST("/users/:id/suspend", h.SuspendUser)
+		admin.POST("/users/:id/reactivate", h.ReactivateUser)
+		admin.PUT("/users/:id/role", h.UpdateUserRole)
+		admin.GET("/retrospectives/orphaned", h.GetOrphanedRetrospectives)
+		admin.POST("/retrospectives/:id/transfer", h.TransferRetrospective)
+		admin.GET("/stats", h.GetSystemStats)
+	}
+}
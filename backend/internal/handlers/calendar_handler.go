@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+}
+
+func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// feedURL is the public URL of the feed with the token, on the host the request was sent to
+func feedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/api/v1/calendar/%s.ics", scheme, c.Request.Host, token)
+}
+
+// GetCalendarFeed godoc
+// @Summary Get the calendar feed URL
+// @Description Get the URL of the user's iCalendar feed with their scheduled retrospectives and action item due dates, creating it on first use. The URL contains a secret token; anyone with it can read the feed.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Feed token and URL"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /users/profile/calendar [get]
+func (h *CalendarHandler) GetCalendarFeed(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	token, err := h.calendarService.GetFeedToken(userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "url": feedURL(c, token)})
+}
+
+// ResetCalendarFeed godoc
+// @Summary Reset the calendar feed URL
+// @Description Replace the token of the user's calendar feed. The previous URL stops working.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "New feed token and URL"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /users/profile/calendar/reset [post]
+func (h *CalendarHandler) ResetCalendarFeed(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	token, err := h.calendarService.ResetFeedToken(userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "url": feedURL(c, token)})
+}
+
+// GetUserFeed godoc
+// @Summary Calendar feed
+// @Description iCalendar feed of the token's owner: scheduled retrospectives they can see and unfinished action items assigned to them with a due date. Authenticated by the token in the URL, for calendar apps.
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally followed by .ics"
+// @Success 200 {file} binary "iCalendar feed"
+// @Failure 404 {object} map[string]string "Invalid calendar token"
+// @Router /calendar/{token} [get]
+func (h *CalendarHandler) GetUserFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	feed, err := h.calendarService.GetUserFeed(token)
+	if err != nil {
+		if err.Error() == "invalid calendar token" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, calendarContentType, feed)
+}
+
+// DownloadRetrospectiveCalendar godoc
+// @Summary Download retrospective calendar
+// @Description Download an .ics with the retrospective, if it is scheduled, and the due dates of its unfinished action items
+// @Tags Retrospectives
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Success 200 {file} binary "iCalendar file"
+// @Failure 400 {object} map[string]string "Invalid retrospective ID"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Router /retrospectives/{id}/calendar.ics [get]
+func (h *CalendarHandler) DownloadRetrospectiveCalendar(c *gin.Context) {
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid retrospective ID"})
+		return
+	}
+
+	ics, err := h.calendarService.GetRetrospectiveCalendar(retrospectiveID)
+	if err != nil {
+		if err.Error() == "retrospective not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"retrospective_%s.ics\"", retrospectiveID))
+	c.Data(http.StatusOK, calendarContentType, ics)
+}
+
+func (h *CalendarHandler) SetupRoutes(r *gin.RouterGroup) {
+	// Public: calendar apps authenticate with the token in the URL
+	r.GET("/calendar/:token", h.GetUserFeed)
+
+	users := r.Group("/users")
+	users.Use(authMiddleware)
+	{
+		users.GET("/profile/calendar", h.GetCalendarFeed)
+		users.POST("/profile/calendar/reset", h.ResetCalendarFeed)
+	}
+
+	retrospectives := r.Group("/retrospectives")
+	retrospectives.Use(authMiddleware)
+	{
+		retrospectives.GET("/:id/calendar.ics", h.DownloadRetrospectiveCalendar)
+	}
+}
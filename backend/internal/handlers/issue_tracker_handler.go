@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type IssueTrackerHandler struct {
+	issueTrackerService *services.IssueTrackerService
+}
+
+func NewIssueTrackerHandler(issueTrackerService *services.IssueTrackerService) *IssueTrackerHandler {
+	return &IssueTrackerHandler{
+		issueTrackerService: issueTrackerService,
+	}
+}
+
+// issueTrackerErrorStatus maps export and sync errors to HTTP statuses
+func issueTrackerErrorStatus(err error) int {
+	switch {
+	case err.Error() == "action item not found":
+		return http.StatusNotFound
+	case err.Error() == "access denied":
+		return http.StatusForbidden
+	case err.Error() == "action item already exported":
+		return http.StatusConflict
+	case err.Error() == "action item not exported", err.Error() == "action item was exported to another issue tracker":
+		return http.StatusBadRequest
+	case err.Error() == "issue tracker not configured":
+		return http.StatusServiceUnavailable
+	case strings.HasPrefix(err.Error(), "issue tracker error"):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetIssueTracker godoc
+// @Summary Get the issue tracker
+// @Description Tell which issue tracker (github or jira) action items can be exported to, if any
+// @Tags Action Items
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Issue tracker"
+// @Router /issue-tracker [get]
+func (h *IssueTrackerHandler) GetIssueTracker(c *gin.Context) {
+	provider := h.issueTrackerService.Provider()
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":  provider != "",
+		"provider": provider,
+	})
+}
+
+// ExportActionItem godoc
+// @Summary Export an action item to the issue tracker
+// @Description Create an issue for the action item in the configured tracker and store its link on the action item
+// @Tags Action Items
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Action item ID"
+// @Success 201 {object} models.ActionItem "Action item with the issue link"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Action item not found"
+// @Failure 409 {object} map[string]string "Action item already exported"
+// @Failure 502 {object} map[string]string "Issue tracker error"
+// @Failure 503 {object} map[string]string "Issue tracker not configured"
+// @Router /action-items/{id}/export [post]
+func (h *IssueTrackerHandler) ExportActionItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	actionItemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	actionItem, err := h.issueTrackerService.ExportActionItem(actionItemID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(issueTrackerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, actionItem)
+}
+
+// SyncActionItem godoc
+// @Summary Sync an action item with its issue
+// @Description Bring the status of the linked issue to the action item now instead of waiting for the periodic sync
+// @Tags Action Items
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Action item ID"
+// @Success 200 {object} map[string]interface{} "Action item and the recorded history events"
+// @Failure 400 {object} map[string]string "Action item not exported"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Action item not found"
+// @Failure 502 {object} map[string]string "Issue tracker error"
+// @Router /action-items/{id}/sync [post]
+func (h *IssueTrackerHandler) SyncActionItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	actionItemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	actionItem, events, err := h.issueTrackerService.SyncActionItem(actionItemID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(issueTrackerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"action_item": actionItem,
+		"events":      events,
+	})
+}
+
+func (h *IssueTrackerHandler) SetupRoutes(r *gin.RouterGroup) {
+	r.GET("/issue-tracker", authMiddleware, h.GetIssueTracker)
+
+	actionItems := r.Group("/action-items")
+	actionItems.Use(authMiddleware)
+	{
+		actionItems.POST("/:id/export", h.ExportActionItem)
+		actionItems.POST("/:id/sync", h.SyncActionItem)
+	}
+}
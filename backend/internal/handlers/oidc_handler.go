@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+
+	"educ-retro/internal/auth"
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCHandler struct {
+	userService          *services.UserService
+	provider             *auth.OIDCProvider
+	postLoginRedirectURL string
+}
+
+// NewOIDCHandler creates the single sign-on handler. When postLoginRedirectURL
+// is set, the callback redirects the browser there with the token, or the
+// error, in the URL fragment instead of answering with JSON.
+func NewOIDCHandler(userService *services.UserService, provider *auth.OIDCProvider, postLoginRedirectURL string) *OIDCHandler {
+	return &OIDCHandler{
+		userService:          userService,
+		provider:             provider,
+		postLoginRedirectURL: postLoginRedirectURL,
+	}
+}
+
+// Login godoc
+// @Summary Start single sign-on
+// @Description Redirect the browser to the OpenID Connect identity provider
+// @Tags Authentication
+// @Success 302 "Redirect to the identity provider"
+// @Failure 502 {object} map[string]string "Identity provider unavailable"
+// @Router /auth/oidc/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, err := h.provider.AuthCodeURL()
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback godoc
+// @Summary Single sign-on callback
+// @Description Exchange the authorization code, provision or link the user by email and return a JWT token
+// @Tags Authentication
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State returned by the identity provider"
+// @Success 200 {object} map[string]interface{} "Login successful"
+// @Failure 401 {object} map[string]string "Authentication failed"
+// @Router /auth/oidc/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if providerError := c.Query("error"); providerError != "" {
+		h.fail(c, providerError, c.Query("error_description"))
+		return
+	}
+
+	identity, err := h.provider.HandleCallback(c.Query("code"), c.Query("state"))
+	if err != nil {
+		h.fail(c, err.Error(), "")
+		return
+	}
+
+	user, token, err := h.userService.LoginWithOIDC(identity)
+	if err != nil {
+		h.fail(c, err.Error(), "")
+		return
+	}
+
+	if h.postLoginRedirectURL != "" {
+		c.Redirect(http.StatusFound, h.postLoginRedirectURL+"#token="+url.QueryEscape(token))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":  user,
+		"token": token,
+	})
+}
+
+// fail answers a failed sign-on with the error, redirecting the browser back
+// to the frontend with it in the URL fragment when there is one
+func (h *OIDCHandler) fail(c *gin.Context, message, description string) {
+	if h.postLoginRedirectURL != "" {
+		c.Redirect(http.StatusFound, h.postLoginRedirectURL+"#error="+url.QueryEscape(message))
+		return
+	}
+
+	response := gin.H{"error": message}
+	if description != "" {
+		response["description"] = description
+	}
+	c.JSON(http.StatusUnauthorized, response)
+}
+
+func (h *OIDCHandler) SetupRoutes(r *gin.RouterGroup) {
+	oidc := r.Group("/auth/oidc")
+	{
+		oidc.GET("/login", h.Login)
+		oidc.GET("/callback", h.Callback)
+	}
+}
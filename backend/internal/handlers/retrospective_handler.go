@@ -1,17 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"educ-retro/internal/export"
 	"educ-retro/internal/models"
 	"educ-retro/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/jung-kurt/gofpdf"
 )
 
 type RetrospectiveHandler struct {
@@ -55,8 +58,10 @@ func (h *RetrospectiveHandler) CreateRetrospective(c *gin.Context) {
 	retrospective, err := h.retrospectiveService.CreateRetrospective(userID.(uuid.UUID), &req)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "access denied" {
+		if err.Error() == "access denied" || err.Error() == "not a member of this team" {
 			status = http.StatusForbidden
+		} else if err.Error() == "scheduled_at must be in the future" {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -65,6 +70,105 @@ func (h *RetrospectiveHandler) CreateRetrospective(c *gin.Context) {
 	c.JSON(http.StatusCreated, retrospective)
 }
 
+// importMaxBytes limits the size of import files
+const importMaxBytes = 10 << 20
+
+// ImportRetrospective godoc
+// @Summary Import a retrospective
+// @Description Create a retrospective with its items, groups, vote counts, action items and discussion notes from a file, in one transaction. The body is a JSON document of the export (format=json) or a CSV spreadsheet with the columns of the CSV export (format=csv), of which only the content column is required. CSV imports need title and template. Assignees are matched to the team members by ID, name or email; those not found leave the action item unassigned, with a warning. Merged items are left out, with a warning. With dry_run=true the file is only validated.
+// @Tags Retrospectives
+// @Accept json,text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "File format, by default from the Content-Type" Enums(json, csv)
+// @Param title query string false "Title, replaces the one of the file"
+// @Param template query string false "Template, replaces the one of the file"
+// @Param team_id query string false "Team of the retrospective"
+// @Param date query string false "Date of a retrospective imported from CSV (YYYY-MM-DD)"
+// @Param dry_run query bool false "Only validate the file"
+// @Success 200 {object} models.RetrospectiveImportReport "Dry run report"
+// @Success 201 {object} models.RetrospectiveImportReport "Retrospective imported"
+// @Failure 400 {object} map[string]interface{} "Invalid file, with the report of its errors"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not a member of the team"
+// @Failure 413 {object} map[string]string "File too large"
+// @Router /retrospectives/import [post]
+func (h *RetrospectiveHandler) ImportRetrospective(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	options := models.RetrospectiveImportOptions{
+		Format:   c.Query("format"),
+		Title:    c.Query("title"),
+		Template: models.RetrospectiveTemplate(c.Query("template")),
+	}
+	if options.Format == "" {
+		options.Format = "json"
+		if strings.Contains(c.ContentType(), "csv") {
+			options.Format = "csv"
+		}
+	}
+	if teamID := c.Query("team_id"); teamID != "" {
+		id, err := uuid.Parse(teamID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team_id"})
+			return
+		}
+		options.TeamID = &id
+	}
+	if date := c.Query("date"); date != "" {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		options.Date = &parsed
+	}
+	if dryRun := c.Query("dry_run"); dryRun != "" {
+		var err error
+		options.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run"})
+			return
+		}
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, importMaxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "import file too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read import file"})
+		return
+	}
+
+	report, err := h.retrospectiveService.ImportRetrospective(userID.(uuid.UUID), data, options)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "not a member of this team" {
+			status = http.StatusForbidden
+		} else if err.Error() == "unsupported import format" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch {
+	case report.DryRun:
+		c.JSON(http.StatusOK, report)
+	case !report.Valid:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the import file has errors", "report": report})
+	default:
+		c.JSON(http.StatusCreated, report)
+	}
+}
+
 // GetUserRetrospectives godoc
 // @Summary Get user's retrospectives
 // @Description Get all retrospectives created by or participated in by the current user
@@ -91,6 +195,31 @@ func (h *RetrospectiveHandler) GetUserRetrospectives(c *gin.Context) {
 	c.JSON(http.StatusOK, retrospectives)
 }
 
+// GetRetrospectiveStats godoc
+// @Summary Get dashboard statistics
+// @Description Get counts by status, participation and action item progress for the retrospectives the user created, joined or that belong to one of their teams
+// @Tags Retrospectives
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.RetrospectiveStats "Retrospective statistics"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /retrospectives/stats [get]
+func (h *RetrospectiveHandler) GetRetrospectiveStats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	stats, err := h.retrospectiveService.GetRetrospectiveStats(userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 // GetRetrospective godoc
 // @Summary Get retrospective details
 // @Description Get detailed information about a specific retrospective
@@ -171,6 +300,8 @@ func (h *RetrospectiveHandler) UpdateRetrospective(c *gin.Context) {
 		status := http.StatusInternalServerError
 		if err.Error() == "access denied" {
 			status = http.StatusForbidden
+		} else if err.Error() == "scheduled_at must be in the future" || err.Error() == "only planned retrospectives can be scheduled" {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -223,16 +354,17 @@ func (h *RetrospectiveHandler) DeleteRetrospective(c *gin.Context) {
 
 // StartRetrospective godoc
 // @Summary Start retrospective
-// @Description Start a retrospective session
+// @Description Start a retrospective session and carry over the unfinished action items of the previous retrospective
 // @Tags Retrospectives
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Retrospective ID"
-// @Success 200 {object} map[string]string "Retrospective started successfully"
+// @Success 200 {object} map[string]interface{} "Retrospective started successfully"
 // @Failure 400 {object} map[string]string "Invalid retrospective ID"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Access denied"
+// @Failure 409 {object} map[string]string "Retrospective is closed"
 // @Router /retrospectives/{id}/start [post]
 func (h *RetrospectiveHandler) StartRetrospective(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -248,17 +380,20 @@ func (h *RetrospectiveHandler) StartRetrospective(c *gin.Context) {
 		return
 	}
 
-	err = h.retrospectiveService.StartRetrospective(retrospectiveID, userID.(uuid.UUID))
+	carried, err := h.retrospectiveService.StartRetrospective(retrospectiveID, userID.(uuid.UUID))
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "access denied" {
+		switch err.Error() {
+		case "access denied":
 			status = http.StatusForbidden
+		case "retrospective is closed":
+			status = http.StatusConflict
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Retrospective started successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Retrospective started successfully", "carried_action_items": carried})
 }
 
 // EndRetrospective godoc
@@ -298,6 +433,13 @@ func (h *RetrospectiveHandler) EndRetrospective(c *gin.Context) {
 		return
 	}
 
+	// Send real-time update via SSE
+	if h.realtimeService != nil {
+		h.realtimeService.BroadcastToRetrospective(retrospectiveID, "retrospective_ended", map[string]interface{}{
+			"retrospective_id": retrospectiveID,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Retrospective ended successfully"})
 }
 
@@ -402,7 +544,7 @@ func (h *RetrospectiveHandler) VoteItem(c *gin.Context) {
 
 	err = h.retrospectiveService.VoteItem(itemID, userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(itemChangeErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -462,7 +604,7 @@ func (h *RetrospectiveHandler) DeleteItem(c *gin.Context) {
 
 	err = h.retrospectiveService.DeleteItem(itemID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(itemChangeErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -476,6 +618,71 @@ func (h *RetrospectiveHandler) DeleteItem(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Item deleted successfully"})
 }
 
+// itemChangeErrorStatus maps item vote and delete errors to HTTP statuses
+func itemChangeErrorStatus(err error) int {
+	switch err.Error() {
+	case "item not found":
+		return http.StatusNotFound
+	case "item was merged into another item", "item has merged items, unmerge first":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// actionItemValidationStatus maps action item creation and update errors to HTTP statuses
+func actionItemValidationStatus(err error) int {
+	switch err.Error() {
+	case "retrospective not found":
+		return http.StatusNotFound
+	case "access denied":
+		return http.StatusForbidden
+	case "invalid item_id", "invalid assigned_to", "invalid due_date format",
+		"item does not belong to this retrospective",
+		"assignee must be a participant or team member of the retrospective":
+		return http.StatusBadRequest
+	default:
+		if strings.HasPrefix(err.Error(), "invalid status") {
+			return http.StatusBadRequest
+		}
+		return http.StatusInternalServerError
+	}
+}
+
+// GetAssignableUsers godoc
+// @Summary List assignable users
+// @Description List the users who can be assigned action items of the retrospective: its creator, its participants and its team members
+// @Tags Action Items
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Success 200 {array} models.AssignableUser "Assignable users"
+// @Failure 400 {object} map[string]string "Invalid retrospective ID"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Router /retrospectives/{id}/assignable-users [get]
+func (h *RetrospectiveHandler) GetAssignableUsers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	users, err := h.retrospectiveService.GetAssignableUsers(retrospectiveID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(actionItemValidationStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
 func (h *RetrospectiveHandler) AddActionItem(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -498,7 +705,7 @@ func (h *RetrospectiveHandler) AddActionItem(c *gin.Context) {
 
 	actionItem, err := h.retrospectiveService.AddActionItem(retrospectiveID, userID.(uuid.UUID), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(actionItemValidationStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -532,22 +739,88 @@ func (h *RetrospectiveHandler) UpdateActionItem(c *gin.Context) {
 		return
 	}
 
-	actionItem, err := h.retrospectiveService.UpdateActionItem(actionItemID, userID.(uuid.UUID), &req)
+	actionItem, events, err := h.retrospectiveService.UpdateActionItem(actionItemID, userID.(uuid.UUID), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(actionItemValidationStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	// Send real-time update via SSE
+	// Send real-time update via SSE, also to the retrospectives reviewing it
 	if h.realtimeService != nil {
-		h.realtimeService.BroadcastToRetrospective(actionItem.RetrospectiveID, "action_item_updated", map[string]interface{}{
+		carriedInto, _ := h.retrospectiveService.GetCarryoverRetrospectiveIDs(actionItem.ID)
+		h.realtimeService.BroadcastToRetrospectives(actionItem.RetrospectiveID, carriedInto, "action_item_updated", map[string]interface{}{
 			"action_item": actionItem,
+			"events":      events,
 		})
 	}
 
 	c.JSON(http.StatusOK, actionItem)
 }
 
+// SetCarryForward godoc
+// @Summary Choose whether to carry an action item forward
+// @Description Choose whether an unfinished action item carried into this retrospective is carried again to the next one
+// @Tags Action Items
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param actionItemId path string true "Action item ID"
+// @Param request body models.CarriedActionItemUpdateRequest true "Carry forward"
+// @Success 200 {object} map[string]interface{} "Carry forward updated"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /retrospectives/{id}/carried-action-items/{actionItemId} [put]
+func (h *RetrospectiveHandler) SetCarryForward(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid retrospective ID"})
+		return
+	}
+
+	actionItemID, err := uuid.Parse(c.Param("actionItemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	var req models.CarriedActionItemUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.retrospectiveService.SetCarryForward(retrospectiveID, actionItemID, userID.(uuid.UUID), *req.CarryForward)
+	if err != nil {
+		switch err.Error() {
+		case "access denied":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "retrospective not found", "action item is not carried over to this retrospective":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	// Send real-time update via SSE
+	if h.realtimeService != nil {
+		h.realtimeService.BroadcastToRetrospective(retrospectiveID, "carried_action_item_updated", map[string]interface{}{
+			"action_item_id": actionItemID,
+			"carry_forward":  *req.CarryForward,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"action_item_id": actionItemID, "carry_forward": *req.CarryForward})
+}
+
 func (h *RetrospectiveHandler) DeleteActionItem(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -760,6 +1033,246 @@ func (h *RetrospectiveHandler) DeleteGroup(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Group deleted successfully"})
 }
 
+// groupErrorStatus maps group editing errors to HTTP statuses
+func groupErrorStatus(err error) int {
+	switch {
+	case err.Error() == "group not found", err.Error() == "retrospective not found", err.Error() == "item is not in this group",
+		strings.HasPrefix(err.Error(), "item not found"):
+		return http.StatusNotFound
+	case err.Error() == "access denied":
+		return http.StatusForbidden
+	case err.Error() == "can only edit groups of active retrospectives":
+		return http.StatusConflict
+	case err.Error() == "name cannot be empty", err.Error() == "item_ids cannot be empty",
+		err.Error() == "item does not belong to this retrospective", err.Error() == "item was merged into another item",
+		strings.HasPrefix(err.Error(), "invalid item ID"), strings.HasPrefix(err.Error(), "min_similarity "):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// SuggestGroups godoc
+// @Summary Suggest groups of similar items
+// @Description Cluster the items of the retrospective that are in no group by the similarity of their contents (the words they share, in Portuguese or English) and propose groups, the largest first. Each suggestion has the name and item_ids of a group to create, so that it is accepted by posting it to /retrospectives/{id}/groups. Only the facilitators can ask for suggestions. Beyond 300 items, only the most voted are clustered.
+// @Tags Groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param min_similarity query number false "Least average similarity of the items of a group, from 0 to 1 (default 0.25)"
+// @Success 200 {object} map[string][]models.GroupSuggestion "Suggested groups"
+// @Failure 400 {object} map[string]string "Invalid min_similarity"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Router /retrospectives/{id}/group-suggestions [get]
+func (h *RetrospectiveHandler) SuggestGroups(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	minSimilarity := services.DefaultGroupSuggestionSimilarity
+	if value := c.Query("min_similarity"); value != "" {
+		if minSimilarity, err = strconv.ParseFloat(value, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_similarity"})
+			return
+		}
+	}
+
+	suggestions, err := h.retrospectiveService.SuggestGroups(retrospectiveID, userID.(uuid.UUID), minSimilarity)
+	if err != nil {
+		c.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
+
+// broadcastGroupUpdated sends the group with its items to the retrospective
+func (h *RetrospectiveHandler) broadcastGroupUpdated(group *models.RetrospectiveGroup) {
+	if h.realtimeService != nil {
+		h.realtimeService.BroadcastToRetrospective(group.RetrospectiveID, "group_updated", map[string]interface{}{
+			"group": group,
+		})
+	}
+}
+
+// UpdateGroup godoc
+// @Summary Update a group
+// @Description Rename a group or change its description. The creator of the group and the facilitators of the retrospective can, while it is active.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param groupId path string true "Group ID"
+// @Param request body models.GroupUpdateRequest true "Group changes"
+// @Success 200 {object} models.RetrospectiveGroup "Updated group with its item IDs"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Failure 409 {object} map[string]string "Retrospective is not active"
+// @Router /retrospectives/groups/{groupId} [put]
+func (h *RetrospectiveHandler) UpdateGroup(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	var req models.GroupUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := h.retrospectiveService.UpdateGroup(groupID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	h.broadcastGroupUpdated(group)
+
+	c.JSON(http.StatusOK, group)
+}
+
+// AddGroupItems godoc
+// @Summary Add items to a group
+// @Description Add items to a group. An item is in at most one group, so items already in another group are moved out of it; a group_updated event is sent for every group that changed.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param groupId path string true "Group ID"
+// @Param request body models.GroupItemsRequest true "Items to add"
+// @Success 200 {object} map[string]interface{} "The group and the groups the items left (moved_from)"
+// @Failure 400 {object} map[string]string "Invalid items"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Group or item not found"
+// @Failure 409 {object} map[string]string "Retrospective is not active"
+// @Router /retrospectives/groups/{groupId}/items [post]
+func (h *RetrospectiveHandler) AddGroupItems(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	var req models.GroupItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	groups, err := h.retrospectiveService.AddGroupItems(groupID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, group := range groups {
+		h.broadcastGroupUpdated(group)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"group": groups[0], "moved_from": groups[1:]})
+}
+
+// RemoveGroupItem godoc
+// @Summary Remove an item from a group
+// @Description Take an item out of a group; the item itself is kept
+// @Tags Groups
+// @Produce json
+// @Security BearerAuth
+// @Param groupId path string true "Group ID"
+// @Param itemId path string true "Item ID"
+// @Success 200 {object} models.RetrospectiveGroup "Updated group with its item IDs"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Group not found or item not in it"
+// @Failure 409 {object} map[string]string "Retrospective is not active"
+// @Router /retrospectives/groups/{groupId}/items/{itemId} [delete]
+func (h *RetrospectiveHandler) RemoveGroupItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	group, err := h.retrospectiveService.RemoveGroupItem(groupID, itemID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(groupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	h.broadcastGroupUpdated(group)
+
+	c.JSON(http.StatusOK, group)
+}
+
+// mergeErrorStatus maps item merge and move errors to HTTP statuses
+func mergeErrorStatus(err error) int {
+	switch err.Error() {
+	case "merge not found", "item not found":
+		return http.StatusNotFound
+	case "access denied":
+		return http.StatusForbidden
+	case "invalid vote_mode", "cannot merge an item into itself", "items must belong to the same retrospective",
+		"items must belong to the same category", "target_category is required to merge items of different categories",
+		"invalid target_category", "invalid category":
+		return http.StatusBadRequest
+	case "item was merged into another item", "undo the later merges into this item first",
+		"can only merge items in active retrospectives", "can only unmerge items in active retrospectives",
+		"can only move items in active retrospectives":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// MergeItems godoc
+// @Summary Merge two items
+// @Description Merge the source item into the target item: the target gets both contents and the votes of the vote mode (sum, the default; max; or reset), and the source is hidden, keeping its content, author and votes. Items of different categories are merged into target_category, a category of the template; by default the merged item stays in the category of the target. The merge can be undone with unmerge-items.
+// @Tags Items
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param request body models.MergeItemsRequest true "Items to merge"
+// @Success 200 {object} map[string]interface{} "Merged item and the merge"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Item not found"
+// @Failure 409 {object} map[string]string "Item already merged or retrospective not active"
+// @Router /retrospectives/{id}/merge-items [post]
 func (h *RetrospectiveHandler) MergeItems(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -792,9 +1305,9 @@ func (h *RetrospectiveHandler) MergeItems(c *gin.Context) {
 		return
 	}
 
-	mergedItem, err := h.retrospectiveService.MergeItems(sourceItemID, targetItemID, userID.(uuid.UUID))
+	mergedItem, merge, err := h.retrospectiveService.MergeItems(sourceItemID, targetItemID, userID.(uuid.UUID), req.VoteMode, req.TargetCategory)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(mergeErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -804,10 +1317,352 @@ func (h *RetrospectiveHandler) MergeItems(c *gin.Context) {
 			"merged_item":    mergedItem,
 			"source_item_id": sourceItemID,
 			"target_item_id": targetItemID,
+			"merge":          merge,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Items merged successfully", "merged_item": mergedItem, "merge": merge})
+}
+
+// UnmergeItems godoc
+// @Summary Undo a merge of items
+// @Description Undo a merge: the target item gets back its content and its votes before the merge, plus the votes it got since, and its category unless it was moved since, and the source item is shown again. Only the last merge into an item can be undone; whoever merged the items and the facilitators can, while the retrospective is active.
+// @Tags Items
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param request body models.UnmergeItemsRequest true "Merge to undo"
+// @Success 200 {object} map[string]interface{} "Restored target and source items"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Merge not found"
+// @Failure 409 {object} map[string]string "Later merges or retrospective not active"
+// @Router /retrospectives/{id}/unmerge-items [post]
+func (h *RetrospectiveHandler) UnmergeItems(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	var req models.UnmergeItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mergeID, err := uuid.Parse(req.MergeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid merge ID"})
+		return
+	}
+
+	targetItem, sourceItem, err := h.retrospectiveService.UnmergeItems(retrospectiveID, mergeID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(mergeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send real-time update via SSE
+	if h.realtimeService != nil {
+		h.realtimeService.BroadcastToRetrospective(retrospectiveID, "items_unmerged", map[string]interface{}{
+			"merge_id":    mergeID,
+			"target_item": targetItem,
+			"source_item": sourceItem,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Merge undone successfully", "target_item": targetItem, "source_item": sourceItem})
+}
+
+// MoveItem godoc
+// @Summary Move an item to another category
+// @Description Move an item filed in the wrong category to another category of the template, by ID or name. Its author and the facilitators can, while the retrospective is active.
+// @Tags Items
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param itemId path string true "Item ID"
+// @Param request body models.MoveItemRequest true "Category"
+// @Success 200 {object} models.RetrospectiveItem "Moved item"
+// @Failure 400 {object} map[string]string "Invalid category"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Item not found"
+// @Failure 409 {object} map[string]string "Item merged or retrospective not active"
+// @Router /retrospectives/items/{itemId}/move [post]
+func (h *RetrospectiveHandler) MoveItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req models.MoveItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, fromCategory, err := h.retrospectiveService.MoveItem(itemID, userID.(uuid.UUID), req.Category)
+	if err != nil {
+		c.JSON(mergeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send real-time update via SSE
+	if h.realtimeService != nil && item.Category != fromCategory {
+		h.realtimeService.BroadcastToRetrospective(item.RetrospectiveID, "item_moved", map[string]interface{}{
+			"item":          item,
+			"from_category": fromCategory,
+		})
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// noteErrorStatus maps discussion note errors to HTTP statuses
+func noteErrorStatus(err error) int {
+	switch err.Error() {
+	case "item not found", "group not found":
+		return http.StatusNotFound
+	case "invalid version":
+		return http.StatusBadRequest
+	case "item was merged into another item", "can only edit notes of active retrospectives", "note was changed by someone else":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// saveDiscussionNote saves the note of the item or group of the path
+// parameter and sends it to the retrospective
+func (h *RetrospectiveHandler) saveDiscussionNote(c *gin.Context, subject models.NoteSubject, param string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	subjectID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + string(subject) + " ID"})
+		return
+	}
+
+	var req models.DiscussionNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.retrospectiveService.SaveDiscussionNote(subject, subjectID, userID.(uuid.UUID), &req)
+	if err != nil {
+		// On a conflict the current note comes along, to merge the changes into
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error(), "note": note})
+		return
+	}
+
+	// Send real-time update via SSE
+	if h.realtimeService != nil {
+		h.realtimeService.BroadcastToRetrospective(note.RetrospectiveID, "note_updated", map[string]interface{}{
+			"note": note,
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Items merged successfully", "merged_item": mergedItem})
+	c.JSON(http.StatusOK, note)
+}
+
+// SaveItemNote godoc
+// @Summary Save the discussion note of an item
+// @Description Save what was said while discussing the item and its outcome, while the retrospective is active. Everyone edits the same note: version is the version that was edited (0 for a new note), and if someone else saved the note since, it is not saved and the current note comes with a 409.
+// @Tags Notes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param itemId path string true "Item ID"
+// @Param request body models.DiscussionNoteRequest true "Note"
+// @Success 200 {object} models.DiscussionNote "Saved note"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Item not found"
+// @Failure 409 {object} map[string]interface{} "Note changed by someone else, or retrospective not active"
+// @Router /retrospectives/items/{itemId}/note [put]
+func (h *RetrospectiveHandler) SaveItemNote(c *gin.Context) {
+	h.saveDiscussionNote(c, models.NoteSubjectItem, "itemId")
+}
+
+// SaveGroupNote godoc
+// @Summary Save the discussion note of a group
+// @Description Save what was said while discussing the group and its outcome, while the retrospective is active. Everyone edits the same note: version is the version that was edited (0 for a new note), and if someone else saved the note since, it is not saved and the current note comes with a 409.
+// @Tags Notes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param groupId path string true "Group ID"
+// @Param request body models.DiscussionNoteRequest true "Note"
+// @Success 200 {object} models.DiscussionNote "Saved note"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Group not found"
+// @Failure 409 {object} map[string]interface{} "Note changed by someone else, or retrospective not active"
+// @Router /retrospectives/groups/{groupId}/note [put]
+func (h *RetrospectiveHandler) SaveGroupNote(c *gin.Context) {
+	h.saveDiscussionNote(c, models.NoteSubjectGroup, "groupId")
+}
+
+func topicErrorStatus(err error) int {
+	switch err.Error() {
+	case "retrospective not found", "topic is not in the discussion queue":
+		return http.StatusNotFound
+	case "access denied":
+		return http.StatusForbidden
+	case "invalid topic type":
+		return http.StatusBadRequest
+	case "can only change the topic of active retrospectives":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetDiscussionQueue godoc
+// @Summary Get the discussion queue
+// @Description Get the groups and the items in no group, the most voted first (a group counts its votes and those of its items), and the topic being discussed with when its discussion started.
+// @Tags Discussion
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Success 200 {object} models.DiscussionQueue "Discussion queue"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Router /retrospectives/{id}/discussion-queue [get]
+func (h *RetrospectiveHandler) GetDiscussionQueue(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	queue, err := h.retrospectiveService.GetDiscussionQueue(retrospectiveID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(topicErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// changeTopic changes the topic being discussed in the retrospective of the
+// path with change, and sends the new topic to the retrospective
+func (h *RetrospectiveHandler) changeTopic(c *gin.Context, change func(retrospectiveID, userID uuid.UUID) (*models.DiscussionQueue, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	queue, err := change(retrospectiveID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(topicErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send real-time update via SSE
+	if h.realtimeService != nil {
+		h.realtimeService.BroadcastToRetrospective(retrospectiveID, "topic_changed", map[string]interface{}{
+			"topic":      queue.Current,
+			"started_at": queue.CurrentStartedAt,
+			"changed_by": userID,
+		})
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// SetCurrentTopic godoc
+// @Summary Set the topic being discussed
+// @Description Start the discussion of an item or group of the discussion queue. Every participant is sent a topic_changed event. Only the facilitators can, while the retrospective is active.
+// @Tags Discussion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param request body models.CurrentTopicRequest true "Topic type (item or group) and ID"
+// @Success 200 {object} models.DiscussionQueue "Discussion queue"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Retrospective not found or topic not in the queue"
+// @Failure 409 {object} map[string]string "Retrospective not active"
+// @Router /retrospectives/{id}/current-topic [put]
+func (h *RetrospectiveHandler) SetCurrentTopic(c *gin.Context) {
+	var req models.CurrentTopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topicID, err := uuid.Parse(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic ID"})
+		return
+	}
+
+	h.changeTopic(c, func(retrospectiveID, userID uuid.UUID) (*models.DiscussionQueue, error) {
+		return h.retrospectiveService.SetCurrentTopic(retrospectiveID, userID, req.Type, topicID)
+	})
+}
+
+// NextTopic godoc
+// @Summary Advance to the next topic
+// @Description Move the discussion to the topic after the current one in the discussion queue, or to the first one when no topic is being discussed; after the last topic, none is. Every participant is sent a topic_changed event. Only the facilitators can, while the retrospective is active.
+// @Tags Discussion
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Success 200 {object} models.DiscussionQueue "Discussion queue"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Failure 409 {object} map[string]string "Retrospective not active"
+// @Router /retrospectives/{id}/current-topic/next [post]
+func (h *RetrospectiveHandler) NextTopic(c *gin.Context) {
+	h.changeTopic(c, h.retrospectiveService.NextTopic)
+}
+
+// ClearCurrentTopic godoc
+// @Summary Clear the topic being discussed
+// @Description End the discussion of the current topic without starting another one. Every participant is sent a topic_changed event with no topic. Only the facilitators can, while the retrospective is active.
+// @Tags Discussion
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Success 200 {object} models.DiscussionQueue "Discussion queue"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Failure 409 {object} map[string]string "Retrospective not active"
+// @Router /retrospectives/{id}/current-topic [delete]
+func (h *RetrospectiveHandler) ClearCurrentTopic(c *gin.Context) {
+	h.changeTopic(c, h.retrospectiveService.ClearCurrentTopic)
 }
 
 func (h *RetrospectiveHandler) SetupRoutes(r *gin.RouterGroup) {
@@ -815,14 +1670,22 @@ func (h *RetrospectiveHandler) SetupRoutes(r *gin.RouterGroup) {
 	retrospectives.Use(authMiddleware)
 	{
 		retrospectives.POST("", h.CreateRetrospective)
+		retrospectives.POST("/import", h.ImportRetrospective)
 		retrospectives.GET("", h.GetUserRetrospectives)
+		retrospectives.GET("/stats", h.GetRetrospectiveStats)
 		// Action Items routes (must be before /:id routes to avoid conflicts)
 		retrospectives.PUT("/action-items/:actionItemId", h.UpdateActionItem)
 		retrospectives.DELETE("/action-items/:actionItemId", h.DeleteActionItem)
 		retrospectives.DELETE("/items/:itemId", h.DeleteItem)
 		retrospectives.POST("/items/:itemId/vote", h.VoteItem)
+		retrospectives.POST("/items/:itemId/move", h.MoveItem)
+		retrospectives.PUT("/items/:itemId/note", h.SaveItemNote)
 		retrospectives.POST("/groups/:groupId/vote", h.VoteGroup)
 		retrospectives.DELETE("/groups/:groupId", h.DeleteGroup)
+		retrospectives.PUT("/groups/:groupId", h.UpdateGroup)
+		retrospectives.POST("/groups/:groupId/items", h.AddGroupItems)
+		retrospectives.DELETE("/groups/:groupId/items/:itemId", h.RemoveGroupItem)
+		retrospectives.PUT("/groups/:groupId/note", h.SaveGroupNote)
 		// Retrospective-specific routes
 		retrospectives.GET("/:id", h.GetRetrospective)
 		retrospectives.PUT("/:id", h.UpdateRetrospective)
@@ -832,10 +1695,18 @@ func (h *RetrospectiveHandler) SetupRoutes(r *gin.RouterGroup) {
 		retrospectives.POST("/:id/reopen", h.ReopenRetrospective)
 		retrospectives.POST("/:id/items", h.AddItem)
 		retrospectives.POST("/:id/action-items", h.AddActionItem)
+		retrospectives.GET("/:id/assignable-users", h.GetAssignableUsers)
+		retrospectives.PUT("/:id/carried-action-items/:actionItemId", h.SetCarryForward)
 		retrospectives.POST("/:id/join", h.JoinRetrospective)
 		retrospectives.GET("/:id/participants", h.GetParticipants)
 		retrospectives.POST("/:id/groups", h.CreateGroup)
+		retrospectives.GET("/:id/group-suggestions", h.SuggestGroups)
+		retrospectives.GET("/:id/discussion-queue", h.GetDiscussionQueue)
+		retrospectives.PUT("/:id/current-topic", h.SetCurrentTopic)
+		retrospectives.DELETE("/:id/current-topic", h.ClearCurrentTopic)
+		retrospectives.POST("/:id/current-topic/next", h.NextTopic)
 		retrospectives.POST("/:id/merge-items", h.MergeItems)
+		retrospectives.POST("/:id/unmerge-items", h.UnmergeItems)
 		retrospectives.PUT("/:id/blur", h.ToggleBlur)
 		retrospectives.GET("/:id/export", h.ExportRetrospective)
 	}
@@ -888,17 +1759,18 @@ func (h *RetrospectiveHandler) ToggleBlur(c *gin.Context) {
 }
 
 // ExportRetrospective godoc
-// @Summary Export retrospective to PDF
-// @Description Export a retrospective to PDF format (only accessible by the creator)
+// @Summary Export retrospective
+// @Description Export a retrospective as PDF, Markdown (to paste into wikis), CSV (items and action items, for spreadsheets) or a complete JSON document. Who can export is set by the export policy of the retrospective; the creator always can.
 // @Tags Retrospectives
 // @Accept json
-// @Produce application/pdf
+// @Produce application/pdf,text/markdown,text/csv,application/json
 // @Security BearerAuth
 // @Param id path string true "Retrospective ID"
-// @Success 200 {file} binary "PDF file"
-// @Failure 400 {object} map[string]string "Invalid retrospective ID"
+// @Param format query string false "Format" Enums(pdf, markdown, csv, json) default(pdf)
+// @Success 200 {file} binary "Exported file"
+// @Failure 400 {object} map[string]string "Invalid retrospective ID or unsupported format"
 // @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 403 {object} map[string]string "Access denied - only creator can export"
+// @Failure 403 {object} map[string]string "Export not allowed by the export policy"
 // @Failure 404 {object} map[string]string "Retrospective not found"
 // @Router /retrospectives/{id}/export [get]
 func (h *RetrospectiveHandler) ExportRetrospective(c *gin.Context) {
@@ -915,11 +1787,16 @@ func (h *RetrospectiveHandler) ExportRetrospective(c *gin.Context) {
 		return
 	}
 
-	// Get retrospective with full details
-	retrospective, err := h.retrospectiveService.GetRetrospectiveWithDetails(retrospectiveID, userID.(uuid.UUID))
+	exporter, ok := export.Lookup(c.DefaultQuery("format", "pdf"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported export format, use one of: " + strings.Join(export.Formats(), ", ")})
+		return
+	}
+
+	report, err := h.retrospectiveService.GetExportReport(retrospectiveID, userID.(uuid.UUID))
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "access denied" {
+		if err.Error() == "export not allowed" {
 			status = http.StatusForbidden
 		} else if err.Error() == "retrospective not found" {
 			status = http.StatusNotFound
@@ -928,114 +1805,17 @@ func (h *RetrospectiveHandler) ExportRetrospective(c *gin.Context) {
 		return
 	}
 
-	// Check if user is the creator
-	if retrospective.CreatedBy != userID.(uuid.UUID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "only the retrospective creator can export"})
-		return
-	}
-
-	// Generate PDF content
-	pdfContent, err := h.generateRetrospectivePDF(retrospective)
+	retrospective := report.Retrospective
+	content, err := exporter.Export(report)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate PDF"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export retrospective"})
 		return
 	}
 
-	// Set headers for PDF download
-	filename := fmt.Sprintf("retrospective_%s_%s.pdf", retrospective.Title, time.Now().Format("2006-01-02"))
-	c.Header("Content-Type", "application/pdf")
+	// Set headers for download
+	filename := fmt.Sprintf("retrospective_%s_%s.%s", retrospective.Title, time.Now().Format("2006-01-02"), exporter.Extension())
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
-	c.Header("Content-Length", fmt.Sprintf("%d", len(pdfContent)))
-
-	c.Data(http.StatusOK, "application/pdf", pdfContent)
-}
-
-// generateRetrospectivePDF creates a PDF representation of the retrospective
-func (h *RetrospectiveHandler) generateRetrospectivePDF(retrospective *models.RetrospectiveWithDetails) ([]byte, error) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
-
-	// Title
-	pdf.Cell(0, 10, fmt.Sprintf("RETROSPECTIVA: %s", retrospective.Title))
-	pdf.Ln(15)
-
-	// Basic info
-	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(0, 8, fmt.Sprintf("Status: %s", retrospective.Status))
-	pdf.Ln(8)
-	pdf.Cell(0, 8, fmt.Sprintf("Template: %s", retrospective.Template))
-	pdf.Ln(8)
-
-	if retrospective.EndedAt != nil {
-		pdf.Cell(0, 8, fmt.Sprintf("Data de Termino: %s", retrospective.EndedAt.Format("02/01/2006 15:04")))
-		pdf.Ln(8)
-	}
-
-	pdf.Ln(5)
-
-	// Groups
-	if len(retrospective.Groups) > 0 {
-		pdf.SetFont("Arial", "B", 14)
-		pdf.Cell(0, 10, "GRUPOS")
-		pdf.Ln(10)
-
-		pdf.SetFont("Arial", "", 10)
-		for _, group := range retrospective.Groups {
-			pdf.Cell(0, 6, fmt.Sprintf("- %s (%d votos)", group.Name, group.Votes))
-			pdf.Ln(6)
-		}
-		pdf.Ln(5)
-	}
-
-	// Items
-	if len(retrospective.Items) > 0 {
-		pdf.SetFont("Arial", "B", 14)
-		pdf.Cell(0, 10, "ITENS")
-		pdf.Ln(10)
-
-		pdf.SetFont("Arial", "", 10)
-		for _, item := range retrospective.Items {
-			// Format like in the attachment: - [category] content (votes votos)
-			content := fmt.Sprintf("- [%s] %s (%d votos)", item.Category, item.Content, item.Votes)
-			pdf.Cell(0, 6, content)
-			pdf.Ln(6)
-		}
-		pdf.Ln(5)
-	}
-
-	// Action Items
-	if len(retrospective.ActionItems) > 0 {
-		pdf.SetFont("Arial", "B", 14)
-		pdf.Cell(0, 10, "ITENS DE ACAO")
-		pdf.Ln(10)
-
-		pdf.SetFont("Arial", "", 10)
-		for _, actionItem := range retrospective.ActionItems {
-			status := "Pendente"
-			if actionItem.Status == "done" {
-				status = "Concluido"
-			} else if actionItem.Status == "in_progress" {
-				status = "Em Progresso"
-			}
-
-			// Format like in the attachment: - Title - Status
-			pdf.Cell(0, 6, fmt.Sprintf("- %s - %s", actionItem.Title, status))
-			pdf.Ln(6)
-		}
-		pdf.Ln(5)
-	}
-
-	// Footer
-	pdf.SetFont("Arial", "", 8)
-	pdf.Cell(0, 6, fmt.Sprintf("Relatorio gerado em: %s", time.Now().Format("02/01/2006 15:04")))
-
-	// Generate PDF bytes
-	var buf strings.Builder
-	err := pdf.Output(&buf)
-	if err != nil {
-		return nil, err
-	}
+	c.Header("Content-Length", fmt.Sprintf("%d", len(content)))
 
-	return []byte(buf.String()), nil
+	c.Data(http.StatusOK, exporter.ContentType(), content)
 }
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/repositories"
+	"educ-retro/internal/services"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrospectiveHandler_StartRetrospective(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		status        models.RetrospectiveStatus
+		expectedCode  int
+		expectedError string
+	}{
+		{name: "already active", status: models.RetroStatusActive, expectedCode: http.StatusOK},
+		{name: "closed", status: models.RetroStatusClosed, expectedCode: http.StatusConflict, expectedError: "retrospective is closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			retrospectiveID := uuid.New()
+			userID := uuid.New()
+			now := time.Now()
+
+			mock.ExpectQuery(`SELECT .* FROM retrospectives WHERE id = \$1`).
+				WithArgs(retrospectiveID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "title", "description", "template", "status", "scheduled_at", "started_at", "ended_at", "created_by", "created_at", "updated_at"}).
+					AddRow(retrospectiveID, uuid.New(), "Sprint 12", nil, models.TemplateStartStopContinue, tt.status, nil, now, nil, userID, now, now))
+			if tt.status != models.RetroStatusActive {
+				// Only planned retrospectives are moved to active
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE retrospectives\s+SET status = 'active'.*WHERE id = \$1 AND status = 'planned'`).
+					WithArgs(retrospectiveID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			}
+
+			service := services.NewRetrospectiveService(repositories.NewRetrospectiveRepository(db), nil)
+			handler := NewRetrospectiveHandler(service, nil)
+			router := gin.New()
+			router.POST("/retrospectives/:id/start", func(c *gin.Context) {
+				c.Set("user_id", userID)
+				handler.StartRetrospective(c)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/retrospectives/"+retrospectiveID.String()+"/start", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, response["error"])
+			} else {
+				assert.Equal(t, float64(0), response["carried_action_items"])
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ScheduleHandler struct {
+	scheduleService *services.ScheduleService
+}
+
+func NewScheduleHandler(scheduleService *services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// scheduleErrorStatus maps schedule errors to HTTP statuses
+func scheduleErrorStatus(err error) int {
+	switch {
+	case err.Error() == "schedule not found":
+		return http.StatusNotFound
+	case err.Error() == "access denied":
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid "), strings.HasSuffix(err.Error(), " is required"),
+		strings.HasSuffix(err.Error(), " must be in the future"), strings.HasPrefix(err.Error(), "interval_weeks "):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseTeamScheduleIDs reads the team and schedule IDs from the path
+func parseTeamScheduleIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	scheduleID, err := uuid.Parse(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return teamID, scheduleID, true
+}
+
+// ListSchedules godoc
+// @Summary List recurring retrospectives
+// @Description List the recurring retrospective schedules of a team
+// @Tags Schedules
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {array} models.RetrospectiveSchedule "Schedules"
+// @Failure 400 {object} map[string]string "Invalid team ID"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Router /teams/{id}/schedules [get]
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	schedules, err := h.scheduleService.ListSchedules(teamID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(scheduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// CreateSchedule godoc
+// @Summary Create a recurring retrospective
+// @Description Create a retrospective for the team every interval_weeks weeks, starting at starts_at (e.g. every second Friday at 14:00). Each retrospective is created ahead of time and starts on its own at its scheduled time.
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body models.RetrospectiveScheduleCreateRequest true "Schedule"
+// @Success 201 {object} models.RetrospectiveSchedule "Schedule created"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Router /teams/{id}/schedules [post]
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req models.RetrospectiveScheduleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.scheduleService.CreateSchedule(teamID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(scheduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateSchedule godoc
+// @Summary Update a recurring retrospective
+// @Description Change the title, template, interval, next run or active flag of a schedule
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param scheduleId path string true "Schedule ID"
+// @Param request body models.RetrospectiveScheduleUpdateRequest true "Changes"
+// @Success 200 {object} models.RetrospectiveSchedule "Schedule updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Router /teams/{id}/schedules/{scheduleId} [put]
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, scheduleID, ok := parseTeamScheduleIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.RetrospectiveScheduleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.scheduleService.UpdateSchedule(teamID, scheduleID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(scheduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a recurring retrospective
+// @Description Stop the recurrence. Retrospectives already created are kept.
+// @Tags Schedules
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param scheduleId path string true "Schedule ID"
+// @Success 200 {object} map[string]string "Schedule deleted"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Schedule not found"
+// @Router /teams/{id}/schedules/{scheduleId} [delete]
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, scheduleID, ok := parseTeamScheduleIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.scheduleService.DeleteSchedule(teamID, scheduleID, userID.(uuid.UUID)); err != nil {
+		c.JSON(scheduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted successfully"})
+}
+
+func (h *ScheduleHandler) SetupRoutes(r *gin.RouterGroup) {
+	schedules := r.Group("/teams/:id/schedules")
+	schedules.Use(authMiddleware)
+	{
+		schedules.GET("", h.ListSchedules)
+		schedules.POST("", h.CreateSchedule)
+		schedules.PUT("/:scheduleId", h.UpdateSchedule)
+		schedules.DELETE("/:scheduleId", h.DeleteSchedule)
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"educ-retro/internal/export"
+	"educ-retro/internal/models"
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ShareHandler struct {
+	shareService *services.ShareService
+}
+
+func NewShareHandler(shareService *services.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+// shareErrorStatus maps sharing errors to HTTP statuses
+func shareErrorStatus(err error) int {
+	switch {
+	case err.Error() == "retrospective not found", err.Error() == "share link not found":
+		return http.StatusNotFound
+	case err.Error() == "access denied", err.Error() == "export not allowed":
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid "), strings.HasPrefix(err.Error(), "expires_in_days "):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetSharing godoc
+// @Summary Get the sharing settings of a retrospective
+// @Description Get the export policy of the retrospective, whether the user can export it and manage the policy, and the public summary links that have not expired (only to users who can export)
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Success 200 {object} models.RetrospectiveSharing "Sharing settings"
+// @Failure 400 {object} map[string]string "Invalid retrospective ID"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Router /retrospectives/{id}/sharing [get]
+func (h *ShareHandler) GetSharing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	sharing, err := h.shareService.GetSharing(retrospectiveID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sharing)
+}
+
+// UpdateExportPolicy godoc
+// @Summary Set who can export a retrospective
+// @Description Set the export policy: creator, facilitators (the creator and the owners of the team), participants (also those who joined the retrospective) or team (also every member of the team). Only the facilitators can change it.
+// @Tags Sharing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param request body models.ExportPolicyUpdateRequest true "Export policy"
+// @Success 200 {object} map[string]string "Export policy updated"
+// @Failure 400 {object} map[string]string "Invalid export policy"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Router /retrospectives/{id}/export-policy [put]
+func (h *ShareHandler) UpdateExportPolicy(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	var req models.ExportPolicyUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.shareService.SetExportPolicy(retrospectiveID, userID.(uuid.UUID), req.ExportPolicy); err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Export policy updated successfully", "export_policy": req.ExportPolicy})
+}
+
+// CreateShareLink godoc
+// @Summary Create a public summary link
+// @Description Create a read-only link to the summary of the retrospective that anyone can open without logging in, until it expires (7 days by default, at most 90). Whoever the export policy allows to export can create links.
+// @Tags Sharing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param request body models.ShareLinkCreateRequest false "Expiry"
+// @Success 201 {object} models.ShareLink "Link created"
+// @Failure 400 {object} map[string]string "Invalid expiry"
+// @Failure 403 {object} map[string]string "Export not allowed"
+// @Failure 404 {object} map[string]string "Retrospective not found"
+// @Router /retrospectives/{id}/share-links [post]
+func (h *ShareHandler) CreateShareLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	var req models.ShareLinkCreateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	link, err := h.shareService.CreateShareLink(retrospectiveID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// DeleteShareLink godoc
+// @Summary Revoke a public summary link
+// @Description Revoke a link; its creator and the facilitators of the retrospective can
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Retrospective ID"
+// @Param linkId path string true "Share link ID"
+// @Success 200 {object} map[string]string "Link revoked"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Share link not found"
+// @Router /retrospectives/{id}/share-links/{linkId} [delete]
+func (h *ShareHandler) DeleteShareLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	retrospectiveID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retrospective ID"})
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share link ID"})
+		return
+	}
+
+	if err := h.shareService.DeleteShareLink(retrospectiveID, linkID, userID.(uuid.UUID)); err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Share link revoked successfully"})
+}
+
+// GetPublicSummary godoc
+// @Summary Read a shared retrospective summary
+// @Description Read the summary of the retrospective of a public link, without logging in. By default the summary is JSON; format=pdf, markdown or csv downloads it as that export.
+// @Tags Sharing
+// @Produce json,application/pdf,text/markdown,text/csv
+// @Param token path string true "Share link token"
+// @Param format query string false "Format" Enums(json, pdf, markdown, csv)
+// @Success 200 {object} map[string]interface{} "Summary and link expiry"
+// @Failure 400 {object} map[string]string "Unsupported format"
+// @Failure 404 {object} map[string]string "Link not found or expired"
+// @Router /public/summaries/{token} [get]
+func (h *ShareHandler) GetPublicSummary(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+
+	// The JSON export has user IDs; the public JSON is the summary instead
+	var exporter export.Exporter
+	if !strings.EqualFold(format, "json") {
+		var ok bool
+		if exporter, ok = export.Lookup(format); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported format, use one of: json, csv, markdown, pdf"})
+			return
+		}
+	}
+
+	report, link, err := h.shareService.GetSharedReport(c.Param("token"))
+	if err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if exporter == nil {
+		c.JSON(http.StatusOK, gin.H{"summary": export.NewSummary(report), "expires_at": link.ExpiresAt})
+		return
+	}
+
+	content, err := exporter.Export(report)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export retrospective"})
+		return
+	}
+
+	filename := fmt.Sprintf("retrospective_%s.%s", report.Retrospective.Title, exporter.Extension())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Data(http.StatusOK, exporter.ContentType(), content)
+}
+
+func (h *ShareHandler) SetupRoutes(r *gin.RouterGroup) {
+	// Public: anyone with the token reads the summary
+	r.GET("/public/summaries/:token", h.GetPublicSummary)
+
+	retrospectives := r.Group("/retrospectives")
+	retrospectives.Use(authMiddleware)
+	{
+		retrospectives.GET("/:id/sharing", h.GetSharing)
+		retrospectives.PUT("/:id/export-policy", h.UpdateExportPolicy)
+		retrospectives.POST("/:id/share-links", h.CreateShareLink)
+		retrospectives.DELETE("/:id/share-links/:linkId", h.DeleteShareLink)
+	}
+}
@@ -30,7 +30,11 @@ func (h *SSEHandler) HandleSSE(c *gin.Context) {
 	}
 
 	// Validate token
-	claims, err := auth.ValidateToken(token)
+	claims, _, err := auth.Authenticate(token)
+	if err == auth.ErrAccountSuspended {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 		return
@@ -89,6 +93,12 @@ func (h *SSEHandler) HandleSSE(c *gin.Context) {
 			// Client disconnected
 			return
 		case <-ticker.C:
+			// Accounts suspended since the connection was opened stop
+			// receiving events
+			if _, _, err := auth.Authenticate(token); err != nil {
+				return
+			}
+
 			// Send keepalive
 			c.SSEvent("ping", map[string]interface{}{
 				"timestamp": time.Now().Unix(),
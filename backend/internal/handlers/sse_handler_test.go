@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"educ-retro/internal/auth"
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEHandler_SuspendedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer auth.SetUserStatusLookup(nil)
+
+	suspendedID := uuid.New()
+	auth.SetUserStatusLookup(func(id uuid.UUID) (string, bool, error) {
+		return "user", id == suspendedID, nil
+	})
+
+	handler := NewSSEHandler(services.NewRealtimeService())
+	router := gin.New()
+	router.GET("/events", handler.HandleSSE)
+
+	token, err := auth.GenerateToken(suspendedID, "ana@example.com", "Ana")
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/events?token="+token+"&retrospective_id="+uuid.New().String(), nil)
+	router.ServeHTTP(w, req)
+
+	// Refused before the event stream starts
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotEqual(t, "text/event-stream", w.Header().Get("Content-Type"))
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "account suspended", response["error"])
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"educ-retro/internal/models"
 	"educ-retro/internal/services"
@@ -62,6 +65,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 // @Success 200 {object} map[string]interface{} "Login successful"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 401 {object} map[string]string "Invalid credentials"
+// @Failure 403 {object} map[string]string "Account suspended"
 // @Router /auth/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req models.UserLoginRequest
@@ -72,6 +76,10 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	user, token, err := h.userService.Login(&req)
 	if err != nil {
+		if err.Error() == "account suspended" {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -148,6 +156,185 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// DeleteAccount godoc
+// @Summary Delete user account
+// @Description Permanently delete the current user's account. With mode=anonymize (default) authored items are kept as anonymous and vote counts are kept; with mode=purge authored items and created action items are deleted and the user's votes are removed from the counts. Retrospectives and groups created by the user are kept without an owner in both modes.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param mode query string false "Deletion mode" Enums(anonymize, purge)
+// @Success 204 "Account deleted"
+// @Failure 400 {object} map[string]string "Invalid deletion mode"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/profile [delete]
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	mode := models.AccountDeletionMode(c.DefaultQuery("mode", string(models.AccountDeletionAnonymize)))
+
+	err := h.userService.DeleteAccount(userID.(uuid.UUID), mode)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "invalid deletion mode") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExportData godoc
+// @Summary Export personal data
+// @Description Download every record stored about the current user as JSON
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UserDataExport "Personal data"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/profile/export [get]
+func (h *UserHandler) ExportData(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	export, err := h.userService.ExportData(userID.(uuid.UUID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("educ-retro-data_%s.json", export.ExportedAt.Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.JSON(http.StatusOK, export)
+}
+
+// GetAnalytics godoc
+// @Summary Get user analytics
+// @Description Get retrospectives participated in, items authored per category, votes cast and received, action items assigned/completed/overdue and the monthly completion rate (percent)
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param months query int false "Number of months in the completion history (1-24, default 6)"
+// @Success 200 {object} models.UserAnalytics "User analytics"
+// @Failure 400 {object} map[string]string "Invalid months"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/analytics [get]
+func (h *UserHandler) GetAnalytics(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	months, err := strconv.Atoi(c.DefaultQuery("months", "6"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid months"})
+		return
+	}
+
+	analytics, err := h.userService.GetAnalytics(userID.(uuid.UUID), months)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "months must be between 1 and 24" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get notification preferences
+// @Description Get the notification settings of the current user
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.NotificationPreferences "Notification preferences"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/profile/notifications [get]
+func (h *UserHandler) GetNotificationPreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	preferences, err := h.userService.GetNotificationPreferences(userID.(uuid.UUID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update notification preferences
+// @Description Opt in or out of action item due date reminders
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param preferences body models.NotificationPreferencesUpdateRequest true "Notification preferences"
+// @Success 200 {object} models.NotificationPreferences "Notification preferences"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/profile/notifications [put]
+func (h *UserHandler) UpdateNotificationPreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req models.NotificationPreferencesUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preferences, err := h.userService.UpdateNotificationPreferences(userID.(uuid.UUID), &models.NotificationPreferences{
+		ActionItemReminders: *req.ActionItemReminders,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
 func (h *UserHandler) SetupRoutes(r *gin.RouterGroup) {
 	auth := r.Group("/auth")
 	{
@@ -160,5 +347,10 @@ func (h *UserHandler) SetupRoutes(r *gin.RouterGroup) {
 	{
 		users.GET("/profile", h.GetProfile)
 		users.PUT("/profile", h.UpdateProfile)
+		users.DELETE("/profile", h.DeleteAccount)
+		users.GET("/profile/export", h.ExportData)
+		users.GET("/profile/notifications", h.GetNotificationPreferences)
+		users.PUT("/profile/notifications", h.UpdateNotificationPreferences)
+		users.GET("/analytics", h.GetAnalytics)
 	}
 }
@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// webhookErrorStatus maps webhook errors to HTTP statuses
+func webhookErrorStatus(err error) int {
+	switch {
+	case err.Error() == "webhook not found", err.Error() == "chat webhook not found":
+		return http.StatusNotFound
+	case err.Error() == "access denied":
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid "), strings.HasPrefix(err.Error(), "at least "),
+		strings.HasPrefix(err.Error(), "limit "), strings.HasPrefix(err.Error(), "offset "):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseTeamWebhookIDs reads the team and webhook IDs from the path
+func parseTeamWebhookIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return teamID, webhookID, true
+}
+
+// ListWebhooks godoc
+// @Summary List team webhooks
+// @Description List the webhooks of a team. Only the team owner can manage webhooks.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {array} models.TeamWebhook "Webhooks"
+// @Failure 400 {object} map[string]string "Invalid team ID"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Router /teams/{id}/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(teamID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// CreateWebhook godoc
+// @Summary Create a team webhook
+// @Description Subscribe a URL to events of the team's retrospectives (item_added, action_item_added, action_item_updated, retrospective_started, retrospective_ended). Payloads are signed with HMAC-SHA256 in the X-Webhook-Signature header; the secret is only returned here.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body models.TeamWebhookCreateRequest true "Webhook"
+// @Success 201 {object} models.TeamWebhook "Webhook created"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Router /teams/{id}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req models.TeamWebhookCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(teamID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a team webhook
+// @Description Change the URL, events or active flag of a webhook
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param webhookId path string true "Webhook ID"
+// @Param request body models.TeamWebhookUpdateRequest true "Changes"
+// @Success 200 {object} models.TeamWebhook "Webhook updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Webhook not found"
+// @Router /teams/{id}/webhooks/{webhookId} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, webhookID, ok := parseTeamWebhookIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.TeamWebhookUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(teamID, webhookID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a team webhook
+// @Description Delete a webhook and its delivery log
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} map[string]string "Webhook deleted"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Webhook not found"
+// @Router /teams/{id}/webhooks/{webhookId} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, webhookID, ok := parseTeamWebhookIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(teamID, webhookID, userID.(uuid.UUID)); err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List the delivery log of a webhook, newest first, with the attempts, response status and last error
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param webhookId path string true "Webhook ID"
+// @Param limit query int false "Page size (1-100)" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.WebhookDeliveryList "Deliveries"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Webhook not found"
+// @Router /teams/{id}/webhooks/{webhookId}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, webhookID, ok := parseTeamWebhookIDs(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(teamID, webhookID, userID.(uuid.UUID), limit, offset)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// GetChatWebhook godoc
+// @Summary Get the team chat channel
+// @Description Get the Slack, Teams or Mattermost incoming webhook the milestones of the team's retrospectives are posted to
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} models.TeamChatWebhook "Chat channel"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Chat webhook not found"
+// @Router /teams/{id}/chat-webhook [get]
+func (h *WebhookHandler) GetChatWebhook(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	webhook, err := h.webhookService.GetChatWebhook(teamID, userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// SetChatWebhook godoc
+// @Summary Set the team chat channel
+// @Description Post the start and end of the team's retrospectives and their new action items to a Slack, Teams or Mattermost incoming webhook. Only the team owner can set it; retrospectives without a team are never posted.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body models.TeamChatWebhookRequest true "Chat channel"
+// @Success 200 {object} models.TeamChatWebhook "Chat channel set"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Router /teams/{id}/chat-webhook [put]
+func (h *WebhookHandler) SetChatWebhook(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req models.TeamChatWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.SetChatWebhook(teamID, userID.(uuid.UUID), &req)
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteChatWebhook godoc
+// @Summary Remove the team chat channel
+// @Description Stop posting the team's retrospectives to chat
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} map[string]string "Chat webhook deleted"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Chat webhook not found"
+// @Router /teams/{id}/chat-webhook [delete]
+func (h *WebhookHandler) DeleteChatWebhook(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	if err := h.webhookService.DeleteChatWebhook(teamID, userID.(uuid.UUID)); err != nil {
+		c.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chat webhook deleted successfully"})
+}
+
+func (h *WebhookHandler) SetupRoutes(r *gin.RouterGroup) {
+	webhooks := r.Group("/teams/:id/webhooks")
+	webhooks.Use(authMiddleware)
+	{
+		webhooks.GET("", h.ListWebhooks)
+		webhooks.POST("", h.CreateWebhook)
+		webhooks.PUT("/:webhookId", h.UpdateWebhook)
+		webhooks.DELETE("/:webhookId", h.DeleteWebhook)
+		webhooks.GET("/:webhookId/deliveries", h.ListWebhookDeliveries)
+	}
+
+	chat := r.Group("/teams/:id/chat-webhook")
+	chat.Use(authMiddleware)
+	{
+		chat.GET("", h.GetChatWebhook)
+		chat.PUT("", h.SetChatWebhook)
+		chat.DELETE("", h.DeleteChatWebhook)
+	}
+}
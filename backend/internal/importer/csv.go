@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"educ-retro/internal/models"
+)
+
+// Columns of a CSV file, by the names their headers may have. The
+// Portuguese names are the ones of the CSV export.
+var csvColumns = map[string]string{
+	"tipo": "type", "type": "type",
+	"categoria": "category", "category": "category",
+	"conteúdo": "content", "conteudo": "content", "content": "content", "título": "content", "titulo": "content", "title": "content",
+	"votos": "votes", "votes": "votes",
+	"grupos": "groups", "grupo": "groups", "groups": "groups", "group": "groups",
+	"responsável": "assignee", "responsavel": "assignee", "assignee": "assignee",
+	"prazo": "due_date", "due_date": "due_date", "due date": "due_date",
+	"status": "status", "situação": "status", "situacao": "status",
+	"descrição": "description", "descricao": "description", "description": "description",
+}
+
+// Action item statuses by their labels in the CSV export
+var csvStatuses = map[string]string{
+	"pendente": "todo", "todo": "todo",
+	"em progresso": "in_progress", "in_progress": "in_progress",
+	"concluído": "done", "concluido": "done", "done": "done",
+}
+
+// ParseCSV reads a spreadsheet with a row per item or action item, like the
+// CSV export. The content column is required; the type column tells items
+// ("Item", the default) from action items ("Action item"). Items of a row
+// with groups are added to those groups, separated by ";".
+func ParseCSV(data []byte) (*Retrospective, []models.ImportIssue) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	// Spreadsheets in Portuguese save CSV separated by semicolons
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []models.ImportIssue{{Location: "line 1", Message: "invalid CSV: " + csvError(err)}}
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		if column, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[column] = i
+		}
+	}
+	if _, ok := columns["content"]; !ok {
+		return nil, []models.ImportIssue{{Location: "line 1", Message: "missing content column"}}
+	}
+
+	retrospective := &Retrospective{}
+	var issues []models.ImportIssue
+	groups := map[string]int{}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			issue := models.ImportIssue{Message: "invalid CSV: " + csvError(err)}
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				issue.Location = fmt.Sprintf("line %d", parseErr.Line)
+			}
+			issues = append(issues, issue)
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		location := fmt.Sprintf("line %d", line)
+
+		cell := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return csvValue(record[i])
+		}
+
+		if isBlankRecord(record) {
+			continue
+		}
+
+		switch rowType := strings.ToLower(cell("type")); {
+		case rowType == "" || rowType == "item":
+			item := Item{
+				Location: location,
+				Key:      location,
+				Category: cell("category"),
+				Content:  cell("content"),
+			}
+			if votes := cell("votes"); votes != "" {
+				item.Votes, err = strconv.Atoi(votes)
+				if err != nil {
+					issues = append(issues, models.ImportIssue{Location: location, Message: fmt.Sprintf("invalid votes %q", votes)})
+				}
+			}
+			retrospective.Items = append(retrospective.Items, item)
+
+			for _, name := range strings.Split(cell("groups"), ";") {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					continue
+				}
+				i, ok := groups[name]
+				if !ok {
+					i = len(retrospective.Groups)
+					groups[name] = i
+					retrospective.Groups = append(retrospective.Groups, Group{Location: location, Name: name})
+				}
+				retrospective.Groups[i].ItemKeys = append(retrospective.Groups[i].ItemKeys, item.Key)
+			}
+
+		case rowType == "action item" || rowType == "action_item" || rowType == "ação" || rowType == "acao":
+			actionItem := ActionItem{
+				Location:     location,
+				Title:        cell("content"),
+				Description:  cell("description"),
+				Status:       "todo",
+				AssigneeName: cell("assignee"),
+			}
+			if status := cell("status"); status != "" {
+				actionItem.Status = status
+				if code, ok := csvStatuses[strings.ToLower(status)]; ok {
+					actionItem.Status = code
+				}
+			}
+			if dueDate := cell("due_date"); dueDate != "" {
+				date, err := parseDate(dueDate)
+				if err != nil {
+					issues = append(issues, models.ImportIssue{Location: location, Message: fmt.Sprintf("invalid due date %q, use YYYY-MM-DD or DD/MM/YYYY", dueDate)})
+				} else {
+					actionItem.DueDate = &date
+				}
+			}
+			retrospective.ActionItems = append(retrospective.ActionItems, actionItem)
+
+		default:
+			issues = append(issues, models.ImportIssue{Location: location, Message: fmt.Sprintf("unknown type %q, use Item or Action item", cell("type"))})
+		}
+	}
+
+	return retrospective, issues
+}
+
+// csvValue undoes the quote the CSV export puts before cells that a
+// spreadsheet would run as formulas
+func csvValue(cell string) string {
+	cell = strings.TrimSpace(cell)
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune("=+-@", rune(cell[1])) {
+		return cell[1:]
+	}
+	return cell
+}
+
+func isBlankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse("02/01/2006", value)
+}
+
+// csvError drops the position from CSV errors, the issue already has it
+func csvError(err error) string {
+	if parseErr, ok := err.(*csv.ParseError); ok {
+		return parseErr.Err.Error()
+	}
+	return err.Error()
+}
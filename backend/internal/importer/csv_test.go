@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	data := []byte("\ufeffTipo,Categoria,Conteúdo,Votos,Grupos,Responsável,Prazo,Status\n" +
+		"Item,Deu certo,'=SOMA(1;2),2,Automação; Qualidade,,,\n" +
+		",Melhorar,\"Deploy\nmanual\",,Automação,,,\n" +
+		",,,,,,,\n" +
+		"Action item,,Automatizar deploy,,,Ana,2024-03-15,Concluído\n")
+
+	retrospective, issues := ParseCSV(data)
+
+	require.Empty(t, issues)
+	require.Len(t, retrospective.Items, 2)
+	assert.Equal(t, Item{Location: "line 2", Key: "line 2", Category: "Deu certo", Content: "=SOMA(1;2)", Votes: 2}, retrospective.Items[0])
+	assert.Equal(t, "Deploy\nmanual", retrospective.Items[1].Content)
+	assert.Equal(t, "line 3", retrospective.Items[1].Location)
+
+	require.Len(t, retrospective.Groups, 2)
+	assert.Equal(t, "Automação", retrospective.Groups[0].Name)
+	assert.Equal(t, []string{"line 2", "line 3"}, retrospective.Groups[0].ItemKeys)
+	assert.Equal(t, []string{"line 2"}, retrospective.Groups[1].ItemKeys)
+
+	require.Len(t, retrospective.ActionItems, 1)
+	actionItem := retrospective.ActionItems[0]
+	assert.Equal(t, "line 6", actionItem.Location)
+	assert.Equal(t, "Automatizar deploy", actionItem.Title)
+	assert.Equal(t, "Ana", actionItem.AssigneeName)
+	assert.Equal(t, "done", actionItem.Status)
+	assert.Equal(t, "2024-03-15", actionItem.DueDate.Format("2006-01-02"))
+}
+
+func TestParseCSV_Issues(t *testing.T) {
+	_, issues := ParseCSV([]byte("Categoria,Votos\nstart,1\n"))
+	assert.Equal(t, "missing content column", issues[0].Message)
+
+	retrospective, issues := ParseCSV([]byte("content;type;due date\nAlgo;Tarefa;\nRevisar;Action item;amanhã\n"))
+	require.NotNil(t, retrospective)
+	assert.Equal(t, []string{
+		`unknown type "Tarefa", use Item or Action item`,
+		`invalid due date "amanhã", use YYYY-MM-DD or DD/MM/YYYY`,
+	}, []string{issues[0].Message, issues[1].Message})
+}
+
+func TestParse(t *testing.T) {
+	_, _, err := Parse("xml", nil)
+	assert.EqualError(t, err, "unsupported import format")
+
+	retrospective, issues, err := Parse("JSON", []byte("{"))
+	assert.NoError(t, err)
+	assert.Nil(t, retrospective)
+	assert.Len(t, issues, 1)
+}
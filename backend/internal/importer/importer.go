@@ -0,0 +1,97 @@
+// Package importer reads retrospectives from files of this project's JSON
+// export or from CSV spreadsheets.
+package importer
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Retrospective is a retrospective as read from a file, before it is
+// validated. Items are referenced by the key they have in the file.
+type Retrospective struct {
+	Title       string
+	Description string
+	Template    string
+	Status      string // empty when the file does not have it
+	StartedAt   *time.Time
+	EndedAt     *time.Time
+	Items       []Item
+	Groups      []Group
+	ActionItems []ActionItem
+	Notes       []Note
+	Merges      []Merge
+}
+
+// Item is an item of the retrospective. Category is the ID or the name of
+// a category of the template.
+type Item struct {
+	Location    string
+	Key         string
+	Category    string
+	Content     string
+	Votes       int
+	IsAnonymous bool
+}
+
+type Group struct {
+	Location    string
+	Key         string
+	Name        string
+	Description string
+	Votes       int
+	ItemKeys    []string
+}
+
+// ActionItem is an action item of the retrospective. The assignee is known
+// by ID in JSON files and by name in CSV files.
+type ActionItem struct {
+	Location     string
+	Title        string
+	Description  string
+	Status       string
+	DueDate      *time.Time
+	ItemKey      string
+	AssigneeID   *uuid.UUID
+	AssigneeName string
+}
+
+// Note is the discussion note of an item or a group, referenced by its key
+type Note struct {
+	Location string
+	ItemKey  string
+	GroupKey string
+	Content  string
+	Outcome  string
+}
+
+// Merge is an item merged into another one. Merged items are not imported,
+// their votes are already counted on the item they were merged into.
+type Merge struct {
+	Location      string
+	TargetKey     string
+	SourceContent string
+}
+
+// Formats lists the supported import formats
+var Formats = []string{"json", "csv"}
+
+// Parse reads a file of the format. Problems with the content are returned
+// as issues, with a nil retrospective when the file cannot be read at all.
+func Parse(format string, data []byte) (*Retrospective, []models.ImportIssue, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		retrospective, issues := ParseJSON(data)
+		return retrospective, issues, nil
+	case "csv":
+		retrospective, issues := ParseCSV(data)
+		return retrospective, issues, nil
+	default:
+		return nil, nil, errors.New("unsupported import format")
+	}
+}
@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"educ-retro/internal/export"
+	"educ-retro/internal/models"
+)
+
+// ParseJSON reads a document of the JSON export
+func ParseJSON(data []byte) (*Retrospective, []models.ImportIssue) {
+	var document export.Document
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, []models.ImportIssue{{Message: "invalid JSON: " + err.Error()}}
+	}
+	if document.Retrospective == nil {
+		return nil, []models.ImportIssue{{Location: "retrospective", Message: "missing retrospective"}}
+	}
+
+	source := document.Retrospective
+	retrospective := &Retrospective{
+		Title:     source.Title,
+		Template:  string(source.Template),
+		Status:    string(source.Status),
+		StartedAt: source.StartedAt,
+		EndedAt:   source.EndedAt,
+	}
+	if source.Description != nil {
+		retrospective.Description = *source.Description
+	}
+
+	for i, item := range source.Items {
+		retrospective.Items = append(retrospective.Items, Item{
+			Location:    fmt.Sprintf("items[%d]", i),
+			Key:         item.ID.String(),
+			Category:    item.Category,
+			Content:     item.Content,
+			Votes:       item.Votes,
+			IsAnonymous: item.IsAnonymous,
+		})
+	}
+
+	for i, group := range source.Groups {
+		imported := Group{
+			Location: fmt.Sprintf("groups[%d]", i),
+			Key:      group.ID.String(),
+			Name:     group.Name,
+			Votes:    group.Votes,
+		}
+		if group.Description != nil {
+			imported.Description = *group.Description
+		}
+		for _, itemID := range document.GroupItems[group.ID] {
+			imported.ItemKeys = append(imported.ItemKeys, itemID.String())
+		}
+		retrospective.Groups = append(retrospective.Groups, imported)
+	}
+
+	for i, actionItem := range source.ActionItems {
+		imported := ActionItem{
+			Location:   fmt.Sprintf("action_items[%d]", i),
+			Title:      actionItem.Title,
+			Status:     actionItem.Status,
+			DueDate:    actionItem.DueDate,
+			AssigneeID: actionItem.AssignedTo,
+		}
+		if actionItem.Description != nil {
+			imported.Description = *actionItem.Description
+		}
+		if actionItem.ItemID != nil {
+			imported.ItemKey = actionItem.ItemID.String()
+		}
+		if actionItem.AssignedTo != nil {
+			imported.AssigneeName = document.UserNames[*actionItem.AssignedTo]
+		}
+		retrospective.ActionItems = append(retrospective.ActionItems, imported)
+	}
+
+	for i, note := range source.Notes {
+		imported := Note{
+			Location: fmt.Sprintf("notes[%d]", i),
+			Content:  note.Content,
+			Outcome:  note.Outcome,
+		}
+		if note.ItemID != nil {
+			imported.ItemKey = note.ItemID.String()
+		}
+		if note.GroupID != nil {
+			imported.GroupKey = note.GroupID.String()
+		}
+		retrospective.Notes = append(retrospective.Notes, imported)
+	}
+
+	for i, merge := range source.Merges {
+		retrospective.Merges = append(retrospective.Merges, Merge{
+			Location:      fmt.Sprintf("merges[%d]", i),
+			TargetKey:     merge.TargetItemID.String(),
+			SourceContent: merge.SourceItem.Content,
+		})
+	}
+
+	return retrospective, nil
+}
@@ -0,0 +1,101 @@
+package issuetracker
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type GitHubConfig struct {
+	// APIURL defaults to https://api.github.com; set it for GitHub Enterprise
+	APIURL     string
+	Token      string
+	Repository string // owner/name
+}
+
+// GitHubProvider creates GitHub Issues in one repository. Closed issues
+// complete the action item; open ones only tell that it is not done.
+type GitHubProvider struct {
+	config     GitHubConfig
+	httpClient *http.Client
+}
+
+func NewGitHubProvider(config GitHubConfig) *GitHubProvider {
+	if config.APIURL == "" {
+		config.APIURL = "https://api.github.com"
+	}
+	config.APIURL = strings.TrimSuffix(config.APIURL, "/")
+
+	return &GitHubProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *GitHubProvider) Name() string {
+	return "github"
+}
+
+type githubIssue struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"` // open, closed
+}
+
+func (i *githubIssue) toIssue() *Issue {
+	issue := &Issue{ID: strconv.Itoa(i.Number), URL: i.HTMLURL}
+	if i.State == "closed" {
+		issue.Status = "done"
+	}
+	return issue
+}
+
+func (p *GitHubProvider) newRequest(method, path string) (*http.Request, error) {
+	req, err := http.NewRequest(method, p.config.APIURL+"/repos/"+p.config.Repository+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return req, nil
+}
+
+func (p *GitHubProvider) CreateIssue(issue *NewIssue) (*Issue, error) {
+	req, err := p.newRequest(http.MethodPost, "/issues")
+	if err != nil {
+		return nil, err
+	}
+
+	body := issue.Body
+	if issue.DueDate != nil {
+		// Issues have no due date; keep it in the description
+		body = fmt.Sprintf("**Prazo:** %s\n\n%s", issue.DueDate.Format("02/01/2006"), body)
+	}
+
+	var created githubIssue
+	err = doJSON(p.httpClient, req, map[string]interface{}{
+		"title": issue.Title,
+		"body":  body,
+	}, &created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github issue: %w", err)
+	}
+
+	return created.toIssue(), nil
+}
+
+func (p *GitHubProvider) GetIssue(externalID string) (*Issue, error) {
+	req, err := p.newRequest(http.MethodGet, "/issues/"+externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue githubIssue
+	if err := doJSON(p.httpClient, req, nil, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get github issue %s: %w", externalID, err)
+	}
+
+	return issue.toIssue(), nil
+}
@@ -0,0 +1,114 @@
+package issuetracker
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type JiraConfig struct {
+	BaseURL    string // e.g. https://example.atlassian.net
+	Email      string
+	APIToken   string
+	ProjectKey string
+	// IssueType defaults to Task
+	IssueType string
+}
+
+// JiraProvider creates Jira issues in one project through the REST API v2.
+// The status category of the issue maps to the action item status.
+type JiraProvider struct {
+	config     JiraConfig
+	httpClient *http.Client
+}
+
+func NewJiraProvider(config JiraConfig) *JiraProvider {
+	if config.IssueType == "" {
+		config.IssueType = "Task"
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	return &JiraProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *JiraProvider) Name() string {
+	return "jira"
+}
+
+func (p *JiraProvider) newRequest(method, path string) (*http.Request, error) {
+	req, err := http.NewRequest(method, p.config.BaseURL+"/rest/api/2"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(p.config.Email, p.config.APIToken)
+	return req, nil
+}
+
+func (p *JiraProvider) browseURL(key string) string {
+	return p.config.BaseURL + "/browse/" + key
+}
+
+func (p *JiraProvider) CreateIssue(issue *NewIssue) (*Issue, error) {
+	req, err := p.newRequest(http.MethodPost, "/issue")
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": p.config.ProjectKey},
+		"issuetype":   map[string]string{"name": p.config.IssueType},
+		"summary":     issue.Title,
+		"description": issue.Body,
+	}
+	if issue.DueDate != nil {
+		fields["duedate"] = issue.DueDate.Format("2006-01-02")
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := doJSON(p.httpClient, req, map[string]interface{}{"fields": fields}, &created); err != nil {
+		return nil, fmt.Errorf("failed to create jira issue: %w", err)
+	}
+
+	// New issues start in the "To Do" category
+	return &Issue{ID: created.Key, URL: p.browseURL(created.Key), Status: "todo"}, nil
+}
+
+func (p *JiraProvider) GetIssue(externalID string) (*Issue, error) {
+	req, err := p.newRequest(http.MethodGet, "/issue/"+url.PathEscape(externalID)+"?fields=status")
+	if err != nil {
+		return nil, err
+	}
+
+	var issue struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Status struct {
+				StatusCategory struct {
+					Key string `json:"key"` // new, indeterminate, done
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := doJSON(p.httpClient, req, nil, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get jira issue %s: %w", externalID, err)
+	}
+
+	result := &Issue{ID: issue.Key, URL: p.browseURL(issue.Key)}
+	switch issue.Fields.Status.StatusCategory.Key {
+	case "new":
+		result.Status = "todo"
+	case "indeterminate":
+		result.Status = "in_progress"
+	case "done":
+		result.Status = "done"
+	}
+
+	return result, nil
+}
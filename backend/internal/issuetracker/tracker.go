@@ -0,0 +1,101 @@
+package issuetracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Provider creates issues for action items in an issue tracker and reads
+// their state back
+type Provider interface {
+	// Name identifies the tracker on linked action items (github, jira)
+	Name() string
+	CreateIssue(issue *NewIssue) (*Issue, error)
+	GetIssue(externalID string) (*Issue, error)
+}
+
+// NewIssue is an action item rendered for the tracker
+type NewIssue struct {
+	Title   string
+	Body    string
+	DueDate *time.Time
+}
+
+// Issue is an issue in the tracker. Status is the action item status it
+// corresponds to (todo, in_progress or done), or empty when the tracker only
+// tells that the issue is open.
+type Issue struct {
+	ID     string
+	URL    string
+	Status string
+}
+
+// ProviderFromEnv builds the provider selected by ISSUE_TRACKER (github or
+// jira). It returns nil when no tracker is configured.
+func ProviderFromEnv() (Provider, error) {
+	switch os.Getenv("ISSUE_TRACKER") {
+	case "":
+		return nil, nil
+	case "github":
+		config := GitHubConfig{
+			APIURL:     os.Getenv("GITHUB_API_URL"),
+			Token:      os.Getenv("GITHUB_TOKEN"),
+			Repository: os.Getenv("GITHUB_REPOSITORY"),
+		}
+		if config.Token == "" || !strings.Contains(config.Repository, "/") {
+			return nil, fmt.Errorf("GITHUB_TOKEN and GITHUB_REPOSITORY (owner/name) are required for the github issue tracker")
+		}
+		return NewGitHubProvider(config), nil
+	case "jira":
+		config := JiraConfig{
+			BaseURL:    os.Getenv("JIRA_BASE_URL"),
+			Email:      os.Getenv("JIRA_EMAIL"),
+			APIToken:   os.Getenv("JIRA_API_TOKEN"),
+			ProjectKey: os.Getenv("JIRA_PROJECT_KEY"),
+			IssueType:  os.Getenv("JIRA_ISSUE_TYPE"),
+		}
+		if config.BaseURL == "" || config.Email == "" || config.APIToken == "" || config.ProjectKey == "" {
+			return nil, fmt.Errorf("JIRA_BASE_URL, JIRA_EMAIL, JIRA_API_TOKEN and JIRA_PROJECT_KEY are required for the jira issue tracker")
+		}
+		return NewJiraProvider(config), nil
+	default:
+		return nil, fmt.Errorf("unknown issue tracker %q", os.Getenv("ISSUE_TRACKER"))
+	}
+}
+
+// doJSON sends the request with an optional JSON body and decodes a JSON
+// response into out. Non-2xx responses are returned as errors.
+func doJSON(client *http.Client, req *http.Request, body, out interface{}) error {
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.ContentLength = int64(len(data))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,144 @@
+package issuetracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubProvider_CreateIssue(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/repos/acme/retro/issues", r.URL.Path)
+		assert.Equal(t, "Bearer gh-token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number": 42, "html_url": "https://github.com/acme/retro/issues/42", "state": "open"}`))
+	}))
+	defer server.Close()
+
+	provider := NewGitHubProvider(GitHubConfig{APIURL: server.URL, Token: "gh-token", Repository: "acme/retro"})
+	dueDate := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	issue, err := provider.CreateIssue(&NewIssue{Title: "Automatizar deploy", Body: "Pipeline manual", DueDate: &dueDate})
+
+	require.NoError(t, err)
+	assert.Equal(t, "42", issue.ID)
+	assert.Equal(t, "https://github.com/acme/retro/issues/42", issue.URL)
+	assert.Empty(t, issue.Status)
+	assert.Equal(t, "Automatizar deploy", payload["title"])
+	assert.Contains(t, payload["body"], "20/05/2024")
+	assert.Contains(t, payload["body"], "Pipeline manual")
+}
+
+func TestGitHubProvider_GetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/retro/issues/42", r.URL.Path)
+		w.Write([]byte(`{"number": 42, "html_url": "https://github.com/acme/retro/issues/42", "state": "closed"}`))
+	}))
+	defer server.Close()
+
+	provider := NewGitHubProvider(GitHubConfig{APIURL: server.URL, Token: "gh-token", Repository: "acme/retro"})
+	issue, err := provider.GetIssue("42")
+
+	require.NoError(t, err)
+	assert.Equal(t, "done", issue.Status)
+}
+
+func TestGitHubProvider_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message": "Bad credentials"}`))
+	}))
+	defer server.Close()
+
+	provider := NewGitHubProvider(GitHubConfig{APIURL: server.URL, Token: "wrong", Repository: "acme/retro"})
+	_, err := provider.CreateIssue(&NewIssue{Title: "Automatizar deploy"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Contains(t, err.Error(), "Bad credentials")
+}
+
+func TestJiraProvider_CreateIssue(t *testing.T) {
+	var payload struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/2/issue", r.URL.Path)
+		email, token, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "ana@example.com", email)
+		assert.Equal(t, "jira-token", token)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "10001", "key": "RETRO-7"}`))
+	}))
+	defer server.Close()
+
+	provider := NewJiraProvider(JiraConfig{BaseURL: server.URL + "/", Email: "ana@example.com", APIToken: "jira-token", ProjectKey: "RETRO"})
+	dueDate := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	issue, err := provider.CreateIssue(&NewIssue{Title: "Automatizar deploy", Body: "Pipeline manual", DueDate: &dueDate})
+
+	require.NoError(t, err)
+	assert.Equal(t, "RETRO-7", issue.ID)
+	assert.Equal(t, server.URL+"/browse/RETRO-7", issue.URL)
+	assert.Equal(t, "todo", issue.Status)
+	assert.Equal(t, map[string]interface{}{"key": "RETRO"}, payload.Fields["project"])
+	assert.Equal(t, map[string]interface{}{"name": "Task"}, payload.Fields["issuetype"])
+	assert.Equal(t, "Automatizar deploy", payload.Fields["summary"])
+	assert.Equal(t, "2024-05-20", payload.Fields["duedate"])
+}
+
+func TestJiraProvider_GetIssue(t *testing.T) {
+	categories := map[string]string{"new": "todo", "indeterminate": "in_progress", "done": "done"}
+
+	for category, status := range categories {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/rest/api/2/issue/RETRO-7", r.URL.Path)
+			assert.Equal(t, "status", r.URL.Query().Get("fields"))
+			w.Write([]byte(`{"key": "RETRO-7", "fields": {"status": {"name": "Whatever", "statusCategory": {"key": "` + category + `"}}}}`))
+		}))
+
+		provider := NewJiraProvider(JiraConfig{BaseURL: server.URL, Email: "ana@example.com", APIToken: "jira-token", ProjectKey: "RETRO"})
+		issue, err := provider.GetIssue("RETRO-7")
+		server.Close()
+
+		require.NoError(t, err)
+		assert.Equal(t, status, issue.Status, category)
+	}
+}
+
+func TestProviderFromEnv(t *testing.T) {
+	t.Setenv("ISSUE_TRACKER", "")
+	provider, err := ProviderFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	t.Setenv("ISSUE_TRACKER", "github")
+	t.Setenv("GITHUB_TOKEN", "gh-token")
+	t.Setenv("GITHUB_REPOSITORY", "retro")
+	_, err = ProviderFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("GITHUB_REPOSITORY", "acme/retro")
+	provider, err = ProviderFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "github", provider.Name())
+
+	t.Setenv("ISSUE_TRACKER", "jira")
+	_, err = ProviderFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("ISSUE_TRACKER", "trello")
+	_, err = ProviderFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetrospectiveImportOptions are the settings of an import besides the file
+type RetrospectiveImportOptions struct {
+	Format string // json or csv
+	// Title and Template replace the ones of the file. CSV files have
+	// neither, so they are required for them.
+	Title    string
+	Template RetrospectiveTemplate
+	TeamID   *uuid.UUID
+	// Date is when a retrospective imported from CSV happened
+	Date *time.Time
+	// DryRun only validates the file
+	DryRun bool
+}
+
+// RetrospectiveImportReport tells what an import created, or would create
+// on a dry run, and what is wrong with the file
+type RetrospectiveImportReport struct {
+	DryRun      bool                  `json:"dry_run"`
+	Valid       bool                  `json:"valid"`
+	Title       string                `json:"title"`
+	Template    RetrospectiveTemplate `json:"template"`
+	Items       int                   `json:"items"`
+	Groups      int                   `json:"groups"`
+	ActionItems int                   `json:"action_items"`
+	Notes       int                   `json:"notes"`
+	Votes       int                   `json:"votes"`
+	// Errors prevent the import; warnings are about data left out
+	Errors   []ImportIssue `json:"errors"`
+	Warnings []ImportIssue `json:"warnings"`
+	// Retrospective is the created retrospective, nil on a dry run or when
+	// the file has errors
+	Retrospective *Retrospective `json:"retrospective,omitempty"`
+}
+
+// ImportIssue is a problem found in an import file
+type ImportIssue struct {
+	// Location in the file, e.g. "line 4" or "items[2]"
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
@@ -50,8 +50,11 @@ type RetrospectiveItem struct {
 	AuthorID        *uuid.UUID `json:"author_id" db:"author_id"` // null if anonymous
 	IsAnonymous     bool       `json:"is_anonymous" db:"is_anonymous"`
 	Votes           int        `json:"votes" db:"votes"`
-	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	// MergedIntoID is the item this one was merged into; merged items are
+	// hidden until the merge is undone
+	MergedIntoID *uuid.UUID `json:"merged_into_id,omitempty" db:"merged_into_id"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type RetrospectiveVote struct {
@@ -74,12 +77,19 @@ type ActionItem struct {
 	CreatedBy       uuid.UUID  `json:"created_by" db:"created_by"`
 	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	// Issue the action item was exported to, if any
+	ExternalProvider *string    `json:"external_provider" db:"external_provider"` // github, jira
+	ExternalID       *string    `json:"external_id" db:"external_id"`
+	ExternalURL      *string    `json:"external_url" db:"external_url"`
+	ExternalSyncedAt *time.Time `json:"external_synced_at" db:"external_synced_at"`
 }
 
 type RetrospectiveCreateRequest struct {
 	Title       string                `json:"title" binding:"required"`
 	Description string                `json:"description"`
 	Template    RetrospectiveTemplate `json:"template" binding:"required"`
+	TeamID      *uuid.UUID            `json:"team_id"`      // only used on creation
+	ScheduledAt *time.Time            `json:"scheduled_at"` // starts on its own at this time
 }
 
 type RetrospectiveItemCreateRequest struct {
@@ -103,6 +113,7 @@ type ActionItemUpdateRequest struct {
 	AssignedTo  *string `json:"assigned_to"`
 	DueDate     *string `json:"due_date"`
 	CompletedAt *string `json:"completed_at"`
+	Note        *string `json:"note"` // why the change was made, kept in the history
 }
 
 type RetrospectiveParticipant struct {
@@ -122,6 +133,8 @@ type RetrospectiveGroup struct {
 	CreatedBy       uuid.UUID `json:"created_by" db:"created_by"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	// ItemIDs are the items in the group; an item is in at most one group
+	ItemIDs []uuid.UUID `json:"item_ids" db:"-"`
 }
 
 type RetrospectiveGroupItem struct {
@@ -130,25 +143,323 @@ type RetrospectiveGroupItem struct {
 	ItemID  uuid.UUID `json:"item_id" db:"item_id"`
 }
 
+type RetrospectiveGroupVote struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	GroupID   uuid.UUID `json:"group_id" db:"group_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
 type GroupCreateRequest struct {
 	Name        string   `json:"name" binding:"required"`
 	Description *string  `json:"description"`
 	ItemIDs     []string `json:"item_ids"`
 }
 
+// GroupSuggestion is a proposed group of similar items that are in no group.
+// Its name, description and item IDs are a GroupCreateRequest, so that the
+// suggestion is accepted by creating it as is.
+type GroupSuggestion struct {
+	GroupCreateRequest
+	// Terms are the words the items share, the most relevant first
+	Terms []string `json:"terms"`
+	// Similarity is the average similarity of the pairs of items, from 0 to 1
+	Similarity float64 `json:"similarity"`
+}
+
+type GroupUpdateRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// GroupItemsRequest adds items to a group, moving them out of the group they
+// were in
+type GroupItemsRequest struct {
+	ItemIDs []string `json:"item_ids" binding:"required"`
+}
+
 type GroupVoteRequest struct {
 	GroupID string `json:"group_id" binding:"required"`
 }
 
+// ItemMergeVoteMode tells the votes of the item two items are merged into
+type ItemMergeVoteMode string
+
+const (
+	ItemMergeVotesSum   ItemMergeVoteMode = "sum"
+	ItemMergeVotesMax   ItemMergeVoteMode = "max"
+	ItemMergeVotesReset ItemMergeVoteMode = "reset"
+)
+
 type MergeItemsRequest struct {
 	SourceItemID string `json:"source_item_id" binding:"required"`
 	TargetItemID string `json:"target_item_id" binding:"required"`
+	// VoteMode is sum (default), max or reset
+	VoteMode ItemMergeVoteMode `json:"vote_mode"`
+	// TargetCategory is the category of the merged item, required when the
+	// items are in different categories; by default it stays the one of the
+	// target item
+	TargetCategory string `json:"target_category"`
+}
+
+// MoveItemRequest moves an item to another category of the template
+type MoveItemRequest struct {
+	Category string `json:"category" binding:"required"`
+}
+
+type UnmergeItemsRequest struct {
+	MergeID string `json:"merge_id" binding:"required"`
+}
+
+// ItemMerge is a merge that can be undone. The source item is kept, hidden,
+// and the target item gets back its content and votes on undo.
+type ItemMerge struct {
+	ID              uuid.UUID         `json:"id" db:"id"`
+	RetrospectiveID uuid.UUID         `json:"retrospective_id" db:"retrospective_id"`
+	TargetItemID    uuid.UUID         `json:"target_item_id" db:"target_item_id"`
+	SourceItem      RetrospectiveItem `json:"source_item" db:"-"`
+	VoteMode        ItemMergeVoteMode `json:"vote_mode" db:"vote_mode"`
+	TargetContent   string            `json:"target_content" db:"target_content"`   // before the merge
+	TargetVotes     int               `json:"target_votes" db:"target_votes"`       // before the merge
+	MergedVotes     int               `json:"merged_votes" db:"merged_votes"`       // right after the merge
+	TargetCategory  string            `json:"target_category" db:"target_category"` // before the merge
+	MergedCategory  string            `json:"merged_category" db:"merged_category"` // right after the merge
+	CreatedBy       *uuid.UUID        `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
 }
 
 type RetrospectiveWithDetails struct {
 	Retrospective
-	Items        []RetrospectiveItem        `json:"items"`
-	ActionItems  []ActionItem               `json:"action_items"`
-	Participants []RetrospectiveParticipant `json:"participants"`
-	Groups       []RetrospectiveGroup       `json:"groups"`
+	Items              []RetrospectiveItem        `json:"items"`
+	ActionItems        []ActionItem               `json:"action_items"`
+	CarriedActionItems []CarriedActionItem        `json:"carried_action_items"`
+	Participants       []RetrospectiveParticipant `json:"participants"`
+	Groups             []RetrospectiveGroup       `json:"groups"`
+	// Merges are the merges of items that can be undone
+	Merges []ItemMerge `json:"merges"`
+	// Notes are the discussion notes of the items and groups
+	Notes []DiscussionNote `json:"notes"`
+}
+
+// NoteSubject is what a discussion note or topic is about
+type NoteSubject string
+
+const (
+	NoteSubjectItem  NoteSubject = "item"
+	NoteSubjectGroup NoteSubject = "group"
+)
+
+// DiscussionNote is what was said while discussing an item or a group, and
+// its outcome. Everyone edits the same note of an item or group; Version
+// changes on every save, so that saving over a newer version is refused.
+type DiscussionNote struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	RetrospectiveID uuid.UUID  `json:"retrospective_id" db:"retrospective_id"`
+	ItemID          *uuid.UUID `json:"item_id,omitempty" db:"item_id"`
+	GroupID         *uuid.UUID `json:"group_id,omitempty" db:"group_id"`
+	Content         string     `json:"content" db:"content"`
+	Outcome         string     `json:"outcome" db:"outcome"`
+	Version         int        `json:"version" db:"version"`
+	UpdatedBy       *uuid.UUID `json:"updated_by" db:"updated_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type DiscussionNoteRequest struct {
+	Content string `json:"content"`
+	Outcome string `json:"outcome"`
+	// Version is the version of the note that was edited, 0 for a new note
+	Version int `json:"version"`
+}
+
+// DiscussionTopic is an item or a group in the discussion queue. Items in a
+// group are discussed with the group, so they are not topics of their own.
+type DiscussionTopic struct {
+	Type NoteSubject `json:"type"`
+	ID   uuid.UUID   `json:"id"`
+	// Title is the content of the item or the name of the group
+	Title    string `json:"title"`
+	Category string `json:"category,omitempty"`
+	// Votes of a group are its own votes and the votes of its items
+	Votes   int         `json:"votes"`
+	ItemIDs []uuid.UUID `json:"item_ids,omitempty"`
+}
+
+// CurrentTopic is the item or group a facilitator set as the one being
+// discussed; both IDs are nil when there is none
+type CurrentTopic struct {
+	ItemID    *uuid.UUID `json:"item_id" db:"current_topic_item_id"`
+	GroupID   *uuid.UUID `json:"group_id" db:"current_topic_group_id"`
+	StartedAt *time.Time `json:"started_at" db:"current_topic_started_at"`
+}
+
+// DiscussionQueue is the order in which the topics of a retrospective are
+// discussed, the most voted first, and the topic being discussed
+type DiscussionQueue struct {
+	Topics []DiscussionTopic `json:"topics"`
+	// Current is the topic being discussed, nil when there is none
+	Current *DiscussionTopic `json:"current"`
+	// CurrentStartedAt is when the discussion of the current topic started
+	CurrentStartedAt *time.Time `json:"current_started_at"`
+}
+
+type CurrentTopicRequest struct {
+	Type NoteSubject `json:"type" binding:"required"`
+	ID   string      `json:"id" binding:"required"`
+}
+
+// CarriedActionItem is an unfinished action item from a previous retrospective
+// that is reviewed in this one. CarryForward tells whether it is carried again
+// to the next retrospective if it is still unfinished.
+type CarriedActionItem struct {
+	ActionItem
+	OriginRetrospectiveTitle string `json:"origin_retrospective_title"`
+	CarryForward             bool   `json:"carry_forward"`
+}
+
+type CarriedActionItemUpdateRequest struct {
+	CarryForward *bool `json:"carry_forward" binding:"required"`
+}
+
+// RetrospectiveStats summarizes the retrospectives a user can see: the ones
+// they created, joined or that belong to one of their teams
+type RetrospectiveStats struct {
+	Total         int                     `json:"total"`
+	ByStatus      map[string]int          `json:"by_status"`
+	Participation ParticipationStats      `json:"participation"`
+	ActionItems   ActionItemProgressStats `json:"action_items"`
+}
+
+type ParticipationStats struct {
+	Created             int     `json:"created"`
+	Joined              int     `json:"joined"`
+	TotalParticipants   int     `json:"total_participants"`
+	AverageParticipants float64 `json:"average_participants"`
+	TotalItems          int     `json:"total_items"`
+}
+
+// ActionItemProgressStats counts action items by status. CompletionRate is a percentage.
+type ActionItemProgressStats struct {
+	Total          int     `json:"total"`
+	Todo           int     `json:"todo"`
+	InProgress     int     `json:"in_progress"`
+	Done           int     `json:"done"`
+	Overdue        int     `json:"overdue"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// AssignableUser is a user who can be assigned action items of a retrospective:
+// its creator, a participant or a member of its team
+type AssignableUser struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Avatar        *string   `json:"avatar"`
+	IsParticipant bool      `json:"is_participant"`
+	IsTeamMember  bool      `json:"is_team_member"`
+}
+
+type RetrospectiveTransferRequest struct {
+	NewOwnerID uuid.UUID `json:"new_owner_id" binding:"required"`
+}
+
+// ActionItemFilter selects action items across retrospectives. Nil and empty
+// fields are not filtered on.
+type ActionItemFilter struct {
+	AssignedTo      *uuid.UUID
+	Status          []string
+	DueFrom         *time.Time
+	DueTo           *time.Time
+	TeamID          *uuid.UUID
+	RetrospectiveID *uuid.UUID
+	Search          string
+	SortBy          string // due_date, created_at, updated_at, status or title
+	SortOrder       string // asc or desc
+	Limit           int
+	Offset          int
+}
+
+// ActionItemRetrospective is the retrospective an action item belongs to
+type ActionItemRetrospective struct {
+	ID       uuid.UUID             `json:"id"`
+	Title    string                `json:"title"`
+	Template RetrospectiveTemplate `json:"template"`
+	Status   RetrospectiveStatus   `json:"status"`
+	TeamID   *uuid.UUID            `json:"team_id"`
+}
+
+type ActionItemWithRetrospective struct {
+	ActionItem
+	Retrospective ActionItemRetrospective `json:"retrospective"`
+}
+
+type ActionItemList struct {
+	ActionItems []ActionItemWithRetrospective `json:"action_items"`
+	Total       int                           `json:"total"`
+	Limit       int                           `json:"limit"`
+	Offset      int                           `json:"offset"`
+}
+
+type ReminderKind string
+
+const (
+	ReminderDueSoon ReminderKind = "due_soon"
+	ReminderOverdue ReminderKind = "overdue"
+)
+
+// ActionItemReminder is a due date reminder to be delivered to the assignee of an action item
+type ActionItemReminder struct {
+	Kind               ReminderKind `json:"kind"`
+	ActionItemID       uuid.UUID    `json:"action_item_id"`
+	ActionItemTitle    string       `json:"action_item_title"`
+	DueDate            time.Time    `json:"due_date"`
+	RetrospectiveID    uuid.UUID    `json:"retrospective_id"`
+	RetrospectiveTitle string       `json:"retrospective_title"`
+	UserID             uuid.UUID    `json:"user_id"`
+	UserEmail          string       `json:"user_email"`
+	UserName           string       `json:"user_name"`
+}
+
+type ActionItemEventType string
+
+const (
+	ActionItemEventCreated        ActionItemEventType = "created"
+	ActionItemEventStatusChanged  ActionItemEventType = "status_changed"
+	ActionItemEventReassigned     ActionItemEventType = "reassigned"
+	ActionItemEventDueDateChanged ActionItemEventType = "due_date_changed"
+)
+
+// ActionItemEvent is an entry in the change history of an action item. Values
+// are the status, the assignee ID or the due date (YYYY-MM-DD); nil means unset.
+type ActionItemEvent struct {
+	ID           uuid.UUID           `json:"id" db:"id"`
+	ActionItemID uuid.UUID           `json:"action_item_id" db:"action_item_id"`
+	UserID       *uuid.UUID          `json:"user_id" db:"user_id"` // null if the account was deleted
+	UserName     string              `json:"user_name"`
+	EventType    ActionItemEventType `json:"event_type" db:"event_type"`
+	OldValue     *string             `json:"old_value" db:"old_value"`
+	NewValue     *string             `json:"new_value" db:"new_value"`
+	Note         *string             `json:"note" db:"note"`
+	CreatedAt    time.Time           `json:"created_at" db:"created_at"`
+}
+
+type ActionItemComment struct {
+	ID           uuid.UUID           `json:"id" db:"id"`
+	ActionItemID uuid.UUID           `json:"action_item_id" db:"action_item_id"`
+	ParentID     *uuid.UUID          `json:"parent_id" db:"parent_id"`
+	UserID       *uuid.UUID          `json:"user_id" db:"user_id"` // null if the account was deleted
+	UserName     string              `json:"user_name"`
+	Content      string              `json:"content" db:"content"`
+	CreatedAt    time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at" db:"updated_at"`
+	Replies      []ActionItemComment `json:"replies"`
+}
+
+type ActionItemCommentCreateRequest struct {
+	Content  string  `json:"content" binding:"required"`
+	ParentID *string `json:"parent_id"`
+}
+
+type ActionItemCommentUpdateRequest struct {
+	Content string `json:"content" binding:"required"`
 }
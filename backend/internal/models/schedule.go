@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetrospectiveSchedule creates a retrospective for the team every
+// IntervalWeeks weeks, on the weekday and time of NextRunAt
+type RetrospectiveSchedule struct {
+	ID            uuid.UUID             `json:"id" db:"id"`
+	TeamID        uuid.UUID             `json:"team_id" db:"team_id"`
+	Title         string                `json:"title" db:"title"`
+	Description   *string               `json:"description" db:"description"`
+	Template      RetrospectiveTemplate `json:"template" db:"template"`
+	IntervalWeeks int                   `json:"interval_weeks" db:"interval_weeks"`
+	NextRunAt     time.Time             `json:"next_run_at" db:"next_run_at"`
+	Active        bool                  `json:"active" db:"active"`
+	CreatedBy     *uuid.UUID            `json:"created_by" db:"created_by"` // nil once the account is deleted
+	CreatedAt     time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+type RetrospectiveScheduleCreateRequest struct {
+	Title         string                `json:"title" binding:"required"`
+	Description   string                `json:"description"`
+	Template      RetrospectiveTemplate `json:"template" binding:"required"`
+	IntervalWeeks int                   `json:"interval_weeks" binding:"required"`
+	StartsAt      time.Time             `json:"starts_at" binding:"required"` // first retrospective
+	Active        *bool                 `json:"active"`
+}
+
+type RetrospectiveScheduleUpdateRequest struct {
+	Title         *string                `json:"title"`
+	Description   *string                `json:"description"`
+	Template      *RetrospectiveTemplate `json:"template"`
+	IntervalWeeks *int                   `json:"interval_weeks"`
+	NextRunAt     *time.Time             `json:"next_run_at"`
+	Active        *bool                  `json:"active"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportPolicy tells who can export a retrospective and create public links
+// to its summary. Each policy also allows everyone the previous one allows.
+type ExportPolicy string
+
+const (
+	ExportPolicyCreator ExportPolicy = "creator"
+	// ExportPolicyFacilitators allows the creator and the owners of the team
+	ExportPolicyFacilitators ExportPolicy = "facilitators"
+	ExportPolicyParticipants ExportPolicy = "participants"
+	// ExportPolicyTeam allows every member of the team, viewers included
+	ExportPolicyTeam ExportPolicy = "team"
+)
+
+// ShareLink is a public read-only link to the summary of a retrospective
+type ShareLink struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	RetrospectiveID uuid.UUID `json:"retrospective_id" db:"retrospective_id"`
+	Token           string    `json:"token" db:"token"`
+	URL             string    `json:"url,omitempty" db:"-"` // page of the summary in the app
+	CreatedBy       uuid.UUID `json:"created_by" db:"created_by"`
+	ExpiresAt       time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+type ShareLinkCreateRequest struct {
+	// Days until the link expires, 7 by default
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+type ExportPolicyUpdateRequest struct {
+	ExportPolicy ExportPolicy `json:"export_policy" binding:"required"`
+}
+
+// RetrospectiveSharing is the export policy of a retrospective and what the
+// user can do with it
+type RetrospectiveSharing struct {
+	ExportPolicy ExportPolicy `json:"export_policy"`
+	CanExport    bool         `json:"can_export"`
+	// CanManage tells whether the user can change the policy and revoke
+	// links of others
+	CanManage  bool        `json:"can_manage"`
+	ShareLinks []ShareLink `json:"share_links"`
+}
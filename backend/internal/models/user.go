@@ -6,14 +6,33 @@ import (
 	"github.com/google/uuid"
 )
 
+// UserRole is the system-wide role of a user, independent of team roles
+type UserRole string
+
+const (
+	UserRoleAdmin UserRole = "admin"
+	UserRoleUser  UserRole = "user"
+)
+
 type User struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Email       string     `json:"email" db:"email"`
+	Name        string     `json:"name" db:"name"`
+	Password    string     `json:"-" db:"password"` // Hidden from JSON
+	Avatar      *string    `json:"avatar" db:"avatar"`
+	Role        UserRole   `json:"role" db:"role"`
+	SuspendedAt *time.Time `json:"suspended_at" db:"suspended_at"` // null if the account is active
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// UserIdentity links a local user to an account at an external identity provider
+type UserIdentity struct {
 	ID        uuid.UUID `json:"id" db:"id"`
-	Email     string    `json:"email" db:"email"`
-	Name      string    `json:"name" db:"name"`
-	Password  string    `json:"-" db:"password"` // Hidden from JSON
-	Avatar    *string   `json:"avatar" db:"avatar"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Provider  string    `json:"provider" db:"provider"`
+	Subject   string    `json:"subject" db:"subject"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type UserCreateRequest struct {
@@ -32,5 +51,116 @@ type UserResponse struct {
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
 	Avatar    *string   `json:"avatar"`
+	Role      UserRole  `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// AccountDeletionMode defines what happens to content authored by a deleted user.
+// In both modes votes and participations are removed and retrospectives and
+// groups created by the user are kept without an owner.
+type AccountDeletionMode string
+
+const (
+	// AccountDeletionAnonymize keeps authored items as anonymous, keeps created
+	// action items without an owner and keeps vote counts
+	AccountDeletionAnonymize AccountDeletionMode = "anonymize"
+	// AccountDeletionPurge also deletes authored items and created action items,
+	// and removes the user's votes from item and group counts
+	AccountDeletionPurge AccountDeletionMode = "purge"
+)
+
+// TeamMembership describes a team the user belongs to
+type TeamMembership struct {
+	TeamID   uuid.UUID `json:"team_id"`
+	TeamName string    `json:"team_name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// UserDataExport contains every record stored about a user (LGPD data portability)
+type UserDataExport struct {
+	ExportedAt              time.Time                  `json:"exported_at"`
+	Profile                 UserResponse               `json:"profile"`
+	Identities              []UserIdentity             `json:"identities"`
+	TeamMemberships         []TeamMembership           `json:"team_memberships"`
+	Retrospectives          []Retrospective            `json:"retrospectives_created"`
+	Participations          []RetrospectiveParticipant `json:"participations"`
+	Items                   []RetrospectiveItem        `json:"items_authored"`
+	ItemVotes               []RetrospectiveVote        `json:"item_votes"`
+	GroupVotes              []RetrospectiveGroupVote   `json:"group_votes"`
+	Groups                  []RetrospectiveGroup       `json:"groups_created"`
+	ActionItemsCreated      []ActionItem               `json:"action_items_created"`
+	ActionItemsAssigned     []ActionItem               `json:"action_items_assigned"`
+	ActionItemComments      []ActionItemComment        `json:"action_item_comments"`
+	ActionItemEvents        []ActionItemEvent          `json:"action_item_events"`
+	Reminders               []ActionItemReminder       `json:"action_item_reminders"`
+	NotificationPreferences *NotificationPreferences   `json:"notification_preferences"`
+	DiscussionNotes         []DiscussionNote           `json:"discussion_notes"`
+	ItemMerges              []ItemMerge                `json:"item_merges"`
+	Schedules               []RetrospectiveSchedule    `json:"schedules_created"`
+	ShareLinks              []ShareLink                `json:"share_links_created"`
+	Webhooks                []TeamWebhook              `json:"webhooks_created"`
+	ChatWebhooks            []TeamChatWebhook          `json:"chat_webhooks_created"`
+}
+
+// UserAnalytics summarizes a user's activity across all retrospectives
+type UserAnalytics struct {
+	RetrospectivesParticipated int                   `json:"retrospectives_participated"`
+	ItemsAuthored              int                   `json:"items_authored"`
+	ItemsByCategory            map[string]int        `json:"items_by_category"`
+	VotesCast                  int                   `json:"votes_cast"`
+	VotesReceived              int                   `json:"votes_received"`
+	ActionItems                ActionItemAnalytics   `json:"action_items"`
+	CompletionHistory          []CompletionRatePoint `json:"completion_history"`
+}
+
+// ActionItemAnalytics counts the action items assigned to a user.
+// CompletionRate is a percentage of completed over assigned.
+type ActionItemAnalytics struct {
+	Assigned       int     `json:"assigned"`
+	Completed      int     `json:"completed"`
+	Overdue        int     `json:"overdue"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// CompletionRatePoint is the completion of action items assigned in a month (YYYY-MM)
+type CompletionRatePoint struct {
+	Period         string  `json:"period"`
+	Assigned       int     `json:"assigned"`
+	Completed      int     `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// AdminUserList is a page of users for the admin API
+type AdminUserList struct {
+	Users  []User `json:"users"`
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+type UserRoleUpdateRequest struct {
+	Role UserRole `json:"role" binding:"required"`
+}
+
+// SystemStats contains system-wide counters for administrators
+type SystemStats struct {
+	Users                  int            `json:"users"`
+	Admins                 int            `json:"admins"`
+	SuspendedUsers         int            `json:"suspended_users"`
+	Retrospectives         int            `json:"retrospectives"`
+	RetrospectivesByStatus map[string]int `json:"retrospectives_by_status"`
+	OrphanedRetrospectives int            `json:"orphaned_retrospectives"`
+	Items                  int            `json:"items"`
+	ActionItems            int            `json:"action_items"`
+	ActionItemsDone        int            `json:"action_items_done"`
+}
+
+// NotificationPreferences are the notification settings of a user
+type NotificationPreferences struct {
+	ActionItemReminders bool `json:"action_item_reminders"`
+}
+
+type NotificationPreferencesUpdateRequest struct {
+	ActionItemReminders *bool `json:"action_item_reminders" binding:"required"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Retrospective events that can be delivered to webhooks
+const (
+	WebhookEventItemAdded            = "item_added"
+	WebhookEventActionItemAdded      = "action_item_added"
+	WebhookEventActionItemUpdated    = "action_item_updated"
+	WebhookEventRetrospectiveStarted = "retrospective_started"
+	WebhookEventRetrospectiveEnded   = "retrospective_ended"
+)
+
+// TeamWebhook subscribes a URL to events of the team's retrospectives. The
+// secret is only returned when the webhook is created.
+type TeamWebhook struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TeamID    uuid.UUID  `json:"team_id" db:"team_id"`
+	URL       string     `json:"url" db:"url"`
+	Secret    string     `json:"secret,omitempty" db:"secret"`
+	Events    []string   `json:"events" db:"events"`
+	Active    bool       `json:"active" db:"active"`
+	CreatedBy *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type TeamWebhookCreateRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required"`
+	Secret string   `json:"secret"` // generated when empty
+	Active *bool    `json:"active"`
+}
+
+type TeamWebhookUpdateRequest struct {
+	URL    *string  `json:"url"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// TeamChatWebhook is the chat channel of a team, an incoming webhook of the
+// provider (slack, teams or mattermost)
+type TeamChatWebhook struct {
+	TeamID    uuid.UUID  `json:"team_id" db:"team_id"`
+	Provider  string     `json:"provider" db:"provider"`
+	URL       string     `json:"url" db:"url"`
+	CreatedBy *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type TeamChatWebhookRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	URL      string `json:"url" binding:"required"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	WebhookID      uuid.UUID             `json:"webhook_id" db:"webhook_id"`
+	Event          string                `json:"event" db:"event"`
+	Payload        json.RawMessage       `json:"payload" db:"payload" swaggertype:"object"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	ResponseStatus *int                  `json:"response_status" db:"response_status"`
+	LastError      *string               `json:"last_error" db:"last_error"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// PendingWebhookDelivery is a delivery claimed for an attempt, with the target of its webhook
+type PendingWebhookDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+type WebhookDeliveryList struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int               `json:"total"`
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+}
@@ -0,0 +1,150 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Chat providers that accept incoming webhooks
+const (
+	ChatSlack      = "slack"
+	ChatTeams      = "teams"
+	ChatMattermost = "mattermost"
+)
+
+// ChatMessage is a message posted to a chat channel. It is rendered with
+// the markup of each provider.
+type ChatMessage struct {
+	Title    string
+	Text     string
+	Sections []ChatSection
+	// URL links the message back to the app, if set
+	URL string
+}
+
+// ChatSection is a titled bullet list inside a message
+type ChatSection struct {
+	Title string
+	Lines []string
+}
+
+// ChatSender posts messages to a team chat channel
+type ChatSender interface {
+	Name() string
+	Send(message *ChatMessage) error
+}
+
+// ChatNotifier posts messages to a Slack, Microsoft Teams or Mattermost
+// incoming webhook
+type ChatNotifier struct {
+	provider   string
+	url        string
+	httpClient *http.Client
+}
+
+func NewChatNotifier(provider, url string) (*ChatNotifier, error) {
+	switch provider {
+	case ChatSlack, ChatTeams, ChatMattermost:
+	default:
+		return nil, fmt.Errorf("unknown chat provider %q", provider)
+	}
+
+	return &ChatNotifier{
+		provider:   provider,
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (n *ChatNotifier) Name() string {
+	return n.provider
+}
+
+func (n *ChatNotifier) Send(message *ChatMessage) error {
+	body, err := json.Marshal(n.payload(message))
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.httpClient.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send chat message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("chat webhook returned %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// payload renders the message for the provider. Slack uses its own mrkdwn
+// syntax; Teams and Mattermost understand markdown, but Teams only breaks
+// lines on blank lines.
+func (n *ChatNotifier) payload(message *ChatMessage) map[string]interface{} {
+	switch n.provider {
+	case ChatSlack:
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%s*", slackEscape(message.Title))
+		if message.Text != "" {
+			fmt.Fprintf(&b, "\n%s", slackEscape(message.Text))
+		}
+		for _, section := range message.Sections {
+			fmt.Fprintf(&b, "\n\n*%s*", slackEscape(section.Title))
+			for _, line := range section.Lines {
+				fmt.Fprintf(&b, "\n• %s", slackEscape(line))
+			}
+		}
+		if message.URL != "" {
+			fmt.Fprintf(&b, "\n\n<%s|Abrir no Educ Retro>", message.URL)
+		}
+		return map[string]interface{}{"text": b.String()}
+	case ChatTeams:
+		parts := []string{}
+		if message.Text != "" {
+			parts = append(parts, message.Text)
+		}
+		for _, section := range message.Sections {
+			parts = append(parts, "**"+section.Title+"**")
+			for _, line := range section.Lines {
+				parts = append(parts, "- "+line)
+			}
+		}
+		if message.URL != "" {
+			parts = append(parts, fmt.Sprintf("[Abrir no Educ Retro](%s)", message.URL))
+		}
+		return map[string]interface{}{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  message.Title,
+			"title":    message.Title,
+			"text":     strings.Join(parts, "\n\n"),
+		}
+	default:
+		var b strings.Builder
+		fmt.Fprintf(&b, "#### %s", message.Title)
+		if message.Text != "" {
+			fmt.Fprintf(&b, "\n%s", message.Text)
+		}
+		for _, section := range message.Sections {
+			fmt.Fprintf(&b, "\n\n**%s**", section.Title)
+			for _, line := range section.Lines {
+				fmt.Fprintf(&b, "\n- %s", line)
+			}
+		}
+		if message.URL != "" {
+			fmt.Fprintf(&b, "\n\n[Abrir no Educ Retro](%s)", message.URL)
+		}
+		return map[string]interface{}{"text": b.String()}
+	}
+}
+
+// slackEscape escapes the characters Slack reserves for links and mentions
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,68 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testChatMessage() *ChatMessage {
+	return &ChatMessage{
+		Title: "Retrospectiva encerrada: Sprint <12>",
+		Text:  "3 itens",
+		Sections: []ChatSection{
+			{Title: "Action items", Lines: []string{"Automatizar deploy — Ana"}},
+		},
+		URL: "http://app.test/retrospectives/1",
+	}
+}
+
+func postChatMessage(t *testing.T, provider string) map[string]interface{} {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := NewChatNotifier(provider, server.URL)
+	require.NoError(t, err)
+	require.NoError(t, notifier.Send(testChatMessage()))
+	return payload
+}
+
+func TestChatNotifier_Slack(t *testing.T) {
+	payload := postChatMessage(t, ChatSlack)
+
+	assert.Equal(t, "*Retrospectiva encerrada: Sprint &lt;12&gt;*\n3 itens\n\n*Action items*\n• Automatizar deploy — Ana\n\n<http://app.test/retrospectives/1|Abrir no Educ Retro>", payload["text"])
+}
+
+func TestChatNotifier_Teams(t *testing.T) {
+	payload := postChatMessage(t, ChatTeams)
+
+	assert.Equal(t, "MessageCard", payload["@type"])
+	assert.Equal(t, "Retrospectiva encerrada: Sprint <12>", payload["title"])
+	assert.Equal(t, "3 itens\n\n**Action items**\n\n- Automatizar deploy — Ana\n\n[Abrir no Educ Retro](http://app.test/retrospectives/1)", payload["text"])
+}
+
+func TestChatNotifier_Mattermost(t *testing.T) {
+	payload := postChatMessage(t, ChatMattermost)
+
+	assert.Equal(t, "#### Retrospectiva encerrada: Sprint <12>\n3 itens\n\n**Action items**\n- Automatizar deploy — Ana\n\n[Abrir no Educ Retro](http://app.test/retrospectives/1)", payload["text"])
+}
+
+func TestChatNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	notifier, err := NewChatNotifier(ChatSlack, server.URL)
+	require.NoError(t, err)
+	assert.Error(t, notifier.Send(testChatMessage()))
+}
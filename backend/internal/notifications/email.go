@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"educ-retro/internal/models"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// EmailNotifier sends reminders by email through an SMTP server
+type EmailNotifier struct {
+	config SMTPConfig
+	appURL string
+	// sendMail is smtp.SendMail, replaceable in tests
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmailNotifier(config SMTPConfig, appURL string) *EmailNotifier {
+	return &EmailNotifier{
+		config:   config,
+		appURL:   appURL,
+		sendMail: smtp.SendMail,
+	}
+}
+
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+func (n *EmailNotifier) Notify(reminder *models.ActionItemReminder) error {
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+
+	headers := []string{
+		"From: " + n.config.From,
+		"To: " + reminder.UserEmail,
+		"Subject: " + mime.QEncoding.Encode("utf-8", reminderSubject(reminder)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+	}
+	message := strings.Join(headers, "\r\n") + "\r\n\r\n" +
+		strings.ReplaceAll(reminderText(reminder, n.appURL), "\n", "\r\n")
+
+	err := n.sendMail(net.JoinHostPort(n.config.Host, n.config.Port), auth, n.config.From, []string{reminder.UserEmail}, []byte(message))
+	if err != nil {
+		return fmt.Errorf("failed to send reminder email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package notifications
+
+import (
+	"log"
+
+	"educ-retro/internal/models"
+)
+
+// LogNotifier writes reminders to the server log. Useful in development and
+// as a fallback when no delivery channel is configured.
+type LogNotifier struct {
+	appURL string
+}
+
+func NewLogNotifier(appURL string) *LogNotifier {
+	return &LogNotifier{appURL: appURL}
+}
+
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+func (n *LogNotifier) Notify(reminder *models.ActionItemReminder) error {
+	log.Printf("Reminder to %s <%s>: %s", reminder.UserName, reminder.UserEmail, reminderSubject(reminder))
+	return nil
+}
@@ -0,0 +1,75 @@
+package notifications
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"educ-retro/internal/models"
+)
+
+// Notifier delivers action item reminders through one channel
+type Notifier interface {
+	// Name identifies the channel in the delivery log
+	Name() string
+	Notify(reminder *models.ActionItemReminder) error
+}
+
+// NotifierFromEnv builds the notifier selected by REMINDER_NOTIFIER (log,
+// webhook or email). It defaults to log.
+func NotifierFromEnv() (Notifier, error) {
+	appURL := strings.TrimSuffix(os.Getenv("APP_URL"), "/")
+
+	switch os.Getenv("REMINDER_NOTIFIER") {
+	case "", "log":
+		return NewLogNotifier(appURL), nil
+	case "webhook":
+		url := os.Getenv("REMINDER_WEBHOOK_URL")
+		if url == "" {
+			return nil, fmt.Errorf("REMINDER_WEBHOOK_URL is required for the webhook notifier")
+		}
+		return NewWebhookNotifier(url, appURL), nil
+	case "email":
+		config := SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}
+		if config.Host == "" || config.From == "" {
+			return nil, fmt.Errorf("SMTP_HOST and SMTP_FROM are required for the email notifier")
+		}
+		if config.Port == "" {
+			config.Port = "587"
+		}
+		return NewEmailNotifier(config, appURL), nil
+	default:
+		return nil, fmt.Errorf("unknown reminder notifier %q", os.Getenv("REMINDER_NOTIFIER"))
+	}
+}
+
+// reminderSubject and reminderText render the reminder for humans
+func reminderSubject(reminder *models.ActionItemReminder) string {
+	if reminder.Kind == models.ReminderOverdue {
+		return fmt.Sprintf("Action item atrasado: %s", reminder.ActionItemTitle)
+	}
+	return fmt.Sprintf("Action item vence em breve: %s", reminder.ActionItemTitle)
+}
+
+func reminderText(reminder *models.ActionItemReminder, appURL string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Olá %s,\n\n", reminder.UserName)
+	if reminder.Kind == models.ReminderOverdue {
+		fmt.Fprintf(&b, "O action item \"%s\" venceu em %s e ainda não foi concluído.\n", reminder.ActionItemTitle, reminder.DueDate.Format("02/01/2006"))
+	} else {
+		fmt.Fprintf(&b, "O action item \"%s\" vence em %s.\n", reminder.ActionItemTitle, reminder.DueDate.Format("02/01/2006"))
+	}
+	fmt.Fprintf(&b, "Retrospectiva: %s\n", reminder.RetrospectiveTitle)
+	if appURL != "" {
+		fmt.Fprintf(&b, "\n%s/retrospectives/%s\n", appURL, reminder.RetrospectiveID)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReminder() *models.ActionItemReminder {
+	return &models.ActionItemReminder{
+		Kind:               models.ReminderOverdue,
+		ActionItemID:       uuid.New(),
+		ActionItemTitle:    "Atualizar documentação",
+		DueDate:            time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC),
+		RetrospectiveID:    uuid.New(),
+		RetrospectiveTitle: "Sprint 12",
+		UserID:             uuid.New(),
+		UserEmail:          "ana@example.com",
+		UserName:           "Ana",
+	}
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "http://app.test")
+	require.NoError(t, notifier.Notify(testReminder()))
+
+	assert.Equal(t, "action_item_reminder", payload["event"])
+	assert.Contains(t, payload["subject"], "atrasado")
+	assert.Contains(t, payload["text"], "08/05/2024")
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "")
+	assert.Error(t, notifier.Notify(testReminder()))
+}
+
+func TestEmailNotifier_Notify(t *testing.T) {
+	notifier := NewEmailNotifier(SMTPConfig{Host: "smtp.test", Port: "587", From: "retro@example.com"}, "http://app.test")
+
+	var sentTo []string
+	var message string
+	notifier.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.test:587", addr)
+		assert.Nil(t, a)
+		sentTo = to
+		message = string(msg)
+		return nil
+	}
+
+	reminder := testReminder()
+	require.NoError(t, notifier.Notify(reminder))
+	assert.Equal(t, []string{"ana@example.com"}, sentTo)
+	assert.Contains(t, message, "To: ana@example.com")
+	assert.Contains(t, message, "http://app.test/retrospectives/"+reminder.RetrospectiveID.String())
+
+	notifier.sendMail = func(string, smtp.Auth, string, []string, []byte) error {
+		return errors.New("connection refused")
+	}
+	assert.Error(t, notifier.Notify(reminder))
+}
+
+func TestNotifierFromEnv(t *testing.T) {
+	t.Setenv("REMINDER_NOTIFIER", "")
+	notifier, err := NotifierFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "log", notifier.Name())
+
+	t.Setenv("REMINDER_NOTIFIER", "webhook")
+	t.Setenv("REMINDER_WEBHOOK_URL", "")
+	_, err = NotifierFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("REMINDER_NOTIFIER", "sms")
+	_, err = NotifierFromEnv()
+	assert.Error(t, err)
+}
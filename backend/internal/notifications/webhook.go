@@ -0,0 +1,54 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"educ-retro/internal/models"
+)
+
+// WebhookNotifier posts reminders as JSON to a URL
+type WebhookNotifier struct {
+	url        string
+	appURL     string
+	httpClient *http.Client
+}
+
+func NewWebhookNotifier(url, appURL string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:        url,
+		appURL:     appURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *WebhookNotifier) Notify(reminder *models.ActionItemReminder) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"event":    "action_item_reminder",
+		"reminder": reminder,
+		"subject":  reminderSubject(reminder),
+		"text":     reminderText(reminder, n.appURL),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.httpClient.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send reminder webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("reminder webhook returned %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"educ-retro/internal/models"
 
@@ -21,11 +22,17 @@ func NewRetrospectiveRepository(db *sql.DB) *RetrospectiveRepository {
 
 func (r *RetrospectiveRepository) Create(retrospective *models.Retrospective) error {
 	query := `
-		INSERT INTO retrospectives (id, title, description, template, status, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO retrospectives (id, title, description, template, status, created_by, team_id, scheduled_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING created_at, updated_at
 	`
 
+	// Retrospectives without a team keep team_id NULL
+	var teamID *uuid.UUID
+	if retrospective.TeamID != uuid.Nil {
+		teamID = &retrospective.TeamID
+	}
+
 	retrospective.ID = uuid.New()
 	err := r.db.QueryRow(query,
 		retrospective.ID,
@@ -34,11 +41,146 @@ func (r *RetrospectiveRepository) Create(retrospective *models.Retrospective) er
 		retrospective.Template,
 		retrospective.Status,
 		retrospective.CreatedBy,
+		teamID,
+		retrospective.ScheduledAt,
 	).Scan(&retrospective.CreatedAt, &retrospective.UpdatedAt)
 
 	return err
 }
 
+// GetExportPolicy returns who can export the retrospective
+func (r *RetrospectiveRepository) GetExportPolicy(id uuid.UUID) (models.ExportPolicy, error) {
+	var policy models.ExportPolicy
+	err := r.db.QueryRow(`SELECT export_policy FROM retrospectives WHERE id = $1`, id).Scan(&policy)
+	return policy, err
+}
+
+func (r *RetrospectiveRepository) SetExportPolicy(id uuid.UUID, policy models.ExportPolicy) error {
+	result, err := r.db.Exec(`UPDATE retrospectives SET export_policy = $2, updated_at = NOW() WHERE id = $1`, id, policy)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetTeamRole returns the role of the user in the team (owner, member or
+// viewer). The owner of the team is always "owner". It returns sql.ErrNoRows
+// when the user is not part of the team.
+func (r *RetrospectiveRepository) GetTeamRole(teamID, userID uuid.UUID) (string, error) {
+	query := `
+		SELECT CASE WHEN t.owner_id = $2 THEN 'owner' ELSE tm.role END
+		FROM teams t
+		LEFT JOIN team_members tm ON tm.team_id = t.id AND tm.user_id = $2
+		WHERE t.id = $1 AND (t.owner_id = $2 OR tm.user_id IS NOT NULL)
+	`
+
+	var role string
+	err := r.db.QueryRow(query, teamID, userID).Scan(&role)
+	return role, err
+}
+
+// Import creates a retrospective with its items, groups, action items and
+// discussion notes in one transaction. IDs must be set by the caller, so that
+// groups, action items and notes can refer to the items. Vote counts are stored as given, without
+// the votes of individual users.
+func (r *RetrospectiveRepository) Import(retrospective *models.Retrospective, items []models.RetrospectiveItem, groups []models.RetrospectiveGroup, groupItems map[uuid.UUID][]uuid.UUID, actionItems []models.ActionItem, notes []models.DiscussionNote) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var teamID *uuid.UUID
+	if retrospective.TeamID != uuid.Nil {
+		teamID = &retrospective.TeamID
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO retrospectives (id, title, description, template, status, created_by, team_id, started_at, ended_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at
+	`, retrospective.ID, retrospective.Title, retrospective.Description, retrospective.Template, retrospective.Status,
+		retrospective.CreatedBy, teamID, retrospective.StartedAt, retrospective.EndedAt,
+	).Scan(&retrospective.CreatedAt, &retrospective.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		item := &items[i]
+		err := tx.QueryRow(`
+			INSERT INTO retrospective_items (id, retrospective_id, category, content, author_id, is_anonymous, votes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at, updated_at
+		`, item.ID, item.RetrospectiveID, item.Category, item.Content, item.AuthorID, item.IsAnonymous, item.Votes,
+		).Scan(&item.CreatedAt, &item.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range groups {
+		group := &groups[i]
+		err := tx.QueryRow(`
+			INSERT INTO retrospective_groups (id, retrospective_id, name, description, created_by, votes)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING created_at, updated_at
+		`, group.ID, group.RetrospectiveID, group.Name, group.Description, group.CreatedBy, group.Votes,
+		).Scan(&group.CreatedAt, &group.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, itemID := range groupItems[group.ID] {
+			_, err := tx.Exec(`
+				INSERT INTO retrospective_group_items (id, group_id, item_id)
+				VALUES ($1, $2, $3)
+			`, uuid.New(), group.ID, itemID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range actionItems {
+		actionItem := &actionItems[i]
+		err := tx.QueryRow(`
+			INSERT INTO action_items (id, retrospective_id, item_id, title, description, assigned_to, status, due_date, completed_at, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING created_at, updated_at
+		`, actionItem.ID, actionItem.RetrospectiveID, actionItem.ItemID, actionItem.Title, actionItem.Description,
+			actionItem.AssignedTo, actionItem.Status, actionItem.DueDate, actionItem.CompletedAt, actionItem.CreatedBy,
+		).Scan(&actionItem.CreatedAt, &actionItem.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range notes {
+		note := &notes[i]
+		err := tx.QueryRow(`
+			INSERT INTO discussion_notes (id, retrospective_id, item_id, group_id, content, outcome, updated_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING version, created_at, updated_at
+		`, note.ID, note.RetrospectiveID, note.ItemID, note.GroupID, note.Content, note.Outcome, note.UpdatedBy,
+		).Scan(&note.Version, &note.CreatedAt, &note.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (r *RetrospectiveRepository) GetByID(id uuid.UUID) (*models.Retrospective, error) {
 	query := `
 		SELECT id, team_id, title, description, template, status, scheduled_at, started_at, ended_at, 
@@ -151,6 +293,50 @@ func (r *RetrospectiveRepository) GetByUserID(userID uuid.UUID) ([]models.Retros
 	return retrospectives, nil
 }
 
+// GetScheduledRetrospectives returns the retrospectives visible to the user
+// that are scheduled from since on, soonest first
+func (r *RetrospectiveRepository) GetScheduledRetrospectives(userID uuid.UUID, since time.Time) ([]models.Retrospective, error) {
+	query := userScopeCTE + `
+		SELECT r.id, r.team_id, r.title, r.description, r.template, r.status, r.scheduled_at, r.started_at, r.ended_at,
+		       r.created_by, r.created_at, r.updated_at
+		FROM retrospectives r
+		INNER JOIN scoped s ON s.id = r.id
+		WHERE r.scheduled_at >= $2
+		ORDER BY r.scheduled_at ASC
+	`
+
+	rows, err := r.db.Query(query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	retrospectives := []models.Retrospective{}
+	for rows.Next() {
+		var retrospective models.Retrospective
+		err := rows.Scan(
+			&retrospective.ID,
+			&retrospective.TeamID,
+			&retrospective.Title,
+			&retrospective.Description,
+			&retrospective.Template,
+			&retrospective.Status,
+			&retrospective.ScheduledAt,
+			&retrospective.StartedAt,
+			&retrospective.EndedAt,
+			&retrospective.CreatedBy,
+			&retrospective.CreatedAt,
+			&retrospective.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		retrospectives = append(retrospectives, retrospective)
+	}
+
+	return retrospectives, rows.Err()
+}
+
 func (r *RetrospectiveRepository) GetAllRetrospectives() ([]models.Retrospective, error) {
 	query := `
 		SELECT id, team_id, title, description, template, status, scheduled_at, started_at, ended_at, 
@@ -191,10 +377,86 @@ func (r *RetrospectiveRepository) GetAllRetrospectives() ([]models.Retrospective
 	return retrospectives, nil
 }
 
+// GetOrphanedRetrospectives returns the retrospectives whose creator deleted their account
+func (r *RetrospectiveRepository) GetOrphanedRetrospectives() ([]models.Retrospective, error) {
+	query := `
+		SELECT id, team_id, title, description, template, status, scheduled_at, started_at, ended_at, 
+		       created_by, created_at, updated_at
+		FROM retrospectives
+		WHERE created_by IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	retrospectives := []models.Retrospective{}
+	for rows.Next() {
+		var retrospective models.Retrospective
+		err := rows.Scan(
+			&retrospective.ID,
+			&retrospective.TeamID,
+			&retrospective.Title,
+			&retrospective.Description,
+			&retrospective.Template,
+			&retrospective.Status,
+			&retrospective.ScheduledAt,
+			&retrospective.StartedAt,
+			&retrospective.EndedAt,
+			&retrospective.CreatedBy,
+			&retrospective.CreatedAt,
+			&retrospective.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		retrospectives = append(retrospectives, retrospective)
+	}
+
+	return retrospectives, nil
+}
+
+// TransferOwnership makes newOwnerID the creator of the retrospective and
+// registers them as a participant so it shows up in their list
+func (r *RetrospectiveRepository) TransferOwnership(id, newOwnerID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE retrospectives SET created_by = $2, updated_at = NOW() WHERE id = $1`, id, newOwnerID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO retrospective_participants (id, retrospective_id, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (retrospective_id, user_id) DO NOTHING
+	`, uuid.New(), id, newOwnerID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *RetrospectiveRepository) Update(retrospective *models.Retrospective) error {
 	query := `
 		UPDATE retrospectives 
-		SET title = $2, description = $3, template = $4, status = $5, started_at = $6, ended_at = $7, updated_at = NOW()
+		SET title = $2, description = $3, template = $4, status = $5, started_at = $6, ended_at = $7, scheduled_at = $8, updated_at = NOW()
 		WHERE id = $1
 		RETURNING updated_at
 	`
@@ -207,6 +469,7 @@ func (r *RetrospectiveRepository) Update(retrospective *models.Retrospective) er
 		retrospective.Status,
 		retrospective.StartedAt,
 		retrospective.EndedAt,
+		retrospective.ScheduledAt,
 	).Scan(&retrospective.UpdatedAt)
 
 	return err
@@ -250,113 +513,363 @@ func (r *RetrospectiveRepository) UpdateStatus(id uuid.UUID, status models.Retro
 	return err
 }
 
-func (r *RetrospectiveRepository) GetRetrospectiveCount(userID uuid.UUID) (int, error) {
+// GetDueRetrospectives returns the planned retrospectives scheduled up to now
+func (r *RetrospectiveRepository) GetDueRetrospectives(now time.Time) ([]uuid.UUID, error) {
 	query := `
-		SELECT COUNT(*)
-		FROM retrospectives r
-		INNER JOIN team_members tm ON r.team_id = tm.team_id
-		WHERE tm.user_id = $1
+		SELECT id FROM retrospectives
+		WHERE status = 'planned' AND scheduled_at <= $1
+		ORDER BY scheduled_at ASC
 	`
 
-	var count int
-	err := r.db.QueryRow(query, userID).Scan(&count)
-	return count, err
-}
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-func (r *RetrospectiveRepository) GetActionItemCount(userID uuid.UUID) (int, error) {
-	query := `
-		SELECT COUNT(*)
-		FROM action_items ai
-		INNER JOIN retrospectives r ON ai.retrospective_id = r.id
-		WHERE r.created_by = $1
-	`
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
 
-	var count int
-	err := r.db.QueryRow(query, userID).Scan(&count)
-	return count, err
+	return ids, rows.Err()
 }
 
-func (r *RetrospectiveRepository) AddItem(item *models.RetrospectiveItem) error {
-	query := `
-		INSERT INTO retrospective_items (id, retrospective_id, category, content, author_id, is_anonymous, votes)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING created_at, updated_at
-	`
-
-	err := r.db.QueryRow(
-		query,
-		item.ID,
-		item.RetrospectiveID,
-		item.Category,
-		item.Content,
-		item.AuthorID,
-		item.IsAnonymous,
-		item.Votes,
-	).Scan(&item.CreatedAt, &item.UpdatedAt)
+// StartRetrospective moves a planned retrospective to active and, in the same
+// transaction, links the unfinished action items of the previous one. It
+// reports false when the retrospective was no longer planned, so each start
+// happens once even with concurrent callers, and how many items were carried.
+func (r *RetrospectiveRepository) StartRetrospective(id uuid.UUID) (bool, int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, 0, err
+	}
+	defer tx.Rollback()
 
-	return err
-}
+	result, err := tx.Exec(`
+		UPDATE retrospectives
+		SET status = 'active', started_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'planned'
+	`, id)
+	if err != nil {
+		return false, 0, err
+	}
 
-func (r *RetrospectiveRepository) VoteItem(itemID, userID uuid.UUID) error {
-	// First, check if user already voted
-	var count int
-	err := r.db.QueryRow(
-		"SELECT COUNT(*) FROM retrospective_votes WHERE item_id = $1 AND user_id = $2",
-		itemID, userID,
-	).Scan(&count)
+	affected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return false, 0, err
+	}
+	if affected == 0 {
+		return false, 0, nil
 	}
 
-	if count > 0 {
-		// User already voted, remove the vote
-		_, err = r.db.Exec(
-			"DELETE FROM retrospective_votes WHERE item_id = $1 AND user_id = $2",
-			itemID, userID,
-		)
-		if err != nil {
-			return err
-		}
+	result, err = tx.Exec(carryOverActionItemsQuery, id)
+	if err != nil {
+		return false, 0, err
+	}
 
-		// Decrease vote count
-		_, err = r.db.Exec(
-			"UPDATE retrospective_items SET votes = votes - 1 WHERE id = $1",
-			itemID,
-		)
-	} else {
-		// Add vote
-		_, err = r.db.Exec(
-			"INSERT INTO retrospective_votes (id, item_id, user_id) VALUES ($1, $2, $3)",
-			uuid.New(), itemID, userID,
-		)
-		if err != nil {
-			return err
-		}
+	carried, err := result.RowsAffected()
+	if err != nil {
+		return false, 0, err
+	}
 
-		// Increase vote count
-		_, err = r.db.Exec(
-			"UPDATE retrospective_items SET votes = votes + 1 WHERE id = $1",
-			itemID,
-		)
+	if err := tx.Commit(); err != nil {
+		return false, 0, err
 	}
 
-	return err
+	return true, int(carried), nil
 }
 
-func (r *RetrospectiveRepository) AddActionItem(actionItem *models.ActionItem) error {
-	query := `
-		INSERT INTO action_items (id, retrospective_id, item_id, title, description, assigned_to, status, due_date, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING created_at, updated_at
-	`
+// userScopeCTE selects the retrospectives visible to the user bound to $1:
+// created by them, joined by them or belonging to one of their teams
+const userScopeCTE = `
+	WITH scoped AS (
+		SELECT r.id, r.status, r.created_by
+		FROM retrospectives r
+		WHERE r.created_by = $1
+		   OR r.id IN (SELECT retrospective_id FROM retrospective_participants WHERE user_id = $1)
+		   OR r.team_id IN (SELECT team_id FROM team_members WHERE user_id = $1)
+	)
+`
+
+func (r *RetrospectiveRepository) GetRetrospectiveStats(userID uuid.UUID) (*models.RetrospectiveStats, error) {
+	stats := &models.RetrospectiveStats{
+		ByStatus: map[string]int{
+			string(models.RetroStatusPlanned):    0,
+			string(models.RetroStatusActive):     0,
+			string(models.RetroStatusCollecting): 0,
+			string(models.RetroStatusVoting):     0,
+			string(models.RetroStatusDiscussing): 0,
+			string(models.RetroStatusClosed):     0,
+		},
+	}
 
-	err := r.db.QueryRow(
-		query,
-		actionItem.ID,
-		actionItem.RetrospectiveID,
-		actionItem.ItemID,
-		actionItem.Title,
-		actionItem.Description,
+	// Retrospectives by status
+	rows, err := r.db.Query(userScopeCTE+`
+		SELECT status, COUNT(*) FROM scoped GROUP BY status
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stats.ByStatus[status] = count
+		stats.Total += count
+	}
+	rows.Close()
+
+	// Participation
+	err = r.db.QueryRow(userScopeCTE+`
+		SELECT
+			(SELECT COUNT(*) FROM scoped WHERE created_by = $1),
+			(SELECT COUNT(*) FROM retrospective_participants WHERE user_id = $1),
+			(SELECT COUNT(*) FROM retrospective_participants WHERE retrospective_id IN (SELECT id FROM scoped)),
+			(SELECT COUNT(*) FROM retrospective_items WHERE retrospective_id IN (SELECT id FROM scoped) AND merged_into_id IS NULL)
+	`, userID).Scan(
+		&stats.Participation.Created,
+		&stats.Participation.Joined,
+		&stats.Participation.TotalParticipants,
+		&stats.Participation.TotalItems,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Action item progress
+	err = r.db.QueryRow(userScopeCTE+`
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'todo'),
+		       COUNT(*) FILTER (WHERE status = 'in_progress'),
+		       COUNT(*) FILTER (WHERE status = 'done'),
+		       COUNT(*) FILTER (WHERE status <> 'done' AND due_date < NOW())
+		FROM action_items
+		WHERE retrospective_id IN (SELECT id FROM scoped)
+	`, userID).Scan(
+		&stats.ActionItems.Total,
+		&stats.ActionItems.Todo,
+		&stats.ActionItems.InProgress,
+		&stats.ActionItems.Done,
+		&stats.ActionItems.Overdue,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// actionItemSortColumns maps the accepted sort fields to SQL expressions
+var actionItemSortColumns = map[string]string{
+	"due_date":   "a.due_date",
+	"created_at": "a.created_at",
+	"updated_at": "a.updated_at",
+	"status":     "a.status",
+	"title":      "LOWER(a.title)",
+}
+
+// ListActionItems returns a page of the action items in the retrospectives
+// visible to the user, together with the total number of matches
+func (r *RetrospectiveRepository) ListActionItems(userID uuid.UUID, filter models.ActionItemFilter) ([]models.ActionItemWithRetrospective, int, error) {
+	conditions := []string{"a.retrospective_id IN (SELECT id FROM scoped)"}
+	args := []interface{}{userID}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.AssignedTo != nil {
+		addCondition("a.assigned_to = $%d", *filter.AssignedTo)
+	}
+	if len(filter.Status) > 0 {
+		placeholders := make([]string, len(filter.Status))
+		for i, status := range filter.Status {
+			args = append(args, status)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, "a.status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if filter.DueFrom != nil {
+		addCondition("a.due_date >= $%d", *filter.DueFrom)
+	}
+	if filter.DueTo != nil {
+		addCondition("a.due_date <= $%d", *filter.DueTo)
+	}
+	if filter.TeamID != nil {
+		addCondition("r.team_id = $%d", *filter.TeamID)
+	}
+	if filter.RetrospectiveID != nil {
+		addCondition("a.retrospective_id = $%d", *filter.RetrospectiveID)
+	}
+	if filter.Search != "" {
+		addCondition("(a.title ILIKE '%%' || $%[1]d || '%%' OR a.description ILIKE '%%' || $%[1]d || '%%' OR r.title ILIKE '%%' || $%[1]d || '%%')", filter.Search)
+	}
+
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	err := r.db.QueryRow(userScopeCTE+`
+		SELECT COUNT(*)
+		FROM action_items a
+		INNER JOIN retrospectives r ON r.id = a.retrospective_id
+		WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortColumn, ok := actionItemSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = actionItemSortColumns["due_date"]
+	}
+	sortOrder := "ASC"
+	if filter.SortOrder == "desc" {
+		sortOrder = "DESC"
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := userScopeCTE + fmt.Sprintf(`
+		SELECT a.id, a.retrospective_id, a.item_id, a.title, a.description, a.assigned_to, a.status,
+		       a.due_date, a.completed_at, a.created_by, a.created_at, a.updated_at,
+		       a.external_provider, a.external_id, a.external_url, a.external_synced_at,
+		       r.title, r.template, r.status, r.team_id
+		FROM action_items a
+		INNER JOIN retrospectives r ON r.id = a.retrospective_id
+		WHERE %s
+		ORDER BY %s %s NULLS LAST, a.created_at ASC, a.id ASC
+		LIMIT $%d OFFSET $%d
+	`, where, sortColumn, sortOrder, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	actionItems := []models.ActionItemWithRetrospective{}
+	for rows.Next() {
+		var actionItem models.ActionItemWithRetrospective
+		err := rows.Scan(
+			&actionItem.ID,
+			&actionItem.RetrospectiveID,
+			&actionItem.ItemID,
+			&actionItem.Title,
+			&actionItem.Description,
+			&actionItem.AssignedTo,
+			&actionItem.Status,
+			&actionItem.DueDate,
+			&actionItem.CompletedAt,
+			&actionItem.CreatedBy,
+			&actionItem.CreatedAt,
+			&actionItem.UpdatedAt,
+			&actionItem.ExternalProvider,
+			&actionItem.ExternalID,
+			&actionItem.ExternalURL,
+			&actionItem.ExternalSyncedAt,
+			&actionItem.Retrospective.Title,
+			&actionItem.Retrospective.Template,
+			&actionItem.Retrospective.Status,
+			&actionItem.Retrospective.TeamID,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		actionItem.Retrospective.ID = actionItem.RetrospectiveID
+		actionItems = append(actionItems, actionItem)
+	}
+
+	return actionItems, total, rows.Err()
+}
+
+func (r *RetrospectiveRepository) AddItem(item *models.RetrospectiveItem) error {
+	query := `
+		INSERT INTO retrospective_items (id, retrospective_id, category, content, author_id, is_anonymous, votes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		item.ID,
+		item.RetrospectiveID,
+		item.Category,
+		item.Content,
+		item.AuthorID,
+		item.IsAnonymous,
+		item.Votes,
+	).Scan(&item.CreatedAt, &item.UpdatedAt)
+
+	return err
+}
+
+func (r *RetrospectiveRepository) VoteItem(itemID, userID uuid.UUID) error {
+	// First, check if user already voted
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM retrospective_votes WHERE item_id = $1 AND user_id = $2",
+		itemID, userID,
+	).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		// User already voted, remove the vote
+		_, err = r.db.Exec(
+			"DELETE FROM retrospective_votes WHERE item_id = $1 AND user_id = $2",
+			itemID, userID,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Decrease vote count
+		_, err = r.db.Exec(
+			"UPDATE retrospective_items SET votes = votes - 1 WHERE id = $1",
+			itemID,
+		)
+	} else {
+		// Add vote
+		_, err = r.db.Exec(
+			"INSERT INTO retrospective_votes (id, item_id, user_id) VALUES ($1, $2, $3)",
+			uuid.New(), itemID, userID,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Increase vote count
+		_, err = r.db.Exec(
+			"UPDATE retrospective_items SET votes = votes + 1 WHERE id = $1",
+			itemID,
+		)
+	}
+
+	return err
+}
+
+func (r *RetrospectiveRepository) AddActionItem(actionItem *models.ActionItem) error {
+	query := `
+		INSERT INTO action_items (id, retrospective_id, item_id, title, description, assigned_to, status, due_date, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		actionItem.ID,
+		actionItem.RetrospectiveID,
+		actionItem.ItemID,
+		actionItem.Title,
+		actionItem.Description,
 		actionItem.AssignedTo,
 		actionItem.Status,
 		actionItem.DueDate,
@@ -385,24 +898,54 @@ func (r *RetrospectiveRepository) GetRetrospectiveWithDetails(retrospectiveID uu
 		return nil, err
 	}
 
+	// Get unfinished action items carried over from previous retrospectives
+	carriedActionItems, err := r.GetCarriedActionItems(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get participants
 	participants, err := r.GetParticipants(retrospectiveID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get groups
+	// Get groups with their items
 	groups, err := r.GetGroupsByRetrospectiveID(retrospectiveID)
 	if err != nil {
 		return nil, err
 	}
+	groupItems, err := r.GetGroupItemIDs(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		groups[i].ItemIDs = groupItems[groups[i].ID]
+		if groups[i].ItemIDs == nil {
+			groups[i].ItemIDs = []uuid.UUID{}
+		}
+	}
+
+	// Get the merges that can be undone
+	merges, err := r.GetItemMerges(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := r.GetDiscussionNotes(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
 
 	return &models.RetrospectiveWithDetails{
-		Retrospective: *retrospective,
-		Items:         items,
-		ActionItems:   actionItems,
-		Participants:  participants,
-		Groups:        groups,
+		Retrospective:      *retrospective,
+		Items:              items,
+		ActionItems:        actionItems,
+		CarriedActionItems: carriedActionItems,
+		Participants:       participants,
+		Groups:             groups,
+		Merges:             merges,
+		Notes:              notes,
 	}, nil
 }
 
@@ -410,7 +953,7 @@ func (r *RetrospectiveRepository) GetItemsByRetrospectiveID(retrospectiveID uuid
 	query := `
 		SELECT id, retrospective_id, category, content, author_id, is_anonymous, votes, created_at, updated_at
 		FROM retrospective_items
-		WHERE retrospective_id = $1
+		WHERE retrospective_id = $1 AND merged_into_id IS NULL
 		ORDER BY votes DESC, created_at ASC
 	`
 
@@ -445,7 +988,8 @@ func (r *RetrospectiveRepository) GetItemsByRetrospectiveID(retrospectiveID uuid
 
 func (r *RetrospectiveRepository) GetActionItemsByRetrospectiveID(retrospectiveID uuid.UUID) ([]models.ActionItem, error) {
 	query := `
-		SELECT id, retrospective_id, item_id, title, description, assigned_to, status, due_date, completed_at, created_by, created_at, updated_at
+		SELECT id, retrospective_id, item_id, title, description, assigned_to, status, due_date, completed_at, created_by, created_at, updated_at,
+		       external_provider, external_id, external_url, external_synced_at
 		FROM action_items
 		WHERE retrospective_id = $1
 		ORDER BY created_at ASC
@@ -473,6 +1017,10 @@ func (r *RetrospectiveRepository) GetActionItemsByRetrospectiveID(retrospectiveI
 			&actionItem.CreatedBy,
 			&actionItem.CreatedAt,
 			&actionItem.UpdatedAt,
+			&actionItem.ExternalProvider,
+			&actionItem.ExternalID,
+			&actionItem.ExternalURL,
+			&actionItem.ExternalSyncedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -483,24 +1031,45 @@ func (r *RetrospectiveRepository) GetActionItemsByRetrospectiveID(retrospectiveI
 	return actionItems, nil
 }
 
-func (r *RetrospectiveRepository) RegisterParticipant(retrospectiveID, userID uuid.UUID) error {
-	query := `
-		INSERT INTO retrospective_participants (id, retrospective_id, user_id)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (retrospective_id, user_id)
-		DO UPDATE SET last_seen = NOW()
-	`
-
-	_, err := r.db.Exec(query, uuid.New(), retrospectiveID, userID)
-	return err
-}
-
-func (r *RetrospectiveRepository) GetParticipants(retrospectiveID uuid.UUID) ([]models.RetrospectiveParticipant, error) {
+// carryOverActionItemsQuery links to the retrospective bound to $1 the
+// unfinished action items of the previous closed retrospective of the same team
+// (or of the same creator for retrospectives without a team), including the
+// items that were carried into it and marked to be carried forward
+const carryOverActionItemsQuery = `
+	WITH current AS (
+		SELECT id, team_id, created_by FROM retrospectives WHERE id = $1
+	),
+	previous AS (
+		SELECT r.id
+		FROM retrospectives r, current c
+		WHERE r.id <> c.id
+		  AND r.status = 'closed'
+		  AND (r.team_id = c.team_id OR (c.team_id IS NULL AND r.team_id IS NULL AND r.created_by = c.created_by))
+		ORDER BY COALESCE(r.ended_at, r.updated_at) DESC
+		LIMIT 1
+	)
+	INSERT INTO action_item_carryovers (id, retrospective_id, action_item_id)
+	SELECT uuid_generate_v4(), $1, a.id
+	FROM action_items a
+	WHERE a.status <> 'done'
+	  AND a.retrospective_id <> $1
+	  AND (a.retrospective_id IN (SELECT id FROM previous)
+	       OR a.id IN (SELECT action_item_id FROM action_item_carryovers
+	                   WHERE carry_forward AND retrospective_id IN (SELECT id FROM previous)))
+	ON CONFLICT (retrospective_id, action_item_id) DO NOTHING
+`
+
+func (r *RetrospectiveRepository) GetCarriedActionItems(retrospectiveID uuid.UUID) ([]models.CarriedActionItem, error) {
 	query := `
-		SELECT id, retrospective_id, user_id, joined_at, last_seen
-		FROM retrospective_participants
-		WHERE retrospective_id = $1
-		ORDER BY joined_at ASC
+		SELECT a.id, a.retrospective_id, a.item_id, a.title, a.description, a.assigned_to, a.status,
+		       a.due_date, a.completed_at, a.created_by, a.created_at, a.updated_at,
+		       a.external_provider, a.external_id, a.external_url, a.external_synced_at,
+		       r.title, c.carry_forward
+		FROM action_item_carryovers c
+		INNER JOIN action_items a ON a.id = c.action_item_id
+		INNER JOIN retrospectives r ON r.id = a.retrospective_id
+		WHERE c.retrospective_id = $1
+		ORDER BY a.created_at ASC
 	`
 
 	rows, err := r.db.Query(query, retrospectiveID)
@@ -509,99 +1078,792 @@ func (r *RetrospectiveRepository) GetParticipants(retrospectiveID uuid.UUID) ([]
 	}
 	defer rows.Close()
 
-	var participants []models.RetrospectiveParticipant
+	carriedActionItems := []models.CarriedActionItem{}
 	for rows.Next() {
-		var participant models.RetrospectiveParticipant
+		var actionItem models.CarriedActionItem
 		err := rows.Scan(
-			&participant.ID,
-			&participant.RetrospectiveID,
-			&participant.UserID,
-			&participant.JoinedAt,
-			&participant.LastSeen,
+			&actionItem.ID,
+			&actionItem.RetrospectiveID,
+			&actionItem.ItemID,
+			&actionItem.Title,
+			&actionItem.Description,
+			&actionItem.AssignedTo,
+			&actionItem.Status,
+			&actionItem.DueDate,
+			&actionItem.CompletedAt,
+			&actionItem.CreatedBy,
+			&actionItem.CreatedAt,
+			&actionItem.UpdatedAt,
+			&actionItem.ExternalProvider,
+			&actionItem.ExternalID,
+			&actionItem.ExternalURL,
+			&actionItem.ExternalSyncedAt,
+			&actionItem.OriginRetrospectiveTitle,
+			&actionItem.CarryForward,
 		)
 		if err != nil {
 			return nil, err
 		}
-		participants = append(participants, participant)
+		carriedActionItems = append(carriedActionItems, actionItem)
 	}
 
-	return participants, nil
+	return carriedActionItems, nil
 }
 
-func (r *RetrospectiveRepository) GetItemByID(itemID uuid.UUID) (*models.RetrospectiveItem, error) {
-	query := `
-		SELECT id, retrospective_id, category, content, author_id, is_anonymous, votes, created_at, updated_at
-		FROM retrospective_items
-		WHERE id = $1
-	`
+func (r *RetrospectiveRepository) SetCarryForward(retrospectiveID, actionItemID uuid.UUID, carryForward bool) error {
+	result, err := r.db.Exec(`
+		UPDATE action_item_carryovers SET carry_forward = $3
+		WHERE retrospective_id = $1 AND action_item_id = $2
+	`, retrospectiveID, actionItemID, carryForward)
+	if err != nil {
+		return err
+	}
 
-	item := &models.RetrospectiveItem{}
-	err := r.db.QueryRow(query, itemID).Scan(
-		&item.ID,
-		&item.RetrospectiveID,
-		&item.Category,
-		&item.Content,
-		&item.AuthorID,
-		&item.IsAnonymous,
-		&item.Votes,
-		&item.CreatedAt,
-		&item.UpdatedAt,
-	)
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
 
+// GetCarryoverRetrospectiveIDs returns the retrospectives an action item was carried into
+func (r *RetrospectiveRepository) GetCarryoverRetrospectiveIDs(actionItemID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(`SELECT retrospective_id FROM action_item_carryovers WHERE action_item_id = $1`, actionItemID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return item, nil
-}
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
 
-func (r *RetrospectiveRepository) DeleteItem(itemID uuid.UUID) error {
-	query := `DELETE FROM retrospective_items WHERE id = $1`
-	_, err := r.db.Exec(query, itemID)
-	return err
+	return ids, nil
 }
 
-// Group methods
-func (r *RetrospectiveRepository) CreateGroup(group *models.RetrospectiveGroup, itemIDs []uuid.UUID) error {
-	tx, err := r.db.Begin()
+// GetPendingReminders returns the reminders not yet delivered for unfinished
+// action items: overdue when due before overdueBefore, due soon when due before
+// dueSoonBefore. Suspended assignees and those who opted out are skipped.
+func (r *RetrospectiveRepository) GetPendingReminders(overdueBefore, dueSoonBefore time.Time) ([]models.ActionItemReminder, error) {
+	query := `
+		SELECT pending.kind, pending.action_item_id, pending.title, pending.due_date,
+		       pending.retrospective_id, pending.retrospective_title, pending.user_id, pending.email, pending.name
+		FROM (
+			SELECT CASE WHEN a.due_date < $1 THEN 'overdue' ELSE 'due_soon' END AS kind,
+			       a.id AS action_item_id, a.title, a.due_date,
+			       r.id AS retrospective_id, r.title AS retrospective_title,
+			       u.id AS user_id, u.email, u.name
+			FROM action_items a
+			INNER JOIN retrospectives r ON r.id = a.retrospective_id
+			INNER JOIN users u ON u.id = a.assigned_to
+			LEFT JOIN user_notification_preferences p ON p.user_id = u.id
+			WHERE a.status <> 'done'
+			  AND a.due_date < $2
+			  AND u.suspended_at IS NULL
+			  AND COALESCE(p.action_item_reminders, TRUE)
+		) pending
+		WHERE NOT EXISTS (
+			SELECT 1 FROM action_item_reminders ar
+			WHERE ar.action_item_id = pending.action_item_id
+			  AND ar.user_id = pending.user_id
+			  AND ar.kind = pending.kind
+			  AND ar.due_date = pending.due_date
+			  AND ar.sent_at IS NOT NULL
+		)
+		ORDER BY pending.due_date ASC
+	`
+
+	rows, err := r.db.Query(query, overdueBefore, dueSoonBefore)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	// Insert group
-	query := `
-		INSERT INTO retrospective_groups (id, retrospective_id, name, description, created_by, votes)
-		VALUES ($1, $2, $3, $4, $5, $6)
+	reminders := []models.ActionItemReminder{}
+	for rows.Next() {
+		var reminder models.ActionItemReminder
+		err := rows.Scan(
+			&reminder.Kind,
+			&reminder.ActionItemID,
+			&reminder.ActionItemTitle,
+			&reminder.DueDate,
+			&reminder.RetrospectiveID,
+			&reminder.RetrospectiveTitle,
+			&reminder.UserID,
+			&reminder.UserEmail,
+			&reminder.UserName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+// ClaimReminder records that the reminder is being delivered through channel.
+// It returns false when the reminder was already sent or another delivery is
+// in progress; claims left unsent for 15 minutes are considered abandoned.
+func (r *RetrospectiveRepository) ClaimReminder(reminder *models.ActionItemReminder, channel string) (bool, error) {
+	query := `
+		INSERT INTO action_item_reminders (id, action_item_id, user_id, kind, due_date, channel)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (action_item_id, user_id, kind, due_date) DO UPDATE
+		SET channel = EXCLUDED.channel, created_at = NOW()
+		WHERE action_item_reminders.sent_at IS NULL
+		  AND action_item_reminders.created_at < NOW() - INTERVAL '15 minutes'
+		RETURNING id
+	`
+
+	var id uuid.UUID
+	err := r.db.QueryRow(query, uuid.New(), reminder.ActionItemID, reminder.UserID, reminder.Kind, reminder.DueDate, channel).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *RetrospectiveRepository) MarkReminderSent(reminder *models.ActionItemReminder) error {
+	_, err := r.db.Exec(`
+		UPDATE action_item_reminders SET sent_at = NOW()
+		WHERE action_item_id = $1 AND user_id = $2 AND kind = $3 AND due_date = $4
+	`, reminder.ActionItemID, reminder.UserID, reminder.Kind, reminder.DueDate)
+	return err
+}
+
+// ReleaseReminder drops an unsent claim so the reminder is retried on the next run
+func (r *RetrospectiveRepository) ReleaseReminder(reminder *models.ActionItemReminder) error {
+	_, err := r.db.Exec(`
+		DELETE FROM action_item_reminders
+		WHERE action_item_id = $1 AND user_id = $2 AND kind = $3 AND due_date = $4 AND sent_at IS NULL
+	`, reminder.ActionItemID, reminder.UserID, reminder.Kind, reminder.DueDate)
+	return err
+}
+
+func (r *RetrospectiveRepository) RegisterParticipant(retrospectiveID, userID uuid.UUID) error {
+	query := `
+		INSERT INTO retrospective_participants (id, retrospective_id, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (retrospective_id, user_id)
+		DO UPDATE SET last_seen = NOW()
+	`
+
+	_, err := r.db.Exec(query, uuid.New(), retrospectiveID, userID)
+	return err
+}
+
+func (r *RetrospectiveRepository) GetParticipants(retrospectiveID uuid.UUID) ([]models.RetrospectiveParticipant, error) {
+	query := `
+		SELECT id, retrospective_id, user_id, joined_at, last_seen
+		FROM retrospective_participants
+		WHERE retrospective_id = $1
+		ORDER BY joined_at ASC
+	`
+
+	rows, err := r.db.Query(query, retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []models.RetrospectiveParticipant
+	for rows.Next() {
+		var participant models.RetrospectiveParticipant
+		err := rows.Scan(
+			&participant.ID,
+			&participant.RetrospectiveID,
+			&participant.UserID,
+			&participant.JoinedAt,
+			&participant.LastSeen,
+		)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, participant)
+	}
+
+	return participants, nil
+}
+
+// itemColumns are the columns scanned by scanItem
+const itemColumns = `id, retrospective_id, category, content, author_id, is_anonymous, votes, merged_into_id, created_at, updated_at`
+
+func scanItem(scanner interface{ Scan(...interface{}) error }) (*models.RetrospectiveItem, error) {
+	item := &models.RetrospectiveItem{}
+	err := scanner.Scan(
+		&item.ID,
+		&item.RetrospectiveID,
+		&item.Category,
+		&item.Content,
+		&item.AuthorID,
+		&item.IsAnonymous,
+		&item.Votes,
+		&item.MergedIntoID,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// GetItemByID returns the item, merged into another one or not
+func (r *RetrospectiveRepository) GetItemByID(itemID uuid.UUID) (*models.RetrospectiveItem, error) {
+	query := `SELECT ` + itemColumns + ` FROM retrospective_items WHERE id = $1`
+	return scanItem(r.db.QueryRow(query, itemID))
+}
+
+// MoveItem moves the item to the category. Items merged into another item are
+// not moved.
+func (r *RetrospectiveRepository) MoveItem(itemID uuid.UUID, category string) (*models.RetrospectiveItem, error) {
+	query := `
+		UPDATE retrospective_items SET category = $2, updated_at = NOW()
+		WHERE id = $1 AND merged_into_id IS NULL
+		RETURNING ` + itemColumns
+	return scanItem(r.db.QueryRow(query, itemID, category))
+}
+
+// DeleteItem deletes an item. Items that other items were merged into are
+// kept, since the hidden merged items would be deleted with them.
+func (r *RetrospectiveRepository) DeleteItem(itemID uuid.UUID) error {
+	query := `
+		DELETE FROM retrospective_items
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM retrospective_items WHERE merged_into_id = $1)
+	`
+	result, err := r.db.Exec(query, itemID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("item has merged items, unmerge first")
+	}
+
+	return nil
+}
+
+// Group methods
+func (r *RetrospectiveRepository) CreateGroup(group *models.RetrospectiveGroup, itemIDs []uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Insert group
+	query := `
+		INSERT INTO retrospective_groups (id, retrospective_id, name, description, created_by, votes)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING created_at, updated_at
 	`
 	err = tx.QueryRow(query, group.ID, group.RetrospectiveID, group.Name, group.Description, group.CreatedBy, 0).
 		Scan(&group.CreatedAt, &group.UpdatedAt)
 	if err != nil {
-		return err
+		return err
+	}
+
+	// Insert group items, moving them out of the group they were in
+	if _, err := addGroupItems(tx, group.ID, itemIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addGroupItems adds the items to the group, removing them from the other
+// groups of its retrospective. It returns the groups the items left.
+func addGroupItems(tx *sql.Tx, groupID uuid.UUID, itemIDs []uuid.UUID) ([]uuid.UUID, error) {
+	leftGroups := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, itemID := range itemIDs {
+		rows, err := tx.Query(`
+			DELETE FROM retrospective_group_items
+			WHERE item_id = $1 AND group_id <> $2
+			RETURNING group_id
+		`, itemID, groupID)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var leftGroupID uuid.UUID
+			if err := rows.Scan(&leftGroupID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if !seen[leftGroupID] {
+				seen[leftGroupID] = true
+				leftGroups = append(leftGroups, leftGroupID)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO retrospective_group_items (id, group_id, item_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (group_id, item_id) DO NOTHING
+		`, uuid.New(), groupID, itemID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return leftGroups, nil
+}
+
+// UpdateGroup saves the name and description of the group
+func (r *RetrospectiveRepository) UpdateGroup(group *models.RetrospectiveGroup) error {
+	query := `
+		UPDATE retrospective_groups SET name = $2, description = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	return r.db.QueryRow(query, group.ID, group.Name, group.Description).Scan(&group.UpdatedAt)
+}
+
+// AddGroupItems adds the items to the group in one transaction, moving them
+// out of the group they were in. It returns the groups the items left.
+func (r *RetrospectiveRepository) AddGroupItems(groupID uuid.UUID, itemIDs []uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	leftGroups, err := addGroupItems(tx, groupID, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, changedGroupID := range append([]uuid.UUID{groupID}, leftGroups...) {
+		if _, err := tx.Exec(`UPDATE retrospective_groups SET updated_at = NOW() WHERE id = $1`, changedGroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return leftGroups, nil
+}
+
+// RemoveGroupItem takes the item out of the group. It returns sql.ErrNoRows
+// when the item is not in the group.
+func (r *RetrospectiveRepository) RemoveGroupItem(groupID, itemID uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM retrospective_group_items WHERE group_id = $1 AND item_id = $2`, groupID, itemID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = r.db.Exec(`UPDATE retrospective_groups SET updated_at = NOW() WHERE id = $1`, groupID)
+	return err
+}
+
+func (r *RetrospectiveRepository) GetGroupsByRetrospectiveID(retrospectiveID uuid.UUID) ([]models.RetrospectiveGroup, error) {
+	query := `
+		SELECT id, retrospective_id, name, description, created_by, created_at, updated_at, votes
+		FROM retrospective_groups
+		WHERE retrospective_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(query, retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.RetrospectiveGroup
+	for rows.Next() {
+		var group models.RetrospectiveGroup
+		err := rows.Scan(&group.ID, &group.RetrospectiveID, &group.Name, &group.Description,
+			&group.CreatedBy, &group.CreatedAt, &group.UpdatedAt, &group.Votes)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// GetGroupItemIDs returns the IDs of the items of each group of the retrospective
+func (r *RetrospectiveRepository) GetGroupItemIDs(retrospectiveID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	query := `
+		SELECT gi.group_id, gi.item_id
+		FROM retrospective_group_items gi
+		JOIN retrospective_groups g ON g.id = gi.group_id
+		JOIN retrospective_items i ON i.id = gi.item_id
+		WHERE g.retrospective_id = $1 AND i.merged_into_id IS NULL
+		ORDER BY i.created_at ASC
+	`
+	rows, err := r.db.Query(query, retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groupItems := map[uuid.UUID][]uuid.UUID{}
+	for rows.Next() {
+		var groupID, itemID uuid.UUID
+		if err := rows.Scan(&groupID, &itemID); err != nil {
+			return nil, err
+		}
+		groupItems[groupID] = append(groupItems[groupID], itemID)
+	}
+
+	return groupItems, rows.Err()
+}
+
+func (r *RetrospectiveRepository) VoteGroup(groupID, userID uuid.UUID) error {
+	// Check if user already voted
+	var exists bool
+	checkQuery := `SELECT EXISTS(SELECT 1 FROM retrospective_group_votes WHERE group_id = $1 AND user_id = $2)`
+	err := r.db.QueryRow(checkQuery, groupID, userID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		// Remove vote
+		_, err = r.db.Exec(`DELETE FROM retrospective_group_votes WHERE group_id = $1 AND user_id = $2`, groupID, userID)
+		if err != nil {
+			return err
+		}
+		// Decrease vote count
+		_, err = r.db.Exec(`UPDATE retrospective_groups SET votes = votes - 1 WHERE id = $1`, groupID)
+	} else {
+		// Add vote
+		_, err = r.db.Exec(`INSERT INTO retrospective_group_votes (id, group_id, user_id) VALUES ($1, $2, $3)`, uuid.New(), groupID, userID)
+		if err != nil {
+			return err
+		}
+		// Increase vote count
+		_, err = r.db.Exec(`UPDATE retrospective_groups SET votes = votes + 1 WHERE id = $1`, groupID)
+	}
+
+	return err
+}
+
+func (r *RetrospectiveRepository) GetGroupByID(groupID uuid.UUID) (*models.RetrospectiveGroup, error) {
+	query := `
+		SELECT id, retrospective_id, name, description, votes, created_by, created_at, updated_at
+		FROM retrospective_groups
+		WHERE id = $1
+	`
+	var group models.RetrospectiveGroup
+	err := r.db.QueryRow(query, groupID).Scan(
+		&group.ID, &group.RetrospectiveID, &group.Name, &group.Description,
+		&group.Votes, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *RetrospectiveRepository) DeleteGroup(groupID uuid.UUID) error {
+	query := `DELETE FROM retrospective_groups WHERE id = $1`
+	_, err := r.db.Exec(query, groupID)
+	return err
+}
+
+// mergedVotes returns the votes of the item two items are merged into
+func mergedVotes(mode models.ItemMergeVoteMode, targetVotes, sourceVotes int) int {
+	switch mode {
+	case models.ItemMergeVotesMax:
+		if sourceVotes > targetVotes {
+			return sourceVotes
+		}
+		return targetVotes
+	case models.ItemMergeVotesReset:
+		return 0
+	default:
+		return targetVotes + sourceVotes
+	}
+}
+
+// MergeItems merges the source item into the target item: the target gets
+// both contents, the votes of the vote mode and the category, and the source
+// is hidden. An empty category keeps the one of the target, and then the
+// items must be in the same category. The merge is recorded so that it can be
+// undone.
+func (r *RetrospectiveRepository) MergeItems(sourceItemID, targetItemID uuid.UUID, voteMode models.ItemMergeVoteMode, category string, userID uuid.UUID) (*models.RetrospectiveItem, *models.ItemMerge, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock both items
+	query := `SELECT ` + itemColumns + ` FROM retrospective_items WHERE id = $1 FOR UPDATE`
+	sourceItem, err := scanItem(tx.QueryRow(query, sourceItemID))
+	if err != nil {
+		return nil, nil, err
+	}
+	targetItem, err := scanItem(tx.QueryRow(query, targetItemID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Check if items belong to same retrospective and category
+	if sourceItem.RetrospectiveID != targetItem.RetrospectiveID {
+		return nil, nil, errors.New("items must belong to the same retrospective")
+	}
+	if category == "" {
+		if sourceItem.Category != targetItem.Category {
+			return nil, nil, errors.New("items must belong to the same category")
+		}
+		category = targetItem.Category
+	}
+	if sourceItem.MergedIntoID != nil || targetItem.MergedIntoID != nil {
+		return nil, nil, errors.New("item was merged into another item")
+	}
+
+	merge := &models.ItemMerge{
+		ID:              uuid.New(),
+		RetrospectiveID: targetItem.RetrospectiveID,
+		TargetItemID:    targetItemID,
+		VoteMode:        voteMode,
+		TargetContent:   targetItem.Content,
+		TargetVotes:     targetItem.Votes,
+		MergedVotes:     mergedVotes(voteMode, targetItem.Votes, sourceItem.Votes),
+		TargetCategory:  targetItem.Category,
+		MergedCategory:  category,
+		CreatedBy:       &userID,
+	}
+	err = tx.QueryRow(`
+		INSERT INTO retrospective_item_merges (id, retrospective_id, target_item_id, source_item_id, vote_mode, target_content, target_votes, merged_votes, target_category, merged_category, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at
+	`, merge.ID, merge.RetrospectiveID, targetItemID, sourceItemID, voteMode, merge.TargetContent, merge.TargetVotes, merge.MergedVotes,
+		merge.TargetCategory, merge.MergedCategory, userID).
+		Scan(&merge.CreatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Merge content (combine both contents)
+	targetItem.Content = targetItem.Content + " | " + sourceItem.Content
+	targetItem.Votes = merge.MergedVotes
+	targetItem.Category = category
+	err = tx.QueryRow(`UPDATE retrospective_items SET content = $1, votes = $2, category = $3, updated_at = NOW() WHERE id = $4 RETURNING updated_at`,
+		targetItem.Content, targetItem.Votes, targetItem.Category, targetItemID).Scan(&targetItem.UpdatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Hide the source item, keeping its content, author and votes
+	_, err = tx.Exec(`UPDATE retrospective_items SET merged_into_id = $1, updated_at = NOW() WHERE id = $2`, targetItemID, sourceItemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	sourceItem.MergedIntoID = &targetItemID
+	merge.SourceItem = *sourceItem
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return targetItem, merge, nil
+}
+
+const itemMergeColumns = `m.id, m.retrospective_id, m.target_item_id, m.vote_mode, m.target_content, m.target_votes, m.merged_votes,
+		m.target_category, m.merged_category, m.created_by, m.created_at,
+		i.id, i.retrospective_id, i.category, i.content, i.author_id, i.is_anonymous, i.votes, i.merged_into_id, i.created_at, i.updated_at`
+
+func scanItemMerge(scanner interface{ Scan(...interface{}) error }) (*models.ItemMerge, error) {
+	var merge models.ItemMerge
+	source := &merge.SourceItem
+	err := scanner.Scan(
+		&merge.ID, &merge.RetrospectiveID, &merge.TargetItemID, &merge.VoteMode, &merge.TargetContent,
+		&merge.TargetVotes, &merge.MergedVotes, &merge.TargetCategory, &merge.MergedCategory, &merge.CreatedBy, &merge.CreatedAt,
+		&source.ID, &source.RetrospectiveID, &source.Category, &source.Content, &source.AuthorID,
+		&source.IsAnonymous, &source.Votes, &source.MergedIntoID, &source.CreatedAt, &source.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &merge, nil
+}
+
+// GetItemMerges returns the merges of items of the retrospective, oldest first
+func (r *RetrospectiveRepository) GetItemMerges(retrospectiveID uuid.UUID) ([]models.ItemMerge, error) {
+	query := `
+		SELECT ` + itemMergeColumns + `
+		FROM retrospective_item_merges m
+		JOIN retrospective_items i ON i.id = m.source_item_id
+		WHERE m.retrospective_id = $1
+		ORDER BY m.created_at ASC
+	`
+	rows, err := r.db.Query(query, retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	merges := []models.ItemMerge{}
+	for rows.Next() {
+		merge, err := scanItemMerge(rows)
+		if err != nil {
+			return nil, err
+		}
+		merges = append(merges, *merge)
+	}
+
+	return merges, rows.Err()
+}
+
+func (r *RetrospectiveRepository) GetItemMergeByID(mergeID uuid.UUID) (*models.ItemMerge, error) {
+	query := `
+		SELECT ` + itemMergeColumns + `
+		FROM retrospective_item_merges m
+		JOIN retrospective_items i ON i.id = m.source_item_id
+		WHERE m.id = $1
+	`
+	return scanItemMerge(r.db.QueryRow(query, mergeID))
+}
+
+// UnmergeItems undoes a merge: the target item gets back its content and its
+// votes before the merge, plus the votes it got since, and its category unless
+// it was moved since, and the source item is shown again. Only the last merge
+// into an item can be undone.
+func (r *RetrospectiveRepository) UnmergeItems(mergeID uuid.UUID) (*models.RetrospectiveItem, *models.RetrospectiveItem, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var targetItemID, sourceItemID uuid.UUID
+	var targetContent, targetCategory, categoryAfterMerge string
+	var targetVotes, votesAfterMerge int
+	err = tx.QueryRow(`
+		SELECT target_item_id, source_item_id, target_content, target_votes, merged_votes, target_category, merged_category
+		FROM retrospective_item_merges WHERE id = $1 FOR UPDATE
+	`, mergeID).Scan(&targetItemID, &sourceItemID, &targetContent, &targetVotes, &votesAfterMerge, &targetCategory, &categoryAfterMerge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var lastMergeID uuid.UUID
+	err = tx.QueryRow(`
+		SELECT id FROM retrospective_item_merges WHERE target_item_id = $1
+		ORDER BY created_at DESC LIMIT 1
+	`, targetItemID).Scan(&lastMergeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lastMergeID != mergeID {
+		return nil, nil, errors.New("undo the later merges into this item first")
+	}
+
+	query := `SELECT ` + itemColumns + ` FROM retrospective_items WHERE id = $1 FOR UPDATE`
+	targetItem, err := scanItem(tx.QueryRow(query, targetItemID))
+	if err != nil {
+		return nil, nil, err
+	}
+	if targetItem.MergedIntoID != nil {
+		return nil, nil, errors.New("item was merged into another item")
+	}
+
+	// Votes cast on the merged item stay with the target
+	targetItem.Content = targetContent
+	targetItem.Votes = targetVotes + targetItem.Votes - votesAfterMerge
+	if targetItem.Votes < 0 {
+		targetItem.Votes = 0
+	}
+	if targetItem.Category == categoryAfterMerge {
+		targetItem.Category = targetCategory
+	}
+	err = tx.QueryRow(`UPDATE retrospective_items SET content = $1, votes = $2, category = $3, updated_at = NOW() WHERE id = $4 RETURNING updated_at`,
+		targetItem.Content, targetItem.Votes, targetItem.Category, targetItemID).Scan(&targetItem.UpdatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sourceItem, err := scanItem(tx.QueryRow(`
+		UPDATE retrospective_items SET merged_into_id = NULL, updated_at = NOW() WHERE id = $1
+		RETURNING `+itemColumns, sourceItemID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM retrospective_item_merges WHERE id = $1`, mergeID); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return targetItem, sourceItem, nil
+}
+
+// Discussion note methods
+const discussionNoteColumns = `n.id, n.retrospective_id, n.item_id, n.group_id, n.content, n.outcome, n.version, n.updated_by, n.created_at, n.updated_at`
+
+func scanDiscussionNote(scanner interface{ Scan(...interface{}) error }) (*models.DiscussionNote, error) {
+	var note models.DiscussionNote
+	err := scanner.Scan(
+		&note.ID, &note.RetrospectiveID, &note.ItemID, &note.GroupID, &note.Content, &note.Outcome,
+		&note.Version, &note.UpdatedBy, &note.CreatedAt, &note.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	// Insert group items
-	for _, itemID := range itemIDs {
-		itemQuery := `
-			INSERT INTO retrospective_group_items (id, group_id, item_id)
-			VALUES ($1, $2, $3)
-		`
-		_, err = tx.Exec(itemQuery, uuid.New(), group.ID, itemID)
-		if err != nil {
-			return err
-		}
-	}
+	return &note, nil
+}
 
-	return tx.Commit()
+// noteSubjectColumn returns the column of the note with the ID of its subject
+func noteSubjectColumn(subject models.NoteSubject) string {
+	if subject == models.NoteSubjectGroup {
+		return "group_id"
+	}
+	return "item_id"
 }
 
-func (r *RetrospectiveRepository) GetGroupsByRetrospectiveID(retrospectiveID uuid.UUID) ([]models.RetrospectiveGroup, error) {
+// GetDiscussionNotes returns the notes of the retrospective, leaving out the
+// ones of items merged into another item
+func (r *RetrospectiveRepository) GetDiscussionNotes(retrospectiveID uuid.UUID) ([]models.DiscussionNote, error) {
 	query := `
-		SELECT id, retrospective_id, name, description, created_by, created_at, updated_at, votes
-		FROM retrospective_groups
-		WHERE retrospective_id = $1
-		ORDER BY created_at ASC
+		SELECT ` + discussionNoteColumns + `
+		FROM discussion_notes n
+		LEFT JOIN retrospective_items i ON i.id = n.item_id
+		WHERE n.retrospective_id = $1 AND i.merged_into_id IS NULL
+		ORDER BY n.created_at ASC
 	`
 	rows, err := r.db.Query(query, retrospectiveID)
 	if err != nil {
@@ -609,147 +1871,155 @@ func (r *RetrospectiveRepository) GetGroupsByRetrospectiveID(retrospectiveID uui
 	}
 	defer rows.Close()
 
-	var groups []models.RetrospectiveGroup
+	notes := []models.DiscussionNote{}
 	for rows.Next() {
-		var group models.RetrospectiveGroup
-		err := rows.Scan(&group.ID, &group.RetrospectiveID, &group.Name, &group.Description,
-			&group.CreatedBy, &group.CreatedAt, &group.UpdatedAt, &group.Votes)
+		note, err := scanDiscussionNote(rows)
 		if err != nil {
 			return nil, err
 		}
-		groups = append(groups, group)
+		notes = append(notes, *note)
 	}
 
-	return groups, nil
+	return notes, rows.Err()
 }
 
-func (r *RetrospectiveRepository) VoteGroup(groupID, userID uuid.UUID) error {
-	// Check if user already voted
-	var exists bool
-	checkQuery := `SELECT EXISTS(SELECT 1 FROM retrospective_group_votes WHERE group_id = $1 AND user_id = $2)`
-	err := r.db.QueryRow(checkQuery, groupID, userID).Scan(&exists)
-	if err != nil {
-		return err
-	}
+// GetDiscussionNote returns the note of the item or group
+func (r *RetrospectiveRepository) GetDiscussionNote(subject models.NoteSubject, subjectID uuid.UUID) (*models.DiscussionNote, error) {
+	query := `SELECT ` + discussionNoteColumns + ` FROM discussion_notes n WHERE n.` + noteSubjectColumn(subject) + ` = $1`
+	return scanDiscussionNote(r.db.QueryRow(query, subjectID))
+}
 
-	if exists {
-		// Remove vote
-		_, err = r.db.Exec(`DELETE FROM retrospective_group_votes WHERE group_id = $1 AND user_id = $2`, groupID, userID)
-		if err != nil {
-			return err
-		}
-		// Decrease vote count
-		_, err = r.db.Exec(`UPDATE retrospective_groups SET votes = votes - 1 WHERE id = $1`, groupID)
+// SaveDiscussionNote creates the note of its item or group when version is 0,
+// or updates it when it is still at version. It returns sql.ErrNoRows when the
+// note was created or saved by someone else meanwhile.
+func (r *RetrospectiveRepository) SaveDiscussionNote(note *models.DiscussionNote, version int) error {
+	var row *sql.Row
+	if version == 0 {
+		row = r.db.QueryRow(`
+			INSERT INTO discussion_notes (id, retrospective_id, item_id, group_id, content, outcome, updated_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT DO NOTHING
+			RETURNING id, version, created_at, updated_at
+		`, uuid.New(), note.RetrospectiveID, note.ItemID, note.GroupID, note.Content, note.Outcome, note.UpdatedBy)
 	} else {
-		// Add vote
-		_, err = r.db.Exec(`INSERT INTO retrospective_group_votes (id, group_id, user_id) VALUES ($1, $2, $3)`, uuid.New(), groupID, userID)
-		if err != nil {
-			return err
+		subject, subjectID := models.NoteSubjectItem, note.ItemID
+		if note.GroupID != nil {
+			subject, subjectID = models.NoteSubjectGroup, note.GroupID
 		}
-		// Increase vote count
-		_, err = r.db.Exec(`UPDATE retrospective_groups SET votes = votes + 1 WHERE id = $1`, groupID)
+		row = r.db.QueryRow(`
+			UPDATE discussion_notes SET content = $1, outcome = $2, updated_by = $3, version = version + 1, updated_at = NOW()
+			WHERE `+noteSubjectColumn(subject)+` = $4 AND version = $5
+			RETURNING id, version, created_at, updated_at
+		`, note.Content, note.Outcome, note.UpdatedBy, subjectID, version)
 	}
 
-	return err
+	return row.Scan(&note.ID, &note.Version, &note.CreatedAt, &note.UpdatedAt)
 }
 
-func (r *RetrospectiveRepository) GetGroupByID(groupID uuid.UUID) (*models.RetrospectiveGroup, error) {
-	query := `
-		SELECT id, retrospective_id, name, description, votes, created_by, created_at, updated_at
-		FROM retrospective_groups
-		WHERE id = $1
-	`
-	var group models.RetrospectiveGroup
-	err := r.db.QueryRow(query, groupID).Scan(
-		&group.ID, &group.RetrospectiveID, &group.Name, &group.Description,
-		&group.Votes, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt,
-	)
+// GetCurrentTopic returns the topic being discussed in the retrospective.
+// Deleting the item or group being discussed sets its ID to NULL and leaves
+// the start time behind, so there is no start time without a topic.
+func (r *RetrospectiveRepository) GetCurrentTopic(retrospectiveID uuid.UUID) (*models.CurrentTopic, error) {
+	topic := &models.CurrentTopic{}
+	err := r.db.QueryRow(`
+		SELECT current_topic_item_id, current_topic_group_id,
+			CASE WHEN current_topic_item_id IS NULL AND current_topic_group_id IS NULL THEN NULL ELSE current_topic_started_at END
+		FROM retrospectives WHERE id = $1
+	`, retrospectiveID).Scan(&topic.ItemID, &topic.GroupID, &topic.StartedAt)
 	if err != nil {
 		return nil, err
 	}
-	return &group, nil
-}
 
-func (r *RetrospectiveRepository) DeleteGroup(groupID uuid.UUID) error {
-	query := `DELETE FROM retrospective_groups WHERE id = $1`
-	_, err := r.db.Exec(query, groupID)
-	return err
+	return topic, nil
 }
 
-func (r *RetrospectiveRepository) MergeItems(sourceItemID, targetItemID uuid.UUID) (*models.RetrospectiveItem, error) {
-	tx, err := r.db.Begin()
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
-	// Get both items
-	var sourceItem, targetItem models.RetrospectiveItem
-	sourceQuery := `SELECT id, retrospective_id, category, content, author_id, is_anonymous, votes, created_at, updated_at FROM retrospective_items WHERE id = $1`
-	targetQuery := `SELECT id, retrospective_id, category, content, author_id, is_anonymous, votes, created_at, updated_at FROM retrospective_items WHERE id = $1`
+// SetCurrentTopic sets the item or group being discussed in the retrospective,
+// starting its discussion now, or clears it when both IDs are nil
+func (r *RetrospectiveRepository) SetCurrentTopic(retrospectiveID uuid.UUID, topic *models.CurrentTopic) error {
+	return r.db.QueryRow(`
+		UPDATE retrospectives
+		SET current_topic_item_id = $2, current_topic_group_id = $3,
+			current_topic_started_at = CASE WHEN $2::uuid IS NULL AND $3::uuid IS NULL THEN NULL ELSE NOW() END,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING current_topic_started_at
+	`, retrospectiveID, topic.ItemID, topic.GroupID).Scan(&topic.StartedAt)
+}
 
-	err = tx.QueryRow(sourceQuery, sourceItemID).Scan(
-		&sourceItem.ID, &sourceItem.RetrospectiveID, &sourceItem.Category, &sourceItem.Content,
-		&sourceItem.AuthorID, &sourceItem.IsAnonymous, &sourceItem.Votes,
-		&sourceItem.CreatedAt, &sourceItem.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
+// Action Item methods
+// GetAssignableUsers returns the active users who can be assigned action items
+// of the retrospective: its creator, its participants and its team members
+func (r *RetrospectiveRepository) GetAssignableUsers(retrospectiveID uuid.UUID) ([]models.AssignableUser, error) {
+	query := `
+		SELECT u.id, u.name, u.email, u.avatar,
+			u.id = r.created_by OR EXISTS (
+				SELECT 1 FROM retrospective_participants p WHERE p.retrospective_id = r.id AND p.user_id = u.id
+			),
+			EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = r.team_id AND tm.user_id = u.id)
+		FROM retrospectives r
+		JOIN users u ON u.id = r.created_by
+			OR u.id IN (SELECT user_id FROM retrospective_participants WHERE retrospective_id = r.id)
+			OR u.id IN (SELECT user_id FROM team_members WHERE team_id = r.team_id)
+		WHERE r.id = $1 AND u.suspended_at IS NULL
+		ORDER BY u.name ASC, u.id ASC
+	`
 
-	err = tx.QueryRow(targetQuery, targetItemID).Scan(
-		&targetItem.ID, &targetItem.RetrospectiveID, &targetItem.Category, &targetItem.Content,
-		&targetItem.AuthorID, &targetItem.IsAnonymous, &targetItem.Votes,
-		&targetItem.CreatedAt, &targetItem.UpdatedAt,
-	)
+	rows, err := r.db.Query(query, retrospectiveID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	// Check if items belong to same retrospective and category
-	if sourceItem.RetrospectiveID != targetItem.RetrospectiveID {
-		return nil, errors.New("items must belong to the same retrospective")
-	}
-	if sourceItem.Category != targetItem.Category {
-		return nil, errors.New("items must belong to the same category")
+	users := []models.AssignableUser{}
+	for rows.Next() {
+		var user models.AssignableUser
+		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Avatar, &user.IsParticipant, &user.IsTeamMember)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
 	}
 
-	// Merge content (combine both contents)
-	mergedContent := targetItem.Content + " | " + sourceItem.Content
-
-	// Discard votes when merging (reset to 0)
-	mergedVotes := 0
+	return users, rows.Err()
+}
 
-	// Update target item with merged content and votes
-	updateQuery := `UPDATE retrospective_items SET content = $1, votes = $2, updated_at = NOW() WHERE id = $3 RETURNING created_at, updated_at`
-	err = tx.QueryRow(updateQuery, mergedContent, mergedVotes, targetItemID).Scan(&targetItem.CreatedAt, &targetItem.UpdatedAt)
-	if err != nil {
-		return nil, err
-	}
+// GetTeamAssignableUsers returns the active users who could be assigned
+// action items of a new retrospective of the team created by the user: the
+// user and the team members. teamID may be nil for retrospectives without
+// a team.
+func (r *RetrospectiveRepository) GetTeamAssignableUsers(teamID *uuid.UUID, userID uuid.UUID) ([]models.AssignableUser, error) {
+	query := `
+		SELECT u.id, u.name, u.email, u.avatar, u.id = $2,
+			EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = $1 AND tm.user_id = u.id)
+		FROM users u
+		WHERE (u.id = $2 OR u.id IN (SELECT user_id FROM team_members WHERE team_id = $1))
+			AND u.suspended_at IS NULL
+		ORDER BY u.name ASC, u.id ASC
+	`
 
-	// Delete source item
-	deleteQuery := `DELETE FROM retrospective_items WHERE id = $1`
-	_, err = tx.Exec(deleteQuery, sourceItemID)
+	rows, err := r.db.Query(query, teamID, userID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	// Update the target item content for return
-	targetItem.Content = mergedContent
-	targetItem.Votes = mergedVotes
-
-	err = tx.Commit()
-	if err != nil {
-		return nil, err
+	users := []models.AssignableUser{}
+	for rows.Next() {
+		var user models.AssignableUser
+		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Avatar, &user.IsParticipant, &user.IsTeamMember)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
 	}
 
-	return &targetItem, nil
+	return users, rows.Err()
 }
 
-// Action Item methods
 func (r *RetrospectiveRepository) GetActionItemByID(actionItemID uuid.UUID) (*models.ActionItem, error) {
 	query := `
-		SELECT id, retrospective_id, item_id, title, description, status, assigned_to, due_date, completed_at, created_by, created_at, updated_at
+		SELECT id, retrospective_id, item_id, title, description, status, assigned_to, due_date, completed_at, created_by, created_at, updated_at,
+		       external_provider, external_id, external_url, external_synced_at
 		FROM action_items
 		WHERE id = $1
 	`
@@ -768,6 +2038,10 @@ func (r *RetrospectiveRepository) GetActionItemByID(actionItemID uuid.UUID) (*mo
 		&actionItem.CreatedBy,
 		&actionItem.CreatedAt,
 		&actionItem.UpdatedAt,
+		&actionItem.ExternalProvider,
+		&actionItem.ExternalID,
+		&actionItem.ExternalURL,
+		&actionItem.ExternalSyncedAt,
 	)
 
 	if err != nil {
@@ -842,7 +2116,8 @@ func (r *RetrospectiveRepository) UpdateActionItem(actionItemID uuid.UUID, req *
 		UPDATE action_items 
 		SET %s 
 		WHERE id = $%d
-		RETURNING id, retrospective_id, item_id, title, description, status, assigned_to, due_date, completed_at, created_by, created_at, updated_at
+		RETURNING id, retrospective_id, item_id, title, description, status, assigned_to, due_date, completed_at, created_by, created_at, updated_at,
+		       external_provider, external_id, external_url, external_synced_at
 	`, strings.Join(setParts, ", "), argIndex)
 
 	var actionItem models.ActionItem
@@ -859,6 +2134,10 @@ func (r *RetrospectiveRepository) UpdateActionItem(actionItemID uuid.UUID, req *
 		&actionItem.CreatedBy,
 		&actionItem.CreatedAt,
 		&actionItem.UpdatedAt,
+		&actionItem.ExternalProvider,
+		&actionItem.ExternalID,
+		&actionItem.ExternalURL,
+		&actionItem.ExternalSyncedAt,
 	)
 
 	if err != nil {
@@ -874,6 +2153,283 @@ func (r *RetrospectiveRepository) DeleteActionItem(actionItemID uuid.UUID) error
 	return err
 }
 
+// SetActionItemExternalLink links the action item to the issue it was
+// exported to, in the given state. It returns sql.ErrNoRows when the action
+// item does not exist or is already linked.
+func (r *RetrospectiveRepository) SetActionItemExternalLink(actionItemID uuid.UUID, provider, externalID, url, externalStatus string) error {
+	query := `
+		UPDATE action_items
+		SET external_provider = $2, external_id = $3, external_url = $4, external_status = $5,
+		    external_synced_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND external_id IS NULL
+	`
+
+	result, err := r.db.Exec(query, actionItemID, provider, externalID, url, externalStatus)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetExternallyLinkedActionItems returns up to limit action items exported to
+// the provider, the least recently synced first
+func (r *RetrospectiveRepository) GetExternallyLinkedActionItems(provider string, limit int) ([]models.ActionItem, error) {
+	query := `
+		SELECT id, retrospective_id, item_id, title, description, assigned_to, status, due_date, completed_at, created_by, created_at, updated_at,
+		       external_provider, external_id, external_url, external_synced_at
+		FROM action_items
+		WHERE external_provider = $1 AND external_id IS NOT NULL
+		ORDER BY external_synced_at ASC NULLS FIRST, id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, provider, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	actionItems := []models.ActionItem{}
+	for rows.Next() {
+		var actionItem models.ActionItem
+		err := rows.Scan(
+			&actionItem.ID,
+			&actionItem.RetrospectiveID,
+			&actionItem.ItemID,
+			&actionItem.Title,
+			&actionItem.Description,
+			&actionItem.AssignedTo,
+			&actionItem.Status,
+			&actionItem.DueDate,
+			&actionItem.CompletedAt,
+			&actionItem.CreatedBy,
+			&actionItem.CreatedAt,
+			&actionItem.UpdatedAt,
+			&actionItem.ExternalProvider,
+			&actionItem.ExternalID,
+			&actionItem.ExternalURL,
+			&actionItem.ExternalSyncedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		actionItems = append(actionItems, actionItem)
+	}
+
+	return actionItems, rows.Err()
+}
+
+// GetActionItemExternalStatus returns the state of the linked issue at the
+// last sync, or nil when it is not known
+func (r *RetrospectiveRepository) GetActionItemExternalStatus(actionItemID uuid.UUID) (*string, error) {
+	var externalStatus *string
+	err := r.db.QueryRow(`SELECT external_status FROM action_items WHERE id = $1`, actionItemID).Scan(&externalStatus)
+	if err != nil {
+		return nil, err
+	}
+	return externalStatus, nil
+}
+
+// MarkActionItemSynced records when the status of the action item was last
+// compared with its issue, and the state the issue was in
+func (r *RetrospectiveRepository) MarkActionItemSynced(actionItemID uuid.UUID, syncedAt time.Time, externalStatus string) error {
+	_, err := r.db.Exec(`UPDATE action_items SET external_synced_at = $2, external_status = $3 WHERE id = $1`, actionItemID, syncedAt, externalStatus)
+	return err
+}
+
+// AddActionItemEvents appends entries to the history of action items
+func (r *RetrospectiveRepository) AddActionItemEvents(events []models.ActionItemEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO action_item_events (id, action_item_id, user_id, event_type, old_value, new_value, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	for i := range events {
+		event := &events[i]
+		if event.ID == uuid.Nil {
+			event.ID = uuid.New()
+		}
+		err := tx.QueryRow(query, event.ID, event.ActionItemID, event.UserID, event.EventType, event.OldValue, event.NewValue, event.Note).
+			Scan(&event.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetActionItemEvents returns the history of an action item, oldest first
+func (r *RetrospectiveRepository) GetActionItemEvents(actionItemID uuid.UUID) ([]models.ActionItemEvent, error) {
+	query := `
+		SELECT e.id, e.action_item_id, e.user_id, COALESCE(u.name, ''), e.event_type, e.old_value, e.new_value, e.note, e.created_at
+		FROM action_item_events e
+		LEFT JOIN users u ON u.id = e.user_id
+		WHERE e.action_item_id = $1
+		ORDER BY e.created_at ASC, e.id ASC
+	`
+
+	rows, err := r.db.Query(query, actionItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.ActionItemEvent{}
+	for rows.Next() {
+		var event models.ActionItemEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.ActionItemID,
+			&event.UserID,
+			&event.UserName,
+			&event.EventType,
+			&event.OldValue,
+			&event.NewValue,
+			&event.Note,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *RetrospectiveRepository) AddActionItemComment(comment *models.ActionItemComment) error {
+	query := `
+		WITH inserted AS (
+			INSERT INTO action_item_comments (id, action_item_id, parent_id, user_id, content)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING user_id, created_at, updated_at
+		)
+		SELECT COALESCE(u.name, ''), i.created_at, i.updated_at
+		FROM inserted i
+		LEFT JOIN users u ON u.id = i.user_id
+	`
+
+	return r.db.QueryRow(query, comment.ID, comment.ActionItemID, comment.ParentID, comment.UserID, comment.Content).
+		Scan(&comment.UserName, &comment.CreatedAt, &comment.UpdatedAt)
+}
+
+const actionItemCommentColumns = `
+	c.id, c.action_item_id, c.parent_id, c.user_id, COALESCE(u.name, ''), c.content, c.created_at, c.updated_at
+`
+
+func scanActionItemComment(scanner interface{ Scan(...interface{}) error }) (*models.ActionItemComment, error) {
+	var comment models.ActionItemComment
+	err := scanner.Scan(
+		&comment.ID,
+		&comment.ActionItemID,
+		&comment.ParentID,
+		&comment.UserID,
+		&comment.UserName,
+		&comment.Content,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// GetActionItemComments returns every comment of an action item, oldest first.
+// Replies are not nested; ParentID links them to their parent.
+func (r *RetrospectiveRepository) GetActionItemComments(actionItemID uuid.UUID) ([]models.ActionItemComment, error) {
+	query := `
+		SELECT ` + actionItemCommentColumns + `
+		FROM action_item_comments c
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.action_item_id = $1
+		ORDER BY c.created_at ASC, c.id ASC
+	`
+
+	rows, err := r.db.Query(query, actionItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []models.ActionItemComment{}
+	for rows.Next() {
+		comment, err := scanActionItemComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *comment)
+	}
+
+	return comments, rows.Err()
+}
+
+func (r *RetrospectiveRepository) GetActionItemCommentByID(id uuid.UUID) (*models.ActionItemComment, error) {
+	query := `
+		SELECT ` + actionItemCommentColumns + `
+		FROM action_item_comments c
+		LEFT JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1
+	`
+
+	return scanActionItemComment(r.db.QueryRow(query, id))
+}
+
+func (r *RetrospectiveRepository) UpdateActionItemComment(id uuid.UUID, content string) (*models.ActionItemComment, error) {
+	query := `
+		WITH updated AS (
+			UPDATE action_item_comments SET content = $2, updated_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + actionItemCommentColumns + `
+		FROM updated c
+		LEFT JOIN users u ON u.id = c.user_id
+	`
+
+	return scanActionItemComment(r.db.QueryRow(query, id, content))
+}
+
+// DeleteActionItemComment deletes a comment and, through the foreign key, its replies
+func (r *RetrospectiveRepository) DeleteActionItemComment(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM action_item_comments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 // ReopenRetrospective reopens a closed retrospective
 func (r *RetrospectiveRepository) ReopenRetrospective(id uuid.UUID) error {
 	query := `
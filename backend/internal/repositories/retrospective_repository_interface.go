@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"educ-retro/internal/models"
 
 	"github.com/google/uuid"
@@ -9,28 +11,69 @@ import (
 // RetrospectiveRepositoryInterface define a interface para o RetrospectiveRepository
 type RetrospectiveRepositoryInterface interface {
 	Create(retrospective *models.Retrospective) error
+	Import(retrospective *models.Retrospective, items []models.RetrospectiveItem, groups []models.RetrospectiveGroup, groupItems map[uuid.UUID][]uuid.UUID, actionItems []models.ActionItem, notes []models.DiscussionNote) error
 	GetByID(id uuid.UUID) (*models.Retrospective, error)
+	GetTeamRole(teamID, userID uuid.UUID) (string, error)
+	GetExportPolicy(id uuid.UUID) (models.ExportPolicy, error)
+	SetExportPolicy(id uuid.UUID, policy models.ExportPolicy) error
 	GetAllRetrospectives() ([]models.Retrospective, error)
+	GetScheduledRetrospectives(userID uuid.UUID, since time.Time) ([]models.Retrospective, error)
+	GetOrphanedRetrospectives() ([]models.Retrospective, error)
+	TransferOwnership(id, newOwnerID uuid.UUID) error
 	GetRetrospectiveWithDetails(id uuid.UUID) (*models.RetrospectiveWithDetails, error)
+	GetDueRetrospectives(now time.Time) ([]uuid.UUID, error)
+	StartRetrospective(id uuid.UUID) (bool, int, error)
 	Update(retrospective *models.Retrospective) error
 	Delete(id uuid.UUID) error
 	UpdateStatus(id uuid.UUID, status models.RetrospectiveStatus) error
-	GetRetrospectiveCount(id uuid.UUID) (int, error)
-	GetActionItemCount(id uuid.UUID) (int, error)
+	GetRetrospectiveStats(userID uuid.UUID) (*models.RetrospectiveStats, error)
+	ListActionItems(userID uuid.UUID, filter models.ActionItemFilter) ([]models.ActionItemWithRetrospective, int, error)
 	AddItem(item *models.RetrospectiveItem) error
 	VoteItem(itemID, userID uuid.UUID) error
 	AddActionItem(actionItem *models.ActionItem) error
+	SetCarryForward(retrospectiveID, actionItemID uuid.UUID, carryForward bool) error
+	GetCarryoverRetrospectiveIDs(actionItemID uuid.UUID) ([]uuid.UUID, error)
+	GetPendingReminders(overdueBefore, dueSoonBefore time.Time) ([]models.ActionItemReminder, error)
+	ClaimReminder(reminder *models.ActionItemReminder, channel string) (bool, error)
+	MarkReminderSent(reminder *models.ActionItemReminder) error
+	ReleaseReminder(reminder *models.ActionItemReminder) error
 	RegisterParticipant(retrospectiveID, userID uuid.UUID) error
 	GetParticipants(retrospectiveID uuid.UUID) ([]models.RetrospectiveParticipant, error)
 	GetItemByID(id uuid.UUID) (*models.RetrospectiveItem, error)
+	MoveItem(itemID uuid.UUID, category string) (*models.RetrospectiveItem, error)
 	DeleteItem(id uuid.UUID) error
 	ReopenRetrospective(id uuid.UUID) error
 	CreateGroup(group *models.RetrospectiveGroup, itemIDs []uuid.UUID) error
 	VoteGroup(groupID, userID uuid.UUID) error
 	GetGroupByID(id uuid.UUID) (*models.RetrospectiveGroup, error)
+	GetGroupItemIDs(retrospectiveID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+	UpdateGroup(group *models.RetrospectiveGroup) error
+	AddGroupItems(groupID uuid.UUID, itemIDs []uuid.UUID) ([]uuid.UUID, error)
+	RemoveGroupItem(groupID, itemID uuid.UUID) error
 	DeleteGroup(id uuid.UUID) error
-	MergeItems(sourceItemID, targetItemID uuid.UUID) (*models.RetrospectiveItem, error)
+	MergeItems(sourceItemID, targetItemID uuid.UUID, voteMode models.ItemMergeVoteMode, category string, userID uuid.UUID) (*models.RetrospectiveItem, *models.ItemMerge, error)
+	GetItemMerges(retrospectiveID uuid.UUID) ([]models.ItemMerge, error)
+	GetItemMergeByID(mergeID uuid.UUID) (*models.ItemMerge, error)
+	UnmergeItems(mergeID uuid.UUID) (*models.RetrospectiveItem, *models.RetrospectiveItem, error)
+	GetDiscussionNotes(retrospectiveID uuid.UUID) ([]models.DiscussionNote, error)
+	GetDiscussionNote(subject models.NoteSubject, subjectID uuid.UUID) (*models.DiscussionNote, error)
+	SaveDiscussionNote(note *models.DiscussionNote, version int) error
+	GetCurrentTopic(retrospectiveID uuid.UUID) (*models.CurrentTopic, error)
+	SetCurrentTopic(retrospectiveID uuid.UUID, topic *models.CurrentTopic) error
 	GetActionItemByID(id uuid.UUID) (*models.ActionItem, error)
+	GetAssignableUsers(retrospectiveID uuid.UUID) ([]models.AssignableUser, error)
+	GetTeamAssignableUsers(teamID *uuid.UUID, userID uuid.UUID) ([]models.AssignableUser, error)
 	UpdateActionItem(actionItemID uuid.UUID, req *models.ActionItemUpdateRequest) (*models.ActionItem, error)
 	DeleteActionItem(id uuid.UUID) error
+	SetActionItemExternalLink(actionItemID uuid.UUID, provider, externalID, url, externalStatus string) error
+	GetExternallyLinkedActionItems(provider string, limit int) ([]models.ActionItem, error)
+	GetActionItemExternalStatus(actionItemID uuid.UUID) (*string, error)
+	MarkActionItemSynced(actionItemID uuid.UUID, syncedAt time.Time, externalStatus string) error
+	AddActionItemEvents(events []models.ActionItemEvent) error
+	GetActionItemEvents(actionItemID uuid.UUID) ([]models.ActionItemEvent, error)
+	AddActionItemComment(comment *models.ActionItemComment) error
+	GetActionItemComments(actionItemID uuid.UUID) ([]models.ActionItemComment, error)
+	GetActionItemCommentByID(id uuid.UUID) (*models.ActionItemComment, error)
+	UpdateActionItemComment(id uuid.UUID, content string) (*models.ActionItemComment, error)
+	DeleteActionItemComment(id uuid.UUID) error
 }
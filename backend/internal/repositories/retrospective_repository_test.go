@@ -29,7 +29,7 @@ func TestRetrospectiveRepository_Create(t *testing.T) {
 	}
 
 	mock.ExpectQuery(`INSERT INTO retrospectives`).
-		WithArgs(sqlmock.AnyArg(), retrospective.Title, retrospective.Description, retrospective.Template, retrospective.Status, retrospective.CreatedBy).
+		WithArgs(sqlmock.AnyArg(), retrospective.Title, retrospective.Description, retrospective.Template, retrospective.Status, retrospective.CreatedBy, retrospective.TeamID, retrospective.ScheduledAt).
 		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).
 			AddRow(time.Now(), time.Now()))
 
@@ -57,7 +57,7 @@ func TestRetrospectiveRepository_Create_Error(t *testing.T) {
 	}
 
 	mock.ExpectQuery(`INSERT INTO retrospectives`).
-		WithArgs(sqlmock.AnyArg(), retrospective.Title, retrospective.Description, retrospective.Template, retrospective.Status, retrospective.CreatedBy).
+		WithArgs(sqlmock.AnyArg(), retrospective.Title, retrospective.Description, retrospective.Template, retrospective.Status, retrospective.CreatedBy, retrospective.TeamID, retrospective.ScheduledAt).
 		WillReturnError(sql.ErrConnDone)
 
 	err = repo.Create(retrospective)
@@ -224,3 +224,874 @@ func TestRetrospectiveRepository_ReopenRetrospective_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestRetrospectiveRepository_GetRetrospectiveStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	userID := uuid.New()
+
+	// Retrospectives are scoped by creator, participation or team membership (no INNER JOIN on teams)
+	mock.ExpectQuery(`WITH scoped AS .*retrospective_participants.*team_members.*SELECT status, COUNT\(\*\) FROM scoped GROUP BY status`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).
+			AddRow("active", 2).
+			AddRow("closed", 3))
+	mock.ExpectQuery(`WITH scoped AS .*SELECT\s+\(SELECT COUNT\(\*\) FROM scoped WHERE created_by = \$1\)`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"created", "joined", "participants", "items"}).AddRow(2, 4, 12, 30))
+	mock.ExpectQuery(`WITH scoped AS .*FROM action_items`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "todo", "in_progress", "done", "overdue"}).AddRow(6, 2, 1, 3, 1))
+
+	stats, err := repo.GetRetrospectiveStats(userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, stats.Total)
+	assert.Equal(t, 2, stats.ByStatus["active"])
+	assert.Equal(t, 3, stats.ByStatus["closed"])
+	assert.Equal(t, 0, stats.ByStatus["planned"])
+	assert.Equal(t, models.ParticipationStats{Created: 2, Joined: 4, TotalParticipants: 12, TotalItems: 30}, stats.Participation)
+	assert.Equal(t, models.ActionItemProgressStats{Total: 6, Todo: 2, InProgress: 1, Done: 3, Overdue: 1}, stats.ActionItems)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_TransferOwnership(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID := uuid.New()
+	newOwnerID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE retrospectives SET created_by = \$2`).
+		WithArgs(retroID, newOwnerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO retrospective_participants`).
+		WithArgs(sqlmock.AnyArg(), retroID, newOwnerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.TransferOwnership(retroID, newOwnerID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_ListActionItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	userID := uuid.New()
+	teamID := uuid.New()
+	retroID := uuid.New()
+	actionItemID := uuid.New()
+	dueDate := time.Now().Add(48 * time.Hour)
+
+	filter := models.ActionItemFilter{
+		AssignedTo: &userID,
+		Status:     []string{"todo", "in_progress"},
+		TeamID:     &teamID,
+		SortBy:     "title",
+		SortOrder:  "desc",
+		Limit:      10,
+		Offset:     20,
+	}
+
+	mock.ExpectQuery(`WITH scoped AS .*SELECT COUNT\(\*\)\s+FROM action_items a.*a.assigned_to = \$2 AND a.status IN \(\$3, \$4\) AND r.team_id = \$5`).
+		WithArgs(userID, userID, "todo", "in_progress", teamID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+	mock.ExpectQuery(`ORDER BY LOWER\(a.title\) DESC NULLS LAST, a.created_at ASC, a.id ASC\s+LIMIT \$6 OFFSET \$7`).
+		WithArgs(userID, userID, "todo", "in_progress", teamID, 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "retrospective_id", "item_id", "title", "description", "assigned_to", "status",
+			"due_date", "completed_at", "created_by", "created_at", "updated_at",
+			"external_provider", "external_id", "external_url", "external_synced_at",
+			"title", "template", "status", "team_id",
+		}).AddRow(
+			actionItemID, retroID, nil, "Automate deploy", nil, userID, "todo",
+			dueDate, nil, userID, time.Now(), time.Now(),
+			nil, nil, nil, nil,
+			"Sprint 12", "start_stop_continue", "closed", teamID,
+		))
+
+	actionItems, total, err := repo.ListActionItems(userID, filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 21, total)
+	assert.Len(t, actionItems, 1)
+	assert.Equal(t, actionItemID, actionItems[0].ID)
+	assert.Equal(t, retroID, actionItems[0].Retrospective.ID)
+	assert.Equal(t, "Sprint 12", actionItems[0].Retrospective.Title)
+	assert.Equal(t, &teamID, actionItems[0].Retrospective.TeamID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_StartRetrospective(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID := uuid.New()
+
+	// Unfinished items of the previous closed retrospective plus the ones carried forward into it
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE retrospectives\s+SET status = 'active', started_at = NOW\(\).*WHERE id = \$1 AND status = 'planned'`).
+		WithArgs(retroID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`WITH current AS .*previous AS .*r.status = 'closed'.*INSERT INTO action_item_carryovers.*a.status <> 'done'.*WHERE carry_forward.*ON CONFLICT \(retrospective_id, action_item_id\) DO NOTHING`).
+		WithArgs(retroID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	started, carried, err := repo.StartRetrospective(retroID)
+
+	assert.NoError(t, err)
+	assert.True(t, started)
+	assert.Equal(t, 3, carried)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_StartRetrospective_NotPlanned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID := uuid.New()
+
+	// Already started by someone else: nothing is carried over again
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE retrospectives\s+SET status = 'active'.*WHERE id = \$1 AND status = 'planned'`).
+		WithArgs(retroID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	started, carried, err := repo.StartRetrospective(retroID)
+
+	assert.NoError(t, err)
+	assert.False(t, started)
+	assert.Equal(t, 0, carried)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_ClaimReminder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	reminder := &models.ActionItemReminder{
+		Kind:         models.ReminderDueSoon,
+		ActionItemID: uuid.New(),
+		UserID:       uuid.New(),
+		DueDate:      time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+	}
+	claimQuery := `INSERT INTO action_item_reminders .*ON CONFLICT \(action_item_id, user_id, kind, due_date\) DO UPDATE.*sent_at IS NULL.*RETURNING id`
+
+	mock.ExpectQuery(claimQuery).
+		WithArgs(sqlmock.AnyArg(), reminder.ActionItemID, reminder.UserID, reminder.Kind, reminder.DueDate, "email").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+
+	claimed, err := repo.ClaimReminder(reminder, "email")
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	// Already sent or claimed by another run
+	mock.ExpectQuery(claimQuery).
+		WithArgs(sqlmock.AnyArg(), reminder.ActionItemID, reminder.UserID, reminder.Kind, reminder.DueDate, "email").
+		WillReturnError(sql.ErrNoRows)
+
+	claimed, err = repo.ClaimReminder(reminder, "email")
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_AddActionItemEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	userID := uuid.New()
+	actionItemID := uuid.New()
+	oldStatus, newStatus := "todo", "done"
+	events := []models.ActionItemEvent{
+		{ActionItemID: actionItemID, UserID: &userID, EventType: models.ActionItemEventStatusChanged, OldValue: &oldStatus, NewValue: &newStatus},
+		{ActionItemID: actionItemID, UserID: &userID, EventType: models.ActionItemEventReassigned, NewValue: &newStatus},
+	}
+
+	mock.ExpectBegin()
+	for _, event := range events {
+		mock.ExpectQuery(`INSERT INTO action_item_events`).
+			WithArgs(sqlmock.AnyArg(), actionItemID, &userID, event.EventType, event.OldValue, event.NewValue, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	}
+	mock.ExpectCommit()
+
+	err = repo.AddActionItemEvents(events)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, events[0].ID)
+	assert.False(t, events[1].CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_DeleteActionItemComment_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	commentID := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM action_item_comments WHERE id`).
+		WithArgs(commentID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteActionItemComment(commentID)
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_GetAssignableUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID := uuid.New()
+	creatorID := uuid.New()
+	memberID := uuid.New()
+
+	mock.ExpectQuery(`FROM retrospectives r\s+JOIN users u ON u.id = r.created_by.*retrospective_participants.*team_members.*u.suspended_at IS NULL`).
+		WithArgs(retroID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "avatar", "is_participant", "is_team_member"}).
+			AddRow(creatorID, "Ana", "ana@example.com", nil, true, false).
+			AddRow(memberID, "Bruno", "bruno@example.com", nil, false, true))
+
+	users, err := repo.GetAssignableUsers(retroID)
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.True(t, users[0].IsParticipant)
+	assert.True(t, users[1].IsTeamMember)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_GetGroupItemIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID := uuid.New()
+	groupID := uuid.New()
+	firstItemID := uuid.New()
+	secondItemID := uuid.New()
+
+	mock.ExpectQuery(`SELECT gi.group_id, gi.item_id\s+FROM retrospective_group_items gi\s+JOIN retrospective_groups g.*WHERE g.retrospective_id = \$1`).
+		WithArgs(retroID).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id", "item_id"}).
+			AddRow(groupID, firstItemID).
+			AddRow(groupID, secondItemID))
+
+	groupItems, err := repo.GetGroupItemIDs(retroID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{firstItemID, secondItemID}, groupItems[groupID])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_Import(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	now := time.Now()
+	userID := uuid.New()
+	retrospective := &models.Retrospective{ID: uuid.New(), Title: "Sprint 12", Template: models.TemplateStartStopContinue, Status: models.RetroStatusClosed, CreatedBy: userID, StartedAt: &now, EndedAt: &now}
+	item := models.RetrospectiveItem{ID: uuid.New(), RetrospectiveID: retrospective.ID, Category: "start", Content: "Pairing", Votes: 3}
+	group := models.RetrospectiveGroup{ID: uuid.New(), RetrospectiveID: retrospective.ID, Name: "Colaboração", Votes: 2, CreatedBy: userID}
+	actionItem := models.ActionItem{ID: uuid.New(), RetrospectiveID: retrospective.ID, Title: "Agendar pairing", Status: "done", CompletedAt: &now, CreatedBy: userID}
+	note := models.DiscussionNote{ID: uuid.New(), RetrospectiveID: retrospective.ID, GroupID: &group.ID, Content: "Mais pairing", UpdatedBy: &userID}
+	timestamps := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO retrospectives \(id, title, description, template, status, created_by, team_id, started_at, ended_at\)`).
+		WithArgs(retrospective.ID, "Sprint 12", nil, retrospective.Template, retrospective.Status, userID, nil, &now, &now).
+		WillReturnRows(timestamps())
+	mock.ExpectQuery(`INSERT INTO retrospective_items`).
+		WithArgs(item.ID, retrospective.ID, "start", "Pairing", nil, false, 3).
+		WillReturnRows(timestamps())
+	mock.ExpectQuery(`INSERT INTO retrospective_groups`).
+		WithArgs(group.ID, retrospective.ID, "Colaboração", nil, userID, 2).
+		WillReturnRows(timestamps())
+	mock.ExpectExec(`INSERT INTO retrospective_group_items`).
+		WithArgs(sqlmock.AnyArg(), group.ID, item.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO action_items \(.*completed_at, created_by\)`).
+		WithArgs(actionItem.ID, retrospective.ID, nil, "Agendar pairing", nil, nil, "done", nil, &now, userID).
+		WillReturnRows(timestamps())
+	mock.ExpectQuery(`INSERT INTO discussion_notes`).
+		WithArgs(note.ID, retrospective.ID, nil, &group.ID, "Mais pairing", "", &userID).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "created_at", "updated_at"}).AddRow(1, now, now))
+	mock.ExpectCommit()
+
+	err = repo.Import(retrospective, []models.RetrospectiveItem{item}, []models.RetrospectiveGroup{group},
+		map[uuid.UUID][]uuid.UUID{group.ID: {item.ID}}, []models.ActionItem{actionItem}, []models.DiscussionNote{note})
+
+	assert.NoError(t, err)
+	assert.Equal(t, now, retrospective.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_Import_RollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retrospective := &models.Retrospective{ID: uuid.New(), Title: "Sprint 12", CreatedBy: uuid.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO retrospectives`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+	mock.ExpectQuery(`INSERT INTO retrospective_items`).
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	err = repo.Import(retrospective, []models.RetrospectiveItem{{ID: uuid.New(), RetrospectiveID: retrospective.ID}}, nil, nil, nil, nil)
+
+	assert.Equal(t, sql.ErrConnDone, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_GetTeamAssignableUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	teamID := uuid.New()
+	userID := uuid.New()
+
+	mock.ExpectQuery(`FROM users u\s+WHERE \(u.id = \$2 OR u.id IN \(SELECT user_id FROM team_members WHERE team_id = \$1\)\)\s+AND u.suspended_at IS NULL`).
+		WithArgs(&teamID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "avatar", "is_participant", "is_team_member"}).
+			AddRow(userID, "Ana", "ana@example.com", nil, true, true))
+
+	users, err := repo.GetTeamAssignableUsers(&teamID, userID)
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_SetActionItemExternalLink(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	actionItemID := uuid.New()
+	linkQuery := `UPDATE action_items\s+SET external_provider = \$2, external_id = \$3, external_url = \$4, external_status = \$5.*WHERE id = \$1 AND external_id IS NULL`
+
+	mock.ExpectExec(linkQuery).
+		WithArgs(actionItemID, "jira", "RETRO-7", "https://example.atlassian.net/browse/RETRO-7", "todo").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SetActionItemExternalLink(actionItemID, "jira", "RETRO-7", "https://example.atlassian.net/browse/RETRO-7", "todo")
+	assert.NoError(t, err)
+
+	// Already linked
+	mock.ExpectExec(linkQuery).
+		WithArgs(actionItemID, "jira", "RETRO-8", "https://example.atlassian.net/browse/RETRO-8", "todo").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.SetActionItemExternalLink(actionItemID, "jira", "RETRO-8", "https://example.atlassian.net/browse/RETRO-8", "todo")
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_GetDueRetrospectives(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	now := time.Now()
+	retroID := uuid.New()
+
+	mock.ExpectQuery(`SELECT id FROM retrospectives\s+WHERE status = 'planned' AND scheduled_at <= \$1`).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(retroID))
+
+	ids, err := repo.GetDueRetrospectives(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{retroID}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_ExportPolicy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retrospectiveID := uuid.New()
+
+	mock.ExpectQuery(`SELECT export_policy FROM retrospectives WHERE id = \$1`).
+		WithArgs(retrospectiveID).
+		WillReturnRows(sqlmock.NewRows([]string{"export_policy"}).AddRow("participants"))
+	mock.ExpectExec(`UPDATE retrospectives SET export_policy = \$2, updated_at = NOW\(\) WHERE id = \$1`).
+		WithArgs(retrospectiveID, models.ExportPolicyTeam).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	policy, err := repo.GetExportPolicy(retrospectiveID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ExportPolicyParticipants, policy)
+
+	err = repo.SetExportPolicy(retrospectiveID, models.ExportPolicyTeam)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_AddGroupItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	groupID := uuid.New()
+	previousGroupID := uuid.New()
+	itemID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM retrospective_group_items\s+WHERE item_id = \$1 AND group_id <> \$2\s+RETURNING group_id`).
+		WithArgs(itemID, groupID).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id"}).AddRow(previousGroupID))
+	mock.ExpectExec(`INSERT INTO retrospective_group_items \(id, group_id, item_id\)\s+VALUES \(\$1, \$2, \$3\)\s+ON CONFLICT \(group_id, item_id\) DO NOTHING`).
+		WithArgs(sqlmock.AnyArg(), groupID, itemID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE retrospective_groups SET updated_at = NOW\(\) WHERE id = \$1`).
+		WithArgs(groupID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE retrospective_groups SET updated_at = NOW\(\) WHERE id = \$1`).
+		WithArgs(previousGroupID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	leftGroups, err := repo.AddGroupItems(groupID, []uuid.UUID{itemID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{previousGroupID}, leftGroups)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_UpdateGroup(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	now := time.Now()
+	group := &models.RetrospectiveGroup{ID: uuid.New(), Name: "Entrega contínua"}
+
+	mock.ExpectQuery(`UPDATE retrospective_groups SET name = \$2, description = \$3, updated_at = NOW\(\)\s+WHERE id = \$1\s+RETURNING updated_at`).
+		WithArgs(group.ID, "Entrega contínua", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
+
+	err = repo.UpdateGroup(group)
+
+	assert.NoError(t, err)
+	assert.Equal(t, now, group.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_RemoveGroupItem_NotInGroup(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	groupID := uuid.New()
+	itemID := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM retrospective_group_items WHERE group_id = \$1 AND item_id = \$2`).
+		WithArgs(groupID, itemID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.RemoveGroupItem(groupID, itemID)
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func itemRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "retrospective_id", "category", "content", "author_id", "is_anonymous", "votes", "merged_into_id", "created_at", "updated_at"})
+}
+
+func TestRetrospectiveRepository_MergeItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, sourceID, targetID, userID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(sourceID).
+		WillReturnRows(itemRows().AddRow(sourceID, retroID, "stop", "Pipeline lento", userID, false, 3, nil, now, now))
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(targetID).
+		WillReturnRows(itemRows().AddRow(targetID, retroID, "stop", "Deploy manual", nil, true, 2, nil, now, now))
+	mock.ExpectQuery(`INSERT INTO retrospective_item_merges`).
+		WithArgs(sqlmock.AnyArg(), retroID, targetID, sourceID, models.ItemMergeVotesMax, "Deploy manual", 2, 3, "stop", "stop", userID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+	mock.ExpectQuery(`UPDATE retrospective_items SET content = \$1, votes = \$2, category = \$3, updated_at = NOW\(\) WHERE id = \$4`).
+		WithArgs("Deploy manual | Pipeline lento", 3, "stop", targetID).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
+	mock.ExpectExec(`UPDATE retrospective_items SET merged_into_id = \$1, updated_at = NOW\(\) WHERE id = \$2`).
+		WithArgs(targetID, sourceID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	merged, merge, err := repo.MergeItems(sourceID, targetID, models.ItemMergeVotesMax, "", userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, merged.Votes)
+	assert.Equal(t, "Deploy manual | Pipeline lento", merged.Content)
+	assert.Equal(t, 2, merge.TargetVotes)
+	assert.Equal(t, "Pipeline lento", merge.SourceItem.Content)
+	assert.Equal(t, &targetID, merge.SourceItem.MergedIntoID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_MergeItems_AlreadyMerged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, sourceID, targetID, otherID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(sourceID).
+		WillReturnRows(itemRows().AddRow(sourceID, retroID, "stop", "Pipeline lento", nil, false, 3, otherID, now, now))
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(targetID).
+		WillReturnRows(itemRows().AddRow(targetID, retroID, "stop", "Deploy manual", nil, false, 2, nil, now, now))
+	mock.ExpectRollback()
+
+	_, _, err = repo.MergeItems(sourceID, targetID, models.ItemMergeVotesSum, "", uuid.New())
+
+	assert.EqualError(t, err, "item was merged into another item")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_MergeItems_CrossCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, sourceID, targetID, userID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(sourceID).
+		WillReturnRows(itemRows().AddRow(sourceID, retroID, "start", "Testes automatizados", userID, false, 1, nil, now, now))
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(targetID).
+		WillReturnRows(itemRows().AddRow(targetID, retroID, "stop", "Deploy manual", nil, false, 2, nil, now, now))
+	mock.ExpectQuery(`INSERT INTO retrospective_item_merges`).
+		WithArgs(sqlmock.AnyArg(), retroID, targetID, sourceID, models.ItemMergeVotesSum, "Deploy manual", 2, 3, "stop", "start", userID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+	mock.ExpectQuery(`UPDATE retrospective_items SET content = \$1, votes = \$2, category = \$3, updated_at = NOW\(\) WHERE id = \$4`).
+		WithArgs("Deploy manual | Testes automatizados", 3, "start", targetID).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
+	mock.ExpectExec(`UPDATE retrospective_items SET merged_into_id = \$1, updated_at = NOW\(\) WHERE id = \$2`).
+		WithArgs(targetID, sourceID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	merged, merge, err := repo.MergeItems(sourceID, targetID, models.ItemMergeVotesSum, "start", userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "start", merged.Category)
+	assert.Equal(t, "stop", merge.TargetCategory)
+	assert.Equal(t, "start", merge.MergedCategory)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_MergeItems_DifferentCategories(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, sourceID, targetID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(sourceID).
+		WillReturnRows(itemRows().AddRow(sourceID, retroID, "start", "Testes automatizados", nil, false, 1, nil, now, now))
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(targetID).
+		WillReturnRows(itemRows().AddRow(targetID, retroID, "stop", "Deploy manual", nil, false, 2, nil, now, now))
+	mock.ExpectRollback()
+
+	_, _, err = repo.MergeItems(sourceID, targetID, models.ItemMergeVotesSum, "", uuid.New())
+
+	assert.EqualError(t, err, "items must belong to the same category")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_MoveItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, itemID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE retrospective_items SET category = \$2, updated_at = NOW\(\)\s+WHERE id = \$1 AND merged_into_id IS NULL\s+RETURNING`).
+		WithArgs(itemID, "continue").
+		WillReturnRows(itemRows().AddRow(itemID, retroID, "continue", "Pair programming", nil, false, 4, nil, now, now))
+
+	item, err := repo.MoveItem(itemID, "continue")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "continue", item.Category)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_DeleteItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	itemID := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM retrospective_items\s+WHERE id = \$1 AND NOT EXISTS \(SELECT 1 FROM retrospective_items WHERE merged_into_id = \$1\)`).
+		WithArgs(itemID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.DeleteItem(itemID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_DeleteItem_MergedItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	itemID := uuid.New()
+
+	// Other items were merged into the item: deleting it would delete them too
+	mock.ExpectExec(`DELETE FROM retrospective_items`).
+		WithArgs(itemID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.EqualError(t, repo.DeleteItem(itemID), "item has merged items, unmerge first")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_UnmergeItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, mergeID, sourceID, targetID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT target_item_id, source_item_id, target_content, target_votes, merged_votes, target_category, merged_category\s+FROM retrospective_item_merges WHERE id = \$1 FOR UPDATE`).
+		WithArgs(mergeID).
+		WillReturnRows(sqlmock.NewRows([]string{"target_item_id", "source_item_id", "target_content", "target_votes", "merged_votes", "target_category", "merged_category"}).
+			AddRow(targetID, sourceID, "Deploy manual", 2, 0, "stop", "start"))
+	mock.ExpectQuery(`SELECT id FROM retrospective_item_merges WHERE target_item_id = \$1\s+ORDER BY created_at DESC LIMIT 1`).
+		WithArgs(targetID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mergeID))
+	mock.ExpectQuery(`SELECT .* FROM retrospective_items WHERE id = \$1 FOR UPDATE`).
+		WithArgs(targetID).
+		WillReturnRows(itemRows().AddRow(targetID, retroID, "start", "Deploy manual | Pipeline lento", nil, false, 1, nil, now, now))
+	// One vote was cast after a merge that reset the votes, and the merge
+	// moved the target to another category
+	mock.ExpectQuery(`UPDATE retrospective_items SET content = \$1, votes = \$2, category = \$3, updated_at = NOW\(\) WHERE id = \$4`).
+		WithArgs("Deploy manual", 3, "stop", targetID).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
+	mock.ExpectQuery(`UPDATE retrospective_items SET merged_into_id = NULL, updated_at = NOW\(\) WHERE id = \$1\s+RETURNING`).
+		WithArgs(sourceID).
+		WillReturnRows(itemRows().AddRow(sourceID, retroID, "stop", "Pipeline lento", nil, false, 3, nil, now, now))
+	mock.ExpectExec(`DELETE FROM retrospective_item_merges WHERE id = \$1`).
+		WithArgs(mergeID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	target, source, err := repo.UnmergeItems(mergeID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, target.Votes)
+	assert.Equal(t, "Deploy manual", target.Content)
+	assert.Equal(t, "stop", target.Category)
+	assert.Equal(t, "Pipeline lento", source.Content)
+	assert.Nil(t, source.MergedIntoID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_UnmergeItems_LaterMerge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	mergeID, targetID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT target_item_id, source_item_id, target_content, target_votes, merged_votes`).
+		WithArgs(mergeID).
+		WillReturnRows(sqlmock.NewRows([]string{"target_item_id", "source_item_id", "target_content", "target_votes", "merged_votes", "target_category", "merged_category"}).
+			AddRow(targetID, uuid.New(), "Deploy manual", 2, 5, "stop", "stop"))
+	mock.ExpectQuery(`SELECT id FROM retrospective_item_merges WHERE target_item_id = \$1`).
+		WithArgs(targetID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectRollback()
+
+	_, _, err = repo.UnmergeItems(mergeID)
+
+	assert.EqualError(t, err, "undo the later merges into this item first")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func noteRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "retrospective_id", "item_id", "group_id", "content", "outcome", "version", "updated_by", "created_at", "updated_at"})
+}
+
+func TestRetrospectiveRepository_GetDiscussionNotes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, itemID, groupID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM discussion_notes n\s+LEFT JOIN retrospective_items i ON i.id = n.item_id\s+WHERE n.retrospective_id = \$1 AND i.merged_into_id IS NULL`).
+		WithArgs(retroID).
+		WillReturnRows(noteRows().
+			AddRow(uuid.New(), retroID, itemID, nil, "Deploy leva 2h", "Automatizar", 3, nil, now, now).
+			AddRow(uuid.New(), retroID, nil, groupID, "", "Cache de dependências", 1, nil, now, now))
+
+	notes, err := repo.GetDiscussionNotes(retroID)
+
+	assert.NoError(t, err)
+	assert.Len(t, notes, 2)
+	assert.Equal(t, &itemID, notes[0].ItemID)
+	assert.Equal(t, 3, notes[0].Version)
+	assert.Equal(t, &groupID, notes[1].GroupID)
+	assert.Nil(t, notes[1].ItemID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_SaveDiscussionNote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, groupID, userID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	note := &models.DiscussionNote{RetrospectiveID: retroID, GroupID: &groupID, Content: "Pipeline lento", UpdatedBy: &userID}
+
+	mock.ExpectQuery(`INSERT INTO discussion_notes .*\s+ON CONFLICT DO NOTHING\s+RETURNING id, version, created_at, updated_at`).
+		WithArgs(sqlmock.AnyArg(), retroID, nil, &groupID, "Pipeline lento", "", &userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).AddRow(uuid.New(), 1, now, now))
+
+	assert.NoError(t, repo.SaveDiscussionNote(note, 0))
+	assert.Equal(t, 1, note.Version)
+
+	note.Outcome = "Cache de dependências"
+	mock.ExpectQuery(`UPDATE discussion_notes SET content = \$1, outcome = \$2, updated_by = \$3, version = version \+ 1, updated_at = NOW\(\)\s+WHERE group_id = \$4 AND version = \$5`).
+		WithArgs("Pipeline lento", "Cache de dependências", &userID, &groupID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).AddRow(note.ID, 2, now, now))
+
+	assert.NoError(t, repo.SaveDiscussionNote(note, 1))
+	assert.Equal(t, 2, note.Version)
+
+	// Saved by someone else meanwhile
+	mock.ExpectQuery(`UPDATE discussion_notes SET .*\s+WHERE group_id = \$4 AND version = \$5`).
+		WithArgs("Pipeline lento", "Cache de dependências", &userID, &groupID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}))
+
+	assert.Equal(t, sql.ErrNoRows, repo.SaveDiscussionNote(note, 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetrospectiveRepository_CurrentTopic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRetrospectiveRepository(db)
+	retroID, itemID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE retrospectives\s+SET current_topic_item_id = \$2, current_topic_group_id = \$3,\s+current_topic_started_at = CASE .* END,.*\s+WHERE id = \$1\s+RETURNING current_topic_started_at`).
+		WithArgs(retroID, &itemID, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"current_topic_started_at"}).AddRow(now))
+
+	topic := &models.CurrentTopic{ItemID: &itemID}
+	assert.NoError(t, repo.SetCurrentTopic(retroID, topic))
+	assert.Equal(t, now, *topic.StartedAt)
+
+	mock.ExpectQuery(`SELECT current_topic_item_id, current_topic_group_id,\s+CASE WHEN current_topic_item_id IS NULL AND current_topic_group_id IS NULL THEN NULL ELSE current_topic_started_at END\s+FROM retrospectives WHERE id = \$1`).
+		WithArgs(retroID).
+		WillReturnRows(sqlmock.NewRows([]string{"current_topic_item_id", "current_topic_group_id", "current_topic_started_at"}).AddRow(itemID, nil, now))
+
+	current, err := repo.GetCurrentTopic(retroID)
+	assert.NoError(t, err)
+	assert.Equal(t, itemID, *current.ItemID)
+	assert.Nil(t, current.GroupID)
+
+	// Clearing the topic clears when it started
+	mock.ExpectQuery(`UPDATE retrospectives\s+SET current_topic_item_id = \$2`).
+		WithArgs(retroID, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"current_topic_started_at"}).AddRow(nil))
+
+	topic = &models.CurrentTopic{}
+	assert.NoError(t, repo.SetCurrentTopic(retroID, topic))
+	assert.Nil(t, topic.StartedAt)
+
+	mock.ExpectQuery(`SELECT current_topic_item_id`).
+		WithArgs(retroID).
+		WillReturnRows(sqlmock.NewRows([]string{"current_topic_item_id", "current_topic_group_id", "current_topic_started_at"}))
+
+	_, err = repo.GetCurrentTopic(retroID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
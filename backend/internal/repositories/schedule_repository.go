@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type ScheduleRepository struct {
+	db *sql.DB
+}
+
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+const scheduleColumns = `id, team_id, title, description, template, interval_weeks, next_run_at, active, created_by, created_at, updated_at`
+
+func scanSchedule(scanner interface{ Scan(...interface{}) error }) (*models.RetrospectiveSchedule, error) {
+	var schedule models.RetrospectiveSchedule
+	err := scanner.Scan(
+		&schedule.ID,
+		&schedule.TeamID,
+		&schedule.Title,
+		&schedule.Description,
+		&schedule.Template,
+		&schedule.IntervalWeeks,
+		&schedule.NextRunAt,
+		&schedule.Active,
+		&schedule.CreatedBy,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (r *ScheduleRepository) Create(schedule *models.RetrospectiveSchedule) error {
+	query := `
+		INSERT INTO retrospective_schedules (id, team_id, title, description, template, interval_weeks, next_run_at, active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at
+	`
+
+	schedule.ID = uuid.New()
+	return r.db.QueryRow(query,
+		schedule.ID,
+		schedule.TeamID,
+		schedule.Title,
+		schedule.Description,
+		schedule.Template,
+		schedule.IntervalWeeks,
+		schedule.NextRunAt,
+		schedule.Active,
+		schedule.CreatedBy,
+	).Scan(&schedule.CreatedAt, &schedule.UpdatedAt)
+}
+
+func (r *ScheduleRepository) GetByID(id uuid.UUID) (*models.RetrospectiveSchedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM retrospective_schedules WHERE id = $1`
+	return scanSchedule(r.db.QueryRow(query, id))
+}
+
+func (r *ScheduleRepository) ListByTeam(teamID uuid.UUID) ([]models.RetrospectiveSchedule, error) {
+	return r.list(`SELECT `+scheduleColumns+` FROM retrospective_schedules WHERE team_id = $1 ORDER BY created_at ASC`, teamID)
+}
+
+// GetDueSchedules returns up to limit active schedules whose next
+// retrospective is at or before until
+func (r *ScheduleRepository) GetDueSchedules(until time.Time, limit int) ([]models.RetrospectiveSchedule, error) {
+	return r.list(`
+		SELECT `+scheduleColumns+` FROM retrospective_schedules
+		WHERE active AND next_run_at <= $1
+		ORDER BY next_run_at ASC
+		LIMIT $2
+	`, until, limit)
+}
+
+func (r *ScheduleRepository) list(query string, args ...interface{}) ([]models.RetrospectiveSchedule, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []models.RetrospectiveSchedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+func (r *ScheduleRepository) Update(schedule *models.RetrospectiveSchedule) error {
+	query := `
+		UPDATE retrospective_schedules
+		SET title = $2, description = $3, template = $4, interval_weeks = $5, next_run_at = $6, active = $7, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(query,
+		schedule.ID,
+		schedule.Title,
+		schedule.Description,
+		schedule.Template,
+		schedule.IntervalWeeks,
+		schedule.NextRunAt,
+		schedule.Active,
+	).Scan(&schedule.UpdatedAt)
+}
+
+// CreateOccurrence creates the retrospective of the next run of the schedule
+// and moves the next run to the following one, in one transaction. It
+// returns sql.ErrNoRows when the next run is no longer the one of the
+// schedule, i.e. another run already took this occurrence or the schedule was
+// changed. The retrospective is created by the creator of the schedule, or
+// by the team owner once the creator's account is deleted.
+func (r *ScheduleRepository) CreateOccurrence(schedule *models.RetrospectiveSchedule, next time.Time, retrospective *models.Retrospective) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE retrospective_schedules SET next_run_at = $3, updated_at = NOW()
+		WHERE id = $1 AND next_run_at = $2
+	`, schedule.ID, schedule.NextRunAt, next)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	retrospective.ID = uuid.New()
+	err = tx.QueryRow(`
+		INSERT INTO retrospectives (id, title, description, template, status, created_by, team_id, scheduled_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, (SELECT owner_id FROM teams WHERE id = $7)), $7, $8)
+		RETURNING created_by, created_at, updated_at
+	`, retrospective.ID, retrospective.Title, retrospective.Description, retrospective.Template, retrospective.Status,
+		schedule.CreatedBy, schedule.TeamID, retrospective.ScheduledAt,
+	).Scan(&retrospective.CreatedBy, &retrospective.CreatedAt, &retrospective.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ScheduleRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM retrospective_schedules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
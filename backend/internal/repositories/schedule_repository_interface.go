@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleRepositoryInterface define a interface para o ScheduleRepository
+type ScheduleRepositoryInterface interface {
+	Create(schedule *models.RetrospectiveSchedule) error
+	GetByID(id uuid.UUID) (*models.RetrospectiveSchedule, error)
+	ListByTeam(teamID uuid.UUID) ([]models.RetrospectiveSchedule, error)
+	GetDueSchedules(until time.Time, limit int) ([]models.RetrospectiveSchedule, error)
+	Update(schedule *models.RetrospectiveSchedule) error
+	CreateOccurrence(schedule *models.RetrospectiveSchedule, next time.Time, retrospective *models.Retrospective) error
+	Delete(id uuid.UUID) error
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleRepository_GetDueSchedules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewScheduleRepository(db)
+	until := time.Now()
+	scheduleID := uuid.New()
+	teamID := uuid.New()
+
+	mock.ExpectQuery(`SELECT .* FROM retrospective_schedules\s+WHERE active AND next_run_at <= \$1`).
+		WithArgs(until, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "title", "description", "template", "interval_weeks", "next_run_at", "active", "created_by", "created_at", "updated_at"}).
+			AddRow(scheduleID, teamID, "Retro quinzenal", nil, "sailboat", 2, until, true, uuid.New(), until, until))
+
+	schedules, err := repo.GetDueSchedules(until, 50)
+
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 1)
+	assert.Equal(t, scheduleID, schedules[0].ID)
+	assert.Equal(t, models.TemplateSailboat, schedules[0].Template)
+	assert.Equal(t, 2, schedules[0].IntervalWeeks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScheduleRepository_CreateOccurrence(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewScheduleRepository(db)
+	now := time.Now()
+	owner := uuid.New()
+	// The creator of the schedule deleted their account
+	schedule := &models.RetrospectiveSchedule{ID: uuid.New(), TeamID: uuid.New(), NextRunAt: now}
+	next := now.AddDate(0, 0, 14)
+	retrospective := &models.Retrospective{Title: "Retro quinzenal - 03/05/2024", Template: models.TemplateSailboat, Status: models.RetroStatusPlanned, ScheduledAt: &now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE retrospective_schedules SET next_run_at = \$3.*WHERE id = \$1 AND next_run_at = \$2`).
+		WithArgs(schedule.ID, now, next).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO retrospectives .*COALESCE\(\$6, \(SELECT owner_id FROM teams WHERE id = \$7\)\)`).
+		WithArgs(sqlmock.AnyArg(), retrospective.Title, nil, retrospective.Template, retrospective.Status, nil, schedule.TeamID, &now).
+		WillReturnRows(sqlmock.NewRows([]string{"created_by", "created_at", "updated_at"}).AddRow(owner, now, now))
+	mock.ExpectCommit()
+
+	err = repo.CreateOccurrence(schedule, next, retrospective)
+
+	assert.NoError(t, err)
+	assert.Equal(t, owner, retrospective.CreatedBy)
+	assert.NotEqual(t, uuid.Nil, retrospective.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScheduleRepository_CreateOccurrence_Taken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewScheduleRepository(db)
+	schedule := &models.RetrospectiveSchedule{ID: uuid.New(), NextRunAt: time.Now()}
+	next := schedule.NextRunAt.AddDate(0, 0, 14)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE retrospective_schedules`).
+		WithArgs(schedule.ID, schedule.NextRunAt, next).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.CreateOccurrence(schedule, next, &models.Retrospective{})
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScheduleRepository_CreateOccurrence_RollsBackTheClaim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewScheduleRepository(db)
+	creator := uuid.New()
+	schedule := &models.RetrospectiveSchedule{ID: uuid.New(), NextRunAt: time.Now(), CreatedBy: &creator}
+	next := schedule.NextRunAt.AddDate(0, 0, 14)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE retrospective_schedules`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO retrospectives`).
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	err = repo.CreateOccurrence(schedule, next, &models.Retrospective{})
+
+	assert.Equal(t, sql.ErrConnDone, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type ShareLinkRepository struct {
+	db *sql.DB
+}
+
+func NewShareLinkRepository(db *sql.DB) *ShareLinkRepository {
+	return &ShareLinkRepository{db: db}
+}
+
+const shareLinkColumns = `id, retrospective_id, token, created_by, expires_at, created_at`
+
+func scanShareLink(scanner interface{ Scan(...interface{}) error }) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := scanner.Scan(
+		&link.ID,
+		&link.RetrospectiveID,
+		&link.Token,
+		&link.CreatedBy,
+		&link.ExpiresAt,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+func (r *ShareLinkRepository) Create(link *models.ShareLink) error {
+	query := `
+		INSERT INTO retrospective_share_links (id, retrospective_id, token, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+
+	link.ID = uuid.New()
+	return r.db.QueryRow(query, link.ID, link.RetrospectiveID, link.Token, link.CreatedBy, link.ExpiresAt).
+		Scan(&link.CreatedAt)
+}
+
+func (r *ShareLinkRepository) GetByID(id uuid.UUID) (*models.ShareLink, error) {
+	query := `SELECT ` + shareLinkColumns + ` FROM retrospective_share_links WHERE id = $1`
+	return scanShareLink(r.db.QueryRow(query, id))
+}
+
+// GetByToken returns the link with the token, expired or not
+func (r *ShareLinkRepository) GetByToken(token string) (*models.ShareLink, error) {
+	query := `SELECT ` + shareLinkColumns + ` FROM retrospective_share_links WHERE token = $1`
+	return scanShareLink(r.db.QueryRow(query, token))
+}
+
+// ListByRetrospective returns the links of the retrospective that have not
+// expired, newest first
+func (r *ShareLinkRepository) ListByRetrospective(retrospectiveID uuid.UUID) ([]models.ShareLink, error) {
+	query := `
+		SELECT ` + shareLinkColumns + ` FROM retrospective_share_links
+		WHERE retrospective_id = $1 AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.ShareLink{}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+
+	return links, rows.Err()
+}
+
+func (r *ShareLinkRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM retrospective_share_links WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ShareLinkRepositoryInterface define a interface para o ShareLinkRepository
+type ShareLinkRepositoryInterface interface {
+	Create(link *models.ShareLink) error
+	GetByID(id uuid.UUID) (*models.ShareLink, error)
+	GetByToken(token string) (*models.ShareLink, error)
+	ListByRetrospective(retrospectiveID uuid.UUID) ([]models.ShareLink, error)
+	Delete(id uuid.UUID) error
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareLinkRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewShareLinkRepository(db)
+	now := time.Now()
+	link := &models.ShareLink{RetrospectiveID: uuid.New(), Token: "abc123", CreatedBy: uuid.New(), ExpiresAt: now.AddDate(0, 0, 7)}
+
+	mock.ExpectQuery(`INSERT INTO retrospective_share_links \(id, retrospective_id, token, created_by, expires_at\)`).
+		WithArgs(sqlmock.AnyArg(), link.RetrospectiveID, "abc123", link.CreatedBy, link.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+
+	err = repo.Create(link)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, link.ID)
+	assert.Equal(t, now, link.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareLinkRepository_ListByRetrospective(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewShareLinkRepository(db)
+	retrospectiveID := uuid.New()
+	linkID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM retrospective_share_links\s+WHERE retrospective_id = \$1 AND expires_at > NOW\(\)\s+ORDER BY created_at DESC`).
+		WithArgs(retrospectiveID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "retrospective_id", "token", "created_by", "expires_at", "created_at"}).
+			AddRow(linkID, retrospectiveID, "abc123", uuid.New(), now.AddDate(0, 0, 7), now))
+
+	links, err := repo.ListByRetrospective(retrospectiveID)
+
+	assert.NoError(t, err)
+	assert.Len(t, links, 1)
+	assert.Equal(t, linkID, links[0].ID)
+	assert.Equal(t, "abc123", links[0].Token)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareLinkRepository_GetByToken_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewShareLinkRepository(db)
+
+	mock.ExpectQuery(`SELECT .* FROM retrospective_share_links WHERE token = \$1`).
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByToken("unknown")
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareLinkRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewShareLinkRepository(db)
+	linkID := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM retrospective_share_links WHERE id = \$1`).
+		WithArgs(linkID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(linkID)
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
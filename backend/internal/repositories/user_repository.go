@@ -3,6 +3,8 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"educ-retro/internal/models"
 
@@ -19,13 +21,16 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 
 func (r *UserRepository) Create(user *models.User) error {
 	query := `
-		INSERT INTO users (id, email, name, password, avatar)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (id, email, name, password, avatar, role)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING created_at, updated_at
 	`
 
 	user.ID = uuid.New()
-	err := r.db.QueryRow(query, user.ID, user.Email, user.Name, user.Password, user.Avatar).
+	if user.Role == "" {
+		user.Role = models.UserRoleUser
+	}
+	err := r.db.QueryRow(query, user.ID, user.Email, user.Name, user.Password, user.Avatar, user.Role).
 		Scan(&user.CreatedAt, &user.UpdatedAt)
 
 	return err
@@ -33,14 +38,14 @@ func (r *UserRepository) Create(user *models.User) error {
 
 func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, email, name, password, avatar, created_at, updated_at
+		SELECT id, email, name, password, avatar, role, suspended_at, created_at, updated_at
 		FROM users WHERE id = $1
 	`
 
 	user := &models.User{}
 	err := r.db.QueryRow(query, id).Scan(
 		&user.ID, &user.Email, &user.Name, &user.Password, &user.Avatar,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.Role, &user.SuspendedAt, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -52,14 +57,14 @@ func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 
 func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	query := `
-		SELECT id, email, name, password, avatar, created_at, updated_at
+		SELECT id, email, name, password, avatar, role, suspended_at, created_at, updated_at
 		FROM users WHERE email = $1
 	`
 
 	user := &models.User{}
 	err := r.db.QueryRow(query, email).Scan(
 		&user.ID, &user.Email, &user.Name, &user.Password, &user.Avatar,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.Role, &user.SuspendedAt, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -104,7 +109,7 @@ func (r *UserRepository) GetUsersByIDs(ids []uuid.UUID) ([]models.User, error) {
 	query := fmt.Sprintf(`
 		SELECT id, email, name, avatar, created_at, updated_at
 		FROM users WHERE id IN (%s)
-	`, fmt.Sprintf("%s", placeholders))
+	`, strings.Join(placeholders, ", "))
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -127,3 +132,864 @@ func (r *UserRepository) GetUsersByIDs(ids []uuid.UUID) ([]models.User, error) {
 
 	return users, nil
 }
+
+// GetByIdentity returns the user linked to an external identity provider account
+func (r *UserRepository) GetByIdentity(provider, subject string) (*models.User, error) {
+	query := `
+		SELECT u.id, u.email, u.name, u.password, u.avatar, u.role, u.suspended_at, u.created_at, u.updated_at
+		FROM users u
+		INNER JOIN user_identities ui ON ui.user_id = u.id
+		WHERE ui.provider = $1 AND ui.subject = $2
+	`
+
+	user := &models.User{}
+	err := r.db.QueryRow(query, provider, subject).Scan(
+		&user.ID, &user.Email, &user.Name, &user.Password, &user.Avatar,
+		&user.Role, &user.SuspendedAt, &user.CreatedAt, &user.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) CreateIdentity(identity *models.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	identity.ID = uuid.New()
+	err := r.db.QueryRow(query, identity.ID, identity.UserID, identity.Provider, identity.Subject).
+		Scan(&identity.CreatedAt)
+
+	return err
+}
+
+// DeleteAccount removes a user and handles the content they authored according
+// to mode. Retrospectives, groups, schedules and webhooks created by the user
+// (and action items, item merges, discussion notes and action item history,
+// unless purged) are kept for the team with the user cleared by the foreign keys.
+// Purged merges can no longer be undone: the merged items stay merged.
+// Teams owned by the user are handed to another member, the team owners first
+// and then the oldest members; a team with no other member is kept without an
+// owner.
+func (r *UserRepository) DeleteAccount(id uuid.UUID, mode models.AccountDeletionMode) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		WITH transferred AS (
+			UPDATE teams t SET owner_id = (
+				SELECT tm.user_id FROM team_members tm
+				WHERE tm.team_id = t.id AND tm.user_id <> $1
+				ORDER BY CASE tm.role WHEN 'owner' THEN 0 WHEN 'member' THEN 1 ELSE 2 END, tm.joined_at ASC
+				LIMIT 1
+			), updated_at = NOW()
+			WHERE t.owner_id = $1
+				AND EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = t.id AND tm.user_id <> $1)
+			RETURNING t.id, t.owner_id
+		)
+		UPDATE team_members tm SET role = 'owner'
+		FROM transferred
+		WHERE tm.team_id = transferred.id AND tm.user_id = transferred.owner_id
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case models.AccountDeletionPurge:
+		// Remove the user's votes from the counters before the vote rows cascade
+		_, err = tx.Exec(`
+			UPDATE retrospective_items SET votes = votes - 1
+			WHERE id IN (SELECT item_id FROM retrospective_votes WHERE user_id = $1)
+		`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			UPDATE retrospective_groups SET votes = votes - 1
+			WHERE id IN (SELECT group_id FROM retrospective_group_votes WHERE user_id = $1)
+		`, id)
+		if err != nil {
+			return err
+		}
+
+		// Bring back the items of others merged into the user's items, which
+		// would otherwise be deleted with them
+		_, err = tx.Exec(`
+			UPDATE retrospective_items SET merged_into_id = NULL, updated_at = NOW()
+			WHERE merged_into_id IN (SELECT id FROM retrospective_items WHERE author_id = $1)
+		`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM retrospective_items WHERE author_id = $1`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM action_items WHERE created_by = $1`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM action_item_comments WHERE user_id = $1`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM action_item_events WHERE user_id = $1`, id)
+		if err != nil {
+			return err
+		}
+
+		// Notes are shared by the team; the user wrote the last version of these
+		_, err = tx.Exec(`DELETE FROM discussion_notes WHERE updated_by = $1`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM retrospective_item_merges WHERE created_by = $1`, id)
+		if err != nil {
+			return err
+		}
+	default:
+		_, err = tx.Exec(`
+			UPDATE retrospective_items
+			SET author_id = NULL, is_anonymous = true, updated_at = NOW()
+			WHERE author_id = $1
+		`, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// GetDataExport collects every record that references the user
+func (r *UserRepository) GetDataExport(id uuid.UUID) (*models.UserDataExport, error) {
+	user, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &models.UserDataExport{
+		ExportedAt: time.Now(),
+		Profile: models.UserResponse{
+			ID:        user.ID,
+			Email:     user.Email,
+			Name:      user.Name,
+			Avatar:    user.Avatar,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt,
+		},
+		Identities:          []models.UserIdentity{},
+		TeamMemberships:     []models.TeamMembership{},
+		Retrospectives:      []models.Retrospective{},
+		Participations:      []models.RetrospectiveParticipant{},
+		Items:               []models.RetrospectiveItem{},
+		ItemVotes:           []models.RetrospectiveVote{},
+		GroupVotes:          []models.RetrospectiveGroupVote{},
+		Groups:              []models.RetrospectiveGroup{},
+		ActionItemsCreated:  []models.ActionItem{},
+		ActionItemsAssigned: []models.ActionItem{},
+		ActionItemComments:  []models.ActionItemComment{},
+		ActionItemEvents:    []models.ActionItemEvent{},
+		Reminders:           []models.ActionItemReminder{},
+		DiscussionNotes:     []models.DiscussionNote{},
+		ItemMerges:          []models.ItemMerge{},
+		Schedules:           []models.RetrospectiveSchedule{},
+		ShareLinks:          []models.ShareLink{},
+		Webhooks:            []models.TeamWebhook{},
+		ChatWebhooks:        []models.TeamChatWebhook{},
+	}
+
+	export.NotificationPreferences, err = r.GetNotificationPreferences(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Identities
+	rows, err := r.db.Query(`
+		SELECT id, user_id, provider, subject, created_at
+		FROM user_identities WHERE user_id = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var identity models.UserIdentity
+		if err := rows.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.Identities = append(export.Identities, identity)
+	}
+	rows.Close()
+
+	// Team memberships
+	rows, err = r.db.Query(`
+		SELECT t.id, t.name, tm.role, tm.joined_at
+		FROM team_members tm
+		INNER JOIN teams t ON t.id = tm.team_id
+		WHERE tm.user_id = $1 ORDER BY tm.joined_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var membership models.TeamMembership
+		if err := rows.Scan(&membership.TeamID, &membership.TeamName, &membership.Role, &membership.JoinedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.TeamMemberships = append(export.TeamMemberships, membership)
+	}
+	rows.Close()
+
+	// Retrospectives created
+	rows, err = r.db.Query(`
+		SELECT id, team_id, title, description, template, status, scheduled_at, started_at, ended_at,
+		       created_by, created_at, updated_at
+		FROM retrospectives WHERE created_by = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var retrospective models.Retrospective
+		err := rows.Scan(
+			&retrospective.ID, &retrospective.TeamID, &retrospective.Title, &retrospective.Description,
+			&retrospective.Template, &retrospective.Status, &retrospective.ScheduledAt, &retrospective.StartedAt,
+			&retrospective.EndedAt, &retrospective.CreatedBy, &retrospective.CreatedAt, &retrospective.UpdatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.Retrospectives = append(export.Retrospectives, retrospective)
+	}
+	rows.Close()
+
+	// Participations
+	rows, err = r.db.Query(`
+		SELECT id, retrospective_id, user_id, joined_at, last_seen
+		FROM retrospective_participants WHERE user_id = $1 ORDER BY joined_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var participant models.RetrospectiveParticipant
+		err := rows.Scan(&participant.ID, &participant.RetrospectiveID, &participant.UserID, &participant.JoinedAt, &participant.LastSeen)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.Participations = append(export.Participations, participant)
+	}
+	rows.Close()
+
+	// Items authored (anonymous items are not linked to the author)
+	rows, err = r.db.Query(`
+		SELECT id, retrospective_id, category, content, author_id, is_anonymous, votes, created_at, updated_at
+		FROM retrospective_items WHERE author_id = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var item models.RetrospectiveItem
+		err := rows.Scan(
+			&item.ID, &item.RetrospectiveID, &item.Category, &item.Content, &item.AuthorID,
+			&item.IsAnonymous, &item.Votes, &item.CreatedAt, &item.UpdatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.Items = append(export.Items, item)
+	}
+	rows.Close()
+
+	// Votes on items
+	rows, err = r.db.Query(`
+		SELECT id, item_id, user_id, created_at
+		FROM retrospective_votes WHERE user_id = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var vote models.RetrospectiveVote
+		if err := rows.Scan(&vote.ID, &vote.ItemID, &vote.UserID, &vote.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.ItemVotes = append(export.ItemVotes, vote)
+	}
+	rows.Close()
+
+	// Votes on groups
+	rows, err = r.db.Query(`
+		SELECT id, group_id, user_id, created_at
+		FROM retrospective_group_votes WHERE user_id = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var vote models.RetrospectiveGroupVote
+		if err := rows.Scan(&vote.ID, &vote.GroupID, &vote.UserID, &vote.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.GroupVotes = append(export.GroupVotes, vote)
+	}
+	rows.Close()
+
+	// Groups created
+	rows, err = r.db.Query(`
+		SELECT id, retrospective_id, name, description, votes, created_by, created_at, updated_at
+		FROM retrospective_groups WHERE created_by = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var group models.RetrospectiveGroup
+		err := rows.Scan(
+			&group.ID, &group.RetrospectiveID, &group.Name, &group.Description,
+			&group.Votes, &group.CreatedBy, &group.CreatedAt, &group.UpdatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.Groups = append(export.Groups, group)
+	}
+	rows.Close()
+
+	// Action items created and assigned
+	export.ActionItemsCreated, err = r.getActionItemsWhere("created_by", id)
+	if err != nil {
+		return nil, err
+	}
+
+	export.ActionItemsAssigned, err = r.getActionItemsWhere("assigned_to", id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments on action items
+	rows, err = r.db.Query(`
+		SELECT c.id, c.action_item_id, c.parent_id, c.user_id, u.name, c.content, c.created_at, c.updated_at
+		FROM action_item_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.user_id = $1 ORDER BY c.created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var comment models.ActionItemComment
+		err := rows.Scan(&comment.ID, &comment.ActionItemID, &comment.ParentID, &comment.UserID, &comment.UserName,
+			&comment.Content, &comment.CreatedAt, &comment.UpdatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.ActionItemComments = append(export.ActionItemComments, comment)
+	}
+	rows.Close()
+
+	// History of action items changed by the user
+	rows, err = r.db.Query(`
+		SELECT e.id, e.action_item_id, e.user_id, u.name, e.event_type, e.old_value, e.new_value, e.note, e.created_at
+		FROM action_item_events e
+		JOIN users u ON u.id = e.user_id
+		WHERE e.user_id = $1 ORDER BY e.created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var event models.ActionItemEvent
+		err := rows.Scan(&event.ID, &event.ActionItemID, &event.UserID, &event.UserName, &event.EventType,
+			&event.OldValue, &event.NewValue, &event.Note, &event.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.ActionItemEvents = append(export.ActionItemEvents, event)
+	}
+	rows.Close()
+
+	// Reminders sent to the user
+	rows, err = r.db.Query(`
+		SELECT ar.kind, ar.action_item_id, a.title, ar.due_date, a.retrospective_id, rt.title, ar.user_id, u.email, u.name
+		FROM action_item_reminders ar
+		JOIN action_items a ON a.id = ar.action_item_id
+		JOIN retrospectives rt ON rt.id = a.retrospective_id
+		JOIN users u ON u.id = ar.user_id
+		WHERE ar.user_id = $1 ORDER BY ar.created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var reminder models.ActionItemReminder
+		err := rows.Scan(&reminder.Kind, &reminder.ActionItemID, &reminder.ActionItemTitle, &reminder.DueDate,
+			&reminder.RetrospectiveID, &reminder.RetrospectiveTitle, &reminder.UserID, &reminder.UserEmail, &reminder.UserName)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.Reminders = append(export.Reminders, reminder)
+	}
+	rows.Close()
+
+	// Discussion notes last edited by the user
+	rows, err = r.db.Query(`
+		SELECT `+discussionNoteColumns+`
+		FROM discussion_notes n WHERE n.updated_by = $1 ORDER BY n.created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		note, err := scanDiscussionNote(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.DiscussionNotes = append(export.DiscussionNotes, *note)
+	}
+	rows.Close()
+
+	// Item merges
+	rows, err = r.db.Query(`
+		SELECT `+itemMergeColumns+`
+		FROM retrospective_item_merges m
+		JOIN retrospective_items i ON i.id = m.source_item_id
+		WHERE m.created_by = $1 ORDER BY m.created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		merge, err := scanItemMerge(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.ItemMerges = append(export.ItemMerges, *merge)
+	}
+	rows.Close()
+
+	// Retrospective schedules
+	rows, err = r.db.Query(`
+		SELECT `+scheduleColumns+` FROM retrospective_schedules WHERE created_by = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.Schedules = append(export.Schedules, *schedule)
+	}
+	rows.Close()
+
+	// Share links, without the tokens that still give access to the summaries
+	rows, err = r.db.Query(`
+		SELECT `+shareLinkColumns+` FROM retrospective_share_links WHERE created_by = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		link.Token = ""
+		export.ShareLinks = append(export.ShareLinks, *link)
+	}
+	rows.Close()
+
+	// Team webhooks, without the signing secrets of the team
+	rows, err = r.db.Query(`
+		SELECT `+webhookColumns+` FROM team_webhooks WHERE created_by = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		webhook.Secret = ""
+		export.Webhooks = append(export.Webhooks, *webhook)
+	}
+	rows.Close()
+
+	// Chat channels; the incoming webhook URL is a credential of the team and is left out
+	rows, err = r.db.Query(`
+		SELECT team_id, provider, created_by, created_at, updated_at
+		FROM team_chat_webhooks WHERE created_by = $1 ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var webhook models.TeamChatWebhook
+		err := rows.Scan(&webhook.TeamID, &webhook.Provider, &webhook.CreatedBy, &webhook.CreatedAt, &webhook.UpdatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		export.ChatWebhooks = append(export.ChatWebhooks, webhook)
+	}
+	rows.Close()
+
+	return export, nil
+}
+
+func (r *UserRepository) getActionItemsWhere(column string, userID uuid.UUID) ([]models.ActionItem, error) {
+	query := fmt.Sprintf(`
+		SELECT id, retrospective_id, item_id, title, description, assigned_to, status, due_date, completed_at, created_by, created_at, updated_at,
+		       external_provider, external_id, external_url, external_synced_at
+		FROM action_items WHERE %s = $1 ORDER BY created_at ASC
+	`, column)
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	actionItems := []models.ActionItem{}
+	for rows.Next() {
+		var actionItem models.ActionItem
+		err := rows.Scan(
+			&actionItem.ID, &actionItem.RetrospectiveID, &actionItem.ItemID, &actionItem.Title,
+			&actionItem.Description, &actionItem.AssignedTo, &actionItem.Status, &actionItem.DueDate,
+			&actionItem.CompletedAt, &actionItem.CreatedBy, &actionItem.CreatedAt, &actionItem.UpdatedAt,
+			&actionItem.ExternalProvider, &actionItem.ExternalID, &actionItem.ExternalURL, &actionItem.ExternalSyncedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		actionItems = append(actionItems, actionItem)
+	}
+
+	return actionItems, nil
+}
+
+// GetAnalytics returns activity counters for the user. The completion history
+// contains only the months since the given time that have assigned action items.
+func (r *UserRepository) GetAnalytics(id uuid.UUID, since time.Time) (*models.UserAnalytics, error) {
+	analytics := &models.UserAnalytics{
+		ItemsByCategory:   make(map[string]int),
+		CompletionHistory: []models.CompletionRatePoint{},
+	}
+
+	// Retrospectives joined or created
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM (
+			SELECT retrospective_id FROM retrospective_participants WHERE user_id = $1
+			UNION
+			SELECT id FROM retrospectives WHERE created_by = $1
+		) participated
+	`, id).Scan(&analytics.RetrospectivesParticipated)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items authored per category and votes received on them
+	rows, err := r.db.Query(`
+		SELECT category, COUNT(*), COALESCE(SUM(votes), 0)
+		FROM retrospective_items
+		WHERE author_id = $1
+		GROUP BY category
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var category string
+		var count, votes int
+		if err := rows.Scan(&category, &count, &votes); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		analytics.ItemsByCategory[category] = count
+		analytics.ItemsAuthored += count
+		analytics.VotesReceived += votes
+	}
+	rows.Close()
+
+	// Votes cast on items and groups
+	err = r.db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM retrospective_votes WHERE user_id = $1)
+		     + (SELECT COUNT(*) FROM retrospective_group_votes WHERE user_id = $1)
+	`, id).Scan(&analytics.VotesCast)
+	if err != nil {
+		return nil, err
+	}
+
+	// Action items assigned
+	err = r.db.QueryRow(`
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'done'),
+		       COUNT(*) FILTER (WHERE status <> 'done' AND due_date < NOW())
+		FROM action_items
+		WHERE assigned_to = $1
+	`, id).Scan(&analytics.ActionItems.Assigned, &analytics.ActionItems.Completed, &analytics.ActionItems.Overdue)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completion per month of creation
+	rows, err = r.db.Query(`
+		SELECT to_char(date_trunc('month', created_at), 'YYYY-MM') AS period,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'done')
+		FROM action_items
+		WHERE assigned_to = $1 AND created_at >= $2
+		GROUP BY period
+		ORDER BY period ASC
+	`, id, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var point models.CompletionRatePoint
+		if err := rows.Scan(&point.Period, &point.Assigned, &point.Completed); err != nil {
+			return nil, err
+		}
+		analytics.CompletionHistory = append(analytics.CompletionHistory, point)
+	}
+
+	return analytics, nil
+}
+
+// List returns a page of users ordered by creation date, optionally filtered by
+// a case-insensitive search on name or email, together with the total count
+func (r *UserRepository) List(search string, limit, offset int) ([]models.User, int, error) {
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM users
+		WHERE $1 = '' OR name ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%'
+	`, search).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, email, name, password, avatar, role, suspended_at, created_at, updated_at
+		FROM users
+		WHERE $1 = '' OR name ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%'
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, search, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID, &user.Email, &user.Name, &user.Password, &user.Avatar,
+			&user.Role, &user.SuspendedAt, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+// SetSuspended suspends or reactivates a user account
+func (r *UserRepository) SetSuspended(id uuid.UUID, suspended bool) error {
+	query := `UPDATE users SET suspended_at = NULL, updated_at = NOW() WHERE id = $1`
+	if suspended {
+		query = `UPDATE users SET suspended_at = COALESCE(suspended_at, NOW()), updated_at = NOW() WHERE id = $1`
+	}
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdateRole(id uuid.UUID, role models.UserRole) error {
+	result, err := r.db.Exec(`UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1`, id, role)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetSystemStats returns system-wide counters for administrators
+func (r *UserRepository) GetSystemStats() (*models.SystemStats, error) {
+	stats := &models.SystemStats{
+		RetrospectivesByStatus: map[string]int{},
+	}
+
+	err := r.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM users),
+			(SELECT COUNT(*) FROM users WHERE role = 'admin'),
+			(SELECT COUNT(*) FROM users WHERE suspended_at IS NOT NULL),
+			(SELECT COUNT(*) FROM retrospectives WHERE created_by IS NULL),
+			(SELECT COUNT(*) FROM retrospective_items),
+			(SELECT COUNT(*) FROM action_items),
+			(SELECT COUNT(*) FROM action_items WHERE status = 'done')
+	`).Scan(
+		&stats.Users, &stats.Admins, &stats.SuspendedUsers, &stats.OrphanedRetrospectives,
+		&stats.Items, &stats.ActionItems, &stats.ActionItemsDone,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`SELECT status, COUNT(*) FROM retrospectives GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		stats.RetrospectivesByStatus[status] = count
+		stats.Retrospectives += count
+	}
+
+	return stats, rows.Err()
+}
+
+// GetNotificationPreferences returns the user's notification settings, or the
+// defaults when they never changed them
+func (r *UserRepository) GetNotificationPreferences(id uuid.UUID) (*models.NotificationPreferences, error) {
+	preferences := &models.NotificationPreferences{ActionItemReminders: true}
+
+	err := r.db.QueryRow(`
+		SELECT action_item_reminders FROM user_notification_preferences WHERE user_id = $1
+	`, id).Scan(&preferences.ActionItemReminders)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (r *UserRepository) UpdateNotificationPreferences(id uuid.UUID, preferences *models.NotificationPreferences) error {
+	_, err := r.db.Exec(`
+		INSERT INTO user_notification_preferences (user_id, action_item_reminders)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET action_item_reminders = EXCLUDED.action_item_reminders, updated_at = NOW()
+	`, id, preferences.ActionItemReminders)
+	return err
+}
+
+// GetCalendarToken returns the token of the user's calendar feed, or "" when
+// it was never created
+func (r *UserRepository) GetCalendarToken(id uuid.UUID) (string, error) {
+	var token sql.NullString
+	err := r.db.QueryRow(`SELECT calendar_token FROM users WHERE id = $1`, id).Scan(&token)
+	if err != nil {
+		return "", err
+	}
+
+	return token.String, nil
+}
+
+// SetCalendarToken replaces the token of the user's calendar feed, which
+// invalidates the previous feed URL
+func (r *UserRepository) SetCalendarToken(id uuid.UUID, token string) error {
+	result, err := r.db.Exec(`UPDATE users SET calendar_token = $2 WHERE id = $1`, id, token)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetUserIDByCalendarToken returns the active user owning the calendar token
+func (r *UserRepository) GetUserIDByCalendarToken(token string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(`
+		SELECT id FROM users WHERE calendar_token = $1 AND suspended_at IS NULL
+	`, token).Scan(&id)
+	return id, err
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"educ-retro/internal/models"
 
 	"github.com/google/uuid"
@@ -14,4 +16,18 @@ type UserRepositoryInterface interface {
 	Update(user *models.User) error
 	Delete(id uuid.UUID) error
 	GetUsersByIDs(ids []uuid.UUID) ([]models.User, error)
+	GetByIdentity(provider, subject string) (*models.User, error)
+	CreateIdentity(identity *models.UserIdentity) error
+	DeleteAccount(id uuid.UUID, mode models.AccountDeletionMode) error
+	GetDataExport(id uuid.UUID) (*models.UserDataExport, error)
+	GetAnalytics(id uuid.UUID, since time.Time) (*models.UserAnalytics, error)
+	List(search string, limit, offset int) ([]models.User, int, error)
+	SetSuspended(id uuid.UUID, suspended bool) error
+	UpdateRole(id uuid.UUID, role models.UserRole) error
+	GetSystemStats() (*models.SystemStats, error)
+	GetNotificationPreferences(id uuid.UUID) (*models.NotificationPreferences, error)
+	UpdateNotificationPreferences(id uuid.UUID, preferences *models.NotificationPreferences) error
+	GetCalendarToken(id uuid.UUID) (string, error)
+	SetCalendarToken(id uuid.UUID, token string) error
+	GetUserIDByCalendarToken(token string) (uuid.UUID, error)
 }
@@ -2,6 +2,11 @@ package repositories
 
 import (
 	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,7 +32,7 @@ func TestUserRepository_Create(t *testing.T) {
 	}
 
 	mock.ExpectQuery(`INSERT INTO users`).
-		WithArgs(sqlmock.AnyArg(), user.Email, user.Name, user.Password, user.Avatar).
+		WithArgs(sqlmock.AnyArg(), user.Email, user.Name, user.Password, user.Avatar, models.UserRoleUser).
 		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).
 			AddRow(time.Now(), time.Now()))
 
@@ -53,7 +58,7 @@ func TestUserRepository_Create_Error(t *testing.T) {
 	}
 
 	mock.ExpectQuery(`INSERT INTO users`).
-		WithArgs(sqlmock.AnyArg(), user.Email, user.Name, user.Password, user.Avatar).
+		WithArgs(sqlmock.AnyArg(), user.Email, user.Name, user.Password, user.Avatar, models.UserRoleUser).
 		WillReturnError(sql.ErrConnDone)
 
 	err = repo.Create(user)
@@ -82,8 +87,8 @@ func TestUserRepository_GetByID(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT.*FROM users WHERE id`).
 		WithArgs(userID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "password", "avatar", "created_at", "updated_at"}).
-			AddRow(expectedUser.ID, expectedUser.Email, expectedUser.Name, expectedUser.Password, expectedUser.Avatar, expectedUser.CreatedAt, expectedUser.UpdatedAt))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "password", "avatar", "role", "suspended_at", "created_at", "updated_at"}).
+			AddRow(expectedUser.ID, expectedUser.Email, expectedUser.Name, expectedUser.Password, expectedUser.Avatar, "user", nil, expectedUser.CreatedAt, expectedUser.UpdatedAt))
 
 	user, err := repo.GetByID(userID)
 
@@ -132,8 +137,8 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 
 	mock.ExpectQuery(`SELECT.*FROM users WHERE email`).
 		WithArgs(email).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "password", "avatar", "created_at", "updated_at"}).
-			AddRow(expectedUser.ID, expectedUser.Email, expectedUser.Name, expectedUser.Password, expectedUser.Avatar, expectedUser.CreatedAt, expectedUser.UpdatedAt))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "password", "avatar", "role", "suspended_at", "created_at", "updated_at"}).
+			AddRow(expectedUser.ID, expectedUser.Email, expectedUser.Name, expectedUser.Password, expectedUser.Avatar, "user", nil, expectedUser.CreatedAt, expectedUser.UpdatedAt))
 
 	user, err := repo.GetByEmail(email)
 
@@ -258,7 +263,7 @@ func TestUserRepository_GetUsersByIDs(t *testing.T) {
 		{ID: userIDs[1], Email: "user2@example.com", Name: "User 2"},
 	}
 
-	mock.ExpectQuery(`SELECT.*FROM users WHERE id IN`).
+	mock.ExpectQuery(`SELECT.*FROM users WHERE id IN \(\$1, \$2\)`).
 		WithArgs(userIDs[0], userIDs[1]).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "avatar", "created_at", "updated_at"}).
 			AddRow(expectedUsers[0].ID, expectedUsers[0].Email, expectedUsers[0].Name, "", time.Now(), time.Now()).
@@ -303,3 +308,340 @@ func TestUserRepository_GetUsersByIDs_Error(t *testing.T) {
 	assert.Nil(t, users)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUserRepository_DeleteAccount_Anonymize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE teams t SET owner_id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE retrospective_items\s+SET author_id = NULL, is_anonymous = true`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM users WHERE id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.DeleteAccount(userID, models.AccountDeletionAnonymize)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_DeleteAccount_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE teams t SET owner_id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE retrospective_items SET votes = votes - 1`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE retrospective_groups SET votes = votes - 1`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE retrospective_items SET merged_into_id = NULL, updated_at = NOW\(\)\s+WHERE merged_into_id IN \(SELECT id FROM retrospective_items WHERE author_id = \$1\)`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM retrospective_items WHERE author_id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM action_items WHERE created_by`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM action_item_comments WHERE user_id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM action_item_events WHERE user_id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`DELETE FROM discussion_notes WHERE updated_by`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM retrospective_item_merges WHERE created_by`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM users WHERE id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.DeleteAccount(userID, models.AccountDeletionPurge)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_DeleteAccount_TeamOwner(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	userID := uuid.New()
+
+	// The owned team goes to another member, promoted to team owner, before
+	// the user is deleted, so the team and its retrospectives are kept
+	mock.ExpectBegin()
+	mock.ExpectExec(`WITH transferred AS \(\s+UPDATE teams t SET owner_id = \(\s+SELECT tm.user_id FROM team_members tm\s+WHERE tm.team_id = t.id AND tm.user_id <> \$1\s+ORDER BY CASE tm.role WHEN 'owner' THEN 0 WHEN 'member' THEN 1 ELSE 2 END, tm.joined_at ASC\s+LIMIT 1\s+\), updated_at = NOW\(\)\s+WHERE t.owner_id = \$1.*RETURNING t.id, t.owner_id\s+\)\s+UPDATE team_members tm SET role = 'owner'`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE retrospective_items\s+SET author_id = NULL`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM users WHERE id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.DeleteAccount(userID, models.AccountDeletionAnonymize)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_DeleteAccount_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE teams t SET owner_id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE retrospective_items`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM users WHERE id`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.DeleteAccount(userID, models.AccountDeletionAnonymize)
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetDataExport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	userID := uuid.New()
+	teamID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT.*FROM users WHERE id`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "name", "password", "avatar", "role", "suspended_at", "created_at", "updated_at"}).
+			AddRow(userID, "test@example.com", "Test User", "hashedpassword", nil, "user", nil, now, now))
+	mock.ExpectQuery(`SELECT action_item_reminders FROM user_notification_preferences`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"action_item_reminders"}).AddRow(false))
+	for _, table := range []string{
+		"user_identities", "team_members", "retrospectives", "retrospective_participants", "retrospective_items",
+		"retrospective_votes", "retrospective_group_votes", "retrospective_groups", "action_items", "action_items",
+		"action_item_comments", "action_item_events", "action_item_reminders", "discussion_notes",
+		"retrospective_item_merges", "retrospective_schedules", "retrospective_share_links",
+	} {
+		mock.ExpectQuery(`FROM ` + table).WithArgs(userID).WillReturnRows(sqlmock.NewRows(nil))
+	}
+	mock.ExpectQuery(`FROM team_webhooks WHERE created_by`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "url", "secret", "events", "active", "created_by", "created_at", "updated_at"}).
+			AddRow(uuid.New(), teamID, "https://example.com/hook", "team-secret", "{retrospective.ended}", true, userID, now, now))
+	mock.ExpectQuery(`SELECT team_id, provider, created_by, created_at, updated_at\s+FROM team_chat_webhooks WHERE created_by`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "provider", "created_by", "created_at", "updated_at"}).
+			AddRow(teamID, "slack", userID, now, now))
+
+	export, err := repo.GetDataExport(userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", export.Profile.Email)
+	assert.False(t, export.NotificationPreferences.ActionItemReminders)
+	assert.Empty(t, export.DiscussionNotes)
+	assert.Len(t, export.Webhooks, 1)
+	assert.Equal(t, "https://example.com/hook", export.Webhooks[0].URL)
+	assert.Empty(t, export.Webhooks[0].Secret)
+	assert.Len(t, export.ChatWebhooks, 1)
+	assert.Empty(t, export.ChatWebhooks[0].URL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUserRepository_UserReferences lists every column that references
+// users(id) with the export field that holds its rows and what an account
+// purge does with them. A migration adding a reference fails the test until
+// GetDataExport and DeleteAccount handle it and it is added here.
+func TestUserRepository_UserReferences(t *testing.T) {
+	references := map[string]struct{ export, purge string }{
+		"teams.owner_id":                        {"team_memberships", "handed to another member"},
+		"team_members.user_id":                  {"team_memberships", "cascade"},
+		"retrospectives.created_by":             {"retrospectives_created", "kept for the team"},
+		"retrospective_items.author_id":         {"items_authored", "deleted"},
+		"retrospective_votes.user_id":           {"item_votes", "counters decremented, cascade"},
+		"action_items.assigned_to":              {"action_items_assigned", "unassigned"},
+		"action_items.created_by":               {"action_items_created", "deleted"},
+		"retrospective_participants.user_id":    {"participations", "cascade"},
+		"retrospective_groups.created_by":       {"groups_created", "kept for the team"},
+		"retrospective_group_votes.user_id":     {"group_votes", "counters decremented, cascade"},
+		"user_identities.user_id":               {"identities", "cascade"},
+		"user_notification_preferences.user_id": {"notification_preferences", "cascade"},
+		"action_item_reminders.user_id":         {"action_item_reminders", "cascade"},
+		"action_item_events.user_id":            {"action_item_events", "deleted"},
+		"action_item_comments.user_id":          {"action_item_comments", "deleted"},
+		"team_webhooks.created_by":              {"webhooks_created", "kept for the team"},
+		"retrospective_schedules.created_by":    {"schedules_created", "kept for the team"},
+		"retrospective_share_links.created_by":  {"share_links_created", "cascade"},
+		"retrospective_item_merges.created_by":  {"item_merges", "deleted"},
+		"discussion_notes.updated_by":           {"discussion_notes", "deleted"},
+		"team_chat_webhooks.created_by":         {"chat_webhooks_created", "kept for the team"},
+	}
+
+	exportFields := map[string]bool{}
+	exportType := reflect.TypeOf(models.UserDataExport{})
+	for i := 0; i < exportType.NumField(); i++ {
+		exportFields[exportType.Field(i).Tag.Get("json")] = true
+	}
+	for reference, handling := range references {
+		assert.True(t, exportFields[handling.export], "%s: no export field %q", reference, handling.export)
+	}
+
+	files, err := filepath.Glob("../../migrations/*.up.sql")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	tablePattern := regexp.MustCompile(`(?i)^\s*(?:CREATE|ALTER) TABLE (?:IF NOT EXISTS )?(\w+)`)
+	foreignKeyPattern := regexp.MustCompile(`(?i)FOREIGN KEY \((\w+)\) REFERENCES users\(`)
+	columnPattern := regexp.MustCompile(`(?i)^\s*(?:ADD COLUMN (?:IF NOT EXISTS )?)?(\w+) .*REFERENCES users\(`)
+
+	found := map[string]bool{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+
+		table := ""
+		for _, line := range strings.Split(string(content), "\n") {
+			if match := tablePattern.FindStringSubmatch(line); match != nil {
+				table = match[1]
+			}
+			if match := foreignKeyPattern.FindStringSubmatch(line); match != nil {
+				found[table+"."+match[1]] = true
+			} else if match := columnPattern.FindStringSubmatch(line); match != nil {
+				found[table+"."+match[1]] = true
+			}
+		}
+	}
+
+	for reference := range found {
+		_, ok := references[reference]
+		assert.True(t, ok, "%s references users(id) but is not covered by the data export and account purge", reference)
+	}
+	for reference := range references {
+		assert.True(t, found[reference], "%s no longer references users(id)", reference)
+	}
+}
+
+func TestUserRepository_GetAnalytics(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	userID := uuid.New()
+	since := time.Now().AddDate(0, -5, 0)
+
+	mock.ExpectQuery(`FROM retrospective_participants WHERE user_id`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectQuery(`SELECT category, COUNT\(\*\), COALESCE\(SUM\(votes\), 0\)`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"category", "count", "votes"}).
+			AddRow("start", 2, 5).
+			AddRow("stop", 1, 0))
+	mock.ExpectQuery(`FROM retrospective_votes WHERE user_id`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	mock.ExpectQuery(`FROM action_items\s+WHERE assigned_to = \$1$`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"assigned", "completed", "overdue"}).AddRow(3, 1, 1))
+	mock.ExpectQuery(`GROUP BY period`).
+		WithArgs(userID, since).
+		WillReturnRows(sqlmock.NewRows([]string{"period", "assigned", "completed"}).AddRow("2026-09", 3, 1))
+
+	analytics, err := repo.GetAnalytics(userID, since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, analytics.RetrospectivesParticipated)
+	assert.Equal(t, 3, analytics.ItemsAuthored)
+	assert.Equal(t, map[string]int{"start": 2, "stop": 1}, analytics.ItemsByCategory)
+	assert.Equal(t, 5, analytics.VotesReceived)
+	assert.Equal(t, 7, analytics.VotesCast)
+	assert.Equal(t, models.ActionItemAnalytics{Assigned: 3, Completed: 1, Overdue: 1}, analytics.ActionItems)
+	assert.Len(t, analytics.CompletionHistory, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_SetSuspended(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectExec(`UPDATE users SET suspended_at = COALESCE\(suspended_at, NOW\(\)\)`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE users SET suspended_at = NULL`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.SetSuspended(userID, true))
+	assert.Equal(t, sql.ErrNoRows, repo.SetSuspended(userID, false))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_CalendarToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT calendar_token FROM users WHERE id = \$1`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_token"}).AddRow(nil))
+
+	token, err := repo.GetCalendarToken(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", token)
+
+	// Suspended users have no feed
+	mock.ExpectQuery(`SELECT id FROM users WHERE calendar_token = \$1 AND suspended_at IS NULL`).
+		WithArgs("secret").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetUserIDByCalendarToken("secret")
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,322 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `id, team_id, url, secret, events, active, created_by, created_at, updated_at`
+
+func scanWebhook(scanner interface{ Scan(...interface{}) error }) (*models.TeamWebhook, error) {
+	var webhook models.TeamWebhook
+	err := scanner.Scan(
+		&webhook.ID,
+		&webhook.TeamID,
+		&webhook.URL,
+		&webhook.Secret,
+		pq.Array(&webhook.Events),
+		&webhook.Active,
+		&webhook.CreatedBy,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) Create(webhook *models.TeamWebhook) error {
+	query := `
+		INSERT INTO team_webhooks (id, team_id, url, secret, events, active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+
+	webhook.ID = uuid.New()
+	return r.db.QueryRow(query,
+		webhook.ID,
+		webhook.TeamID,
+		webhook.URL,
+		webhook.Secret,
+		pq.Array(webhook.Events),
+		webhook.Active,
+		webhook.CreatedBy,
+	).Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+func (r *WebhookRepository) GetByID(id uuid.UUID) (*models.TeamWebhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM team_webhooks WHERE id = $1`
+	return scanWebhook(r.db.QueryRow(query, id))
+}
+
+func (r *WebhookRepository) ListByTeam(teamID uuid.UUID) ([]models.TeamWebhook, error) {
+	return r.list(`SELECT `+webhookColumns+` FROM team_webhooks WHERE team_id = $1 ORDER BY created_at ASC`, teamID)
+}
+
+// GetActiveForEvent returns the active webhooks of the team subscribed to the event
+func (r *WebhookRepository) GetActiveForEvent(teamID uuid.UUID, event string) ([]models.TeamWebhook, error) {
+	return r.list(`
+		SELECT `+webhookColumns+` FROM team_webhooks
+		WHERE team_id = $1 AND active AND $2 = ANY(events)
+		ORDER BY created_at ASC
+	`, teamID, event)
+}
+
+func (r *WebhookRepository) list(query string, args ...interface{}) ([]models.TeamWebhook, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.TeamWebhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *WebhookRepository) Update(webhook *models.TeamWebhook) error {
+	query := `
+		UPDATE team_webhooks
+		SET url = $2, events = $3, active = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(query, webhook.ID, webhook.URL, pq.Array(webhook.Events), webhook.Active).
+		Scan(&webhook.UpdatedAt)
+}
+
+func (r *WebhookRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM team_webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(query,
+		delivery.ID,
+		delivery.WebhookID,
+		delivery.Event,
+		[]byte(delivery.Payload),
+		delivery.Status,
+		delivery.NextAttemptAt,
+	).Scan(&delivery.CreatedAt, &delivery.UpdatedAt)
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries that are due by
+// moving their next attempt to leaseUntil, so a crashed attempt is retried
+// after the lease expires and concurrent workers do not pick the same delivery.
+func (r *WebhookRepository) ClaimDueDeliveries(now, leaseUntil time.Time, limit int) ([]models.PendingWebhookDelivery, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries SET next_attempt_at = $2, updated_at = NOW()
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d
+				JOIN team_webhooks w ON w.id = d.webhook_id
+				WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.active
+				ORDER BY d.next_attempt_at ASC
+				LIMIT $3
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING id, webhook_id, event, payload, status, attempts, created_at
+		)
+		SELECT c.id, c.webhook_id, c.event, c.payload, c.status, c.attempts, c.created_at, w.url, w.secret
+		FROM claimed c
+		JOIN team_webhooks w ON w.id = c.webhook_id
+		ORDER BY c.created_at ASC
+	`
+
+	rows, err := r.db.Query(query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.PendingWebhookDelivery{}
+	for rows.Next() {
+		var delivery models.PendingWebhookDelivery
+		var payload []byte
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.CreatedAt,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// RecordDeliveryAttempt stores the outcome of an attempt: status, attempts,
+// response status, last error, next attempt and delivery time
+func (r *WebhookRepository) RecordDeliveryAttempt(delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5,
+		    next_attempt_at = $6, delivered_at = $7, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+	)
+	return err
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first
+func (r *WebhookRepository) ListDeliveries(webhookID uuid.UUID, limit, offset int) ([]models.WebhookDelivery, int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1`, webhookID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, webhook_id, event, payload, status, attempts, response_status, last_error,
+		       next_attempt_at, delivered_at, created_at, updated_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, webhookID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var payload []byte
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.ResponseStatus,
+			&delivery.LastError,
+			&delivery.NextAttemptAt,
+			&delivery.DeliveredAt,
+			&delivery.CreatedAt,
+			&delivery.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, total, rows.Err()
+}
+
+func (r *WebhookRepository) GetChatWebhook(teamID uuid.UUID) (*models.TeamChatWebhook, error) {
+	query := `
+		SELECT team_id, provider, url, created_by, created_at, updated_at
+		FROM team_chat_webhooks
+		WHERE team_id = $1
+	`
+
+	var webhook models.TeamChatWebhook
+	err := r.db.QueryRow(query, teamID).Scan(
+		&webhook.TeamID,
+		&webhook.Provider,
+		&webhook.URL,
+		&webhook.CreatedBy,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// SetChatWebhook creates or replaces the chat channel of the team
+func (r *WebhookRepository) SetChatWebhook(webhook *models.TeamChatWebhook) error {
+	query := `
+		INSERT INTO team_chat_webhooks (team_id, provider, url, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_id) DO UPDATE
+		SET provider = EXCLUDED.provider, url = EXCLUDED.url, updated_at = NOW()
+		RETURNING created_by, created_at, updated_at
+	`
+
+	return r.db.QueryRow(query, webhook.TeamID, webhook.Provider, webhook.URL, webhook.CreatedBy).
+		Scan(&webhook.CreatedBy, &webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+func (r *WebhookRepository) DeleteChatWebhook(teamID uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM team_chat_webhooks WHERE team_id = $1`, teamID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
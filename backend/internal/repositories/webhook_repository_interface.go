@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// WebhookRepositoryInterface define a interface para o WebhookRepository
+type WebhookRepositoryInterface interface {
+	Create(webhook *models.TeamWebhook) error
+	GetByID(id uuid.UUID) (*models.TeamWebhook, error)
+	ListByTeam(teamID uuid.UUID) ([]models.TeamWebhook, error)
+	Update(webhook *models.TeamWebhook) error
+	Delete(id uuid.UUID) error
+	GetActiveForEvent(teamID uuid.UUID, event string) ([]models.TeamWebhook, error)
+	CreateDelivery(delivery *models.WebhookDelivery) error
+	ClaimDueDeliveries(now, leaseUntil time.Time, limit int) ([]models.PendingWebhookDelivery, error)
+	RecordDeliveryAttempt(delivery *models.WebhookDelivery) error
+	ListDeliveries(webhookID uuid.UUID, limit, offset int) ([]models.WebhookDelivery, int, error)
+	GetChatWebhook(teamID uuid.UUID) (*models.TeamChatWebhook, error)
+	SetChatWebhook(webhook *models.TeamChatWebhook) error
+	DeleteChatWebhook(teamID uuid.UUID) error
+}
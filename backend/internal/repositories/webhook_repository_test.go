@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRepository_GetActiveForEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	teamID := uuid.New()
+	webhookID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM team_webhooks\s+WHERE team_id = \$1 AND active AND \$2 = ANY\(events\)`).
+		WithArgs(teamID, models.WebhookEventItemAdded).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "url", "secret", "events", "active", "created_by", "created_at", "updated_at"}).
+			AddRow(webhookID, teamID, "https://example.com/hook", "s3cret", "{item_added,retrospective_ended}", true, nil, now, now))
+
+	webhooks, err := repo.GetActiveForEvent(teamID, models.WebhookEventItemAdded)
+
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+	assert.Equal(t, webhookID, webhooks[0].ID)
+	assert.Equal(t, []string{"item_added", "retrospective_ended"}, webhooks[0].Events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	webhookID := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM team_webhooks WHERE id = \$1`).
+		WithArgs(webhookID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(webhookID)
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_ClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	leaseUntil := now.Add(5 * time.Minute)
+	deliveryID := uuid.New()
+	payload := json.RawMessage(`{"event":"item_added"}`)
+
+	mock.ExpectQuery(`WITH claimed AS \(\s+UPDATE webhook_deliveries SET next_attempt_at = \$2.*d.status = 'pending' AND d.next_attempt_at <= \$1.*LIMIT \$3\s+FOR UPDATE OF d SKIP LOCKED`).
+		WithArgs(now, leaseUntil, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event", "payload", "status", "attempts", "created_at", "url", "secret"}).
+			AddRow(deliveryID, uuid.New(), "item_added", []byte(payload), "pending", 2, now, "https://example.com/hook", "s3cret"))
+
+	deliveries, err := repo.ClaimDueDeliveries(now, leaseUntil, 50)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, deliveryID, deliveries[0].ID)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, payload, deliveries[0].Payload)
+	assert.Equal(t, "s3cret", deliveries[0].Secret)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_SetChatWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	teamID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+
+	// One channel per team: setting it again replaces the provider and URL
+	mock.ExpectQuery(`INSERT INTO team_chat_webhooks .*ON CONFLICT \(team_id\) DO UPDATE\s+SET provider = EXCLUDED.provider, url = EXCLUDED.url`).
+		WithArgs(teamID, "slack", "https://hooks.slack.test/T000/B000", &userID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_by", "created_at", "updated_at"}).AddRow(userID, now, now))
+
+	webhook := &models.TeamChatWebhook{TeamID: teamID, Provider: "slack", URL: "https://hooks.slack.test/T000/B000", CreatedBy: &userID}
+	err = repo.SetChatWebhook(webhook)
+
+	assert.NoError(t, err)
+	assert.Equal(t, now, webhook.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_DeleteChatWebhook_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepository(db)
+	teamID := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM team_chat_webhooks WHERE team_id = \$1`).
+		WithArgs(teamID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteChatWebhook(teamID)
+
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// AdminService implements the system administration operations. Callers must
+// check that the acting user has the admin role.
+type AdminService struct {
+	userRepo  repositories.UserRepositoryInterface
+	retroRepo repositories.RetrospectiveRepositoryInterface
+}
+
+func NewAdminService(userRepo repositories.UserRepositoryInterface, retroRepo repositories.RetrospectiveRepositoryInterface) *AdminService {
+	return &AdminService{
+		userRepo:  userRepo,
+		retroRepo: retroRepo,
+	}
+}
+
+func (s *AdminService) ListUsers(search string, limit, offset int) (*models.AdminUserList, error) {
+	if limit < 1 || limit > 100 {
+		return nil, errors.New("limit must be between 1 and 100")
+	}
+	if offset < 0 {
+		return nil, errors.New("offset must not be negative")
+	}
+
+	users, total, err := s.userRepo.List(search, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AdminUserList{
+		Users:  users,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// SetUserSuspended suspends or reactivates an account. Suspended users cannot
+// log in and their existing tokens are rejected.
+func (s *AdminService) SetUserSuspended(adminID, userID uuid.UUID, suspended bool) (*models.User, error) {
+	if adminID == userID {
+		return nil, errors.New("cannot suspend your own account")
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if err := s.userRepo.SetSuspended(userID, suspended); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(userID)
+}
+
+func (s *AdminService) UpdateUserRole(adminID, userID uuid.UUID, role models.UserRole) (*models.User, error) {
+	if role != models.UserRoleAdmin && role != models.UserRoleUser {
+		return nil, errors.New("invalid role. Must be one of: admin, user")
+	}
+
+	// Prevents the last administrator from locking everyone out
+	if adminID == userID {
+		return nil, errors.New("cannot change your own role")
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if err := s.userRepo.UpdateRole(userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(userID)
+}
+
+// GetOrphanedRetrospectives returns the retrospectives left without an owner
+// after their creator deleted their account
+func (s *AdminService) GetOrphanedRetrospectives() ([]models.Retrospective, error) {
+	return s.retroRepo.GetOrphanedRetrospectives()
+}
+
+// TransferRetrospective hands a retrospective over to another user, who
+// becomes its owner
+func (s *AdminService) TransferRetrospective(retrospectiveID, newOwnerID uuid.UUID) (*models.Retrospective, error) {
+	if _, err := s.retroRepo.GetByID(retrospectiveID); err != nil {
+		return nil, errors.New("retrospective not found")
+	}
+
+	newOwner, err := s.userRepo.GetByID(newOwnerID)
+	if err != nil {
+		return nil, errors.New("new owner not found")
+	}
+	if newOwner.SuspendedAt != nil {
+		return nil, errors.New("new owner is suspended")
+	}
+
+	if err := s.retroRepo.TransferOwnership(retrospectiveID, newOwnerID); err != nil {
+		return nil, err
+	}
+
+	return s.retroRepo.GetByID(retrospectiveID)
+}
+
+func (s *AdminService) GetSystemStats() (*models.SystemStats, error) {
+	return s.userRepo.GetSystemStats()
+}
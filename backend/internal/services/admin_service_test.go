@@ -0,0 +1,106 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdminServiceWithUsers(users ...*models.User) (*AdminService, *MockUserRepository, *MockRetrospectiveRepository) {
+	userRepo := NewMockUserRepository()
+	retroRepo := NewMockRetrospectiveRepository()
+	for _, user := range users {
+		userRepo.users[user.ID] = user
+		userRepo.emails[user.Email] = user
+	}
+	return NewAdminService(userRepo, retroRepo), userRepo, retroRepo
+}
+
+func TestAdminService_ListUsers(t *testing.T) {
+	service, _, _ := newAdminServiceWithUsers(
+		&models.User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana"},
+		&models.User{ID: uuid.New(), Email: "bruno@example.com", Name: "Bruno"},
+	)
+
+	list, err := service.ListUsers("ana", 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "ana@example.com", list.Users[0].Email)
+
+	_, err = service.ListUsers("", 0, 0)
+	assert.EqualError(t, err, "limit must be between 1 and 100")
+
+	_, err = service.ListUsers("", 20, -1)
+	assert.EqualError(t, err, "offset must not be negative")
+}
+
+func TestAdminService_SetUserSuspended(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.UserRoleAdmin}
+	user := &models.User{ID: uuid.New(), Email: "user@example.com", Role: models.UserRoleUser}
+	service, _, _ := newAdminServiceWithUsers(admin, user)
+
+	suspended, err := service.SetUserSuspended(admin.ID, user.ID, true)
+	require.NoError(t, err)
+	assert.NotNil(t, suspended.SuspendedAt)
+
+	reactivated, err := service.SetUserSuspended(admin.ID, user.ID, false)
+	require.NoError(t, err)
+	assert.Nil(t, reactivated.SuspendedAt)
+
+	_, err = service.SetUserSuspended(admin.ID, admin.ID, true)
+	assert.EqualError(t, err, "cannot suspend your own account")
+
+	_, err = service.SetUserSuspended(admin.ID, uuid.New(), true)
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestAdminService_UpdateUserRole(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.UserRoleAdmin}
+	user := &models.User{ID: uuid.New(), Email: "user@example.com", Role: models.UserRoleUser}
+	service, _, _ := newAdminServiceWithUsers(admin, user)
+
+	promoted, err := service.UpdateUserRole(admin.ID, user.ID, models.UserRoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, models.UserRoleAdmin, promoted.Role)
+
+	_, err = service.UpdateUserRole(admin.ID, user.ID, "owner")
+	assert.EqualError(t, err, "invalid role. Must be one of: admin, user")
+
+	_, err = service.UpdateUserRole(admin.ID, admin.ID, models.UserRoleUser)
+	assert.EqualError(t, err, "cannot change your own role")
+}
+
+func TestAdminService_TransferRetrospective(t *testing.T) {
+	suspendedAt := time.Now()
+	owner := &models.User{ID: uuid.New(), Email: "owner@example.com"}
+	suspended := &models.User{ID: uuid.New(), Email: "suspended@example.com", SuspendedAt: &suspendedAt}
+	service, _, retroRepo := newAdminServiceWithUsers(owner, suspended)
+
+	orphanedID := uuid.New()
+	retroRepo.retrospectives[orphanedID] = &models.Retrospective{ID: orphanedID, Title: "Orphaned"}
+	ownedID := uuid.New()
+	retroRepo.retrospectives[ownedID] = &models.Retrospective{ID: ownedID, Title: "Owned", CreatedBy: owner.ID}
+
+	orphaned, err := service.GetOrphanedRetrospectives()
+	require.NoError(t, err)
+	require.Len(t, orphaned, 1)
+	assert.Equal(t, orphanedID, orphaned[0].ID)
+
+	retrospective, err := service.TransferRetrospective(orphanedID, owner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, owner.ID, retrospective.CreatedBy)
+
+	_, err = service.TransferRetrospective(ownedID, suspended.ID)
+	assert.EqualError(t, err, "new owner is suspended")
+
+	_, err = service.TransferRetrospective(ownedID, uuid.New())
+	assert.EqualError(t, err, "new owner not found")
+
+	_, err = service.TransferRetrospective(uuid.New(), owner.ID)
+	assert.EqualError(t, err, "retrospective not found")
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"educ-retro/internal/calendar"
+	"educ-retro/internal/models"
+	"educ-retro/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// calendarHistory is how far back the feed lists retrospectives and due dates
+	calendarHistory = 90 * 24 * time.Hour
+	// retrospectiveDuration is the length of retrospective events, which have no end time
+	retrospectiveDuration = time.Hour
+	// calendarActionItemsLimit caps the action items listed in a feed
+	calendarActionItemsLimit = 500
+)
+
+// CalendarService renders iCalendar feeds of scheduled retrospectives and
+// action item due dates. Calendar apps cannot authenticate, so each user has
+// a secret feed token that can be reset to revoke the feed URL.
+type CalendarService struct {
+	userRepo  repositories.UserRepositoryInterface
+	retroRepo repositories.RetrospectiveRepositoryInterface
+	appURL    string
+	now       func() time.Time
+}
+
+func NewCalendarService(userRepo repositories.UserRepositoryInterface, retroRepo repositories.RetrospectiveRepositoryInterface, appURL string) *CalendarService {
+	return &CalendarService{
+		userRepo:  userRepo,
+		retroRepo: retroRepo,
+		appURL:    strings.TrimSuffix(appURL, "/"),
+		now:       time.Now,
+	}
+}
+
+// GetFeedToken returns the user's feed token, creating it on first use
+func (s *CalendarService) GetFeedToken(userID uuid.UUID) (string, error) {
+	token, err := s.userRepo.GetCalendarToken(userID)
+	if err != nil || token != "" {
+		return token, err
+	}
+
+	return s.ResetFeedToken(userID)
+}
+
+// ResetFeedToken replaces the user's feed token; the previous feed URL stops working
+func (s *CalendarService) ResetFeedToken(userID uuid.UUID) (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(secret)
+
+	if err := s.userRepo.SetCalendarToken(userID, token); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// GetUserFeed returns the feed of the token's owner: the retrospectives they
+// can see that are scheduled and the unfinished action items assigned to them
+// that have a due date
+func (s *CalendarService) GetUserFeed(token string) ([]byte, error) {
+	if token == "" {
+		return nil, errors.New("invalid calendar token")
+	}
+
+	userID, err := s.userRepo.GetUserIDByCalendarToken(token)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("invalid calendar token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	since := s.now().Add(-calendarHistory)
+	cal := &calendar.Calendar{Name: "Educ Retro"}
+
+	retrospectives, err := s.retroRepo.GetScheduledRetrospectives(userID, since)
+	if err != nil {
+		return nil, err
+	}
+	for i := range retrospectives {
+		cal.Events = append(cal.Events, s.retrospectiveEvent(&retrospectives[i]))
+	}
+
+	actionItems, _, err := s.retroRepo.ListActionItems(userID, models.ActionItemFilter{
+		AssignedTo: &userID,
+		Status:     []string{"todo", "in_progress"},
+		DueFrom:    &since,
+		SortBy:     "due_date",
+		SortOrder:  "asc",
+		Limit:      calendarActionItemsLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range actionItems {
+		if actionItems[i].DueDate != nil {
+			cal.Events = append(cal.Events, s.actionItemEvent(&actionItems[i].ActionItem, actionItems[i].Retrospective.Title))
+		}
+	}
+
+	return cal.Render(), nil
+}
+
+// GetRetrospectiveCalendar returns an .ics with the retrospective, if it is
+// scheduled, and the due dates of its unfinished action items
+func (s *CalendarService) GetRetrospectiveCalendar(retrospectiveID uuid.UUID) ([]byte, error) {
+	retrospective, err := s.retroRepo.GetRetrospectiveWithDetails(retrospectiveID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("retrospective not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &calendar.Calendar{Name: retrospective.Title}
+	if retrospective.ScheduledAt != nil {
+		cal.Events = append(cal.Events, s.retrospectiveEvent(&retrospective.Retrospective))
+	}
+	for i := range retrospective.ActionItems {
+		actionItem := &retrospective.ActionItems[i]
+		if actionItem.DueDate != nil && actionItem.Status != "done" {
+			cal.Events = append(cal.Events, s.actionItemEvent(actionItem, retrospective.Title))
+		}
+	}
+
+	return cal.Render(), nil
+}
+
+func (s *CalendarService) retrospectiveURL(retrospectiveID uuid.UUID) string {
+	if s.appURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/retrospectives/%s", s.appURL, retrospectiveID)
+}
+
+func (s *CalendarService) retrospectiveEvent(retrospective *models.Retrospective) calendar.Event {
+	description := ""
+	if retrospective.Description != nil {
+		description = *retrospective.Description
+	}
+
+	return calendar.Event{
+		UID:         fmt.Sprintf("retrospective-%s@educ-retro", retrospective.ID),
+		Summary:     "Retrospectiva: " + retrospective.Title,
+		Description: description,
+		URL:         s.retrospectiveURL(retrospective.ID),
+		Start:       *retrospective.ScheduledAt,
+		End:         retrospective.ScheduledAt.Add(retrospectiveDuration),
+		Stamp:       retrospective.UpdatedAt,
+	}
+}
+
+// actionItemEvent is an all-day event on the due date of the action item
+func (s *CalendarService) actionItemEvent(actionItem *models.ActionItem, retrospectiveTitle string) calendar.Event {
+	description := fmt.Sprintf("Action item da retrospectiva \"%s\"", retrospectiveTitle)
+	if actionItem.Description != nil && *actionItem.Description != "" {
+		description = *actionItem.Description + "\n\n" + description
+	}
+
+	return calendar.Event{
+		UID:         fmt.Sprintf("action-item-%s@educ-retro", actionItem.ID),
+		Summary:     "Prazo: " + actionItem.Title,
+		Description: description,
+		URL:         s.retrospectiveURL(actionItem.RetrospectiveID),
+		Start:       *actionItem.DueDate,
+		AllDay:      true,
+		Stamp:       actionItem.UpdatedAt,
+	}
+}
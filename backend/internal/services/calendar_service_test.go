@@ -0,0 +1,117 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarService_FeedToken(t *testing.T) {
+	mockUserRepo := NewMockUserRepository()
+	service := NewCalendarService(mockUserRepo, NewMockRetrospectiveRepository(), "http://app.test/")
+
+	userID := uuid.New()
+	mockUserRepo.users[userID] = &models.User{ID: userID, Email: "ana@example.com", Name: "Ana"}
+
+	token, err := service.GetFeedToken(userID)
+	require.NoError(t, err)
+	assert.Len(t, token, 48)
+
+	again, err := service.GetFeedToken(userID)
+	require.NoError(t, err)
+	assert.Equal(t, token, again)
+
+	reset, err := service.ResetFeedToken(userID)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, reset)
+
+	// The previous feed URL stops working
+	_, err = service.GetUserFeed(token)
+	assert.EqualError(t, err, "invalid calendar token")
+	_, err = service.GetUserFeed(reset)
+	assert.NoError(t, err)
+	_, err = service.GetUserFeed("")
+	assert.EqualError(t, err, "invalid calendar token")
+}
+
+func TestCalendarService_GetUserFeed(t *testing.T) {
+	mockUserRepo := NewMockUserRepository()
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewCalendarService(mockUserRepo, mockRetroRepo, "http://app.test/")
+	service.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	userID := uuid.New()
+	mockUserRepo.users[userID] = &models.User{ID: userID, Email: "ana@example.com", Name: "Ana"}
+	token, err := service.GetFeedToken(userID)
+	require.NoError(t, err)
+
+	scheduledAt := time.Date(2024, 5, 10, 17, 0, 0, 0, time.UTC)
+	oldSchedule := time.Date(2023, 1, 10, 17, 0, 0, 0, time.UTC)
+	retro := &models.Retrospective{ID: uuid.New(), Title: "Sprint 12", ScheduledAt: &scheduledAt, CreatedBy: uuid.New()}
+	oldRetro := &models.Retrospective{ID: uuid.New(), Title: "Sprint 1", ScheduledAt: &oldSchedule, CreatedBy: userID}
+	otherRetro := &models.Retrospective{ID: uuid.New(), Title: "Outro time", ScheduledAt: &scheduledAt, CreatedBy: uuid.New()}
+	mockRetroRepo.retrospectives[retro.ID] = retro
+	mockRetroRepo.retrospectives[oldRetro.ID] = oldRetro
+	mockRetroRepo.retrospectives[otherRetro.ID] = otherRetro
+	mockRetroRepo.members[retro.ID] = []uuid.UUID{userID}
+
+	dueDate := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	assigned := &models.ActionItem{ID: uuid.New(), RetrospectiveID: retro.ID, Title: "Automatizar deploy", Status: "todo", AssignedTo: &userID, DueDate: &dueDate}
+	done := &models.ActionItem{ID: uuid.New(), RetrospectiveID: retro.ID, Title: "Revisar testes", Status: "done", AssignedTo: &userID, DueDate: &dueDate}
+	noDueDate := &models.ActionItem{ID: uuid.New(), RetrospectiveID: retro.ID, Title: "Sem prazo", Status: "todo", AssignedTo: &userID}
+	for _, actionItem := range []*models.ActionItem{assigned, done, noDueDate} {
+		mockRetroRepo.actionItems[actionItem.ID] = actionItem
+	}
+
+	feed, err := service.GetUserFeed(token)
+	require.NoError(t, err)
+	ics := string(feed)
+
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+	assert.Contains(t, ics, "UID:retrospective-"+retro.ID.String()+"@educ-retro")
+	assert.Contains(t, ics, "SUMMARY:Retrospectiva: Sprint 12")
+	assert.Contains(t, ics, "DTSTART:20240510T170000Z\r\nDTEND:20240510T180000Z")
+	assert.Contains(t, ics, "URL:http://app.test/retrospectives/"+retro.ID.String())
+	assert.Contains(t, ics, "SUMMARY:Prazo: Automatizar deploy")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20240520")
+	assert.NotContains(t, ics, "Revisar testes")
+	assert.NotContains(t, ics, "Sprint 1\r\n")
+	assert.NotContains(t, ics, "Outro time")
+
+	// Suspended users lose their feed
+	now := time.Now()
+	mockUserRepo.users[userID].SuspendedAt = &now
+	_, err = service.GetUserFeed(token)
+	assert.EqualError(t, err, "invalid calendar token")
+}
+
+func TestCalendarService_GetRetrospectiveCalendar(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewCalendarService(NewMockUserRepository(), mockRetroRepo, "http://app.test/")
+
+	dueDate := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	retro := models.Retrospective{ID: uuid.New(), Title: "Sprint 12"}
+	mockRetroRepo.details[retro.ID] = &models.RetrospectiveWithDetails{
+		Retrospective: retro,
+		ActionItems: []models.ActionItem{
+			{ID: uuid.New(), RetrospectiveID: retro.ID, Title: "Automatizar deploy", Status: "in_progress", DueDate: &dueDate},
+			{ID: uuid.New(), RetrospectiveID: retro.ID, Title: "Sem prazo", Status: "todo"},
+		},
+	}
+
+	ics, err := service.GetRetrospectiveCalendar(retro.ID)
+	require.NoError(t, err)
+	assert.Contains(t, string(ics), "X-WR-CALNAME:Sprint 12")
+	// Not scheduled: only the due date
+	assert.Equal(t, 1, strings.Count(string(ics), "BEGIN:VEVENT"))
+	assert.Contains(t, string(ics), "SUMMARY:Prazo: Automatizar deploy")
+
+	_, err = service.GetRetrospectiveCalendar(uuid.New())
+	assert.EqualError(t, err, "retrospective not found")
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/notifications"
+	"educ-retro/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// chatTopItems is how many of the most voted items the end summary lists
+const chatTopItems = 5
+
+// ChatService posts retrospective milestones to the chat channel of the
+// retrospective's team: when a retrospective starts or ends and when an action
+// item is created. The end message summarizes the most voted items and the
+// action items. Retrospectives without a team are never posted.
+type ChatService struct {
+	webhookRepo repositories.WebhookRepositoryInterface
+	retroRepo   repositories.RetrospectiveRepositoryInterface
+	userRepo    repositories.UserRepositoryInterface
+	templates   *TemplateService
+	appURL      string
+	// newSender builds the sender for the channel of a team
+	newSender func(provider, url string) (notifications.ChatSender, error)
+}
+
+func NewChatService(webhookRepo repositories.WebhookRepositoryInterface, retroRepo repositories.RetrospectiveRepositoryInterface, userRepo repositories.UserRepositoryInterface, appURL string) *ChatService {
+	return &ChatService{
+		webhookRepo: webhookRepo,
+		retroRepo:   retroRepo,
+		userRepo:    userRepo,
+		templates:   NewTemplateService(),
+		appURL:      strings.TrimSuffix(appURL, "/"),
+		newSender: func(provider, url string) (notifications.ChatSender, error) {
+			return notifications.NewChatNotifier(provider, url)
+		},
+	}
+}
+
+// HandleEvent is a RealtimeService listener. Messages are posted in the
+// background so a slow chat server never holds up the request.
+func (s *ChatService) HandleEvent(retrospectiveID uuid.UUID, eventType string, data interface{}) {
+	switch eventType {
+	case "retrospective_started", "retrospective_ended", "action_item_added":
+	default:
+		return
+	}
+
+	go func() {
+		if err := s.Notify(retrospectiveID, eventType, data); err != nil {
+			log.Printf("Failed to post %s for retrospective %s to chat: %v", eventType, retrospectiveID, err)
+		}
+	}()
+}
+
+// Notify builds the message for the event and posts it to the chat channel
+// of the retrospective's team, if it has one
+func (s *ChatService) Notify(retrospectiveID uuid.UUID, eventType string, data interface{}) error {
+	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
+	if err != nil {
+		return err
+	}
+	if retrospective.TeamID == uuid.Nil {
+		return nil
+	}
+
+	webhook, err := s.webhookRepo.GetChatWebhook(retrospective.TeamID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	details, err := s.retroRepo.GetRetrospectiveWithDetails(retrospectiveID)
+	if err != nil {
+		return err
+	}
+
+	var message *notifications.ChatMessage
+	switch eventType {
+	case "retrospective_started":
+		message = s.startedMessage(details)
+	case "retrospective_ended":
+		message = s.endedMessage(details)
+	case "action_item_added":
+		actionItem := actionItemFromEvent(data)
+		if actionItem == nil {
+			return fmt.Errorf("missing action item in %s event", eventType)
+		}
+		message = &notifications.ChatMessage{
+			Title: fmt.Sprintf("Novo action item em %s", details.Title),
+			Text:  s.actionItemLine(actionItem),
+			URL:   s.retrospectiveURL(retrospectiveID),
+		}
+	default:
+		return nil
+	}
+
+	sender, err := s.newSender(webhook.Provider, webhook.URL)
+	if err != nil {
+		return err
+	}
+	return sender.Send(message)
+}
+
+func (s *ChatService) startedMessage(details *models.RetrospectiveWithDetails) *notifications.ChatMessage {
+	message := &notifications.ChatMessage{
+		Title: fmt.Sprintf("Retrospectiva iniciada: %s", details.Title),
+		URL:   s.retrospectiveURL(details.ID),
+	}
+	if details.Description != nil {
+		message.Text = *details.Description
+	}
+
+	if len(details.CarriedActionItems) > 0 {
+		section := notifications.ChatSection{Title: "Action items pendentes da retrospectiva anterior"}
+		for i := range details.CarriedActionItems {
+			section.Lines = append(section.Lines, s.actionItemLine(&details.CarriedActionItems[i].ActionItem))
+		}
+		message.Sections = append(message.Sections, section)
+	}
+
+	return message
+}
+
+func (s *ChatService) endedMessage(details *models.RetrospectiveWithDetails) *notifications.ChatMessage {
+	message := &notifications.ChatMessage{
+		Title: fmt.Sprintf("Retrospectiva encerrada: %s", details.Title),
+		Text: fmt.Sprintf("%d itens, %d participantes e %d action items.",
+			len(details.Items), len(details.Participants), len(details.ActionItems)),
+		URL: s.retrospectiveURL(details.ID),
+	}
+
+	items := make([]models.RetrospectiveItem, 0, len(details.Items))
+	for _, item := range details.Items {
+		if item.Votes > 0 {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Votes > items[j].Votes
+	})
+	if len(items) > chatTopItems {
+		items = items[:chatTopItems]
+	}
+	if len(items) > 0 {
+		categories := s.categoryNames(details.Template)
+		section := notifications.ChatSection{Title: "Itens mais votados"}
+		for _, item := range items {
+			category := item.Category
+			if name, ok := categories[item.Category]; ok {
+				category = name
+			}
+			section.Lines = append(section.Lines, fmt.Sprintf("%s (%s, %s)", item.Content, category, pluralVotes(item.Votes)))
+		}
+		message.Sections = append(message.Sections, section)
+	}
+
+	if len(details.ActionItems) > 0 {
+		section := notifications.ChatSection{Title: "Action items"}
+		for i := range details.ActionItems {
+			section.Lines = append(section.Lines, s.actionItemLine(&details.ActionItems[i]))
+		}
+		message.Sections = append(message.Sections, section)
+	}
+
+	return message
+}
+
+// actionItemLine describes an action item with its assignee and due date
+func (s *ChatService) actionItemLine(actionItem *models.ActionItem) string {
+	line := actionItem.Title
+	if actionItem.AssignedTo != nil {
+		if user, err := s.userRepo.GetByID(*actionItem.AssignedTo); err == nil {
+			line += " — " + user.Name
+		}
+	}
+	if actionItem.DueDate != nil {
+		line += " (prazo " + actionItem.DueDate.Format("02/01/2006") + ")"
+	}
+	return line
+}
+
+func (s *ChatService) categoryNames(template models.RetrospectiveTemplate) map[string]string {
+	names := map[string]string{}
+	categories, err := s.templates.GetTemplateCategories(string(template))
+	if err != nil {
+		return names
+	}
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	return names
+}
+
+func (s *ChatService) retrospectiveURL(retrospectiveID uuid.UUID) string {
+	if s.appURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/retrospectives/%s", s.appURL, retrospectiveID)
+}
+
+// actionItemFromEvent reads the action item out of an action_item_added
+// event payload
+func actionItemFromEvent(data interface{}) *models.ActionItem {
+	payload, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	actionItem, _ := payload["action_item"].(*models.ActionItem)
+	return actionItem
+}
+
+func pluralVotes(votes int) string {
+	if votes == 1 {
+		return "1 voto"
+	}
+	return fmt.Sprintf("%d votos", votes)
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/notifications"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChatSender records the messages instead of posting them
+type fakeChatSender struct {
+	messages []*notifications.ChatMessage
+}
+
+func (f *fakeChatSender) Name() string {
+	return "fake"
+}
+
+func (f *fakeChatSender) Send(message *notifications.ChatMessage) error {
+	f.messages = append(f.messages, message)
+	return nil
+}
+
+func TestChatService_EndedSummary(t *testing.T) {
+	mockWebhookRepo := NewMockWebhookRepository()
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockUserRepo := NewMockUserRepository()
+	service := NewChatService(mockWebhookRepo, mockRetroRepo, mockUserRepo, "http://app.test/")
+
+	sender := &fakeChatSender{}
+	var provider, url string
+	service.newSender = func(p, u string) (notifications.ChatSender, error) {
+		provider, url = p, u
+		return sender, nil
+	}
+
+	teamID := uuid.New()
+	mockWebhookRepo.chatWebhooks[teamID] = &models.TeamChatWebhook{TeamID: teamID, Provider: notifications.ChatSlack, URL: "https://hooks.slack.test/T000/B000"}
+
+	assigneeID := uuid.New()
+	mockUserRepo.users[assigneeID] = &models.User{ID: assigneeID, Name: "Ana"}
+
+	dueDate := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	details := &models.RetrospectiveWithDetails{
+		Retrospective: models.Retrospective{
+			ID:       uuid.New(),
+			TeamID:   teamID,
+			Title:    "Sprint 12",
+			Template: models.TemplateStartStopContinue,
+			Status:   models.RetroStatusClosed,
+		},
+		Items: []models.RetrospectiveItem{
+			{ID: uuid.New(), Category: "stop", Content: "Deploy manual", Votes: 2},
+			{ID: uuid.New(), Category: "start", Content: "Sem votos", Votes: 0},
+			{ID: uuid.New(), Category: "start", Content: "Pair programming", Votes: 5},
+		},
+		ActionItems: []models.ActionItem{
+			{ID: uuid.New(), Title: "Automatizar deploy", AssignedTo: &assigneeID, DueDate: &dueDate},
+		},
+	}
+	mockRetroRepo.retrospectives[details.ID] = &details.Retrospective
+	mockRetroRepo.details[details.ID] = details
+
+	require.NoError(t, service.Notify(details.ID, "retrospective_ended", nil))
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, notifications.ChatSlack, provider)
+	assert.Equal(t, "https://hooks.slack.test/T000/B000", url)
+
+	message := sender.messages[0]
+	assert.Equal(t, "Retrospectiva encerrada: Sprint 12", message.Title)
+	assert.Equal(t, "http://app.test/retrospectives/"+details.ID.String(), message.URL)
+	require.Len(t, message.Sections, 2)
+	assert.Equal(t, []string{"Pair programming (Start, 5 votos)", "Deploy manual (Stop, 2 votos)"}, message.Sections[0].Lines)
+	assert.Equal(t, []string{"Automatizar deploy — Ana (prazo 20/05/2024)"}, message.Sections[1].Lines)
+}
+
+func TestChatService_ActionItemAdded(t *testing.T) {
+	mockWebhookRepo := NewMockWebhookRepository()
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewChatService(mockWebhookRepo, mockRetroRepo, NewMockUserRepository(), "")
+
+	sender := &fakeChatSender{}
+	service.newSender = func(provider, url string) (notifications.ChatSender, error) {
+		return sender, nil
+	}
+
+	teamID := uuid.New()
+	mockWebhookRepo.chatWebhooks[teamID] = &models.TeamChatWebhook{TeamID: teamID, Provider: notifications.ChatTeams, URL: "https://teams.test/webhook"}
+	details := &models.RetrospectiveWithDetails{
+		Retrospective: models.Retrospective{ID: uuid.New(), TeamID: teamID, Title: "Sprint 12", Status: models.RetroStatusActive},
+	}
+	mockRetroRepo.retrospectives[details.ID] = &details.Retrospective
+	mockRetroRepo.details[details.ID] = details
+
+	actionItem := &models.ActionItem{ID: uuid.New(), RetrospectiveID: details.ID, Title: "Revisar testes"}
+	require.NoError(t, service.Notify(details.ID, "action_item_added", map[string]interface{}{"action_item": actionItem}))
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, "Novo action item em Sprint 12", sender.messages[0].Title)
+	assert.Equal(t, "Revisar testes", sender.messages[0].Text)
+
+	assert.Error(t, service.Notify(details.ID, "action_item_added", map[string]interface{}{}))
+}
+
+func TestChatService_Started(t *testing.T) {
+	mockWebhookRepo := NewMockWebhookRepository()
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewChatService(mockWebhookRepo, mockRetroRepo, NewMockUserRepository(), "")
+
+	sender := &fakeChatSender{}
+	service.newSender = func(provider, url string) (notifications.ChatSender, error) {
+		return sender, nil
+	}
+
+	teamID := uuid.New()
+	mockWebhookRepo.chatWebhooks[teamID] = &models.TeamChatWebhook{TeamID: teamID, Provider: notifications.ChatMattermost, URL: "https://chat.test/hooks/abc"}
+	details := &models.RetrospectiveWithDetails{
+		Retrospective: models.Retrospective{ID: uuid.New(), TeamID: teamID, Title: "Sprint 12", Status: models.RetroStatusActive},
+	}
+	mockRetroRepo.retrospectives[details.ID] = &details.Retrospective
+	mockRetroRepo.details[details.ID] = details
+
+	require.NoError(t, service.Notify(details.ID, "retrospective_started", nil))
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, "Retrospectiva iniciada: Sprint 12", sender.messages[0].Title)
+	assert.Empty(t, sender.messages[0].Sections)
+
+	// Other events are not posted
+	require.NoError(t, service.Notify(details.ID, "item_voted", nil))
+	assert.Len(t, sender.messages, 1)
+}
+
+func TestChatService_OnlyTeamChannel(t *testing.T) {
+	mockWebhookRepo := NewMockWebhookRepository()
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewChatService(mockWebhookRepo, mockRetroRepo, NewMockUserRepository(), "")
+
+	urls := []string{}
+	sender := &fakeChatSender{}
+	service.newSender = func(provider, url string) (notifications.ChatSender, error) {
+		urls = append(urls, url)
+		return sender, nil
+	}
+
+	teamA := uuid.New()
+	teamB := uuid.New()
+	mockWebhookRepo.chatWebhooks[teamA] = &models.TeamChatWebhook{TeamID: teamA, Provider: notifications.ChatSlack, URL: "https://hooks.slack.test/team-a"}
+
+	personal := &models.Retrospective{ID: uuid.New(), Title: "Pessoal", Status: models.RetroStatusActive}
+	ofTeamB := &models.Retrospective{ID: uuid.New(), TeamID: teamB, Title: "Time B", Status: models.RetroStatusActive}
+	ofTeamA := &models.Retrospective{ID: uuid.New(), TeamID: teamA, Title: "Time A", Status: models.RetroStatusActive}
+	for _, retro := range []*models.Retrospective{personal, ofTeamB, ofTeamA} {
+		mockRetroRepo.retrospectives[retro.ID] = retro
+		mockRetroRepo.details[retro.ID] = &models.RetrospectiveWithDetails{Retrospective: *retro}
+	}
+
+	// Without a team or a team channel nothing is posted
+	require.NoError(t, service.Notify(personal.ID, "retrospective_started", nil))
+	require.NoError(t, service.Notify(ofTeamB.ID, "retrospective_started", nil))
+	assert.Empty(t, sender.messages)
+
+	require.NoError(t, service.Notify(ofTeamA.ID, "retrospective_started", nil))
+	assert.Len(t, sender.messages, 1)
+	assert.Equal(t, []string{"https://hooks.slack.test/team-a"}, urls)
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"educ-retro/internal/issuetracker"
+	"educ-retro/internal/models"
+	"educ-retro/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// issueSyncBatchSize is how many linked action items a sync run checks, the
+// least recently synced first
+const issueSyncBatchSize = 100
+
+// IssueTrackerService exports action items as issues of the configured
+// tracker and brings the status of the issues back to the action items.
+// Status changes coming from the tracker are recorded in the action item
+// history and broadcast like changes made in the app.
+type IssueTrackerService struct {
+	retroRepo       repositories.RetrospectiveRepositoryInterface
+	provider        issuetracker.Provider
+	realtimeService *RealtimeService
+	appURL          string
+	now             func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	runMu    sync.Mutex
+}
+
+// NewIssueTrackerService creates the service. provider may be nil when no
+// tracker is configured, in which case exports and syncs fail.
+func NewIssueTrackerService(retroRepo repositories.RetrospectiveRepositoryInterface, provider issuetracker.Provider, realtimeService *RealtimeService, appURL string) *IssueTrackerService {
+	return &IssueTrackerService{
+		retroRepo:       retroRepo,
+		provider:        provider,
+		realtimeService: realtimeService,
+		appURL:          strings.TrimSuffix(appURL, "/"),
+		now:             time.Now,
+		stop:            make(chan struct{}),
+	}
+}
+
+// Provider returns the name of the configured tracker, or "" when there is none
+func (s *IssueTrackerService) Provider() string {
+	if s.provider == nil {
+		return ""
+	}
+	return s.provider.Name()
+}
+
+// getManagedActionItem returns the action item if the user can manage it
+func (s *IssueTrackerService) getManagedActionItem(actionItemID, userID uuid.UUID) (*models.ActionItem, error) {
+	if s.provider == nil {
+		return nil, errors.New("issue tracker not configured")
+	}
+
+	actionItem, err := s.retroRepo.GetActionItemByID(actionItemID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("action item not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := canManageActionItem(s.retroRepo, actionItem, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("access denied")
+	}
+
+	return actionItem, nil
+}
+
+// ExportActionItem creates an issue for the action item and links them
+func (s *IssueTrackerService) ExportActionItem(actionItemID, userID uuid.UUID) (*models.ActionItem, error) {
+	actionItem, err := s.getManagedActionItem(actionItemID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if actionItem.ExternalID != nil {
+		return nil, errors.New("action item already exported")
+	}
+
+	retrospective, err := s.retroRepo.GetByID(actionItem.RetrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err := s.provider.CreateIssue(&issuetracker.NewIssue{
+		Title:   actionItem.Title,
+		Body:    s.issueBody(actionItem, retrospective),
+		DueDate: actionItem.DueDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("issue tracker error: %w", err)
+	}
+
+	err = s.retroRepo.SetActionItemExternalLink(actionItem.ID, s.provider.Name(), issue.ID, issue.URL, issue.Status)
+	if err == sql.ErrNoRows {
+		// Exported concurrently; the issue just created is left unlinked
+		return nil, errors.New("action item already exported")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	provider := s.provider.Name()
+	actionItem.ExternalProvider = &provider
+	actionItem.ExternalID = &issue.ID
+	actionItem.ExternalURL = &issue.URL
+	actionItem.ExternalSyncedAt = &now
+
+	s.broadcast(actionItem, []models.ActionItemEvent{})
+
+	return actionItem, nil
+}
+
+func (s *IssueTrackerService) issueBody(actionItem *models.ActionItem, retrospective *models.Retrospective) string {
+	var b strings.Builder
+
+	if actionItem.Description != nil && *actionItem.Description != "" {
+		b.WriteString(*actionItem.Description)
+		b.WriteString("\n\n---\n")
+	}
+	fmt.Fprintf(&b, "Action item da retrospectiva \"%s\".", retrospective.Title)
+	if s.appURL != "" {
+		fmt.Fprintf(&b, "\n%s/retrospectives/%s", s.appURL, retrospective.ID)
+	}
+
+	return b.String()
+}
+
+// SyncActionItem brings the status of the linked issue to the action item
+// right away. It returns the action item and the recorded history events.
+func (s *IssueTrackerService) SyncActionItem(actionItemID, userID uuid.UUID) (*models.ActionItem, []models.ActionItemEvent, error) {
+	actionItem, err := s.getManagedActionItem(actionItemID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if actionItem.ExternalID == nil {
+		return nil, nil, errors.New("action item not exported")
+	}
+	if actionItem.ExternalProvider == nil || *actionItem.ExternalProvider != s.provider.Name() {
+		return nil, nil, errors.New("action item was exported to another issue tracker")
+	}
+
+	return s.sync(actionItem)
+}
+
+// Start syncs the linked action items immediately and then every interval
+// until Stop is called
+func (s *IssueTrackerService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if changed, err := s.RunOnce(); err != nil {
+				log.Printf("Failed to sync action items with %s: %v", s.Provider(), err)
+			} else if changed > 0 {
+				log.Printf("Updated %d action items from %s", changed, s.Provider())
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *IssueTrackerService) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// RunOnce syncs a batch of linked action items and returns how many changed
+// status. An issue that cannot be read is skipped until the next run.
+func (s *IssueTrackerService) RunOnce() (int, error) {
+	if s.provider == nil {
+		return 0, nil
+	}
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	actionItems, err := s.retroRepo.GetExternallyLinkedActionItems(s.provider.Name(), issueSyncBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for i := range actionItems {
+		_, events, err := s.sync(&actionItems[i])
+		if err != nil {
+			log.Printf("Failed to sync action item %s: %v", actionItems[i].ID, err)
+			continue
+		}
+		if len(events) > 0 {
+			changed++
+		}
+	}
+
+	return changed, nil
+}
+
+// sync reads the issue of the action item and applies its status when the
+// issue changed since the last sync. While the issue stays as it was, the
+// status set in the app is kept.
+func (s *IssueTrackerService) sync(actionItem *models.ActionItem) (*models.ActionItem, []models.ActionItemEvent, error) {
+	issue, err := s.provider.GetIssue(*actionItem.ExternalID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("issue tracker error: %w", err)
+	}
+
+	lastSeen, err := s.retroRepo.GetActionItemExternalStatus(actionItem.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.now()
+	events := []models.ActionItemEvent{}
+	updated := actionItem
+
+	status := actionItem.Status
+	if lastSeen == nil || *lastSeen != issue.Status {
+		status = syncedStatus(actionItem.Status, issue)
+	}
+	if status != actionItem.Status {
+		req := &models.ActionItemUpdateRequest{Status: &status}
+		if status == "done" {
+			completedAt := now.UTC().Format("2006-01-02T15:04:05Z")
+			req.CompletedAt = &completedAt
+		} else if actionItem.Status == "done" {
+			empty := ""
+			req.CompletedAt = &empty
+		}
+
+		updated, err = s.retroRepo.UpdateActionItem(actionItem.ID, req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		note := fmt.Sprintf("Sincronizado de %s (%s)", s.provider.Name(), issue.ID)
+		oldStatus := actionItem.Status
+		events = append(events, models.ActionItemEvent{
+			ActionItemID: actionItem.ID,
+			EventType:    models.ActionItemEventStatusChanged,
+			OldValue:     &oldStatus,
+			NewValue:     &status,
+			Note:         &note,
+		})
+		if err := s.retroRepo.AddActionItemEvents(events); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := s.retroRepo.MarkActionItemSynced(actionItem.ID, now, issue.Status); err != nil {
+		return nil, nil, err
+	}
+	updated.ExternalSyncedAt = &now
+
+	if len(events) > 0 {
+		s.broadcast(updated, events)
+	}
+
+	return updated, events, nil
+}
+
+// syncedStatus is the action item status after syncing with the issue. When
+// the tracker only tells that the issue is open, an action item in progress
+// stays in progress and a done one is reopened.
+func syncedStatus(current string, issue *issuetracker.Issue) string {
+	switch {
+	case issue.Status != "":
+		return issue.Status
+	case current == "done":
+		return "todo"
+	default:
+		return current
+	}
+}
+
+// broadcast sends action_item_updated to the retrospective of the action item
+// and to the retrospectives it was carried into
+func (s *IssueTrackerService) broadcast(actionItem *models.ActionItem, events []models.ActionItemEvent) {
+	if s.realtimeService == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"action_item": actionItem,
+		"events":      events,
+	}
+
+	carriedInto, _ := s.retroRepo.GetCarryoverRetrospectiveIDs(actionItem.ID)
+	s.realtimeService.BroadcastToRetrospectives(actionItem.RetrospectiveID, carriedInto, "action_item_updated", data)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"educ-retro/internal/issuetracker"
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIssueTracker keeps issues in memory
+type fakeIssueTracker struct {
+	created []issuetracker.NewIssue
+	issues  map[string]*issuetracker.Issue
+	fail    bool
+}
+
+func newFakeIssueTracker() *fakeIssueTracker {
+	return &fakeIssueTracker{issues: make(map[string]*issuetracker.Issue)}
+}
+
+func (t *fakeIssueTracker) Name() string { return "github" }
+
+func (t *fakeIssueTracker) CreateIssue(issue *issuetracker.NewIssue) (*issuetracker.Issue, error) {
+	if t.fail {
+		return nil, errors.New("tracker unavailable")
+	}
+	t.created = append(t.created, *issue)
+	id := "1"
+	t.issues[id] = &issuetracker.Issue{ID: id, URL: "https://github.com/acme/retro/issues/1"}
+	return t.issues[id], nil
+}
+
+func (t *fakeIssueTracker) GetIssue(externalID string) (*issuetracker.Issue, error) {
+	if t.fail {
+		return nil, errors.New("tracker unavailable")
+	}
+	issue := *t.issues[externalID]
+	return &issue, nil
+}
+
+// addActionItem adds an action item in progress to a new retrospective
+func addActionItem(mockRetroRepo *MockRetrospectiveRepository) *models.ActionItem {
+	userID := uuid.New()
+	retrospectiveID := uuid.New()
+	description := "O deploy ainda é manual"
+	mockRetroRepo.retrospectives[retrospectiveID] = &models.Retrospective{ID: retrospectiveID, Title: "Sprint 12", CreatedBy: userID}
+	actionItem := &models.ActionItem{
+		ID:              uuid.New(),
+		RetrospectiveID: retrospectiveID,
+		Title:           "Automatizar deploy",
+		Description:     &description,
+		Status:          "in_progress",
+		CreatedBy:       userID,
+	}
+	mockRetroRepo.actionItems[actionItem.ID] = actionItem
+	return actionItem
+}
+
+func TestIssueTrackerService_ExportActionItem(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	tracker := newFakeIssueTracker()
+	service := NewIssueTrackerService(mockRetroRepo, tracker, nil, "http://app.test/")
+	actionItem := addActionItem(mockRetroRepo)
+
+	exported, err := service.ExportActionItem(actionItem.ID, actionItem.CreatedBy)
+	require.NoError(t, err)
+	assert.Equal(t, "github", *exported.ExternalProvider)
+	assert.Equal(t, "1", *exported.ExternalID)
+	assert.Equal(t, "https://github.com/acme/retro/issues/1", *exported.ExternalURL)
+
+	require.Len(t, tracker.created, 1)
+	assert.Equal(t, "Automatizar deploy", tracker.created[0].Title)
+	assert.Contains(t, tracker.created[0].Body, "O deploy ainda é manual")
+	assert.Contains(t, tracker.created[0].Body, "http://app.test/retrospectives/"+actionItem.RetrospectiveID.String())
+
+	_, err = service.ExportActionItem(actionItem.ID, actionItem.CreatedBy)
+	assert.EqualError(t, err, "action item already exported")
+	assert.Len(t, tracker.created, 1)
+}
+
+func TestIssueTrackerService_ExportActionItem_Errors(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	tracker := newFakeIssueTracker()
+	service := NewIssueTrackerService(mockRetroRepo, tracker, nil, "http://app.test/")
+	actionItem := addActionItem(mockRetroRepo)
+
+	_, err := service.ExportActionItem(actionItem.ID, uuid.New())
+	assert.EqualError(t, err, "access denied")
+
+	_, err = service.ExportActionItem(uuid.New(), actionItem.CreatedBy)
+	assert.EqualError(t, err, "action item not found")
+
+	tracker.fail = true
+	_, err = service.ExportActionItem(actionItem.ID, actionItem.CreatedBy)
+	assert.EqualError(t, err, "issue tracker error: tracker unavailable")
+	assert.Nil(t, actionItem.ExternalID)
+
+	unconfigured := NewIssueTrackerService(NewMockRetrospectiveRepository(), nil, nil, "")
+	assert.Equal(t, "", unconfigured.Provider())
+	_, err = unconfigured.ExportActionItem(actionItem.ID, actionItem.CreatedBy)
+	assert.EqualError(t, err, "issue tracker not configured")
+}
+
+func TestIssueTrackerService_SyncActionItem(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	tracker := newFakeIssueTracker()
+	service := NewIssueTrackerService(mockRetroRepo, tracker, nil, "http://app.test/")
+	actionItem := addActionItem(mockRetroRepo)
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	_, _, err := service.SyncActionItem(actionItem.ID, actionItem.CreatedBy)
+	assert.EqualError(t, err, "action item not exported")
+
+	_, err = service.ExportActionItem(actionItem.ID, actionItem.CreatedBy)
+	require.NoError(t, err)
+
+	// An open GitHub issue keeps the action item in progress
+	synced, events, err := service.SyncActionItem(actionItem.ID, actionItem.CreatedBy)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, "in_progress", synced.Status)
+	assert.Equal(t, now, *synced.ExternalSyncedAt)
+
+	// Closing the issue completes it
+	tracker.issues["1"].Status = "done"
+	synced, events, err = service.SyncActionItem(actionItem.ID, actionItem.CreatedBy)
+	require.NoError(t, err)
+	assert.Equal(t, "done", synced.Status)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.ActionItemEventStatusChanged, events[0].EventType)
+	assert.Equal(t, "in_progress", *events[0].OldValue)
+	assert.Equal(t, "done", *events[0].NewValue)
+	assert.Nil(t, events[0].UserID)
+	assert.Equal(t, "Sincronizado de github (1)", *events[0].Note)
+	assert.Len(t, mockRetroRepo.events, 1)
+}
+
+func TestIssueTrackerService_RunOnce(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	tracker := newFakeIssueTracker()
+	service := NewIssueTrackerService(mockRetroRepo, tracker, nil, "http://app.test/")
+	actionItem := addActionItem(mockRetroRepo)
+
+	_, err := service.ExportActionItem(actionItem.ID, actionItem.CreatedBy)
+	require.NoError(t, err)
+
+	// Completed in the app while the issue is still open: the sync keeps it
+	mockRetroRepo.actionItems[actionItem.ID].Status = "done"
+	changed, err := service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 0, changed)
+	assert.Equal(t, "done", mockRetroRepo.actionItems[actionItem.ID].Status)
+
+	// Closed and then reopened in the tracker
+	tracker.issues["1"].Status = "done"
+	changed, err = service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 0, changed)
+
+	tracker.issues["1"].Status = ""
+	changed, err = service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, "todo", mockRetroRepo.actionItems[actionItem.ID].Status)
+
+	changed, err = service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 0, changed)
+
+	// Unreadable issues are skipped
+	tracker.fail = true
+	changed, err = service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 0, changed)
+}
+
+func TestSyncedStatus(t *testing.T) {
+	assert.Equal(t, "done", syncedStatus("todo", &issuetracker.Issue{Status: "done"}))
+	assert.Equal(t, "in_progress", syncedStatus("todo", &issuetracker.Issue{Status: "in_progress"}))
+	assert.Equal(t, "in_progress", syncedStatus("in_progress", &issuetracker.Issue{}))
+	assert.Equal(t, "todo", syncedStatus("done", &issuetracker.Issue{}))
+}
@@ -22,8 +22,14 @@ type RealtimeService struct {
 	broadcast  chan RealtimeEvent
 	blurStates map[uuid.UUID]bool // Map to store blur state per retrospective
 	blurMu     sync.RWMutex
+	listeners  []EventListener
+	listenerMu sync.RWMutex
 }
 
+// EventListener receives every event broadcast to a retrospective, e.g. to
+// forward it to integrations outside the browser
+type EventListener func(retrospectiveID uuid.UUID, eventType string, data interface{})
+
 type RealtimeClient struct {
 	ID              string
 	RetrospectiveID uuid.UUID
@@ -94,6 +100,23 @@ func (s *RealtimeService) UnregisterClient(client *RealtimeClient) {
 }
 
 func (s *RealtimeService) BroadcastToRetrospective(retrospectiveID uuid.UUID, eventType string, data interface{}) {
+	s.sendToClients(eventType, data)
+	s.notifyListeners(retrospectiveID, eventType, data)
+}
+
+// BroadcastToRetrospectives sends an event of the retrospective also to the
+// other retrospectives it is shown in, such as the ones an action item was
+// carried into. Listeners receive it once, for retrospectiveID, so webhooks
+// and chat see one event per change.
+func (s *RealtimeService) BroadcastToRetrospectives(retrospectiveID uuid.UUID, alsoTo []uuid.UUID, eventType string, data interface{}) {
+	s.sendToClients(eventType, data)
+	for range alsoTo {
+		s.sendToClients(eventType, data)
+	}
+	s.notifyListeners(retrospectiveID, eventType, data)
+}
+
+func (s *RealtimeService) sendToClients(eventType string, data interface{}) {
 	event := RealtimeEvent{
 		Type:      eventType,
 		Data:      data,
@@ -115,6 +138,21 @@ func (s *RealtimeService) BroadcastToRetrospective(retrospectiveID uuid.UUID, ev
 	s.clientsMu.RUnlock()
 }
 
+// AddListener registers a listener for the events broadcast to retrospectives
+func (s *RealtimeService) AddListener(listener EventListener) {
+	s.listenerMu.Lock()
+	defer s.listenerMu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *RealtimeService) notifyListeners(retrospectiveID uuid.UUID, eventType string, data interface{}) {
+	s.listenerMu.RLock()
+	defer s.listenerMu.RUnlock()
+	for _, listener := range s.listeners {
+		listener(retrospectiveID, eventType, data)
+	}
+}
+
 func (c *RealtimeClient) SendJSON() ([]byte, error) {
 	select {
 	case event := <-c.Send:
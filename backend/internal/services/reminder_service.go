@@ -0,0 +1,104 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"educ-retro/internal/notifications"
+	"educ-retro/internal/repositories"
+)
+
+// ReminderService periodically reminds assignees of action items that are
+// due soon or overdue. Each reminder is delivered once per due date.
+type ReminderService struct {
+	retroRepo repositories.RetrospectiveRepositoryInterface
+	notifier  notifications.Notifier
+	// dueSoon is how long before the due date the due soon reminder is sent
+	dueSoon time.Duration
+	now     func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	runMu    sync.Mutex
+}
+
+func NewReminderService(retroRepo repositories.RetrospectiveRepositoryInterface, notifier notifications.Notifier, dueSoon time.Duration) *ReminderService {
+	return &ReminderService{
+		retroRepo: retroRepo,
+		notifier:  notifier,
+		dueSoon:   dueSoon,
+		now:       time.Now,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start runs the reminders immediately and then every interval until Stop is called
+func (s *ReminderService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if sent, err := s.RunOnce(); err != nil {
+				log.Printf("Failed to send action item reminders: %v", err)
+			} else if sent > 0 {
+				log.Printf("Sent %d action item reminders", sent)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *ReminderService) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// RunOnce delivers the pending reminders and returns how many were sent.
+// Failed deliveries are released and retried on the next run.
+func (s *ReminderService) RunOnce() (int, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	// Due dates are stored as dates (midnight UTC): an item is overdue from the
+	// day after its due date
+	today := s.now().UTC().Truncate(24 * time.Hour)
+
+	reminders, err := s.retroRepo.GetPendingReminders(today, today.Add(s.dueSoon))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range reminders {
+		reminder := &reminders[i]
+
+		claimed, err := s.retroRepo.ClaimReminder(reminder, s.notifier.Name())
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.notifier.Notify(reminder); err != nil {
+			log.Printf("Failed to deliver %s reminder for action item %s: %v", reminder.Kind, reminder.ActionItemID, err)
+			if err := s.retroRepo.ReleaseReminder(reminder); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		if err := s.retroRepo.MarkReminderSent(reminder); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNotifier struct {
+	delivered []models.ActionItemReminder
+	fail      bool
+}
+
+func (n *mockNotifier) Name() string { return "mock" }
+
+func (n *mockNotifier) Notify(reminder *models.ActionItemReminder) error {
+	if n.fail {
+		return errors.New("channel unavailable")
+	}
+	n.delivered = append(n.delivered, *reminder)
+	return nil
+}
+
+func TestReminderService_RunOnce(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	notifier := &mockNotifier{}
+	service := NewReminderService(mockRetroRepo, notifier, 48*time.Hour)
+	service.now = func() time.Time { return time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC) }
+
+	mockRetroRepo.pendingReminders = []models.ActionItemReminder{
+		{Kind: models.ReminderOverdue, ActionItemID: uuid.New(), UserID: uuid.New(), DueDate: time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)},
+		{Kind: models.ReminderDueSoon, ActionItemID: uuid.New(), UserID: uuid.New(), DueDate: time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC)},
+	}
+
+	sent, err := service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Len(t, notifier.delivered, 2)
+
+	// Overdue from the day after the due date, due soon within the next 48 hours
+	assert.Equal(t, time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), mockRetroRepo.lastReminderWindow[0])
+	assert.Equal(t, time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC), mockRetroRepo.lastReminderWindow[1])
+
+	// Reminders are delivered only once
+	sent, err = service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Len(t, notifier.delivered, 2)
+}
+
+func TestReminderService_RetriesFailedDeliveries(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	notifier := &mockNotifier{fail: true}
+	service := NewReminderService(mockRetroRepo, notifier, 48*time.Hour)
+
+	mockRetroRepo.pendingReminders = []models.ActionItemReminder{
+		{Kind: models.ReminderDueSoon, ActionItemID: uuid.New(), UserID: uuid.New(), DueDate: time.Now()},
+	}
+
+	sent, err := service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Empty(t, mockRetroRepo.sentReminders)
+
+	notifier.fail = false
+	sent, err = service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+}
@@ -1,22 +1,33 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
 
+	"educ-retro/internal/export"
+	"educ-retro/internal/importer"
 	"educ-retro/internal/models"
 	"educ-retro/internal/repositories"
+	"educ-retro/internal/similarity"
 
 	"github.com/google/uuid"
 )
 
 type RetrospectiveService struct {
-	retroRepo repositories.RetrospectiveRepositoryInterface
+	retroRepo       repositories.RetrospectiveRepositoryInterface
+	realtimeService *RealtimeService
 }
 
-func NewRetrospectiveService(retroRepo repositories.RetrospectiveRepositoryInterface) *RetrospectiveService {
+func NewRetrospectiveService(retroRepo repositories.RetrospectiveRepositoryInterface, realtimeService *RealtimeService) *RetrospectiveService {
 	return &RetrospectiveService{
-		retroRepo: retroRepo,
+		retroRepo:       retroRepo,
+		realtimeService: realtimeService,
 	}
 }
 
@@ -29,6 +40,25 @@ func (s *RetrospectiveService) CreateRetrospective(userID uuid.UUID, req *models
 		CreatedBy:   userID,
 	}
 
+	if req.ScheduledAt != nil {
+		if !req.ScheduledAt.After(time.Now()) {
+			return nil, errors.New("scheduled_at must be in the future")
+		}
+		retrospective.ScheduledAt = req.ScheduledAt
+	}
+
+	// A team retrospective can only be created by a member of the team
+	if req.TeamID != nil && *req.TeamID != uuid.Nil {
+		_, err := s.retroRepo.GetTeamRole(*req.TeamID, userID)
+		if err == sql.ErrNoRows {
+			return nil, errors.New("not a member of this team")
+		}
+		if err != nil {
+			return nil, err
+		}
+		retrospective.TeamID = *req.TeamID
+	}
+
 	err := s.retroRepo.Create(retrospective)
 	if err != nil {
 		return nil, err
@@ -37,6 +67,335 @@ func (s *RetrospectiveService) CreateRetrospective(userID uuid.UUID, req *models
 	return retrospective, nil
 }
 
+// importMaxItems limits the items of an imported retrospective
+const importMaxItems = 2000
+
+// ImportRetrospective creates a retrospective with its items, groups, vote
+// counts, action items and discussion notes from a file of the JSON export
+// or a CSV spreadsheet. The file is validated first and nothing is created when it
+// has errors or on a dry run; the report tells what was, or would be,
+// created and what is wrong with the file.
+func (s *RetrospectiveService) ImportRetrospective(userID uuid.UUID, data []byte, options models.RetrospectiveImportOptions) (*models.RetrospectiveImportReport, error) {
+	parsed, issues, err := importer.Parse(options.Format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same as a creation, only members can import into a team
+	if options.TeamID != nil && *options.TeamID != uuid.Nil {
+		if _, err := s.retroRepo.GetTeamRole(*options.TeamID, userID); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, errors.New("not a member of this team")
+			}
+			return nil, err
+		}
+	}
+
+	report := &models.RetrospectiveImportReport{
+		DryRun:   options.DryRun,
+		Errors:   append([]models.ImportIssue{}, issues...),
+		Warnings: []models.ImportIssue{},
+	}
+	if parsed == nil {
+		return report, nil
+	}
+
+	imported, err := s.buildImport(userID, parsed, options, report)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Valid = len(report.Errors) == 0
+	if !report.Valid || options.DryRun {
+		return report, nil
+	}
+
+	err = s.retroRepo.Import(imported.retrospective, imported.items, imported.groups, imported.groupItems, imported.actionItems, imported.notes)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Retrospective = imported.retrospective
+	return report, nil
+}
+
+// retrospectiveImport is what an import creates
+type retrospectiveImport struct {
+	retrospective *models.Retrospective
+	items         []models.RetrospectiveItem
+	groups        []models.RetrospectiveGroup
+	groupItems    map[uuid.UUID][]uuid.UUID
+	actionItems   []models.ActionItem
+	notes         []models.DiscussionNote
+}
+
+var importStatuses = map[models.RetrospectiveStatus]bool{
+	models.RetroStatusPlanned:    true,
+	models.RetroStatusActive:     true,
+	models.RetroStatusCollecting: true,
+	models.RetroStatusVoting:     true,
+	models.RetroStatusDiscussing: true,
+	models.RetroStatusClosed:     true,
+}
+
+// buildImport validates the parsed file, adding its problems to the report,
+// and builds what the import creates
+func (s *RetrospectiveService) buildImport(userID uuid.UUID, parsed *importer.Retrospective, options models.RetrospectiveImportOptions, report *models.RetrospectiveImportReport) (*retrospectiveImport, error) {
+	addError := func(location, format string, args ...interface{}) {
+		report.Errors = append(report.Errors, models.ImportIssue{Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+	addWarning := func(location, format string, args ...interface{}) {
+		report.Warnings = append(report.Warnings, models.ImportIssue{Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	retrospective := &models.Retrospective{
+		ID:        uuid.New(),
+		Title:     strings.TrimSpace(parsed.Title),
+		Template:  models.RetrospectiveTemplate(parsed.Template),
+		Status:    models.RetrospectiveStatus(parsed.Status),
+		StartedAt: parsed.StartedAt,
+		EndedAt:   parsed.EndedAt,
+		CreatedBy: userID,
+	}
+	if options.Title != "" {
+		retrospective.Title = strings.TrimSpace(options.Title)
+	}
+	if options.Template != "" {
+		retrospective.Template = options.Template
+	}
+	if options.TeamID != nil {
+		retrospective.TeamID = *options.TeamID
+	}
+	if parsed.Description != "" {
+		retrospective.Description = &parsed.Description
+	}
+	report.Title = retrospective.Title
+	report.Template = retrospective.Template
+
+	if retrospective.Title == "" {
+		addError("title", "title is required")
+	}
+
+	// Past retrospectives are the usual import, so files without a status are closed
+	if retrospective.Status == "" {
+		retrospective.Status = models.RetroStatusClosed
+	}
+	if !importStatuses[retrospective.Status] {
+		addError("status", "invalid status %q", retrospective.Status)
+	}
+	if options.Date != nil {
+		retrospective.StartedAt = options.Date
+		retrospective.EndedAt = options.Date
+	}
+	if retrospective.Status == models.RetroStatusClosed && retrospective.EndedAt == nil {
+		now := time.Now()
+		retrospective.EndedAt = &now
+	}
+	if retrospective.Status != models.RetroStatusPlanned && retrospective.StartedAt == nil {
+		retrospective.StartedAt = retrospective.EndedAt
+	}
+
+	template, err := NewTemplateService().GetTemplate(string(retrospective.Template))
+	if err != nil {
+		addError("template", "invalid template %q", retrospective.Template)
+	}
+
+	imported := &retrospectiveImport{
+		retrospective: retrospective,
+		groupItems:    map[uuid.UUID][]uuid.UUID{},
+	}
+
+	if len(parsed.Items) > importMaxItems {
+		addError("items", "too many items, the maximum is %d", importMaxItems)
+	}
+
+	itemIDs := map[string]uuid.UUID{}
+	for _, item := range parsed.Items {
+		if strings.TrimSpace(item.Content) == "" {
+			addError(item.Location, "content is required")
+		}
+		if item.Votes < 0 {
+			addError(item.Location, "votes cannot be negative")
+		}
+
+		category := item.Category
+		if template != nil {
+			category = templateCategoryID(template, item.Category)
+			if category == "" {
+				addError(item.Location, "unknown category %q of template %s", item.Category, template.ID)
+			}
+		}
+
+		id := uuid.New()
+		itemIDs[item.Key] = id
+		imported.items = append(imported.items, models.RetrospectiveItem{
+			ID:              id,
+			RetrospectiveID: retrospective.ID,
+			Category:        category,
+			Content:         strings.TrimSpace(item.Content),
+			IsAnonymous:     item.IsAnonymous,
+			Votes:           item.Votes,
+		})
+		report.Items++
+		report.Votes += item.Votes
+	}
+
+	for _, merge := range parsed.Merges {
+		addWarning(merge.Location, "merged item %q left out, its votes stay with item %s", merge.SourceContent, merge.TargetKey)
+	}
+
+	groupIDs := map[string]uuid.UUID{}
+	for _, group := range parsed.Groups {
+		if strings.TrimSpace(group.Name) == "" {
+			addError(group.Location, "group name is required")
+		}
+		if group.Votes < 0 {
+			addError(group.Location, "votes cannot be negative")
+		}
+
+		imported.groups = append(imported.groups, models.RetrospectiveGroup{
+			ID:              uuid.New(),
+			RetrospectiveID: retrospective.ID,
+			Name:            strings.TrimSpace(group.Name),
+			Votes:           group.Votes,
+			CreatedBy:       userID,
+		})
+		created := &imported.groups[len(imported.groups)-1]
+		groupIDs[group.Key] = created.ID
+		if group.Description != "" {
+			description := group.Description
+			created.Description = &description
+		}
+
+		for _, key := range group.ItemKeys {
+			itemID, ok := itemIDs[key]
+			if !ok {
+				addWarning(group.Location, "item %s not found, left out of group %q", key, group.Name)
+				continue
+			}
+			imported.groupItems[created.ID] = append(imported.groupItems[created.ID], itemID)
+		}
+		report.Groups++
+		report.Votes += group.Votes
+	}
+
+	assignees, err := s.retroRepo.GetTeamAssignableUsers(options.TeamID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, actionItem := range parsed.ActionItems {
+		if strings.TrimSpace(actionItem.Title) == "" {
+			addError(actionItem.Location, "title is required")
+		}
+		if actionItem.Status != "todo" && actionItem.Status != "in_progress" && actionItem.Status != "done" {
+			addError(actionItem.Location, "invalid status %q", actionItem.Status)
+		}
+
+		created := models.ActionItem{
+			ID:              uuid.New(),
+			RetrospectiveID: retrospective.ID,
+			Title:           strings.TrimSpace(actionItem.Title),
+			Status:          actionItem.Status,
+			DueDate:         actionItem.DueDate,
+			CreatedBy:       userID,
+		}
+		if actionItem.Description != "" {
+			description := actionItem.Description
+			created.Description = &description
+		}
+		if created.Status == "done" {
+			created.CompletedAt = retrospective.EndedAt
+			if created.CompletedAt == nil {
+				now := time.Now()
+				created.CompletedAt = &now
+			}
+		}
+
+		if actionItem.ItemKey != "" {
+			if itemID, ok := itemIDs[actionItem.ItemKey]; ok {
+				created.ItemID = &itemID
+			} else {
+				addWarning(actionItem.Location, "item %s not found, the action item is not linked to it", actionItem.ItemKey)
+			}
+		}
+
+		if actionItem.AssigneeID != nil || actionItem.AssigneeName != "" {
+			created.AssignedTo = findImportAssignee(assignees, actionItem.AssigneeID, actionItem.AssigneeName)
+			if created.AssignedTo == nil {
+				name := actionItem.AssigneeName
+				if name == "" {
+					name = actionItem.AssigneeID.String()
+				}
+				addWarning(actionItem.Location, "assignee %q is not a member of the team, the action item is left unassigned", name)
+			}
+		}
+
+		imported.actionItems = append(imported.actionItems, created)
+		report.ActionItems++
+	}
+
+	for _, note := range parsed.Notes {
+		created := models.DiscussionNote{
+			ID:              uuid.New(),
+			RetrospectiveID: retrospective.ID,
+			Content:         strings.TrimSpace(note.Content),
+			Outcome:         strings.TrimSpace(note.Outcome),
+			UpdatedBy:       &userID,
+		}
+		if created.Content == "" && created.Outcome == "" {
+			continue
+		}
+		if itemID, ok := itemIDs[note.ItemKey]; ok && note.ItemKey != "" {
+			created.ItemID = &itemID
+		} else if groupID, ok := groupIDs[note.GroupKey]; ok && note.GroupKey != "" {
+			created.GroupID = &groupID
+		} else {
+			addWarning(note.Location, "item or group of the note not found, the note is left out")
+			continue
+		}
+		imported.notes = append(imported.notes, created)
+		report.Notes++
+	}
+
+	return imported, nil
+}
+
+// templateCategoryID returns the ID of the template category with the ID or
+// the name, ignoring case, or "" when there is none
+func templateCategoryID(template *TemplateDefinition, category string) string {
+	category = strings.TrimSpace(category)
+	for _, candidate := range template.Categories {
+		if strings.EqualFold(candidate.ID, category) || strings.EqualFold(candidate.Name, category) {
+			return candidate.ID
+		}
+	}
+	return ""
+}
+
+// findImportAssignee finds the assignee of an imported action item by ID,
+// then by name or email. A name shared by several users matches none.
+func findImportAssignee(users []models.AssignableUser, id *uuid.UUID, name string) *uuid.UUID {
+	if id != nil {
+		for i := range users {
+			if users[i].ID == *id {
+				return &users[i].ID
+			}
+		}
+	}
+
+	var found *uuid.UUID
+	for i, user := range users {
+		if name != "" && (strings.EqualFold(user.Name, strings.TrimSpace(name)) || strings.EqualFold(user.Email, strings.TrimSpace(name))) {
+			if found != nil {
+				return nil
+			}
+			found = &users[i].ID
+		}
+	}
+	return found
+}
+
 func (s *RetrospectiveService) GetUserRetrospectives(userID uuid.UUID) ([]models.RetrospectiveWithDetails, error) {
 	// Get all retrospectives with full details including action items
 	retrospectives, err := s.retroRepo.GetAllRetrospectives()
@@ -92,6 +451,17 @@ func (s *RetrospectiveService) UpdateRetrospective(retrospectiveID, userID uuid.
 		return nil, errors.New("access denied")
 	}
 
+	// Only planned retrospectives can be (re)scheduled; the schedule of the
+	// others is kept
+	if retrospective.Status == models.RetroStatusPlanned {
+		if req.ScheduledAt != nil && !sameTime(req.ScheduledAt, retrospective.ScheduledAt) && !req.ScheduledAt.After(time.Now()) {
+			return nil, errors.New("scheduled_at must be in the future")
+		}
+		retrospective.ScheduledAt = req.ScheduledAt
+	} else if req.ScheduledAt != nil && !sameTime(req.ScheduledAt, retrospective.ScheduledAt) {
+		return nil, errors.New("only planned retrospectives can be scheduled")
+	}
+
 	// Update fields
 	retrospective.Title = req.Title
 	retrospective.Description = &req.Description
@@ -105,6 +475,13 @@ func (s *RetrospectiveService) UpdateRetrospective(retrospectiveID, userID uuid.
 	return retrospective, nil
 }
 
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
 func (s *RetrospectiveService) DeleteRetrospective(retrospectiveID, userID uuid.UUID) error {
 	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
 	if err != nil {
@@ -124,18 +501,110 @@ func (s *RetrospectiveService) DeleteRetrospective(retrospectiveID, userID uuid.
 	return s.retroRepo.Delete(retrospectiveID)
 }
 
-func (s *RetrospectiveService) StartRetrospective(retrospectiveID, userID uuid.UUID) error {
+// StartRetrospective starts the session and links the unfinished action items
+// of the previous retrospective for review. It returns how many were carried over.
+func (s *RetrospectiveService) StartRetrospective(retrospectiveID, userID uuid.UUID) (int, error) {
 	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Check if user is the creator
+	if retrospective.CreatedBy != userID {
+		return 0, errors.New("access denied")
+	}
+
+	// Starting a retrospective that is already running changes nothing
+	if retrospective.Status == models.RetroStatusActive {
+		return 0, nil
+	}
+
+	started, carried, err := startRetrospective(s.retroRepo, s.realtimeService, retrospectiveID)
+	if err != nil {
+		return 0, err
+	}
+	if !started {
+		return 0, errors.New("retrospective is closed")
+	}
+
+	return carried, nil
+}
+
+// startRetrospective moves a planned retrospective to active together with the
+// carry-over of the unfinished action items of the previous one, and sends
+// retrospective_started to browsers, team webhooks and chat. It is the only way
+// a retrospective starts: manually, on join and at the scheduled time. It
+// reports false when the retrospective was not planned anymore.
+func startRetrospective(retroRepo repositories.RetrospectiveRepositoryInterface, realtimeService *RealtimeService, retrospectiveID uuid.UUID) (bool, int, error) {
+	started, carried, err := retroRepo.StartRetrospective(retrospectiveID)
+	if err != nil || !started {
+		return started, carried, err
+	}
+
+	if realtimeService != nil {
+		realtimeService.BroadcastToRetrospective(retrospectiveID, "retrospective_started", map[string]interface{}{
+			"retrospective_id":     retrospectiveID,
+			"carried_action_items": carried,
+		})
+	}
+
+	return true, carried, nil
+}
+
+// SetCarryForward chooses whether an action item carried into the retrospective
+// is carried again to the next one if it is still unfinished
+func (s *RetrospectiveService) SetCarryForward(retrospectiveID, actionItemID, userID uuid.UUID, carryForward bool) error {
+	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
+	if err != nil {
+		return errors.New("retrospective not found")
+	}
+
 	if retrospective.CreatedBy != userID {
 		return errors.New("access denied")
 	}
 
-	return s.retroRepo.UpdateStatus(retrospectiveID, models.RetroStatusActive)
+	if err := s.retroRepo.SetCarryForward(retrospectiveID, actionItemID, carryForward); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("action item is not carried over to this retrospective")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetCarryoverRetrospectiveIDs returns the retrospectives an action item was carried into
+func (s *RetrospectiveService) GetCarryoverRetrospectiveIDs(actionItemID uuid.UUID) ([]uuid.UUID, error) {
+	return s.retroRepo.GetCarryoverRetrospectiveIDs(actionItemID)
+}
+
+// canManageActionItem tells whether the user created the action item, its
+// retrospective or a retrospective it was carried into
+func canManageActionItem(retroRepo repositories.RetrospectiveRepositoryInterface, actionItem *models.ActionItem, userID uuid.UUID) (bool, error) {
+	if actionItem.CreatedBy == userID {
+		return true, nil
+	}
+
+	retrospective, err := retroRepo.GetByID(actionItem.RetrospectiveID)
+	if err != nil {
+		return false, err
+	}
+	if retrospective.CreatedBy == userID {
+		return true, nil
+	}
+
+	carriedInto, err := retroRepo.GetCarryoverRetrospectiveIDs(actionItem.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, retrospectiveID := range carriedInto {
+		carriedRetrospective, err := retroRepo.GetByID(retrospectiveID)
+		if err == nil && carriedRetrospective.CreatedBy == userID {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 func (s *RetrospectiveService) EndRetrospective(retrospectiveID, userID uuid.UUID) error {
@@ -152,20 +621,72 @@ func (s *RetrospectiveService) EndRetrospective(retrospectiveID, userID uuid.UUI
 	return s.retroRepo.UpdateStatus(retrospectiveID, models.RetroStatusClosed)
 }
 
-func (s *RetrospectiveService) GetRetrospectiveStats(userID uuid.UUID) (map[string]int, error) {
-	retroCount, err := s.retroRepo.GetRetrospectiveCount(userID)
+// GetRetrospectiveStats returns dashboard counters for the retrospectives the
+// user created, joined or that belong to one of their teams
+func (s *RetrospectiveService) GetRetrospectiveStats(userID uuid.UUID) (*models.RetrospectiveStats, error) {
+	stats, err := s.retroRepo.GetRetrospectiveStats(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	actionCount, err := s.retroRepo.GetActionItemCount(userID)
+	if stats.Total > 0 {
+		stats.Participation.AverageParticipants = math.Round(float64(stats.Participation.TotalParticipants)/float64(stats.Total)*10) / 10
+	}
+
+	stats.ActionItems.CompletionRate = completionRate(stats.ActionItems.Done, stats.ActionItems.Total)
+
+	return stats, nil
+}
+
+// ListActionItems returns action items across the retrospectives the user
+// created, joined or that belong to one of their teams
+func (s *RetrospectiveService) ListActionItems(userID uuid.UUID, filter models.ActionItemFilter) (*models.ActionItemList, error) {
+	for _, status := range filter.Status {
+		if status != "todo" && status != "in_progress" && status != "done" {
+			return nil, errors.New("invalid status. Must be one of: todo, in_progress, done")
+		}
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = "due_date"
+	}
+	switch filter.SortBy {
+	case "due_date", "created_at", "updated_at", "status", "title":
+	default:
+		return nil, errors.New("invalid sort. Must be one of: due_date, created_at, updated_at, status, title")
+	}
+
+	if filter.SortOrder == "" {
+		filter.SortOrder = "asc"
+	}
+	if filter.SortOrder != "asc" && filter.SortOrder != "desc" {
+		return nil, errors.New("invalid order. Must be one of: asc, desc")
+	}
+
+	if filter.DueFrom != nil && filter.DueTo != nil && filter.DueTo.Before(*filter.DueFrom) {
+		return nil, errors.New("due_to must not be before due_from")
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		return nil, errors.New("limit must be between 1 and 100")
+	}
+	if filter.Offset < 0 {
+		return nil, errors.New("offset must not be negative")
+	}
+
+	actionItems, total, err := s.retroRepo.ListActionItems(userID, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]int{
-		"retrospectives": retroCount,
-		"action_items":   actionCount,
+	return &models.ActionItemList{
+		ActionItems: actionItems,
+		Total:       total,
+		Limit:       filter.Limit,
+		Offset:      filter.Offset,
 	}, nil
 }
 
@@ -194,10 +715,20 @@ func (s *RetrospectiveService) AddItem(retrospectiveID, userID uuid.UUID, req *m
 }
 
 func (s *RetrospectiveService) VoteItem(itemID, userID uuid.UUID) error {
+	if err := s.checkItemNotMerged(itemID); err != nil {
+		return err
+	}
 	return s.retroRepo.VoteItem(itemID, userID)
 }
 
 func (s *RetrospectiveService) AddActionItem(retrospectiveID, userID uuid.UUID, req *models.ActionItemCreateRequest) (*models.ActionItem, error) {
+	if _, err := s.retroRepo.GetByID(retrospectiveID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("retrospective not found")
+		}
+		return nil, err
+	}
+
 	// Allow any authenticated user to add action items
 	actionItem := &models.ActionItem{
 		ID:              uuid.New(),
@@ -213,14 +744,26 @@ func (s *RetrospectiveService) AddActionItem(retrospectiveID, userID uuid.UUID,
 		if err != nil {
 			return nil, errors.New("invalid item_id")
 		}
+
+		// The linked item must be one of the retrospective's items
+		item, err := s.retroRepo.GetItemByID(itemID)
+		if err == sql.ErrNoRows || (err == nil && item.RetrospectiveID != retrospectiveID) {
+			return nil, errors.New("item does not belong to this retrospective")
+		}
+		if err != nil {
+			return nil, err
+		}
 		actionItem.ItemID = &itemID
 	}
 
-	if req.AssignedTo != nil {
+	if req.AssignedTo != nil && *req.AssignedTo != "" {
 		assignedToID, err := uuid.Parse(*req.AssignedTo)
 		if err != nil {
 			return nil, errors.New("invalid assigned_to")
 		}
+		if err := s.validateAssignee(assignedToID, retrospectiveID); err != nil {
+			return nil, err
+		}
 		actionItem.AssignedTo = &assignedToID
 	}
 
@@ -237,6 +780,17 @@ func (s *RetrospectiveService) AddActionItem(retrospectiveID, userID uuid.UUID,
 		return nil, err
 	}
 
+	status := actionItem.Status
+	err = s.retroRepo.AddActionItemEvents([]models.ActionItemEvent{{
+		ActionItemID: actionItem.ID,
+		UserID:       &userID,
+		EventType:    models.ActionItemEventCreated,
+		NewValue:     &status,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
 	return actionItem, nil
 }
 
@@ -245,50 +799,257 @@ func (s *RetrospectiveService) GetRetrospectiveWithDetails(retrospectiveID, user
 	return s.retroRepo.GetRetrospectiveWithDetails(retrospectiveID)
 }
 
-func (s *RetrospectiveService) RegisterParticipant(retrospectiveID, userID uuid.UUID) error {
-	// First, register the participant
-	err := s.retroRepo.RegisterParticipant(retrospectiveID, userID)
+// GetExportReport gathers what an export of the retrospective shows, if its
+// export policy allows the user to export it
+func (s *RetrospectiveService) GetExportReport(retrospectiveID, userID uuid.UUID) (*export.Report, error) {
+	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
 	if err != nil {
-		return err
+		if err == sql.ErrNoRows {
+			return nil, errors.New("retrospective not found")
+		}
+		return nil, err
 	}
 
-	// Check if retrospective should be started automatically
-	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
+	allowed, err := canExport(s.retroRepo, retrospective, userID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("export not allowed")
 	}
 
-	// Only auto-start if retrospective is in "planned" status
-	if retrospective.Status == models.RetroStatusPlanned {
-		// Get current participant count
-		participants, err := s.retroRepo.GetParticipants(retrospectiveID)
-		if err != nil {
-			return err
+	return buildExportReport(s.retroRepo, retrospectiveID)
+}
+
+// buildExportReport gathers what an export of the retrospective shows: its
+// details, the template categories, the items of each group and the names
+// of the assignees
+func buildExportReport(retroRepo repositories.RetrospectiveRepositoryInterface, retrospectiveID uuid.UUID) (*export.Report, error) {
+	retrospective, err := retroRepo.GetRetrospectiveWithDetails(retrospectiveID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("retrospective not found")
 		}
+		return nil, err
+	}
 
-		// If there's at least 2 participants, start the retrospective automatically
-		if len(participants) > 1 {
-			err = s.retroRepo.UpdateStatus(retrospectiveID, models.RetroStatusActive)
-			if err != nil {
-				return err
-			}
+	report := &export.Report{
+		Retrospective: retrospective,
+		TemplateName:  string(retrospective.Template),
+		UserNames:     map[uuid.UUID]string{},
+		GeneratedAt:   time.Now(),
+	}
+
+	templates := NewTemplateService()
+	if template, err := templates.GetTemplate(string(retrospective.Template)); err == nil {
+		report.TemplateName = template.Name
+		for _, category := range template.Categories {
+			report.Categories = append(report.Categories, export.Category{ID: category.ID, Name: category.Name, Color: category.Color})
 		}
 	}
 
-	return nil
-}
+	report.GroupItems, err = retroRepo.GetGroupItemIDs(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *RetrospectiveService) GetParticipants(retrospectiveID uuid.UUID) ([]models.RetrospectiveParticipant, error) {
-	return s.retroRepo.GetParticipants(retrospectiveID)
-}
+	users, err := retroRepo.GetAssignableUsers(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		report.UserNames[user.ID] = user.Name
+	}
 
-func (s *RetrospectiveService) GetItemByID(itemID uuid.UUID) (*models.RetrospectiveItem, error) {
-	return s.retroRepo.GetItemByID(itemID)
+	return report, nil
 }
 
-func (s *RetrospectiveService) DeleteItem(itemID uuid.UUID) error {
-	return s.retroRepo.DeleteItem(itemID)
-}
+// canExport tells whether the export policy of the retrospective allows the
+// user to export it and share its summary. The creator always can.
+func canExport(retroRepo repositories.RetrospectiveRepositoryInterface, retrospective *models.Retrospective, userID uuid.UUID) (bool, error) {
+	if retrospective.CreatedBy == userID {
+		return true, nil
+	}
+
+	policy, err := retroRepo.GetExportPolicy(retrospective.ID)
+	if err != nil || policy == models.ExportPolicyCreator {
+		return false, err
+	}
+
+	if retrospective.TeamID != uuid.Nil {
+		role, err := retroRepo.GetTeamRole(retrospective.TeamID, userID)
+		if err != nil && err != sql.ErrNoRows {
+			return false, err
+		}
+		if role == "owner" || (role != "" && policy == models.ExportPolicyTeam) {
+			return true, nil
+		}
+	}
+
+	if policy == models.ExportPolicyParticipants || policy == models.ExportPolicyTeam {
+		participants, err := retroRepo.GetParticipants(retrospective.ID)
+		if err != nil {
+			return false, err
+		}
+		for _, participant := range participants {
+			if participant.UserID == userID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// isFacilitator tells whether the user created the retrospective or owns
+// its team
+func isFacilitator(retroRepo repositories.RetrospectiveRepositoryInterface, retrospective *models.Retrospective, userID uuid.UUID) (bool, error) {
+	if retrospective.CreatedBy == userID {
+		return true, nil
+	}
+	if retrospective.TeamID == uuid.Nil {
+		return false, nil
+	}
+
+	role, err := retroRepo.GetTeamRole(retrospective.TeamID, userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return role == "owner", err
+}
+
+func (s *RetrospectiveService) RegisterParticipant(retrospectiveID, userID uuid.UUID) error {
+	// First, register the participant
+	err := s.retroRepo.RegisterParticipant(retrospectiveID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Check if retrospective should be started automatically
+	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
+	if err != nil {
+		return err
+	}
+
+	// Only auto-start if retrospective is in "planned" status. Scheduled
+	// retrospectives wait for their time, started by the ScheduleService.
+	scheduled := retrospective.ScheduledAt != nil && retrospective.ScheduledAt.After(time.Now())
+	if retrospective.Status == models.RetroStatusPlanned && !scheduled {
+		// Get current participant count
+		participants, err := s.retroRepo.GetParticipants(retrospectiveID)
+		if err != nil {
+			return err
+		}
+
+		// If there's at least 2 participants, start the retrospective automatically
+		if len(participants) > 1 {
+			if _, _, err := startRetrospective(s.retroRepo, s.realtimeService, retrospectiveID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *RetrospectiveService) GetParticipants(retrospectiveID uuid.UUID) ([]models.RetrospectiveParticipant, error) {
+	return s.retroRepo.GetParticipants(retrospectiveID)
+}
+
+func (s *RetrospectiveService) GetItemByID(itemID uuid.UUID) (*models.RetrospectiveItem, error) {
+	return s.retroRepo.GetItemByID(itemID)
+}
+
+// retrospectiveCategory returns the ID of the category of the template of the
+// retrospective with the ID or the name, or "" when there is none
+func retrospectiveCategory(retrospective *models.Retrospective, category string) (string, error) {
+	template, err := NewTemplateService().GetTemplate(string(retrospective.Template))
+	if err != nil {
+		return "", err
+	}
+	return templateCategoryID(template, category), nil
+}
+
+// MoveItem moves the item to another category of the template, for when it
+// was filed in the wrong one. Its author and the facilitators can, while the
+// retrospective is active. It returns the item and the category it was in.
+func (s *RetrospectiveService) MoveItem(itemID, userID uuid.UUID, category string) (*models.RetrospectiveItem, string, error) {
+	item, err := s.retroRepo.GetItemByID(itemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", errors.New("item not found")
+		}
+		return nil, "", err
+	}
+	if item.MergedIntoID != nil {
+		return nil, "", errors.New("item was merged into another item")
+	}
+
+	retrospective, err := s.retroRepo.GetByID(item.RetrospectiveID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if item.AuthorID == nil || *item.AuthorID != userID {
+		facilitator, err := isFacilitator(s.retroRepo, retrospective, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		if !facilitator {
+			return nil, "", errors.New("access denied")
+		}
+	}
+
+	if retrospective.Status != models.RetroStatusActive {
+		return nil, "", errors.New("can only move items in active retrospectives")
+	}
+
+	categoryID, err := retrospectiveCategory(retrospective, category)
+	if err != nil {
+		return nil, "", err
+	}
+	if categoryID == "" {
+		return nil, "", errors.New("invalid category")
+	}
+
+	fromCategory := item.Category
+	if categoryID == fromCategory {
+		return item, fromCategory, nil
+	}
+
+	item, err = s.retroRepo.MoveItem(itemID, categoryID)
+	if err == sql.ErrNoRows {
+		return nil, "", errors.New("item was merged into another item")
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return item, fromCategory, nil
+}
+
+func (s *RetrospectiveService) DeleteItem(itemID uuid.UUID) error {
+	if err := s.checkItemNotMerged(itemID); err != nil {
+		return err
+	}
+	return s.retroRepo.DeleteItem(itemID)
+}
+
+// checkItemNotMerged rejects changes to items merged into another item, which
+// stay hidden until the merge is undone
+func (s *RetrospectiveService) checkItemNotMerged(itemID uuid.UUID) error {
+	item, err := s.retroRepo.GetItemByID(itemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("item not found")
+		}
+		return err
+	}
+	if item.MergedIntoID != nil {
+		return errors.New("item was merged into another item")
+	}
+	return nil
+}
 
 func (s *RetrospectiveService) ReopenRetrospective(retrospectiveID, userID uuid.UUID) error {
 	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
@@ -357,135 +1118,1072 @@ func (s *RetrospectiveService) CreateGroup(retrospectiveID, userID uuid.UUID, re
 		return nil, err
 	}
 
+	group.ItemIDs = itemIDs
+	if group.ItemIDs == nil {
+		group.ItemIDs = []uuid.UUID{}
+	}
+
 	return group, nil
 }
 
-func (s *RetrospectiveService) VoteGroup(groupID, userID uuid.UUID) error {
-	return s.retroRepo.VoteGroup(groupID, userID)
+// DefaultGroupSuggestionSimilarity is the least average similarity of the
+// items of a suggested group, unless another is asked for
+const DefaultGroupSuggestionSimilarity = 0.25
+
+// groupSuggestionMaxItems limits the items clustered for group suggestions,
+// as clustering takes cubic time in the number of items
+const groupSuggestionMaxItems = 300
+
+// SuggestGroups proposes groups of the items of the retrospective that are in
+// no group, clustered by the similarity of their contents, for the
+// facilitators to create them. minSimilarity is from 0 to 1. Beyond
+// groupSuggestionMaxItems items, only the most voted are clustered.
+func (s *RetrospectiveService) SuggestGroups(retrospectiveID, userID uuid.UUID, minSimilarity float64) ([]models.GroupSuggestion, error) {
+	if minSimilarity < 0 || minSimilarity > 1 {
+		return nil, errors.New("min_similarity must be between 0 and 1")
+	}
+
+	retrospective, err := s.retroRepo.GetRetrospectiveWithDetails(retrospectiveID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("retrospective not found")
+		}
+		return nil, err
+	}
+
+	facilitator, err := isFacilitator(s.retroRepo, &retrospective.Retrospective, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !facilitator {
+		return nil, errors.New("access denied")
+	}
+
+	groupItems, err := s.retroRepo.GetGroupItemIDs(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	grouped := map[uuid.UUID]bool{}
+	for _, itemIDs := range groupItems {
+		for _, itemID := range itemIDs {
+			grouped[itemID] = true
+		}
+	}
+
+	items := []models.RetrospectiveItem{}
+	for _, item := range retrospective.Items {
+		if !grouped[item.ID] && item.MergedIntoID == nil {
+			items = append(items, item)
+		}
+	}
+	if len(items) > groupSuggestionMaxItems {
+		items = mostVotedItems(items, groupSuggestionMaxItems)
+	}
+
+	contents := make([]string, len(items))
+	for i, item := range items {
+		contents[i] = item.Content
+	}
+
+	suggestions := []models.GroupSuggestion{}
+	for _, cluster := range similarity.Clusters(contents, minSimilarity) {
+		suggestion := models.GroupSuggestion{
+			GroupCreateRequest: models.GroupCreateRequest{Name: suggestionName(cluster.Terms), ItemIDs: []string{}},
+			Terms:              cluster.Terms,
+			Similarity:         cluster.Similarity,
+		}
+		for _, member := range cluster.Members {
+			suggestion.ItemIDs = append(suggestion.ItemIDs, items[member].ID.String())
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
 }
 
-func (s *RetrospectiveService) GetGroupByID(groupID uuid.UUID) (*models.RetrospectiveGroup, error) {
-	return s.retroRepo.GetGroupByID(groupID)
+// mostVotedItems returns the limit most voted items, the oldest first on
+// ties, in the order they are given
+func mostVotedItems(items []models.RetrospectiveItem, limit int) []models.RetrospectiveItem {
+	ranked := make([]int, len(items))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := items[ranked[i]], items[ranked[j]]
+		if a.Votes != b.Votes {
+			return a.Votes > b.Votes
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	kept := ranked[:limit]
+	sort.Ints(kept)
+	mostVoted := make([]models.RetrospectiveItem, len(kept))
+	for i, index := range kept {
+		mostVoted[i] = items[index]
+	}
+	return mostVoted
 }
 
-func (s *RetrospectiveService) DeleteGroup(groupID, userID uuid.UUID) error {
-	// First get the group to check permissions - we need to find which retrospective it belongs to
-	// Since we don't have a direct GetGroupByID method, we'll add one to the repository
+// suggestionName names a suggested group by the first terms its items share
+func suggestionName(terms []string) string {
+	if len(terms) == 0 {
+		return "Itens semelhantes"
+	}
+	if len(terms) > 2 {
+		terms = terms[:2]
+	}
+	name := []rune(strings.Join(terms, " / "))
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
+
+// getEditableGroup returns the group and its retrospective if the user can
+// change the group: its creator or a facilitator, while the retrospective is
+// active
+func (s *RetrospectiveService) getEditableGroup(groupID, userID uuid.UUID) (*models.RetrospectiveGroup, *models.Retrospective, error) {
 	group, err := s.retroRepo.GetGroupByID(groupID)
 	if err != nil {
-		return err
+		if err == sql.ErrNoRows {
+			return nil, nil, errors.New("group not found")
+		}
+		return nil, nil, err
+	}
+
+	retrospective, err := s.retroRepo.GetByID(group.RetrospectiveID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, errors.New("retrospective not found")
+		}
+		return nil, nil, err
 	}
 
-	// Check if user is the creator
 	if group.CreatedBy != userID {
-		return errors.New("access denied")
+		facilitator, err := isFacilitator(s.retroRepo, retrospective, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !facilitator {
+			return nil, nil, errors.New("access denied")
+		}
 	}
 
-	return s.retroRepo.DeleteGroup(groupID)
+	if retrospective.Status != models.RetroStatusActive {
+		return nil, nil, errors.New("can only edit groups of active retrospectives")
+	}
+
+	return group, retrospective, nil
 }
 
-func (s *RetrospectiveService) MergeItems(sourceItemID, targetItemID, userID uuid.UUID) (*models.RetrospectiveItem, error) {
-	// Get source item to verify permissions and retrospective
-	sourceItem, err := s.retroRepo.GetItemByID(sourceItemID)
+// loadGroupItems fills the item IDs of the groups of the retrospective
+func (s *RetrospectiveService) loadGroupItems(retrospectiveID uuid.UUID, groups ...*models.RetrospectiveGroup) error {
+	groupItems, err := s.retroRepo.GetGroupItemIDs(retrospectiveID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Get target item to verify permissions and retrospective
-	targetItem, err := s.retroRepo.GetItemByID(targetItemID)
+	for _, group := range groups {
+		group.ItemIDs = groupItems[group.ID]
+		if group.ItemIDs == nil {
+			group.ItemIDs = []uuid.UUID{}
+		}
+	}
+
+	return nil
+}
+
+// UpdateGroup renames the group or changes its description
+func (s *RetrospectiveService) UpdateGroup(groupID, userID uuid.UUID, req *models.GroupUpdateRequest) (*models.RetrospectiveGroup, error) {
+	group, _, err := s.getEditableGroup(groupID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Verify both items belong to the same retrospective
-	if sourceItem.RetrospectiveID != targetItem.RetrospectiveID {
-		return nil, errors.New("items must belong to the same retrospective")
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		group.Name = name
+	}
+	if req.Description != nil {
+		group.Description = req.Description
+		if strings.TrimSpace(*req.Description) == "" {
+			group.Description = nil
+		}
 	}
 
-	// Verify retrospective is active (can only merge items in active retrospectives)
-	retrospective, err := s.retroRepo.GetByID(sourceItem.RetrospectiveID)
-	if err != nil {
+	if err := s.retroRepo.UpdateGroup(group); err != nil {
 		return nil, err
 	}
 
-	if retrospective.Status != models.RetroStatusActive {
-		return nil, errors.New("can only merge items in active retrospectives")
+	if err := s.loadGroupItems(group.RetrospectiveID, group); err != nil {
+		return nil, err
 	}
 
-	// Merge items
-	return s.retroRepo.MergeItems(sourceItemID, targetItemID)
-}
-
-// Action Item methods
-func (s *RetrospectiveService) GetActionItemByID(actionItemID uuid.UUID) (*models.ActionItem, error) {
-	return s.retroRepo.GetActionItemByID(actionItemID)
+	return group, nil
 }
 
-func (s *RetrospectiveService) UpdateActionItem(actionItemID, userID uuid.UUID, req *models.ActionItemUpdateRequest) (*models.ActionItem, error) {
-	// Get the action item to check permissions
-	actionItem, err := s.retroRepo.GetActionItemByID(actionItemID)
-	if err != nil {
-		return nil, err
+// AddGroupItems adds items to the group, moving them out of the group they
+// were in. It returns the group followed by the groups the items left.
+func (s *RetrospectiveService) AddGroupItems(groupID, userID uuid.UUID, req *models.GroupItemsRequest) ([]*models.RetrospectiveGroup, error) {
+	if len(req.ItemIDs) == 0 {
+		return nil, errors.New("item_ids cannot be empty")
 	}
 
-	// Check if user is the creator or has access to the retrospective
-	retrospective, err := s.retroRepo.GetByID(actionItem.RetrospectiveID)
+	group, _, err := s.getEditableGroup(groupID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Allow creator of action item or creator of retrospective to update
-	if actionItem.CreatedBy != userID && retrospective.CreatedBy != userID {
-		return nil, errors.New("access denied")
-	}
+	var itemIDs []uuid.UUID
+	for _, itemIDStr := range req.ItemIDs {
+		itemID, err := uuid.Parse(itemIDStr)
+		if err != nil {
+			return nil, errors.New("invalid item ID: " + itemIDStr)
+		}
 
-	// Validate status if provided
-	if req.Status != nil {
-		validStatuses := []string{"todo", "in_progress", "done"}
-		isValid := false
-		for _, status := range validStatuses {
-			if *req.Status == status {
-				isValid = true
-				break
+		item, err := s.retroRepo.GetItemByID(itemID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, errors.New("item not found: " + itemIDStr)
 			}
+			return nil, err
 		}
-		if !isValid {
-			return nil, errors.New("invalid status. Must be one of: todo, in_progress, done")
+		if item.RetrospectiveID != group.RetrospectiveID {
+			return nil, errors.New("item does not belong to this retrospective")
 		}
-
-		// If status is being changed to "done" and completed_at is not provided, set it to now
-		if *req.Status == "done" && req.CompletedAt == nil {
-			now := time.Now().Format("2006-01-02T15:04:05Z")
-			req.CompletedAt = &now
+		if item.MergedIntoID != nil {
+			return nil, errors.New("item was merged into another item")
 		}
 
-		// If status is being changed from "done" to something else, clear completed_at
-		if *req.Status != "done" && actionItem.Status == "done" {
-			empty := ""
-			req.CompletedAt = &empty
+		itemIDs = append(itemIDs, itemID)
+	}
+
+	leftGroupIDs, err := s.retroRepo.AddGroupItems(groupID, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []*models.RetrospectiveGroup{group}
+	for _, leftGroupID := range leftGroupIDs {
+		leftGroup, err := s.retroRepo.GetGroupByID(leftGroupID)
+		if err != nil {
+			return nil, err
 		}
+		groups = append(groups, leftGroup)
+	}
+
+	if err := s.loadGroupItems(group.RetrospectiveID, groups...); err != nil {
+		return nil, err
 	}
 
-	return s.retroRepo.UpdateActionItem(actionItemID, req)
+	return groups, nil
 }
 
-func (s *RetrospectiveService) DeleteActionItem(actionItemID, userID uuid.UUID) error {
-	// Get the action item to check permissions
-	actionItem, err := s.retroRepo.GetActionItemByID(actionItemID)
+// RemoveGroupItem takes the item out of the group
+func (s *RetrospectiveService) RemoveGroupItem(groupID, itemID, userID uuid.UUID) (*models.RetrospectiveGroup, error) {
+	group, _, err := s.getEditableGroup(groupID, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Check if user is the creator or has access to the retrospective
-	retrospective, err := s.retroRepo.GetByID(actionItem.RetrospectiveID)
-	if err != nil {
-		return err
+	if err := s.retroRepo.RemoveGroupItem(groupID, itemID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("item is not in this group")
+		}
+		return nil, err
 	}
 
-	// Allow creator of action item or creator of retrospective to delete
-	if actionItem.CreatedBy != userID && retrospective.CreatedBy != userID {
-		return errors.New("access denied")
+	if err := s.loadGroupItems(group.RetrospectiveID, group); err != nil {
+		return nil, err
 	}
 
-	return s.retroRepo.DeleteActionItem(actionItemID)
+	return group, nil
+}
+
+func (s *RetrospectiveService) VoteGroup(groupID, userID uuid.UUID) error {
+	return s.retroRepo.VoteGroup(groupID, userID)
+}
+
+func (s *RetrospectiveService) GetGroupByID(groupID uuid.UUID) (*models.RetrospectiveGroup, error) {
+	return s.retroRepo.GetGroupByID(groupID)
+}
+
+func (s *RetrospectiveService) DeleteGroup(groupID, userID uuid.UUID) error {
+	// First get the group to check permissions - we need to find which retrospective it belongs to
+	// Since we don't have a direct GetGroupByID method, we'll add one to the repository
+	group, err := s.retroRepo.GetGroupByID(groupID)
+	if err != nil {
+		return err
+	}
+
+	// Check if user is the creator
+	if group.CreatedBy != userID {
+		return errors.New("access denied")
+	}
+
+	return s.retroRepo.DeleteGroup(groupID)
+}
+
+// MergeItems merges the source item into the target item, keeping the source
+// so that the merge can be undone. The vote mode defaults to sum. Items of
+// different categories are merged into the target category, which can also
+// move the merged item out of the category of both.
+func (s *RetrospectiveService) MergeItems(sourceItemID, targetItemID, userID uuid.UUID, voteMode models.ItemMergeVoteMode, targetCategory string) (*models.RetrospectiveItem, *models.ItemMerge, error) {
+	switch voteMode {
+	case "":
+		voteMode = models.ItemMergeVotesSum
+	case models.ItemMergeVotesSum, models.ItemMergeVotesMax, models.ItemMergeVotesReset:
+	default:
+		return nil, nil, errors.New("invalid vote_mode")
+	}
+
+	if sourceItemID == targetItemID {
+		return nil, nil, errors.New("cannot merge an item into itself")
+	}
+
+	// Get source item to verify permissions and retrospective
+	sourceItem, err := s.retroRepo.GetItemByID(sourceItemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, errors.New("item not found")
+		}
+		return nil, nil, err
+	}
+
+	// Get target item to verify permissions and retrospective
+	targetItem, err := s.retroRepo.GetItemByID(targetItemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, errors.New("item not found")
+		}
+		return nil, nil, err
+	}
+
+	// Verify both items belong to the same retrospective
+	if sourceItem.RetrospectiveID != targetItem.RetrospectiveID {
+		return nil, nil, errors.New("items must belong to the same retrospective")
+	}
+
+	// Verify retrospective is active (can only merge items in active retrospectives)
+	retrospective, err := s.retroRepo.GetByID(sourceItem.RetrospectiveID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if retrospective.Status != models.RetroStatusActive {
+		return nil, nil, errors.New("can only merge items in active retrospectives")
+	}
+
+	category := ""
+	if targetCategory != "" {
+		if category, err = retrospectiveCategory(retrospective, targetCategory); err != nil {
+			return nil, nil, err
+		}
+		if category == "" {
+			return nil, nil, errors.New("invalid target_category")
+		}
+	} else if sourceItem.Category != targetItem.Category {
+		return nil, nil, errors.New("target_category is required to merge items of different categories")
+	}
+
+	// Merge items
+	return s.retroRepo.MergeItems(sourceItemID, targetItemID, voteMode, category, userID)
+}
+
+// UnmergeItems undoes a merge of the retrospective, showing the source item
+// again. Whoever merged the items and the facilitators can, while the
+// retrospective is active. It returns the target and source items.
+func (s *RetrospectiveService) UnmergeItems(retrospectiveID, mergeID, userID uuid.UUID) (*models.RetrospectiveItem, *models.RetrospectiveItem, error) {
+	merge, err := s.retroRepo.GetItemMergeByID(mergeID)
+	if err == sql.ErrNoRows || (err == nil && merge.RetrospectiveID != retrospectiveID) {
+		return nil, nil, errors.New("merge not found")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if merge.CreatedBy == nil || *merge.CreatedBy != userID {
+		facilitator, err := isFacilitator(s.retroRepo, retrospective, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !facilitator {
+			return nil, nil, errors.New("access denied")
+		}
+	}
+
+	if retrospective.Status != models.RetroStatusActive {
+		return nil, nil, errors.New("can only unmerge items in active retrospectives")
+	}
+
+	return s.retroRepo.UnmergeItems(mergeID)
+}
+
+// SaveDiscussionNote saves the discussion note of the item or group, while
+// the retrospective is active. Anyone can edit a note. When it was saved by
+// someone else since the version that was edited, it is not saved and the
+// current note is returned with the error, to merge the changes into.
+func (s *RetrospectiveService) SaveDiscussionNote(subject models.NoteSubject, subjectID, userID uuid.UUID, req *models.DiscussionNoteRequest) (*models.DiscussionNote, error) {
+	note := &models.DiscussionNote{Content: strings.TrimSpace(req.Content), Outcome: strings.TrimSpace(req.Outcome), UpdatedBy: &userID}
+	switch subject {
+	case models.NoteSubjectItem:
+		item, err := s.retroRepo.GetItemByID(subjectID)
+		if err == sql.ErrNoRows {
+			return nil, errors.New("item not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if item.MergedIntoID != nil {
+			return nil, errors.New("item was merged into another item")
+		}
+		note.RetrospectiveID, note.ItemID = item.RetrospectiveID, &subjectID
+	case models.NoteSubjectGroup:
+		group, err := s.retroRepo.GetGroupByID(subjectID)
+		if err == sql.ErrNoRows {
+			return nil, errors.New("group not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		note.RetrospectiveID, note.GroupID = group.RetrospectiveID, &subjectID
+	default:
+		return nil, errors.New("invalid note subject")
+	}
+
+	if req.Version < 0 {
+		return nil, errors.New("invalid version")
+	}
+
+	retrospective, err := s.retroRepo.GetByID(note.RetrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+	if retrospective.Status != models.RetroStatusActive {
+		return nil, errors.New("can only edit notes of active retrospectives")
+	}
+
+	if err := s.retroRepo.SaveDiscussionNote(note, req.Version); err != nil {
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+		current, err := s.retroRepo.GetDiscussionNote(subject, subjectID)
+		if err != nil {
+			return nil, err
+		}
+		return current, errors.New("note was changed by someone else")
+	}
+
+	return note, nil
+}
+
+// GetDiscussionQueue returns the topics of the retrospective in the order they
+// are discussed, and the topic being discussed
+func (s *RetrospectiveService) GetDiscussionQueue(retrospectiveID, userID uuid.UUID) (*models.DiscussionQueue, error) {
+	retrospective, err := s.retroRepo.GetRetrospectiveWithDetails(retrospectiveID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("retrospective not found")
+		}
+		return nil, err
+	}
+
+	return s.discussionQueue(retrospective)
+}
+
+// discussionQueue lists the groups and the items in no group, of the categories
+// of the template, the most voted first and then the oldest. Merged items are
+// left out; their votes are in the items they were merged into.
+func (s *RetrospectiveService) discussionQueue(retrospective *models.RetrospectiveWithDetails) (*models.DiscussionQueue, error) {
+	template, err := NewTemplateService().GetTemplate(string(retrospective.Template))
+	if err != nil {
+		return nil, err
+	}
+
+	type queuedTopic struct {
+		topic     models.DiscussionTopic
+		createdAt time.Time
+	}
+	queued := []queuedTopic{}
+
+	itemVotes := map[uuid.UUID]int{}
+	for _, item := range retrospective.Items {
+		if item.MergedIntoID == nil {
+			itemVotes[item.ID] = item.Votes
+		}
+	}
+
+	grouped := map[uuid.UUID]bool{}
+	for _, group := range retrospective.Groups {
+		topic := models.DiscussionTopic{Type: models.NoteSubjectGroup, ID: group.ID, Title: group.Name, Votes: group.Votes}
+		for _, itemID := range group.ItemIDs {
+			grouped[itemID] = true
+			if votes, ok := itemVotes[itemID]; ok {
+				topic.Votes += votes
+				topic.ItemIDs = append(topic.ItemIDs, itemID)
+			}
+		}
+		queued = append(queued, queuedTopic{topic: topic, createdAt: group.CreatedAt})
+	}
+
+	for _, item := range retrospective.Items {
+		if item.MergedIntoID != nil || grouped[item.ID] || templateCategoryID(template, item.Category) == "" {
+			continue
+		}
+		queued = append(queued, queuedTopic{
+			topic:     models.DiscussionTopic{Type: models.NoteSubjectItem, ID: item.ID, Title: item.Content, Category: item.Category, Votes: item.Votes},
+			createdAt: item.CreatedAt,
+		})
+	}
+
+	sort.SliceStable(queued, func(i, j int) bool {
+		if queued[i].topic.Votes != queued[j].topic.Votes {
+			return queued[i].topic.Votes > queued[j].topic.Votes
+		}
+		return queued[i].createdAt.Before(queued[j].createdAt)
+	})
+
+	queue := &models.DiscussionQueue{Topics: make([]models.DiscussionTopic, len(queued))}
+	for i := range queued {
+		queue.Topics[i] = queued[i].topic
+	}
+
+	current, err := s.retroRepo.GetCurrentTopic(retrospective.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i, topic := range queue.Topics {
+		if (topic.Type == models.NoteSubjectItem && current.ItemID != nil && *current.ItemID == topic.ID) ||
+			(topic.Type == models.NoteSubjectGroup && current.GroupID != nil && *current.GroupID == topic.ID) {
+			queue.Current, queue.CurrentStartedAt = &queue.Topics[i], current.StartedAt
+		}
+	}
+
+	return queue, nil
+}
+
+// getLedDiscussion returns the retrospective if the user can change the topic
+// being discussed: a facilitator, while the retrospective is active
+func (s *RetrospectiveService) getLedDiscussion(retrospectiveID, userID uuid.UUID) (*models.RetrospectiveWithDetails, error) {
+	retrospective, err := s.retroRepo.GetRetrospectiveWithDetails(retrospectiveID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("retrospective not found")
+		}
+		return nil, err
+	}
+
+	facilitator, err := isFacilitator(s.retroRepo, &retrospective.Retrospective, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !facilitator {
+		return nil, errors.New("access denied")
+	}
+
+	if retrospective.Status != models.RetroStatusActive {
+		return nil, errors.New("can only change the topic of active retrospectives")
+	}
+
+	return retrospective, nil
+}
+
+// SetCurrentTopic starts the discussion of an item or group of the queue.
+// Only the facilitators can, while the retrospective is active.
+func (s *RetrospectiveService) SetCurrentTopic(retrospectiveID, userID uuid.UUID, topicType models.NoteSubject, topicID uuid.UUID) (*models.DiscussionQueue, error) {
+	if topicType != models.NoteSubjectItem && topicType != models.NoteSubjectGroup {
+		return nil, errors.New("invalid topic type")
+	}
+
+	retrospective, err := s.getLedDiscussion(retrospectiveID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	queue, err := s.discussionQueue(retrospective)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, topic := range queue.Topics {
+		if topic.Type == topicType && topic.ID == topicID {
+			return s.changeTopic(retrospectiveID, queue, &queue.Topics[i])
+		}
+	}
+
+	return nil, errors.New("topic is not in the discussion queue")
+}
+
+// NextTopic moves the discussion to the topic after the current one in the
+// queue, or to the first one when no topic is being discussed. A current
+// topic grouped or merged since it was set is followed to the group or the
+// item it went into. After the last topic, no topic is being discussed.
+func (s *RetrospectiveService) NextTopic(retrospectiveID, userID uuid.UUID) (*models.DiscussionQueue, error) {
+	retrospective, err := s.getLedDiscussion(retrospectiveID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	queue, err := s.discussionQueue(retrospective)
+	if err != nil {
+		return nil, err
+	}
+
+	next := 0
+	for i := range queue.Topics {
+		if queue.Current == &queue.Topics[i] {
+			next = i + 1
+		}
+	}
+	if queue.Current == nil {
+		current, err := s.retroRepo.GetCurrentTopic(retrospectiveID)
+		if err != nil {
+			return nil, err
+		}
+		next = topicPosition(retrospective, queue, current) + 1
+	}
+
+	var topic *models.DiscussionTopic
+	if next < len(queue.Topics) {
+		topic = &queue.Topics[next]
+	}
+
+	return s.changeTopic(retrospectiveID, queue, topic)
+}
+
+// topicPosition returns the position in the queue of the topic, or of the
+// group or the item it was grouped or merged into, -1 when there is none
+func topicPosition(retrospective *models.RetrospectiveWithDetails, queue *models.DiscussionQueue, topic *models.CurrentTopic) int {
+	itemID := topic.ItemID
+	if itemID != nil {
+		mergedInto := map[uuid.UUID]uuid.UUID{}
+		for _, merge := range retrospective.Merges {
+			mergedInto[merge.SourceItem.ID] = merge.TargetItemID
+		}
+		for _, item := range retrospective.Items {
+			if item.MergedIntoID != nil {
+				mergedInto[item.ID] = *item.MergedIntoID
+			}
+		}
+		// Merged items can be merged again, each item at most once
+		for range mergedInto {
+			target, ok := mergedInto[*itemID]
+			if !ok {
+				break
+			}
+			itemID = &target
+		}
+	}
+
+	for i, queued := range queue.Topics {
+		switch {
+		case queued.Type == models.NoteSubjectGroup && topic.GroupID != nil && *topic.GroupID == queued.ID:
+			return i
+		case queued.Type == models.NoteSubjectItem && itemID != nil && *itemID == queued.ID:
+			return i
+		case queued.Type == models.NoteSubjectGroup && itemID != nil:
+			for _, groupedID := range queued.ItemIDs {
+				if groupedID == *itemID {
+					return i
+				}
+			}
+		}
+	}
+
+	return -1
+}
+
+// ClearCurrentTopic ends the discussion of the current topic without starting
+// another one
+func (s *RetrospectiveService) ClearCurrentTopic(retrospectiveID, userID uuid.UUID) (*models.DiscussionQueue, error) {
+	retrospective, err := s.getLedDiscussion(retrospectiveID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	queue, err := s.discussionQueue(retrospective)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.changeTopic(retrospectiveID, queue, nil)
+}
+
+// changeTopic saves the topic being discussed, nil for none, in the queue
+func (s *RetrospectiveService) changeTopic(retrospectiveID uuid.UUID, queue *models.DiscussionQueue, topic *models.DiscussionTopic) (*models.DiscussionQueue, error) {
+	current := &models.CurrentTopic{}
+	if topic != nil {
+		if topic.Type == models.NoteSubjectGroup {
+			current.GroupID = &topic.ID
+		} else {
+			current.ItemID = &topic.ID
+		}
+	}
+
+	if err := s.retroRepo.SetCurrentTopic(retrospectiveID, current); err != nil {
+		return nil, err
+	}
+
+	queue.Current, queue.CurrentStartedAt = topic, current.StartedAt
+	return queue, nil
+}
+
+// Action Item methods
+func (s *RetrospectiveService) GetActionItemByID(actionItemID uuid.UUID) (*models.ActionItem, error) {
+	return s.retroRepo.GetActionItemByID(actionItemID)
+}
+
+// UpdateActionItem updates an action item and records the status, assignee
+// and due date changes in its history. It returns the recorded events.
+func (s *RetrospectiveService) UpdateActionItem(actionItemID, userID uuid.UUID, req *models.ActionItemUpdateRequest) (*models.ActionItem, []models.ActionItemEvent, error) {
+	// Get the action item to check permissions
+	actionItem, err := s.retroRepo.GetActionItemByID(actionItemID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Allow creator of action item or creator of a retrospective it belongs or
+	// was carried to to update
+	allowed, err := canManageActionItem(s.retroRepo, actionItem, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		return nil, nil, errors.New("access denied")
+	}
+
+	// Validate the new assignee against the retrospective of the action item and
+	// the retrospectives it was carried into
+	if req.AssignedTo != nil && *req.AssignedTo != "" {
+		assignedToID, err := uuid.Parse(*req.AssignedTo)
+		if err != nil {
+			return nil, nil, errors.New("invalid assigned_to")
+		}
+
+		if actionItem.AssignedTo == nil || *actionItem.AssignedTo != assignedToID {
+			retrospectiveIDs, err := s.retroRepo.GetCarryoverRetrospectiveIDs(actionItem.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			retrospectiveIDs = append([]uuid.UUID{actionItem.RetrospectiveID}, retrospectiveIDs...)
+
+			if err := s.validateAssignee(assignedToID, retrospectiveIDs...); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// Validate status if provided
+	if req.Status != nil {
+		validStatuses := []string{"todo", "in_progress", "done"}
+		isValid := false
+		for _, status := range validStatuses {
+			if *req.Status == status {
+				isValid = true
+				break
+			}
+		}
+		if !isValid {
+			return nil, nil, errors.New("invalid status. Must be one of: todo, in_progress, done")
+		}
+
+		// If status is being changed to "done" and completed_at is not provided, set it to now
+		if *req.Status == "done" && req.CompletedAt == nil {
+			now := time.Now().Format("2006-01-02T15:04:05Z")
+			req.CompletedAt = &now
+		}
+
+		// If status is being changed from "done" to something else, clear completed_at
+		if *req.Status != "done" && actionItem.Status == "done" {
+			empty := ""
+			req.CompletedAt = &empty
+		}
+	}
+
+	updated, err := s.retroRepo.UpdateActionItem(actionItemID, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := actionItemChanges(actionItem, updated, userID, req.Note)
+	if err := s.retroRepo.AddActionItemEvents(events); err != nil {
+		return nil, nil, err
+	}
+
+	return updated, events, nil
+}
+
+// actionItemChanges lists the tracked changes between two versions of an action item
+func actionItemChanges(before, after *models.ActionItem, userID uuid.UUID, note *string) []models.ActionItemEvent {
+	if note != nil && strings.TrimSpace(*note) == "" {
+		note = nil
+	}
+
+	events := []models.ActionItemEvent{}
+	add := func(eventType models.ActionItemEventType, oldValue, newValue *string) {
+		if (oldValue == nil) == (newValue == nil) && (oldValue == nil || *oldValue == *newValue) {
+			return
+		}
+		events = append(events, models.ActionItemEvent{
+			ActionItemID: after.ID,
+			UserID:       &userID,
+			EventType:    eventType,
+			OldValue:     oldValue,
+			NewValue:     newValue,
+			Note:         note,
+		})
+	}
+
+	add(models.ActionItemEventStatusChanged, &before.Status, &after.Status)
+	add(models.ActionItemEventReassigned, uuidValue(before.AssignedTo), uuidValue(after.AssignedTo))
+	add(models.ActionItemEventDueDateChanged, dateValue(before.DueDate), dateValue(after.DueDate))
+
+	return events
+}
+
+func uuidValue(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	value := id.String()
+	return &value
+}
+
+func dateValue(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	value := date.Format("2006-01-02")
+	return &value
+}
+
+func (s *RetrospectiveService) DeleteActionItem(actionItemID, userID uuid.UUID) error {
+	// Get the action item to check permissions
+	actionItem, err := s.retroRepo.GetActionItemByID(actionItemID)
+	if err != nil {
+		return err
+	}
+
+	// Check if user is the creator or has access to the retrospective
+	retrospective, err := s.retroRepo.GetByID(actionItem.RetrospectiveID)
+	if err != nil {
+		return err
+	}
+
+	// Allow creator of action item or creator of retrospective to delete
+	if actionItem.CreatedBy != userID && retrospective.CreatedBy != userID {
+		return errors.New("access denied")
+	}
+
+	return s.retroRepo.DeleteActionItem(actionItemID)
+}
+
+// getActionItem returns the action item or an "action item not found" error
+func (s *RetrospectiveService) getActionItem(actionItemID uuid.UUID) (*models.ActionItem, error) {
+	actionItem, err := s.retroRepo.GetActionItemByID(actionItemID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("action item not found")
+	}
+	return actionItem, err
+}
+
+// GetAssignableUsers returns the users who can be assigned action items of the
+// retrospective. Only those users, its creator, participants and team
+// members, can list them.
+func (s *RetrospectiveService) GetAssignableUsers(retrospectiveID, userID uuid.UUID) ([]models.AssignableUser, error) {
+	if _, err := s.retroRepo.GetByID(retrospectiveID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("retrospective not found")
+		}
+		return nil, err
+	}
+
+	users, err := s.retroRepo.GetAssignableUsers(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if user.ID == userID {
+			return users, nil
+		}
+	}
+
+	return nil, errors.New("access denied")
+}
+
+// validateAssignee checks that the user can be assigned action items of at
+// least one of the retrospectives
+func (s *RetrospectiveService) validateAssignee(userID uuid.UUID, retrospectiveIDs ...uuid.UUID) error {
+	for _, retrospectiveID := range retrospectiveIDs {
+		users, err := s.retroRepo.GetAssignableUsers(retrospectiveID)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if user.ID == userID {
+				return nil
+			}
+		}
+	}
+
+	return errors.New("assignee must be a participant or team member of the retrospective")
+}
+
+// GetActionItemHistory returns the change history of an action item, oldest first
+func (s *RetrospectiveService) GetActionItemHistory(actionItemID uuid.UUID) ([]models.ActionItemEvent, error) {
+	if _, err := s.getActionItem(actionItemID); err != nil {
+		return nil, err
+	}
+
+	return s.retroRepo.GetActionItemEvents(actionItemID)
+}
+
+// GetActionItemComments returns the comments of an action item as threads:
+// top level comments, oldest first, with their replies nested.
+func (s *RetrospectiveService) GetActionItemComments(actionItemID uuid.UUID) ([]models.ActionItemComment, error) {
+	if _, err := s.getActionItem(actionItemID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.retroRepo.GetActionItemComments(actionItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCommentThreads(comments), nil
+}
+
+func buildCommentThreads(comments []models.ActionItemComment) []models.ActionItemComment {
+	children := make(map[uuid.UUID][]models.ActionItemComment)
+	known := make(map[uuid.UUID]bool, len(comments))
+	for _, comment := range comments {
+		known[comment.ID] = true
+	}
+
+	roots := []models.ActionItemComment{}
+	for _, comment := range comments {
+		if comment.ParentID != nil && known[*comment.ParentID] {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	var attach func(comment models.ActionItemComment) models.ActionItemComment
+	attach = func(comment models.ActionItemComment) models.ActionItemComment {
+		comment.Replies = []models.ActionItemComment{}
+		for _, reply := range children[comment.ID] {
+			comment.Replies = append(comment.Replies, attach(reply))
+		}
+		return comment
+	}
+
+	for i := range roots {
+		roots[i] = attach(roots[i])
+	}
+
+	return roots
+}
+
+func (s *RetrospectiveService) AddActionItemComment(actionItemID, userID uuid.UUID, req *models.ActionItemCommentCreateRequest) (*models.ActionItemComment, error) {
+	if _, err := s.getActionItem(actionItemID); err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, errors.New("content is required")
+	}
+
+	comment := &models.ActionItemComment{
+		ID:           uuid.New(),
+		ActionItemID: actionItemID,
+		UserID:       &userID,
+		Content:      content,
+		Replies:      []models.ActionItemComment{},
+	}
+
+	if req.ParentID != nil && *req.ParentID != "" {
+		parentID, err := uuid.Parse(*req.ParentID)
+		if err != nil {
+			return nil, errors.New("invalid parent_id")
+		}
+
+		parent, err := s.retroRepo.GetActionItemCommentByID(parentID)
+		if err == sql.ErrNoRows || (err == nil && parent.ActionItemID != actionItemID) {
+			return nil, errors.New("parent comment not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		comment.ParentID = &parentID
+	}
+
+	if err := s.retroRepo.AddActionItemComment(comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// getActionItemComment returns the comment if it belongs to the action item
+func (s *RetrospectiveService) getActionItemComment(actionItemID, commentID uuid.UUID) (*models.ActionItemComment, error) {
+	comment, err := s.retroRepo.GetActionItemCommentByID(commentID)
+	if err == sql.ErrNoRows || (err == nil && comment.ActionItemID != actionItemID) {
+		return nil, errors.New("comment not found")
+	}
+	return comment, err
+}
+
+// UpdateActionItemComment edits a comment. Only its author can edit it.
+func (s *RetrospectiveService) UpdateActionItemComment(actionItemID, commentID, userID uuid.UUID, req *models.ActionItemCommentUpdateRequest) (*models.ActionItemComment, error) {
+	comment, err := s.getActionItemComment(actionItemID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID == nil || *comment.UserID != userID {
+		return nil, errors.New("access denied")
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, errors.New("content is required")
+	}
+
+	return s.retroRepo.UpdateActionItemComment(commentID, content)
+}
+
+// DeleteActionItemComment deletes a comment with its replies. The author of the
+// comment and whoever can manage the action item can delete it.
+func (s *RetrospectiveService) DeleteActionItemComment(actionItemID, commentID, userID uuid.UUID) error {
+	comment, err := s.getActionItemComment(actionItemID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID == nil || *comment.UserID != userID {
+		actionItem, err := s.getActionItem(actionItemID)
+		if err != nil {
+			return err
+		}
+
+		allowed, err := canManageActionItem(s.retroRepo, actionItem, userID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("access denied")
+		}
+	}
+
+	return s.retroRepo.DeleteActionItemComment(commentID)
 }
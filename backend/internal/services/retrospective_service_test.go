@@ -2,25 +2,74 @@ package services
 
 import (
 	"database/sql"
+	"errors"
+	"sort"
 	"testing"
 	"time"
 
+	"educ-retro/internal/export"
 	"educ-retro/internal/models"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockRetrospectiveRepository é um mock simples do RetrospectiveRepository
 type MockRetrospectiveRepository struct {
 	retrospectives map[uuid.UUID]*models.Retrospective
 	details        map[uuid.UUID]*models.RetrospectiveWithDetails
+	actionItems    map[uuid.UUID]*models.ActionItem
+	// carryovers maps a retrospective to the action items carried into it and their carry_forward flag
+	carryovers map[uuid.UUID]map[uuid.UUID]bool
+	// lastActionItemFilter is the filter received by ListActionItems
+	lastActionItemFilter models.ActionItemFilter
+	pendingReminders     []models.ActionItemReminder
+	// sentReminders maps a reminder key to whether its delivery completed
+	sentReminders      map[string]bool
+	lastReminderWindow [2]time.Time
+	events             []models.ActionItemEvent
+	comments           map[uuid.UUID]*models.ActionItemComment
+	// members maps a retrospective to its participants and team members, besides its creator
+	members map[uuid.UUID][]uuid.UUID
+	items   map[uuid.UUID]*models.RetrospectiveItem
+	// teamRoles maps a team and user pair to the user's role in the team
+	teamRoles map[[2]uuid.UUID]string
+	groups    map[uuid.UUID]*models.RetrospectiveGroup
+	merges    map[uuid.UUID]*models.ItemMerge
+	// groupItems maps a group to its items
+	groupItems map[uuid.UUID][]uuid.UUID
+	// userNames names the users returned as assignable
+	userNames map[uuid.UUID]string
+	// exportPolicies holds the export policies set, creator when unset
+	exportPolicies map[uuid.UUID]models.ExportPolicy
+	// notes maps an item or group to its discussion note
+	notes map[uuid.UUID]*models.DiscussionNote
+	// currentTopics holds the topics being discussed, none when unset
+	currentTopics map[uuid.UUID]*models.CurrentTopic
+	// externalStatuses holds the state of the linked issues at the last sync
+	externalStatuses map[uuid.UUID]string
 }
 
 func NewMockRetrospectiveRepository() *MockRetrospectiveRepository {
 	return &MockRetrospectiveRepository{
-		retrospectives: make(map[uuid.UUID]*models.Retrospective),
-		details:        make(map[uuid.UUID]*models.RetrospectiveWithDetails),
+		retrospectives:   make(map[uuid.UUID]*models.Retrospective),
+		details:          make(map[uuid.UUID]*models.RetrospectiveWithDetails),
+		actionItems:      make(map[uuid.UUID]*models.ActionItem),
+		carryovers:       make(map[uuid.UUID]map[uuid.UUID]bool),
+		sentReminders:    make(map[string]bool),
+		comments:         make(map[uuid.UUID]*models.ActionItemComment),
+		members:          make(map[uuid.UUID][]uuid.UUID),
+		items:            make(map[uuid.UUID]*models.RetrospectiveItem),
+		teamRoles:        make(map[[2]uuid.UUID]string),
+		groups:           make(map[uuid.UUID]*models.RetrospectiveGroup),
+		notes:            make(map[uuid.UUID]*models.DiscussionNote),
+		merges:           make(map[uuid.UUID]*models.ItemMerge),
+		groupItems:       make(map[uuid.UUID][]uuid.UUID),
+		userNames:        make(map[uuid.UUID]string),
+		exportPolicies:   make(map[uuid.UUID]models.ExportPolicy),
+		currentTopics:    make(map[uuid.UUID]*models.CurrentTopic),
+		externalStatuses: make(map[uuid.UUID]string),
 	}
 }
 
@@ -35,6 +84,35 @@ func (m *MockRetrospectiveRepository) Create(retrospective *models.Retrospective
 	return nil
 }
 
+func (m *MockRetrospectiveRepository) Import(retrospective *models.Retrospective, items []models.RetrospectiveItem, groups []models.RetrospectiveGroup, groupItems map[uuid.UUID][]uuid.UUID, actionItems []models.ActionItem, notes []models.DiscussionNote) error {
+	if err := m.Create(retrospective); err != nil {
+		return err
+	}
+	m.details[retrospective.ID] = &models.RetrospectiveWithDetails{
+		Retrospective: *retrospective,
+		Items:         items,
+		Groups:        groups,
+		ActionItems:   actionItems,
+	}
+	for i := range items {
+		m.items[items[i].ID] = &items[i]
+	}
+	for groupID, itemIDs := range groupItems {
+		m.groupItems[groupID] = itemIDs
+	}
+	for i := range actionItems {
+		m.actionItems[actionItems[i].ID] = &actionItems[i]
+	}
+	for i := range notes {
+		subjectID := notes[i].GroupID
+		if notes[i].ItemID != nil {
+			subjectID = notes[i].ItemID
+		}
+		m.notes[*subjectID] = &notes[i]
+	}
+	return nil
+}
+
 func (m *MockRetrospectiveRepository) GetByID(id uuid.UUID) (*models.Retrospective, error) {
 	retrospective, exists := m.retrospectives[id]
 	if !exists {
@@ -44,6 +122,26 @@ func (m *MockRetrospectiveRepository) GetByID(id uuid.UUID) (*models.Retrospecti
 	return &retrospectiveCopy, nil
 }
 
+func (m *MockRetrospectiveRepository) GetScheduledRetrospectives(userID uuid.UUID, since time.Time) ([]models.Retrospective, error) {
+	retrospectives := []models.Retrospective{}
+	for _, retro := range m.retrospectives {
+		if retro.ScheduledAt == nil || retro.ScheduledAt.Before(since) {
+			continue
+		}
+		visible := retro.CreatedBy == userID
+		for _, memberID := range m.members[retro.ID] {
+			visible = visible || memberID == userID
+		}
+		if visible {
+			retrospectives = append(retrospectives, *retro)
+		}
+	}
+	sort.Slice(retrospectives, func(i, j int) bool {
+		return retrospectives[i].ScheduledAt.Before(*retrospectives[j].ScheduledAt)
+	})
+	return retrospectives, nil
+}
+
 func (m *MockRetrospectiveRepository) GetAllRetrospectives() ([]models.Retrospective, error) {
 	var retrospectives []models.Retrospective
 	for _, retro := range m.retrospectives {
@@ -52,6 +150,25 @@ func (m *MockRetrospectiveRepository) GetAllRetrospectives() ([]models.Retrospec
 	return retrospectives, nil
 }
 
+func (m *MockRetrospectiveRepository) GetOrphanedRetrospectives() ([]models.Retrospective, error) {
+	retrospectives := []models.Retrospective{}
+	for _, retro := range m.retrospectives {
+		if retro.CreatedBy == uuid.Nil {
+			retrospectives = append(retrospectives, *retro)
+		}
+	}
+	return retrospectives, nil
+}
+
+func (m *MockRetrospectiveRepository) TransferOwnership(id, newOwnerID uuid.UUID) error {
+	retrospective, exists := m.retrospectives[id]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	retrospective.CreatedBy = newOwnerID
+	return nil
+}
+
 func (m *MockRetrospectiveRepository) GetRetrospectiveWithDetails(id uuid.UUID) (*models.RetrospectiveWithDetails, error) {
 	details, exists := m.details[id]
 	if !exists {
@@ -61,6 +178,29 @@ func (m *MockRetrospectiveRepository) GetRetrospectiveWithDetails(id uuid.UUID)
 	return &detailsCopy, nil
 }
 
+func (m *MockRetrospectiveRepository) GetDueRetrospectives(now time.Time) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, retro := range m.retrospectives {
+		if retro.Status == models.RetroStatusPlanned && retro.ScheduledAt != nil && !retro.ScheduledAt.After(now) {
+			ids = append(ids, retro.ID)
+		}
+	}
+	return ids, nil
+}
+
+// StartRetrospective starts a planned retrospective and carries the action
+// items over, like the repository does in one transaction
+func (m *MockRetrospectiveRepository) StartRetrospective(id uuid.UUID) (bool, int, error) {
+	retro, exists := m.retrospectives[id]
+	if !exists || retro.Status != models.RetroStatusPlanned {
+		return false, 0, nil
+	}
+	retro.Status = models.RetroStatusActive
+	startedAt := time.Now()
+	retro.StartedAt = &startedAt
+	return true, m.carryOverActionItems(id), nil
+}
+
 func (m *MockRetrospectiveRepository) Update(retrospective *models.Retrospective) error {
 	if _, exists := m.retrospectives[retrospective.ID]; !exists {
 		return sql.ErrNoRows
@@ -86,15 +226,121 @@ func (m *MockRetrospectiveRepository) UpdateStatus(id uuid.UUID, status models.R
 	}
 	return nil
 }
-func (m *MockRetrospectiveRepository) GetRetrospectiveCount(id uuid.UUID) (int, error)   { return 1, nil }
-func (m *MockRetrospectiveRepository) GetActionItemCount(id uuid.UUID) (int, error)      { return 0, nil }
-func (m *MockRetrospectiveRepository) AddItem(item *models.RetrospectiveItem) error      { return nil }
-func (m *MockRetrospectiveRepository) VoteItem(itemID, userID uuid.UUID) error           { return nil }
-func (m *MockRetrospectiveRepository) AddActionItem(actionItem *models.ActionItem) error { return nil }
+func (m *MockRetrospectiveRepository) GetRetrospectiveStats(userID uuid.UUID) (*models.RetrospectiveStats, error) {
+	stats := &models.RetrospectiveStats{ByStatus: map[string]int{}}
+	for _, retro := range m.retrospectives {
+		stats.Total++
+		stats.ByStatus[string(retro.Status)]++
+		if retro.CreatedBy == userID {
+			stats.Participation.Created++
+		}
+	}
+	stats.Participation.TotalParticipants = stats.Total * 3
+	stats.ActionItems = models.ActionItemProgressStats{Total: 3, Todo: 1, Done: 2}
+	return stats, nil
+}
+func (m *MockRetrospectiveRepository) ListActionItems(userID uuid.UUID, filter models.ActionItemFilter) ([]models.ActionItemWithRetrospective, int, error) {
+	m.lastActionItemFilter = filter
+	actionItems := []models.ActionItemWithRetrospective{}
+	for _, actionItem := range m.actionItems {
+		if filter.AssignedTo != nil && (actionItem.AssignedTo == nil || *actionItem.AssignedTo != *filter.AssignedTo) {
+			continue
+		}
+		if len(filter.Status) > 0 && !containsString(filter.Status, actionItem.Status) {
+			continue
+		}
+		if filter.DueFrom != nil && (actionItem.DueDate == nil || actionItem.DueDate.Before(*filter.DueFrom)) {
+			continue
+		}
+		withRetrospective := models.ActionItemWithRetrospective{ActionItem: *actionItem}
+		if retro, exists := m.retrospectives[actionItem.RetrospectiveID]; exists {
+			withRetrospective.Retrospective = models.ActionItemRetrospective{ID: retro.ID, Title: retro.Title}
+		}
+		actionItems = append(actionItems, withRetrospective)
+	}
+	return actionItems, len(actionItems), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+func (m *MockRetrospectiveRepository) AddItem(item *models.RetrospectiveItem) error { return nil }
+func (m *MockRetrospectiveRepository) VoteItem(itemID, userID uuid.UUID) error      { return nil }
+func (m *MockRetrospectiveRepository) AddActionItem(actionItem *models.ActionItem) error {
+	m.actionItems[actionItem.ID] = actionItem
+	return nil
+}
+
+// carryOverActionItems carries the unfinished action items of the most recently
+// closed retrospective of the same creator
+func (m *MockRetrospectiveRepository) carryOverActionItems(retrospectiveID uuid.UUID) int {
+	current := m.retrospectives[retrospectiveID]
+	var previous *models.Retrospective
+	for _, retro := range m.retrospectives {
+		if retro.ID == retrospectiveID || retro.Status != models.RetroStatusClosed || retro.CreatedBy != current.CreatedBy {
+			continue
+		}
+		if previous == nil || retro.EndedAt.After(*previous.EndedAt) {
+			previous = retro
+		}
+	}
+	if previous == nil {
+		return 0
+	}
+
+	if m.carryovers[retrospectiveID] == nil {
+		m.carryovers[retrospectiveID] = make(map[uuid.UUID]bool)
+	}
+	carried := 0
+	for _, actionItem := range m.actionItems {
+		if actionItem.Status == "done" {
+			continue
+		}
+		carryForward, carriedIntoPrevious := m.carryovers[previous.ID][actionItem.ID]
+		if actionItem.RetrospectiveID != previous.ID && !(carriedIntoPrevious && carryForward) {
+			continue
+		}
+		if _, exists := m.carryovers[retrospectiveID][actionItem.ID]; !exists {
+			m.carryovers[retrospectiveID][actionItem.ID] = true
+			carried++
+		}
+	}
+	return carried
+}
+
+func (m *MockRetrospectiveRepository) SetCarryForward(retrospectiveID, actionItemID uuid.UUID, carryForward bool) error {
+	if _, exists := m.carryovers[retrospectiveID][actionItemID]; !exists {
+		return sql.ErrNoRows
+	}
+	m.carryovers[retrospectiveID][actionItemID] = carryForward
+	return nil
+}
+
+func (m *MockRetrospectiveRepository) GetCarryoverRetrospectiveIDs(actionItemID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for retrospectiveID, actionItems := range m.carryovers {
+		if _, exists := actionItems[actionItemID]; exists {
+			ids = append(ids, retrospectiveID)
+		}
+	}
+	return ids, nil
+}
 func (m *MockRetrospectiveRepository) RegisterParticipant(retrospectiveID, userID uuid.UUID) error {
 	return nil
 }
 func (m *MockRetrospectiveRepository) GetParticipants(retrospectiveID uuid.UUID) ([]models.RetrospectiveParticipant, error) {
+	if members, exists := m.members[retrospectiveID]; exists {
+		participants := []models.RetrospectiveParticipant{}
+		for _, userID := range members {
+			participants = append(participants, models.RetrospectiveParticipant{ID: uuid.New(), RetrospectiveID: retrospectiveID, UserID: userID})
+		}
+		return participants, nil
+	}
 	// Return a mock participant to simulate that someone joined
 	return []models.RetrospectiveParticipant{
 		{
@@ -106,33 +352,451 @@ func (m *MockRetrospectiveRepository) GetParticipants(retrospectiveID uuid.UUID)
 		},
 	}, nil
 }
+func (m *MockRetrospectiveRepository) GetPendingReminders(overdueBefore, dueSoonBefore time.Time) ([]models.ActionItemReminder, error) {
+	m.lastReminderWindow = [2]time.Time{overdueBefore, dueSoonBefore}
+	reminders := []models.ActionItemReminder{}
+	for _, reminder := range m.pendingReminders {
+		if !m.sentReminders[reminderKey(&reminder)] {
+			reminders = append(reminders, reminder)
+		}
+	}
+	return reminders, nil
+}
+
+func (m *MockRetrospectiveRepository) ClaimReminder(reminder *models.ActionItemReminder, channel string) (bool, error) {
+	key := reminderKey(reminder)
+	if _, claimed := m.sentReminders[key]; claimed {
+		return false, nil
+	}
+	m.sentReminders[key] = false
+	return true, nil
+}
+
+func (m *MockRetrospectiveRepository) MarkReminderSent(reminder *models.ActionItemReminder) error {
+	m.sentReminders[reminderKey(reminder)] = true
+	return nil
+}
+
+func (m *MockRetrospectiveRepository) ReleaseReminder(reminder *models.ActionItemReminder) error {
+	delete(m.sentReminders, reminderKey(reminder))
+	return nil
+}
+
+func reminderKey(reminder *models.ActionItemReminder) string {
+	return reminder.ActionItemID.String() + "|" + reminder.UserID.String() + "|" + string(reminder.Kind) + "|" + reminder.DueDate.String()
+}
+
 func (m *MockRetrospectiveRepository) GetItemByID(id uuid.UUID) (*models.RetrospectiveItem, error) {
-	return nil, sql.ErrNoRows
+	item, exists := m.items[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	itemCopy := *item
+	return &itemCopy, nil
+}
+func (m *MockRetrospectiveRepository) MoveItem(itemID uuid.UUID, category string) (*models.RetrospectiveItem, error) {
+	item, exists := m.items[itemID]
+	if !exists || item.MergedIntoID != nil {
+		return nil, sql.ErrNoRows
+	}
+	item.Category = category
+	itemCopy := *item
+	return &itemCopy, nil
+}
+func (m *MockRetrospectiveRepository) DeleteItem(id uuid.UUID) error {
+	for _, item := range m.items {
+		if item.MergedIntoID != nil && *item.MergedIntoID == id {
+			return errors.New("item has merged items, unmerge first")
+		}
+	}
+	delete(m.items, id)
+	return nil
 }
-func (m *MockRetrospectiveRepository) DeleteItem(id uuid.UUID) error          { return nil }
 func (m *MockRetrospectiveRepository) ReopenRetrospective(id uuid.UUID) error { return nil }
 func (m *MockRetrospectiveRepository) CreateGroup(group *models.RetrospectiveGroup, itemIDs []uuid.UUID) error {
+	groupCopy := *group
+	m.groups[group.ID] = &groupCopy
+	m.AddGroupItems(group.ID, itemIDs)
 	return nil
 }
 func (m *MockRetrospectiveRepository) VoteGroup(groupID, userID uuid.UUID) error { return nil }
 func (m *MockRetrospectiveRepository) GetGroupByID(id uuid.UUID) (*models.RetrospectiveGroup, error) {
-	return nil, sql.ErrNoRows
+	group, exists := m.groups[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	groupCopy := *group
+	return &groupCopy, nil
+}
+func (m *MockRetrospectiveRepository) GetGroupItemIDs(retrospectiveID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	groupItems := map[uuid.UUID][]uuid.UUID{}
+	if details, exists := m.details[retrospectiveID]; exists {
+		for _, group := range details.Groups {
+			if itemIDs, exists := m.groupItems[group.ID]; exists {
+				groupItems[group.ID] = itemIDs
+			}
+		}
+	}
+	for _, group := range m.groups {
+		if itemIDs, exists := m.groupItems[group.ID]; exists && group.RetrospectiveID == retrospectiveID && len(itemIDs) > 0 {
+			groupItems[group.ID] = itemIDs
+		}
+	}
+	return groupItems, nil
+}
+func (m *MockRetrospectiveRepository) UpdateGroup(group *models.RetrospectiveGroup) error {
+	if _, exists := m.groups[group.ID]; !exists {
+		return sql.ErrNoRows
+	}
+	groupCopy := *group
+	m.groups[group.ID] = &groupCopy
+	return nil
+}
+func (m *MockRetrospectiveRepository) AddGroupItems(groupID uuid.UUID, itemIDs []uuid.UUID) ([]uuid.UUID, error) {
+	leftGroups := []uuid.UUID{}
+	for _, itemID := range itemIDs {
+		for otherGroupID, otherItemIDs := range m.groupItems {
+			if otherGroupID == groupID {
+				continue
+			}
+			for i, otherItemID := range otherItemIDs {
+				if otherItemID == itemID {
+					m.groupItems[otherGroupID] = append(otherItemIDs[:i:i], otherItemIDs[i+1:]...)
+					leftGroups = append(leftGroups, otherGroupID)
+					break
+				}
+			}
+		}
+		found := false
+		for _, existing := range m.groupItems[groupID] {
+			found = found || existing == itemID
+		}
+		if !found {
+			m.groupItems[groupID] = append(m.groupItems[groupID], itemID)
+		}
+	}
+	return leftGroups, nil
+}
+func (m *MockRetrospectiveRepository) RemoveGroupItem(groupID, itemID uuid.UUID) error {
+	for i, existing := range m.groupItems[groupID] {
+		if existing == itemID {
+			m.groupItems[groupID] = append(m.groupItems[groupID][:i:i], m.groupItems[groupID][i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
 }
 func (m *MockRetrospectiveRepository) DeleteGroup(id uuid.UUID) error { return nil }
-func (m *MockRetrospectiveRepository) MergeItems(sourceItemID, targetItemID uuid.UUID) (*models.RetrospectiveItem, error) {
-	return nil, nil
+func (m *MockRetrospectiveRepository) MergeItems(sourceItemID, targetItemID uuid.UUID, voteMode models.ItemMergeVoteMode, category string, userID uuid.UUID) (*models.RetrospectiveItem, *models.ItemMerge, error) {
+	source, target := m.items[sourceItemID], m.items[targetItemID]
+	if source.MergedIntoID != nil || target.MergedIntoID != nil {
+		return nil, nil, errors.New("item was merged into another item")
+	}
+	if category == "" {
+		category = target.Category
+	}
+	merge := &models.ItemMerge{
+		ID: uuid.New(), RetrospectiveID: target.RetrospectiveID, TargetItemID: targetItemID, VoteMode: voteMode,
+		TargetContent: target.Content, TargetVotes: target.Votes, TargetCategory: target.Category, MergedCategory: category,
+		CreatedBy: &userID, CreatedAt: time.Now(),
+	}
+	target.Category = category
+	switch voteMode {
+	case models.ItemMergeVotesSum:
+		target.Votes += source.Votes
+	case models.ItemMergeVotesMax:
+		if source.Votes > target.Votes {
+			target.Votes = source.Votes
+		}
+	case models.ItemMergeVotesReset:
+		target.Votes = 0
+	}
+	merge.MergedVotes = target.Votes
+	target.Content += " | " + source.Content
+	source.MergedIntoID = &targetItemID
+	merge.SourceItem = *source
+	m.merges[merge.ID] = merge
+	targetCopy := *target
+	return &targetCopy, merge, nil
+}
+func (m *MockRetrospectiveRepository) GetItemMerges(retrospectiveID uuid.UUID) ([]models.ItemMerge, error) {
+	merges := []models.ItemMerge{}
+	for _, merge := range m.merges {
+		if merge.RetrospectiveID == retrospectiveID {
+			merges = append(merges, *merge)
+		}
+	}
+	return merges, nil
+}
+func (m *MockRetrospectiveRepository) GetItemMergeByID(mergeID uuid.UUID) (*models.ItemMerge, error) {
+	merge, exists := m.merges[mergeID]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	mergeCopy := *merge
+	return &mergeCopy, nil
+}
+func (m *MockRetrospectiveRepository) UnmergeItems(mergeID uuid.UUID) (*models.RetrospectiveItem, *models.RetrospectiveItem, error) {
+	merge := m.merges[mergeID]
+	for _, other := range m.merges {
+		if other.TargetItemID == merge.TargetItemID && other.CreatedAt.After(merge.CreatedAt) {
+			return nil, nil, errors.New("undo the later merges into this item first")
+		}
+	}
+	target, source := m.items[merge.TargetItemID], m.items[merge.SourceItem.ID]
+	target.Content = merge.TargetContent
+	target.Votes = merge.TargetVotes + target.Votes - merge.MergedVotes
+	if target.Category == merge.MergedCategory {
+		target.Category = merge.TargetCategory
+	}
+	source.MergedIntoID = nil
+	delete(m.merges, mergeID)
+	targetCopy, sourceCopy := *target, *source
+	return &targetCopy, &sourceCopy, nil
+}
+func (m *MockRetrospectiveRepository) GetDiscussionNotes(retrospectiveID uuid.UUID) ([]models.DiscussionNote, error) {
+	notes := []models.DiscussionNote{}
+	for _, note := range m.notes {
+		if note.RetrospectiveID == retrospectiveID {
+			notes = append(notes, *note)
+		}
+	}
+	return notes, nil
+}
+func (m *MockRetrospectiveRepository) GetDiscussionNote(subject models.NoteSubject, subjectID uuid.UUID) (*models.DiscussionNote, error) {
+	note, exists := m.notes[subjectID]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	noteCopy := *note
+	return &noteCopy, nil
+}
+func (m *MockRetrospectiveRepository) SaveDiscussionNote(note *models.DiscussionNote, version int) error {
+	subjectID := note.ItemID
+	if note.GroupID != nil {
+		subjectID = note.GroupID
+	}
+	current, exists := m.notes[*subjectID]
+	if (exists && current.Version != version) || (!exists && version != 0) {
+		return sql.ErrNoRows
+	}
+	note.ID, note.Version, note.UpdatedAt = uuid.New(), version+1, time.Now()
+	if exists {
+		note.ID, note.CreatedAt = current.ID, current.CreatedAt
+	} else {
+		note.CreatedAt = note.UpdatedAt
+	}
+	noteCopy := *note
+	m.notes[*subjectID] = &noteCopy
+	return nil
+}
+func (m *MockRetrospectiveRepository) GetCurrentTopic(retrospectiveID uuid.UUID) (*models.CurrentTopic, error) {
+	if _, exists := m.retrospectives[retrospectiveID]; !exists {
+		return nil, sql.ErrNoRows
+	}
+	topic := models.CurrentTopic{}
+	if current, exists := m.currentTopics[retrospectiveID]; exists {
+		topic = *current
+	}
+	return &topic, nil
+}
+func (m *MockRetrospectiveRepository) SetCurrentTopic(retrospectiveID uuid.UUID, topic *models.CurrentTopic) error {
+	if _, exists := m.retrospectives[retrospectiveID]; !exists {
+		return sql.ErrNoRows
+	}
+	topic.StartedAt = nil
+	if topic.ItemID != nil || topic.GroupID != nil {
+		now := time.Now()
+		topic.StartedAt = &now
+	}
+	topicCopy := *topic
+	m.currentTopics[retrospectiveID] = &topicCopy
+	return nil
 }
 func (m *MockRetrospectiveRepository) GetActionItemByID(id uuid.UUID) (*models.ActionItem, error) {
-	return nil, sql.ErrNoRows
+	actionItem, exists := m.actionItems[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	actionItemCopy := *actionItem
+	return &actionItemCopy, nil
+}
+func (m *MockRetrospectiveRepository) GetAssignableUsers(retrospectiveID uuid.UUID) ([]models.AssignableUser, error) {
+	users := []models.AssignableUser{}
+	if retrospective, exists := m.retrospectives[retrospectiveID]; exists {
+		users = append(users, models.AssignableUser{ID: retrospective.CreatedBy, IsParticipant: true})
+	}
+	for _, userID := range m.members[retrospectiveID] {
+		users = append(users, models.AssignableUser{ID: userID, IsParticipant: true})
+	}
+	return users, nil
+}
+func (m *MockRetrospectiveRepository) GetTeamAssignableUsers(teamID *uuid.UUID, userID uuid.UUID) ([]models.AssignableUser, error) {
+	users := []models.AssignableUser{{ID: userID, Name: m.userNames[userID], IsParticipant: true}}
+	if teamID != nil {
+		for pair := range m.teamRoles {
+			if pair[0] == *teamID && pair[1] != userID {
+				users = append(users, models.AssignableUser{ID: pair[1], Name: m.userNames[pair[1]], IsTeamMember: true})
+			}
+		}
+	}
+	return users, nil
+}
+func (m *MockRetrospectiveRepository) GetExportPolicy(retrospectiveID uuid.UUID) (models.ExportPolicy, error) {
+	if _, exists := m.retrospectives[retrospectiveID]; !exists {
+		return "", sql.ErrNoRows
+	}
+	if policy, exists := m.exportPolicies[retrospectiveID]; exists {
+		return policy, nil
+	}
+	return models.ExportPolicyCreator, nil
+}
+func (m *MockRetrospectiveRepository) SetExportPolicy(retrospectiveID uuid.UUID, policy models.ExportPolicy) error {
+	if _, exists := m.retrospectives[retrospectiveID]; !exists {
+		return sql.ErrNoRows
+	}
+	m.exportPolicies[retrospectiveID] = policy
+	return nil
+}
+func (m *MockRetrospectiveRepository) GetTeamRole(teamID, userID uuid.UUID) (string, error) {
+	role, exists := m.teamRoles[[2]uuid.UUID{teamID, userID}]
+	if !exists {
+		return "", sql.ErrNoRows
+	}
+	return role, nil
 }
 func (m *MockRetrospectiveRepository) UpdateActionItem(actionItemID uuid.UUID, req *models.ActionItemUpdateRequest) (*models.ActionItem, error) {
-	return nil, nil
+	actionItem, exists := m.actionItems[actionItemID]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	if req.Status != nil {
+		actionItem.Status = *req.Status
+	}
+	if req.AssignedTo != nil {
+		actionItem.AssignedTo = nil
+		if *req.AssignedTo != "" {
+			assignedTo, err := uuid.Parse(*req.AssignedTo)
+			if err != nil {
+				return nil, err
+			}
+			actionItem.AssignedTo = &assignedTo
+		}
+	}
+	if req.DueDate != nil {
+		actionItem.DueDate = nil
+		if *req.DueDate != "" {
+			dueDate, err := time.Parse("2006-01-02", *req.DueDate)
+			if err != nil {
+				return nil, err
+			}
+			actionItem.DueDate = &dueDate
+		}
+	}
+	actionItemCopy := *actionItem
+	return &actionItemCopy, nil
 }
 func (m *MockRetrospectiveRepository) DeleteActionItem(id uuid.UUID) error { return nil }
+func (m *MockRetrospectiveRepository) SetActionItemExternalLink(actionItemID uuid.UUID, provider, externalID, url, externalStatus string) error {
+	actionItem, exists := m.actionItems[actionItemID]
+	if !exists || actionItem.ExternalID != nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	actionItem.ExternalProvider = &provider
+	actionItem.ExternalID = &externalID
+	actionItem.ExternalURL = &url
+	actionItem.ExternalSyncedAt = &now
+	m.externalStatuses[actionItemID] = externalStatus
+	return nil
+}
+func (m *MockRetrospectiveRepository) GetExternallyLinkedActionItems(provider string, limit int) ([]models.ActionItem, error) {
+	actionItems := []models.ActionItem{}
+	for _, actionItem := range m.actionItems {
+		if actionItem.ExternalProvider != nil && *actionItem.ExternalProvider == provider && len(actionItems) < limit {
+			actionItems = append(actionItems, *actionItem)
+		}
+	}
+	return actionItems, nil
+}
+func (m *MockRetrospectiveRepository) GetActionItemExternalStatus(actionItemID uuid.UUID) (*string, error) {
+	externalStatus, exists := m.externalStatuses[actionItemID]
+	if !exists {
+		return nil, nil
+	}
+	return &externalStatus, nil
+}
+func (m *MockRetrospectiveRepository) MarkActionItemSynced(actionItemID uuid.UUID, syncedAt time.Time, externalStatus string) error {
+	if actionItem, exists := m.actionItems[actionItemID]; exists {
+		actionItem.ExternalSyncedAt = &syncedAt
+		m.externalStatuses[actionItemID] = externalStatus
+	}
+	return nil
+}
+func (m *MockRetrospectiveRepository) AddActionItemEvents(events []models.ActionItemEvent) error {
+	m.events = append(m.events, events...)
+	return nil
+}
+func (m *MockRetrospectiveRepository) GetActionItemEvents(actionItemID uuid.UUID) ([]models.ActionItemEvent, error) {
+	events := []models.ActionItemEvent{}
+	for _, event := range m.events {
+		if event.ActionItemID == actionItemID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+func (m *MockRetrospectiveRepository) AddActionItemComment(comment *models.ActionItemComment) error {
+	// Keep insertion order through the creation time
+	comment.CreatedAt = time.Now().Add(time.Duration(len(m.comments)) * time.Millisecond)
+	comment.UpdatedAt = comment.CreatedAt
+	commentCopy := *comment
+	m.comments[comment.ID] = &commentCopy
+	return nil
+}
+func (m *MockRetrospectiveRepository) GetActionItemComments(actionItemID uuid.UUID) ([]models.ActionItemComment, error) {
+	comments := []models.ActionItemComment{}
+	for _, comment := range m.comments {
+		if comment.ActionItemID == actionItemID {
+			comments = append(comments, *comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+	return comments, nil
+}
+func (m *MockRetrospectiveRepository) GetActionItemCommentByID(id uuid.UUID) (*models.ActionItemComment, error) {
+	comment, exists := m.comments[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	commentCopy := *comment
+	return &commentCopy, nil
+}
+func (m *MockRetrospectiveRepository) UpdateActionItemComment(id uuid.UUID, content string) (*models.ActionItemComment, error) {
+	comment, exists := m.comments[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	comment.Content = content
+	commentCopy := *comment
+	return &commentCopy, nil
+}
+func (m *MockRetrospectiveRepository) DeleteActionItemComment(id uuid.UUID) error {
+	if _, exists := m.comments[id]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(m.comments, id)
+	for childID, comment := range m.comments {
+		if comment.ParentID != nil && *comment.ParentID == id {
+			m.DeleteActionItemComment(childID)
+		}
+	}
+	return nil
+}
 
 func TestNewRetrospectiveService(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	assert.NotNil(t, service)
 	assert.Equal(t, mockRetroRepo, service.retroRepo)
@@ -140,7 +804,7 @@ func TestNewRetrospectiveService(t *testing.T) {
 
 func TestRetrospectiveService_CreateRetrospective(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	userID := uuid.New()
 	request := &models.RetrospectiveCreateRequest{
@@ -158,9 +822,31 @@ func TestRetrospectiveService_CreateRetrospective(t *testing.T) {
 	assert.Equal(t, models.RetroStatusPlanned, retrospective.Status)
 }
 
+func TestRetrospectiveService_CreateRetrospective_Team(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+
+	userID := uuid.New()
+	teamID := uuid.New()
+	request := &models.RetrospectiveCreateRequest{
+		Title:    "Team Retrospective",
+		Template: "start_stop_continue",
+		TeamID:   &teamID,
+	}
+
+	_, err := service.CreateRetrospective(userID, request)
+	assert.EqualError(t, err, "not a member of this team")
+
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, userID}] = "member"
+	retrospective, err := service.CreateRetrospective(userID, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, teamID, retrospective.TeamID)
+}
+
 func TestRetrospectiveService_GetUserRetrospectives(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	userID := uuid.New()
 
@@ -197,7 +883,7 @@ func TestRetrospectiveService_GetUserRetrospectives(t *testing.T) {
 
 func TestRetrospectiveService_GetRetrospective(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -219,7 +905,7 @@ func TestRetrospectiveService_GetRetrospective(t *testing.T) {
 
 func TestRetrospectiveService_GetRetrospective_AccessDenied(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -242,7 +928,7 @@ func TestRetrospectiveService_GetRetrospective_AccessDenied(t *testing.T) {
 
 func TestRetrospectiveService_UpdateRetrospective(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -268,9 +954,49 @@ func TestRetrospectiveService_UpdateRetrospective(t *testing.T) {
 	assert.Equal(t, request.Title, updatedRetrospective.Title)
 }
 
+func TestRetrospectiveService_Scheduling(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+	userID := uuid.New()
+
+	past := time.Now().Add(-time.Hour)
+	_, err := service.CreateRetrospective(userID, &models.RetrospectiveCreateRequest{
+		Title: "Retro", Template: "start_stop_continue", ScheduledAt: &past,
+	})
+	assert.EqualError(t, err, "scheduled_at must be in the future")
+
+	scheduledAt := time.Now().Add(48 * time.Hour)
+	retrospective, err := service.CreateRetrospective(userID, &models.RetrospectiveCreateRequest{
+		Title: "Retro", Template: "start_stop_continue", ScheduledAt: &scheduledAt,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &scheduledAt, retrospective.ScheduledAt)
+
+	// Rescheduling a planned retrospective
+	later := scheduledAt.Add(24 * time.Hour)
+	updated, err := service.UpdateRetrospective(retrospective.ID, userID, &models.RetrospectiveCreateRequest{
+		Title: "Retro", Template: "start_stop_continue", ScheduledAt: &later,
+	})
+	assert.NoError(t, err)
+	assert.True(t, later.Equal(*updated.ScheduledAt))
+
+	// Once started, the schedule cannot change
+	mockRetroRepo.retrospectives[retrospective.ID].Status = models.RetroStatusActive
+	_, err = service.UpdateRetrospective(retrospective.ID, userID, &models.RetrospectiveCreateRequest{
+		Title: "Retro", Template: "start_stop_continue", ScheduledAt: &scheduledAt,
+	})
+	assert.EqualError(t, err, "only planned retrospectives can be scheduled")
+
+	updated, err = service.UpdateRetrospective(retrospective.ID, userID, &models.RetrospectiveCreateRequest{
+		Title: "Retro renomeada", Template: "start_stop_continue",
+	})
+	assert.NoError(t, err)
+	assert.True(t, later.Equal(*updated.ScheduledAt))
+}
+
 func TestRetrospectiveService_UpdateRetrospective_AccessDenied(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -299,7 +1025,7 @@ func TestRetrospectiveService_UpdateRetrospective_AccessDenied(t *testing.T) {
 
 func TestRetrospectiveService_DeleteRetrospective(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -319,7 +1045,7 @@ func TestRetrospectiveService_DeleteRetrospective(t *testing.T) {
 
 func TestRetrospectiveService_DeleteRetrospective_AccessDenied(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -341,7 +1067,7 @@ func TestRetrospectiveService_DeleteRetrospective_AccessDenied(t *testing.T) {
 
 func TestRetrospectiveService_ReopenRetrospective(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -362,7 +1088,7 @@ func TestRetrospectiveService_ReopenRetrospective(t *testing.T) {
 
 func TestRetrospectiveService_ReopenRetrospective_AccessDenied(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -385,7 +1111,7 @@ func TestRetrospectiveService_ReopenRetrospective_AccessDenied(t *testing.T) {
 
 func TestRetrospectiveService_ReopenRetrospective_NotClosed(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -407,7 +1133,7 @@ func TestRetrospectiveService_ReopenRetrospective_NotClosed(t *testing.T) {
 
 func TestRetrospectiveService_RegisterParticipant_AutoStart(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -432,9 +1158,54 @@ func TestRetrospectiveService_RegisterParticipant_AutoStart(t *testing.T) {
 	assert.Equal(t, models.RetroStatusPlanned, updatedRetrospective.Status)
 }
 
+func TestRetrospectiveService_RegisterParticipant_AutoStartCarriesOver(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	realtimeService := NewRealtimeService()
+	service := NewRetrospectiveService(mockRetroRepo, realtimeService)
+
+	started := []uuid.UUID{}
+	realtimeService.AddListener(func(retrospectiveID uuid.UUID, eventType string, data interface{}) {
+		if eventType == "retrospective_started" {
+			started = append(started, retrospectiveID)
+		}
+	})
+
+	facilitator := uuid.New()
+	member := uuid.New()
+	endedAt := time.Now().Add(-7 * 24 * time.Hour)
+
+	previousID := uuid.New()
+	mockRetroRepo.retrospectives[previousID] = &models.Retrospective{ID: previousID, Status: models.RetroStatusClosed, CreatedBy: facilitator, EndedAt: &endedAt}
+	currentID := uuid.New()
+	mockRetroRepo.retrospectives[currentID] = &models.Retrospective{ID: currentID, Status: models.RetroStatusPlanned, CreatedBy: facilitator}
+	mockRetroRepo.members[currentID] = []uuid.UUID{facilitator, member}
+
+	unfinished := &models.ActionItem{ID: uuid.New(), RetrospectiveID: previousID, Status: "todo", CreatedBy: member}
+	mockRetroRepo.actionItems[unfinished.ID] = unfinished
+
+	// The second participant starts it, the same way as a manual start
+	err := service.RegisterParticipant(currentID, member)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RetroStatusActive, mockRetroRepo.retrospectives[currentID].Status)
+	assert.Contains(t, mockRetroRepo.carryovers[currentID], unfinished.ID)
+	assert.Equal(t, []uuid.UUID{currentID}, started)
+
+	// Already started: a manual start changes nothing and nothing is sent again
+	carried, err := service.StartRetrospective(currentID, facilitator)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, carried)
+	assert.Len(t, started, 1)
+
+	// A closed retrospective is reopened, not started
+	mockRetroRepo.retrospectives[currentID].Status = models.RetroStatusClosed
+	_, err = service.StartRetrospective(currentID, facilitator)
+	assert.EqualError(t, err, "retrospective is closed")
+}
+
 func TestRetrospectiveService_RegisterParticipant_NoAutoStartForActive(t *testing.T) {
 	mockRetroRepo := NewMockRetrospectiveRepository()
-	service := NewRetrospectiveService(mockRetroRepo)
+	service := NewRetrospectiveService(mockRetroRepo, nil)
 
 	retrospectiveID := uuid.New()
 	userID := uuid.New()
@@ -458,3 +1229,946 @@ func TestRetrospectiveService_RegisterParticipant_NoAutoStartForActive(t *testin
 	assert.NoError(t, err)
 	assert.Equal(t, models.RetroStatusActive, updatedRetrospective.Status)
 }
+
+func TestRetrospectiveService_GetRetrospectiveStats(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+
+	userID := uuid.New()
+	mockRetroRepo.retrospectives[uuid.New()] = &models.Retrospective{Status: models.RetroStatusActive, CreatedBy: userID}
+	mockRetroRepo.retrospectives[uuid.New()] = &models.Retrospective{Status: models.RetroStatusClosed, CreatedBy: uuid.New()}
+
+	stats, err := service.GetRetrospectiveStats(userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Total)
+	assert.Equal(t, 1, stats.ByStatus["active"])
+	assert.Equal(t, 1, stats.Participation.Created)
+	assert.Equal(t, 3.0, stats.Participation.AverageParticipants)
+	assert.Equal(t, 66.7, stats.ActionItems.CompletionRate)
+}
+
+func TestRetrospectiveService_ListActionItems(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+	userID := uuid.New()
+
+	list, err := service.ListActionItems(userID, models.ActionItemFilter{Status: []string{"todo", "in_progress"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 50, list.Limit)
+	assert.Equal(t, "due_date", mockRetroRepo.lastActionItemFilter.SortBy)
+	assert.Equal(t, "asc", mockRetroRepo.lastActionItemFilter.SortOrder)
+
+	dueFrom := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	dueTo := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		filter        models.ActionItemFilter
+		expectedError string
+	}{
+		{"invalid status", models.ActionItemFilter{Status: []string{"blocked"}}, "invalid status. Must be one of: todo, in_progress, done"},
+		{"invalid sort", models.ActionItemFilter{SortBy: "votes"}, "invalid sort. Must be one of: due_date, created_at, updated_at, status, title"},
+		{"invalid order", models.ActionItemFilter{SortOrder: "up"}, "invalid order. Must be one of: asc, desc"},
+		{"inverted due range", models.ActionItemFilter{DueFrom: &dueFrom, DueTo: &dueTo}, "due_to must not be before due_from"},
+		{"limit too large", models.ActionItemFilter{Limit: 500}, "limit must be between 1 and 100"},
+		{"negative offset", models.ActionItemFilter{Offset: -1}, "offset must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ListActionItems(userID, tt.filter)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestRetrospectiveService_CarryOverActionItems(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+
+	facilitator := uuid.New()
+	member := uuid.New()
+	endedAt := time.Now().Add(-7 * 24 * time.Hour)
+
+	previousID := uuid.New()
+	mockRetroRepo.retrospectives[previousID] = &models.Retrospective{ID: previousID, Status: models.RetroStatusClosed, CreatedBy: facilitator, EndedAt: &endedAt}
+	currentID := uuid.New()
+	mockRetroRepo.retrospectives[currentID] = &models.Retrospective{ID: currentID, Status: models.RetroStatusPlanned, CreatedBy: facilitator}
+
+	unfinished := &models.ActionItem{ID: uuid.New(), RetrospectiveID: previousID, Status: "in_progress", CreatedBy: member}
+	done := &models.ActionItem{ID: uuid.New(), RetrospectiveID: previousID, Status: "done", CreatedBy: member}
+	mockRetroRepo.actionItems[unfinished.ID] = unfinished
+	mockRetroRepo.actionItems[done.ID] = done
+
+	_, err := service.StartRetrospective(currentID, member)
+	assert.EqualError(t, err, "access denied")
+
+	carried, err := service.StartRetrospective(currentID, facilitator)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, carried)
+	assert.Contains(t, mockRetroRepo.carryovers[currentID], unfinished.ID)
+
+	// The facilitator of the new retrospective can close and re-assign the carried item
+	mockRetroRepo.members[currentID] = []uuid.UUID{member}
+	status := "done"
+	assignedTo := member.String()
+	updated, _, err := service.UpdateActionItem(unfinished.ID, facilitator, &models.ActionItemUpdateRequest{Status: &status, AssignedTo: &assignedTo})
+	assert.NoError(t, err)
+	assert.Equal(t, "done", updated.Status)
+	assert.Equal(t, &member, updated.AssignedTo)
+
+	// Other users still cannot
+	_, _, err = service.UpdateActionItem(unfinished.ID, uuid.New(), &models.ActionItemUpdateRequest{Status: &status})
+	assert.EqualError(t, err, "access denied")
+
+	err = service.SetCarryForward(currentID, unfinished.ID, facilitator, false)
+	assert.NoError(t, err)
+	assert.False(t, mockRetroRepo.carryovers[currentID][unfinished.ID])
+
+	err = service.SetCarryForward(currentID, done.ID, facilitator, false)
+	assert.EqualError(t, err, "action item is not carried over to this retrospective")
+
+	err = service.SetCarryForward(currentID, unfinished.ID, member, true)
+	assert.EqualError(t, err, "access denied")
+}
+
+func TestRetrospectiveService_ActionItemHistory(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+
+	creator := uuid.New()
+	assignee := uuid.New()
+	retrospective := &models.Retrospective{ID: uuid.New(), CreatedBy: creator, Status: models.RetroStatusActive}
+	mockRetroRepo.retrospectives[retrospective.ID] = retrospective
+	mockRetroRepo.members[retrospective.ID] = []uuid.UUID{assignee}
+
+	actionItem, err := service.AddActionItem(retrospective.ID, creator, &models.ActionItemCreateRequest{Title: "Revisar pipeline"})
+	assert.NoError(t, err)
+
+	status := "in_progress"
+	assignedTo := assignee.String()
+	dueDate := "2024-06-10"
+	note := "Ana assumiu"
+	_, events, err := service.UpdateActionItem(actionItem.ID, creator, &models.ActionItemUpdateRequest{
+		Status:     &status,
+		AssignedTo: &assignedTo,
+		DueDate:    &dueDate,
+		Note:       &note,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+
+	// Unchanged fields are not recorded
+	title := "Revisar pipeline de deploy"
+	_, events, err = service.UpdateActionItem(actionItem.ID, creator, &models.ActionItemUpdateRequest{Title: &title, Status: &status})
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	history, err := service.GetActionItemHistory(actionItem.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 4)
+
+	assert.Equal(t, models.ActionItemEventCreated, history[0].EventType)
+	assert.Equal(t, "todo", *history[0].NewValue)
+
+	assert.Equal(t, models.ActionItemEventStatusChanged, history[1].EventType)
+	assert.Equal(t, "todo", *history[1].OldValue)
+	assert.Equal(t, "in_progress", *history[1].NewValue)
+	assert.Equal(t, &note, history[1].Note)
+
+	assert.Equal(t, models.ActionItemEventReassigned, history[2].EventType)
+	assert.Nil(t, history[2].OldValue)
+	assert.Equal(t, assignee.String(), *history[2].NewValue)
+
+	assert.Equal(t, models.ActionItemEventDueDateChanged, history[3].EventType)
+	assert.Equal(t, "2024-06-10", *history[3].NewValue)
+	assert.Equal(t, &creator, history[3].UserID)
+
+	_, err = service.GetActionItemHistory(uuid.New())
+	assert.EqualError(t, err, "action item not found")
+}
+
+func TestRetrospectiveService_ActionItemComments(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+
+	creator := uuid.New()
+	member := uuid.New()
+	other := uuid.New()
+	retrospective := &models.Retrospective{ID: uuid.New(), CreatedBy: creator, Status: models.RetroStatusActive}
+	mockRetroRepo.retrospectives[retrospective.ID] = retrospective
+
+	actionItem, err := service.AddActionItem(retrospective.ID, creator, &models.ActionItemCreateRequest{Title: "Revisar pipeline"})
+	assert.NoError(t, err)
+
+	comment, err := service.AddActionItemComment(actionItem.ID, member, &models.ActionItemCommentCreateRequest{Content: "  Bloqueado pelo time de infra  "})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bloqueado pelo time de infra", comment.Content)
+
+	parentID := comment.ID.String()
+	reply, err := service.AddActionItemComment(actionItem.ID, creator, &models.ActionItemCommentCreateRequest{Content: "Vou falar com eles", ParentID: &parentID})
+	assert.NoError(t, err)
+	assert.Equal(t, &comment.ID, reply.ParentID)
+
+	_, err = service.AddActionItemComment(actionItem.ID, member, &models.ActionItemCommentCreateRequest{Content: "Segunda thread"})
+	assert.NoError(t, err)
+
+	threads, err := service.GetActionItemComments(actionItem.ID)
+	assert.NoError(t, err)
+	assert.Len(t, threads, 2)
+	assert.Equal(t, comment.ID, threads[0].ID)
+	assert.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, reply.ID, threads[0].Replies[0].ID)
+	assert.Empty(t, threads[1].Replies)
+
+	_, err = service.AddActionItemComment(actionItem.ID, member, &models.ActionItemCommentCreateRequest{Content: "   "})
+	assert.EqualError(t, err, "content is required")
+
+	unknownParent := uuid.New().String()
+	_, err = service.AddActionItemComment(actionItem.ID, member, &models.ActionItemCommentCreateRequest{Content: "x", ParentID: &unknownParent})
+	assert.EqualError(t, err, "parent comment not found")
+
+	// Only the author edits
+	_, err = service.UpdateActionItemComment(actionItem.ID, comment.ID, creator, &models.ActionItemCommentUpdateRequest{Content: "editado"})
+	assert.EqualError(t, err, "access denied")
+	updated, err := service.UpdateActionItemComment(actionItem.ID, comment.ID, member, &models.ActionItemCommentUpdateRequest{Content: "editado"})
+	assert.NoError(t, err)
+	assert.Equal(t, "editado", updated.Content)
+
+	// The author and whoever manages the action item delete
+	err = service.DeleteActionItemComment(actionItem.ID, comment.ID, other)
+	assert.EqualError(t, err, "access denied")
+	err = service.DeleteActionItemComment(actionItem.ID, comment.ID, creator)
+	assert.NoError(t, err)
+
+	threads, err = service.GetActionItemComments(actionItem.ID)
+	assert.NoError(t, err)
+	assert.Len(t, threads, 1)
+
+	err = service.DeleteActionItemComment(uuid.New(), reply.ID, creator)
+	assert.EqualError(t, err, "comment not found")
+}
+
+func TestRetrospectiveService_ActionItemReferences(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+
+	creator := uuid.New()
+	participant := uuid.New()
+	outsider := uuid.New()
+	retrospective := &models.Retrospective{ID: uuid.New(), CreatedBy: creator, Status: models.RetroStatusActive}
+	other := &models.Retrospective{ID: uuid.New(), CreatedBy: outsider, Status: models.RetroStatusActive}
+	mockRetroRepo.retrospectives[retrospective.ID] = retrospective
+	mockRetroRepo.retrospectives[other.ID] = other
+	mockRetroRepo.members[retrospective.ID] = []uuid.UUID{participant}
+
+	item := &models.RetrospectiveItem{ID: uuid.New(), RetrospectiveID: retrospective.ID}
+	otherItem := &models.RetrospectiveItem{ID: uuid.New(), RetrospectiveID: other.ID}
+	mockRetroRepo.items[item.ID] = item
+	mockRetroRepo.items[otherItem.ID] = otherItem
+
+	itemID := item.ID.String()
+	assignedTo := participant.String()
+	actionItem, err := service.AddActionItem(retrospective.ID, creator, &models.ActionItemCreateRequest{
+		Title:      "Automatizar release",
+		ItemID:     &itemID,
+		AssignedTo: &assignedTo,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &participant, actionItem.AssignedTo)
+	assert.Equal(t, &item.ID, actionItem.ItemID)
+
+	otherItemID := otherItem.ID.String()
+	_, err = service.AddActionItem(retrospective.ID, creator, &models.ActionItemCreateRequest{Title: "x", ItemID: &otherItemID})
+	assert.EqualError(t, err, "item does not belong to this retrospective")
+
+	unknownItemID := uuid.New().String()
+	_, err = service.AddActionItem(retrospective.ID, creator, &models.ActionItemCreateRequest{Title: "x", ItemID: &unknownItemID})
+	assert.EqualError(t, err, "item does not belong to this retrospective")
+
+	outsiderID := outsider.String()
+	_, err = service.AddActionItem(retrospective.ID, creator, &models.ActionItemCreateRequest{Title: "x", AssignedTo: &outsiderID})
+	assert.EqualError(t, err, "assignee must be a participant or team member of the retrospective")
+
+	_, err = service.AddActionItem(uuid.New(), creator, &models.ActionItemCreateRequest{Title: "x"})
+	assert.EqualError(t, err, "retrospective not found")
+
+	// Reassigning follows the same rule
+	_, _, err = service.UpdateActionItem(actionItem.ID, creator, &models.ActionItemUpdateRequest{AssignedTo: &outsiderID})
+	assert.EqualError(t, err, "assignee must be a participant or team member of the retrospective")
+
+	creatorID := creator.String()
+	updated, _, err := service.UpdateActionItem(actionItem.ID, creator, &models.ActionItemUpdateRequest{AssignedTo: &creatorID})
+	assert.NoError(t, err)
+	assert.Equal(t, &creator, updated.AssignedTo)
+
+	users, err := service.GetAssignableUsers(retrospective.ID, participant)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	// Users outside the retrospective cannot list its members
+	_, err = service.GetAssignableUsers(retrospective.ID, outsider)
+	assert.EqualError(t, err, "access denied")
+
+	_, err = service.GetAssignableUsers(uuid.New(), creator)
+	assert.EqualError(t, err, "retrospective not found")
+}
+
+func TestRetrospectiveService_ImportJSON(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+
+	// Export a retrospective of one team and import it into another
+	owner := uuid.New()
+	member := uuid.New()
+	teamID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, owner}] = "owner"
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, member}] = "member"
+	mockRetroRepo.userNames[member] = "Bruno"
+
+	endedAt := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	source := &models.RetrospectiveWithDetails{
+		Retrospective: models.Retrospective{ID: uuid.New(), Title: "Sprint 12", Template: models.TemplateStartStopContinue, Status: models.RetroStatusClosed, StartedAt: &endedAt, EndedAt: &endedAt},
+		Items: []models.RetrospectiveItem{
+			{ID: uuid.New(), Category: "start", Content: "Pair programming", Votes: 3},
+			{ID: uuid.New(), Category: "stop", Content: "Reuniões longas", Votes: 1},
+		},
+		Groups: []models.RetrospectiveGroup{{ID: uuid.New(), Name: "Colaboração", Votes: 2}},
+		Merges: []models.ItemMerge{{TargetItemID: uuid.New(), SourceItem: models.RetrospectiveItem{Content: "Pairing"}}},
+	}
+	missingItem := uuid.New()
+	source.Notes = []models.DiscussionNote{
+		{ItemID: &source.Items[0].ID, Content: "Funcionou bem", Outcome: "Manter"},
+		{GroupID: &source.Groups[0].ID, Content: "Mais pairing"},
+		{ItemID: &source.Items[1].ID},
+		{ItemID: &missingItem, Content: "Perdida"},
+	}
+	anotherUser := uuid.New()
+	source.ActionItems = []models.ActionItem{
+		{Title: "Agendar pairing", Status: "done", ItemID: &source.Items[0].ID, AssignedTo: &member},
+		{Title: "Encurtar dailies", Status: "todo", AssignedTo: &anotherUser},
+	}
+	data, err := export.JSON(&export.Report{
+		Retrospective: source,
+		GroupItems:    map[uuid.UUID][]uuid.UUID{source.Groups[0].ID: {source.Items[0].ID, uuid.New()}},
+		UserNames:     map[uuid.UUID]string{member: "Bruno", anotherUser: "Carla"},
+	})
+	require.NoError(t, err)
+
+	options := models.RetrospectiveImportOptions{Format: "json", TeamID: &teamID, DryRun: true}
+	report, err := service.ImportRetrospective(owner, data, options)
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Nil(t, report.Retrospective)
+	assert.Equal(t, 2, report.Items)
+	assert.Equal(t, 1, report.Groups)
+	assert.Equal(t, 2, report.ActionItems)
+	assert.Equal(t, 2, report.Notes)
+	assert.Equal(t, 6, report.Votes)
+	assert.Len(t, report.Warnings, 4) // the merged item, the unknown group item, Carla and the note of the unknown item
+	assert.Empty(t, mockRetroRepo.retrospectives)
+
+	options.DryRun = false
+	report, err = service.ImportRetrospective(owner, data, options)
+	require.NoError(t, err)
+	require.NotNil(t, report.Retrospective)
+
+	imported := mockRetroRepo.details[report.Retrospective.ID]
+	assert.Equal(t, "Sprint 12", imported.Title)
+	assert.Equal(t, teamID, imported.TeamID)
+	assert.Equal(t, owner, imported.CreatedBy)
+	assert.Equal(t, models.RetroStatusClosed, imported.Status)
+	assert.Equal(t, &endedAt, imported.EndedAt)
+	assert.NotEqual(t, source.Items[0].ID, imported.Items[0].ID)
+	assert.Equal(t, 3, imported.Items[0].Votes)
+	assert.Equal(t, []uuid.UUID{imported.Items[0].ID}, mockRetroRepo.groupItems[imported.Groups[0].ID])
+	assert.Equal(t, &imported.Items[0].ID, imported.ActionItems[0].ItemID)
+	assert.Equal(t, &member, imported.ActionItems[0].AssignedTo)
+	assert.Equal(t, &endedAt, imported.ActionItems[0].CompletedAt)
+	assert.Nil(t, imported.ActionItems[1].AssignedTo)
+	assert.Equal(t, "Manter", mockRetroRepo.notes[imported.Items[0].ID].Outcome)
+	assert.Equal(t, "Mais pairing", mockRetroRepo.notes[imported.Groups[0].ID].Content)
+
+	// Only members import into a team
+	_, err = service.ImportRetrospective(uuid.New(), data, options)
+	assert.EqualError(t, err, "not a member of this team")
+}
+
+func TestRetrospectiveService_ImportCSV(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRetroRepo, nil)
+	userID := uuid.New()
+	mockRetroRepo.userNames[userID] = "Ana Souza"
+
+	data := []byte("Tipo;Categoria;Conteúdo;Votos;Grupos;Responsável;Prazo;Status\n" +
+		"Item;Start;Testes automatizados;2;Qualidade;;;\n" +
+		"Item;continue;Code review;1;Qualidade;;;\n" +
+		"Action item;;Configurar CI;;;ana souza;15/03/2024;Em progresso\n")
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	options := models.RetrospectiveImportOptions{Format: "csv", Title: "Planilha de março", Template: models.TemplateStartStopContinue, Date: &date}
+
+	report, err := service.ImportRetrospective(userID, data, options)
+	require.NoError(t, err)
+	assert.True(t, report.Valid, "%v", report.Errors)
+	assert.Empty(t, report.Warnings)
+
+	imported := mockRetroRepo.details[report.Retrospective.ID]
+	assert.Equal(t, "Planilha de março", imported.Title)
+	assert.Equal(t, &date, imported.EndedAt)
+	assert.Equal(t, "start", imported.Items[0].Category)
+	assert.Equal(t, "continue", imported.Items[1].Category)
+	assert.Len(t, mockRetroRepo.groupItems[imported.Groups[0].ID], 2)
+	assert.Equal(t, "in_progress", imported.ActionItems[0].Status)
+	assert.Equal(t, &userID, imported.ActionItems[0].AssignedTo)
+	assert.Equal(t, "2024-03-15", imported.ActionItems[0].DueDate.Format("2006-01-02"))
+
+	// Nothing is created when the file has errors
+	invalid := []byte("Tipo,Categoria,Conteúdo,Votos,Status\n" +
+		"Item,Inexistente,Algo,1,\n" +
+		"Item,start,,x,\n" +
+		"Action item,,Revisar,,talvez\n")
+	report, err = service.ImportRetrospective(userID, invalid, models.RetrospectiveImportOptions{Format: "csv", Template: models.TemplateStartStopContinue})
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	assert.Equal(t, []models.ImportIssue{
+		{Location: "line 3", Message: `invalid votes "x"`},
+		{Location: "title", Message: "title is required"},
+		{Location: "line 2", Message: `unknown category "Inexistente" of template start_stop_continue`},
+		{Location: "line 3", Message: "content is required"},
+		{Location: "line 4", Message: `invalid status "talvez"`},
+	}, report.Errors)
+	assert.Len(t, mockRetroRepo.retrospectives, 1)
+
+	_, err = service.ImportRetrospective(userID, data, models.RetrospectiveImportOptions{Format: "xlsx"})
+	assert.EqualError(t, err, "unsupported import format")
+}
+
+func TestRetrospectiveService_GetExportReport_Policy(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+
+	participant := uuid.New()
+	retro := &models.Retrospective{ID: uuid.New(), Title: "Sprint 12", Template: models.TemplateStartStopContinue, CreatedBy: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	mockRepo.details[retro.ID] = &models.RetrospectiveWithDetails{Retrospective: *retro}
+	mockRepo.members[retro.ID] = []uuid.UUID{participant}
+
+	_, err := service.GetExportReport(retro.ID, retro.CreatedBy)
+	assert.NoError(t, err)
+	_, err = service.GetExportReport(retro.ID, participant)
+	assert.EqualError(t, err, "export not allowed")
+
+	mockRepo.exportPolicies[retro.ID] = models.ExportPolicyParticipants
+	report, err := service.GetExportReport(retro.ID, participant)
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint 12", report.Retrospective.Title)
+
+	_, err = service.GetExportReport(uuid.New(), participant)
+	assert.EqualError(t, err, "retrospective not found")
+}
+
+// addItems adds items of the stop category to the retrospective
+func addItems(mockRepo *MockRetrospectiveRepository, retrospectiveID uuid.UUID, contents ...string) []*models.RetrospectiveItem {
+	items := []*models.RetrospectiveItem{}
+	for _, content := range contents {
+		item := &models.RetrospectiveItem{ID: uuid.New(), RetrospectiveID: retrospectiveID, Category: "stop", Content: content}
+		mockRepo.items[item.ID] = item
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestRetrospectiveService_UpdateGroup(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual")
+	member := uuid.New()
+	mockRepo.teamRoles[[2]uuid.UUID{retro.TeamID, member}] = "member"
+
+	group, err := service.CreateGroup(retro.ID, member, &models.GroupCreateRequest{Name: "Deploy", ItemIDs: []string{items[0].ID.String()}})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{items[0].ID}, group.ItemIDs)
+
+	name := "  Entrega contínua "
+	description := "Tudo sobre o deploy"
+	updated, err := service.UpdateGroup(group.ID, member, &models.GroupUpdateRequest{Name: &name, Description: &description})
+	require.NoError(t, err)
+	assert.Equal(t, "Entrega contínua", updated.Name)
+	assert.Equal(t, "Tudo sobre o deploy", *updated.Description)
+	assert.Equal(t, []uuid.UUID{items[0].ID}, updated.ItemIDs)
+
+	// The facilitator edits groups of others; other members cannot
+	empty := ""
+	updated, err = service.UpdateGroup(group.ID, retro.CreatedBy, &models.GroupUpdateRequest{Description: &empty})
+	require.NoError(t, err)
+	assert.Nil(t, updated.Description)
+
+	_, err = service.UpdateGroup(group.ID, uuid.New(), &models.GroupUpdateRequest{Name: &name})
+	assert.EqualError(t, err, "access denied")
+	blank := " "
+	_, err = service.UpdateGroup(group.ID, member, &models.GroupUpdateRequest{Name: &blank})
+	assert.EqualError(t, err, "name cannot be empty")
+	_, err = service.UpdateGroup(uuid.New(), member, &models.GroupUpdateRequest{Name: &name})
+	assert.EqualError(t, err, "group not found")
+
+	retro.Status = models.RetroStatusClosed
+	_, err = service.UpdateGroup(group.ID, member, &models.GroupUpdateRequest{Name: &name})
+	assert.EqualError(t, err, "can only edit groups of active retrospectives")
+}
+
+func TestRetrospectiveService_GroupItems(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento", "Boa comunicação")
+
+	deploy, err := service.CreateGroup(retro.ID, retro.CreatedBy, &models.GroupCreateRequest{Name: "Deploy", ItemIDs: []string{items[0].ID.String(), items[1].ID.String()}})
+	require.NoError(t, err)
+	team, err := service.CreateGroup(retro.ID, retro.CreatedBy, &models.GroupCreateRequest{Name: "Time"})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{}, team.ItemIDs)
+
+	// Adding an item moves it out of the group it was in
+	groups, err := service.AddGroupItems(team.ID, retro.CreatedBy, &models.GroupItemsRequest{ItemIDs: []string{items[1].ID.String(), items[2].ID.String()}})
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, team.ID, groups[0].ID)
+	assert.ElementsMatch(t, []uuid.UUID{items[1].ID, items[2].ID}, groups[0].ItemIDs)
+	assert.Equal(t, deploy.ID, groups[1].ID)
+	assert.Equal(t, []uuid.UUID{items[0].ID}, groups[1].ItemIDs)
+
+	group, err := service.RemoveGroupItem(team.ID, items[2].ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{items[1].ID}, group.ItemIDs)
+
+	_, err = service.RemoveGroupItem(team.ID, items[2].ID, retro.CreatedBy)
+	assert.EqualError(t, err, "item is not in this group")
+
+	other := &models.RetrospectiveItem{ID: uuid.New(), RetrospectiveID: uuid.New(), Content: "Outra retrospectiva"}
+	mockRepo.items[other.ID] = other
+	_, err = service.AddGroupItems(team.ID, retro.CreatedBy, &models.GroupItemsRequest{ItemIDs: []string{other.ID.String()}})
+	assert.EqualError(t, err, "item does not belong to this retrospective")
+	_, err = service.AddGroupItems(team.ID, retro.CreatedBy, &models.GroupItemsRequest{ItemIDs: []string{"abc"}})
+	assert.EqualError(t, err, "invalid item ID: abc")
+	_, err = service.AddGroupItems(team.ID, retro.CreatedBy, &models.GroupItemsRequest{})
+	assert.EqualError(t, err, "item_ids cannot be empty")
+	_, err = service.AddGroupItems(team.ID, uuid.New(), &models.GroupItemsRequest{ItemIDs: []string{items[2].ID.String()}})
+	assert.EqualError(t, err, "access denied")
+}
+
+func TestRetrospectiveService_MergeItems_VoteModes(t *testing.T) {
+	tests := []struct {
+		mode  models.ItemMergeVoteMode
+		votes int
+	}{
+		{"", 5},
+		{models.ItemMergeVotesSum, 5},
+		{models.ItemMergeVotesMax, 3},
+		{models.ItemMergeVotesReset, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			mockRepo := NewMockRetrospectiveRepository()
+			service := NewRetrospectiveService(mockRepo, nil)
+			retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+			mockRepo.retrospectives[retro.ID] = retro
+			items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento")
+			items[0].Votes, items[1].Votes = 2, 3
+
+			merged, merge, err := service.MergeItems(items[1].ID, items[0].ID, retro.CreatedBy, tt.mode, "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.votes, merged.Votes)
+			assert.Equal(t, "Deploy manual | Pipeline lento", merged.Content)
+			assert.Equal(t, 2, merge.TargetVotes)
+			// The source item is kept as it was
+			assert.Equal(t, "Pipeline lento", merge.SourceItem.Content)
+			assert.Equal(t, 3, merge.SourceItem.Votes)
+		})
+	}
+}
+
+func TestRetrospectiveService_MergeItems_Errors(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento")
+
+	_, _, err := service.MergeItems(items[1].ID, items[0].ID, retro.CreatedBy, "average", "")
+	assert.EqualError(t, err, "invalid vote_mode")
+	_, _, err = service.MergeItems(items[0].ID, items[0].ID, retro.CreatedBy, "", "")
+	assert.EqualError(t, err, "cannot merge an item into itself")
+	_, _, err = service.MergeItems(uuid.New(), items[0].ID, retro.CreatedBy, "", "")
+	assert.EqualError(t, err, "item not found")
+
+	retro.Status = models.RetroStatusClosed
+	_, _, err = service.MergeItems(items[1].ID, items[0].ID, retro.CreatedBy, "", "")
+	assert.EqualError(t, err, "can only merge items in active retrospectives")
+}
+
+func TestRetrospectiveService_UnmergeItems(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento", "Boa comunicação")
+	member := uuid.New()
+	items[0].Votes, items[1].Votes, items[2].Votes = 2, 3, 1
+
+	_, first, err := service.MergeItems(items[1].ID, items[0].ID, member, models.ItemMergeVotesSum, "")
+	require.NoError(t, err)
+	_, second, err := service.MergeItems(items[2].ID, items[0].ID, member, models.ItemMergeVotesReset, "")
+	require.NoError(t, err)
+
+	// Merges are undone last first
+	_, _, err = service.UnmergeItems(retro.ID, first.ID, member)
+	assert.EqualError(t, err, "undo the later merges into this item first")
+
+	// Only whoever merged and the facilitators undo a merge
+	_, _, err = service.UnmergeItems(retro.ID, second.ID, uuid.New())
+	assert.EqualError(t, err, "access denied")
+	_, _, err = service.UnmergeItems(uuid.New(), second.ID, member)
+	assert.EqualError(t, err, "merge not found")
+
+	// A vote cast on the merged item stays with the target
+	mockRepo.items[items[0].ID].Votes++
+
+	target, source, err := service.UnmergeItems(retro.ID, second.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy manual | Pipeline lento", target.Content)
+	assert.Equal(t, 6, target.Votes)
+	assert.Equal(t, items[2].ID, source.ID)
+	assert.Nil(t, source.MergedIntoID)
+
+	target, source, err = service.UnmergeItems(retro.ID, first.ID, member)
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy manual", target.Content)
+	assert.Equal(t, 3, target.Votes)
+	assert.Equal(t, 3, source.Votes)
+}
+
+func TestRetrospectiveService_MergeItems_CrossCategory(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento")
+	items[1].Category = "start"
+
+	_, _, err := service.MergeItems(items[1].ID, items[0].ID, retro.CreatedBy, "", "")
+	assert.EqualError(t, err, "target_category is required to merge items of different categories")
+	_, _, err = service.MergeItems(items[1].ID, items[0].ID, retro.CreatedBy, "", "liked")
+	assert.EqualError(t, err, "invalid target_category")
+
+	// The category is found by name as well
+	merged, merge, err := service.MergeItems(items[1].ID, items[0].ID, retro.CreatedBy, "", "Start")
+	require.NoError(t, err)
+	assert.Equal(t, "start", merged.Category)
+	assert.Equal(t, "stop", merge.TargetCategory)
+
+	target, _, err := service.UnmergeItems(retro.ID, merge.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Equal(t, "stop", target.Category)
+
+	// Undo keeps the category the merged item was moved to since
+	_, merge, err = service.MergeItems(items[1].ID, items[0].ID, retro.CreatedBy, "", "start")
+	require.NoError(t, err)
+	_, _, err = service.MoveItem(items[0].ID, retro.CreatedBy, "continue")
+	require.NoError(t, err)
+	target, _, err = service.UnmergeItems(retro.ID, merge.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Equal(t, "continue", target.Category)
+	assert.Equal(t, "continue", mockRepo.items[items[0].ID].Category)
+}
+
+func TestRetrospectiveService_MoveItem(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento", "Boa comunicação")
+	author, owner, member := uuid.New(), uuid.New(), uuid.New()
+	items[0].AuthorID = &author
+	mockRepo.teamRoles[[2]uuid.UUID{retro.TeamID, owner}] = "owner"
+	mockRepo.teamRoles[[2]uuid.UUID{retro.TeamID, member}] = "member"
+
+	item, fromCategory, err := service.MoveItem(items[0].ID, author, "continue")
+	require.NoError(t, err)
+	assert.Equal(t, "continue", item.Category)
+	assert.Equal(t, "stop", fromCategory)
+
+	// The facilitators move the items of others, anonymous ones too
+	item, fromCategory, err = service.MoveItem(items[1].ID, owner, "Start")
+	require.NoError(t, err)
+	assert.Equal(t, "start", item.Category)
+	assert.Equal(t, "stop", fromCategory)
+
+	_, _, err = service.MoveItem(items[2].ID, member, "start")
+	assert.EqualError(t, err, "access denied")
+	_, _, err = service.MoveItem(items[2].ID, owner, "glad")
+	assert.EqualError(t, err, "invalid category")
+	_, _, err = service.MoveItem(uuid.New(), owner, "start")
+	assert.EqualError(t, err, "item not found")
+
+	_, _, err = service.MergeItems(items[2].ID, items[1].ID, owner, "", "start")
+	require.NoError(t, err)
+	_, _, err = service.MoveItem(items[2].ID, owner, "continue")
+	assert.EqualError(t, err, "item was merged into another item")
+
+	retro.Status = models.RetroStatusClosed
+	_, _, err = service.MoveItem(items[1].ID, owner, "continue")
+	assert.EqualError(t, err, "can only move items in active retrospectives")
+}
+
+func TestRetrospectiveService_MergedItemsCannotBeVotedOrDeleted(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento")
+	_, merge, err := service.MergeItems(items[1].ID, items[0].ID, retro.CreatedBy, "", "")
+	require.NoError(t, err)
+
+	assert.EqualError(t, service.VoteItem(items[1].ID, retro.CreatedBy), "item was merged into another item")
+	assert.EqualError(t, service.DeleteItem(items[1].ID), "item was merged into another item")
+	assert.EqualError(t, service.VoteItem(uuid.New(), retro.CreatedBy), "item not found")
+	assert.NoError(t, service.VoteItem(items[0].ID, retro.CreatedBy))
+
+	// Deleting the target would delete the hidden source with it
+	assert.EqualError(t, service.DeleteItem(items[0].ID), "item has merged items, unmerge first")
+	_, _, err = service.UnmergeItems(retro.ID, merge.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.NoError(t, service.DeleteItem(items[0].ID))
+	_, err = service.GetItemByID(items[1].ID)
+	assert.NoError(t, err)
+}
+
+func TestRetrospectiveService_SuggestGroups(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento", "Boa comunicação")
+	for _, content := range []string{"Os deploys manuais quebram", "Comunicação com o cliente foi boa", "Café da manhã"} {
+		item := &models.RetrospectiveItem{ID: uuid.New(), RetrospectiveID: retro.ID, Category: "start", Content: content}
+		mockRepo.items[item.ID] = item
+		items = append(items, item)
+	}
+	details := &models.RetrospectiveWithDetails{Retrospective: *retro}
+	for _, item := range items {
+		details.Items = append(details.Items, *item)
+	}
+	mockRepo.details[retro.ID] = details
+
+	suggestions, err := service.SuggestGroups(retro.ID, retro.CreatedBy, DefaultGroupSuggestionSimilarity)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "Deploy / manual", suggestions[0].Name)
+	assert.Equal(t, []string{items[0].ID.String(), items[3].ID.String()}, suggestions[0].ItemIDs)
+	assert.Equal(t, []string{items[2].ID.String(), items[4].ID.String()}, suggestions[1].ItemIDs)
+
+	// A suggestion is accepted by creating it as is
+	group, err := service.CreateGroup(retro.ID, retro.CreatedBy, &suggestions[0].GroupCreateRequest)
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy / manual", group.Name)
+
+	// Grouped items are not suggested again
+	suggestions, err = service.SuggestGroups(retro.ID, retro.CreatedBy, DefaultGroupSuggestionSimilarity)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, []string{items[2].ID.String(), items[4].ID.String()}, suggestions[0].ItemIDs)
+
+	suggestions, err = service.SuggestGroups(retro.ID, retro.CreatedBy, 1)
+	require.NoError(t, err)
+	assert.Empty(t, suggestions)
+
+	member := uuid.New()
+	mockRepo.teamRoles[[2]uuid.UUID{retro.TeamID, member}] = "member"
+	_, err = service.SuggestGroups(retro.ID, member, DefaultGroupSuggestionSimilarity)
+	assert.EqualError(t, err, "access denied")
+	_, err = service.SuggestGroups(retro.ID, retro.CreatedBy, 1.5)
+	assert.EqualError(t, err, "min_similarity must be between 0 and 1")
+	_, err = service.SuggestGroups(uuid.New(), retro.CreatedBy, DefaultGroupSuggestionSimilarity)
+	assert.EqualError(t, err, "retrospective not found")
+}
+
+func TestMostVotedItems(t *testing.T) {
+	start := time.Now()
+	items := []models.RetrospectiveItem{
+		{Content: "a", Votes: 1, CreatedAt: start},
+		{Content: "b", Votes: 3, CreatedAt: start.Add(time.Minute)},
+		{Content: "c", Votes: 1, CreatedAt: start.Add(-time.Minute)},
+		{Content: "d", Votes: 2, CreatedAt: start},
+	}
+
+	mostVoted := mostVotedItems(items, 3)
+
+	// In the given order, the oldest kept on ties
+	require.Len(t, mostVoted, 3)
+	assert.Equal(t, "b", mostVoted[0].Content)
+	assert.Equal(t, "c", mostVoted[1].Content)
+	assert.Equal(t, "d", mostVoted[2].Content)
+}
+
+func TestRetrospectiveService_SaveDiscussionNote(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento", "Boa comunicação")
+	alice, bob := uuid.New(), uuid.New()
+
+	note, err := service.SaveDiscussionNote(models.NoteSubjectItem, items[0].ID, alice, &models.DiscussionNoteRequest{Content: " Deploy leva 2h "})
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy leva 2h", note.Content)
+	assert.Equal(t, 1, note.Version)
+	assert.Equal(t, retro.ID, note.RetrospectiveID)
+	assert.Equal(t, &items[0].ID, note.ItemID)
+	assert.Equal(t, &alice, note.UpdatedBy)
+
+	note, err = service.SaveDiscussionNote(models.NoteSubjectItem, items[0].ID, bob, &models.DiscussionNoteRequest{
+		Content: "Deploy leva 2h", Outcome: "Automatizar o deploy", Version: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, note.Version)
+	assert.Equal(t, "Automatizar o deploy", note.Outcome)
+
+	// Saving over a newer version is refused, with the current note
+	current, err := service.SaveDiscussionNote(models.NoteSubjectItem, items[0].ID, alice, &models.DiscussionNoteRequest{Content: "Outra coisa", Version: 1})
+	assert.EqualError(t, err, "note was changed by someone else")
+	require.NotNil(t, current)
+	assert.Equal(t, "Automatizar o deploy", current.Outcome)
+	_, err = service.SaveDiscussionNote(models.NoteSubjectItem, items[0].ID, alice, &models.DiscussionNoteRequest{Content: "Nova nota"})
+	assert.EqualError(t, err, "note was changed by someone else")
+
+	group, err := service.CreateGroup(retro.ID, alice, &models.GroupCreateRequest{Name: "Pipeline", ItemIDs: []string{items[1].ID.String()}})
+	require.NoError(t, err)
+	note, err = service.SaveDiscussionNote(models.NoteSubjectGroup, group.ID, bob, &models.DiscussionNoteRequest{Outcome: "Cache de dependências"})
+	require.NoError(t, err)
+	assert.Equal(t, &group.ID, note.GroupID)
+	assert.Nil(t, note.ItemID)
+
+	notes, err := mockRepo.GetDiscussionNotes(retro.ID)
+	require.NoError(t, err)
+	assert.Len(t, notes, 2)
+
+	_, err = service.SaveDiscussionNote(models.NoteSubjectItem, uuid.New(), alice, &models.DiscussionNoteRequest{})
+	assert.EqualError(t, err, "item not found")
+	_, err = service.SaveDiscussionNote(models.NoteSubjectGroup, uuid.New(), alice, &models.DiscussionNoteRequest{})
+	assert.EqualError(t, err, "group not found")
+	_, err = service.SaveDiscussionNote(models.NoteSubjectItem, items[2].ID, alice, &models.DiscussionNoteRequest{Version: -1})
+	assert.EqualError(t, err, "invalid version")
+
+	retro.Status = models.RetroStatusClosed
+	_, err = service.SaveDiscussionNote(models.NoteSubjectItem, items[2].ID, alice, &models.DiscussionNoteRequest{Content: "Tarde demais"})
+	assert.EqualError(t, err, "can only edit notes of active retrospectives")
+}
+
+func TestRetrospectiveService_DiscussionQueue(t *testing.T) {
+	mockRepo := NewMockRetrospectiveRepository()
+	service := NewRetrospectiveService(mockRepo, nil)
+	retro := &models.Retrospective{ID: uuid.New(), Template: models.TemplateStartStopContinue, Status: models.RetroStatusActive, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRepo.retrospectives[retro.ID] = retro
+	items := addItems(mockRepo, retro.ID, "Deploy manual", "Pipeline lento", "Boa comunicação")
+	items[0].Votes, items[1].Votes = 1, 3
+	start := time.Now()
+	kudos := &models.RetrospectiveItem{ID: uuid.New(), RetrospectiveID: retro.ID, Category: "kudos", Content: "Valeu, time!", Votes: 5}
+	mergedInto := items[0].ID
+	merged := &models.RetrospectiveItem{ID: uuid.New(), RetrospectiveID: retro.ID, Category: "stop", Content: "Deploy na mão", Votes: 4, MergedIntoID: &mergedInto}
+	group := models.RetrospectiveGroup{ID: uuid.New(), RetrospectiveID: retro.ID, Name: "Comunicação", Votes: 1, ItemIDs: []uuid.UUID{items[2].ID}, CreatedAt: start}
+	items[2].Votes = 1
+
+	details := &models.RetrospectiveWithDetails{Retrospective: *retro, Groups: []models.RetrospectiveGroup{group}}
+	for i, item := range append(items, kudos, merged) {
+		item.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		details.Items = append(details.Items, *item)
+	}
+	mockRepo.details[retro.ID] = details
+
+	// The most voted first; kudos, merged and grouped items are not topics
+	queue, err := service.GetDiscussionQueue(retro.ID, uuid.New())
+	require.NoError(t, err)
+	require.Len(t, queue.Topics, 3)
+	assert.Equal(t, items[1].ID, queue.Topics[0].ID)
+	assert.Equal(t, "stop", queue.Topics[0].Category)
+	assert.Equal(t, models.DiscussionTopic{Type: models.NoteSubjectGroup, ID: group.ID, Title: "Comunicação", Votes: 2, ItemIDs: []uuid.UUID{items[2].ID}}, queue.Topics[1])
+	assert.Equal(t, items[0].ID, queue.Topics[2].ID)
+	assert.Nil(t, queue.Current)
+
+	// Advancing starts from the first topic and ends after the last one
+	queue, err = service.NextTopic(retro.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	require.NotNil(t, queue.Current)
+	assert.Equal(t, items[1].ID, queue.Current.ID)
+	assert.NotNil(t, queue.CurrentStartedAt)
+
+	queue, err = service.NextTopic(retro.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Equal(t, group.ID, queue.Current.ID)
+	assert.Equal(t, &group.ID, mockRepo.currentTopics[retro.ID].GroupID)
+
+	queue, err = service.GetDiscussionQueue(retro.ID, uuid.New())
+	require.NoError(t, err)
+	require.NotNil(t, queue.Current)
+	assert.Equal(t, models.NoteSubjectGroup, queue.Current.Type)
+
+	queue, err = service.SetCurrentTopic(retro.ID, retro.CreatedBy, models.NoteSubjectItem, items[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, items[0].ID, queue.Current.ID)
+
+	queue, err = service.NextTopic(retro.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Nil(t, queue.Current)
+	assert.Nil(t, queue.CurrentStartedAt)
+
+	_, err = service.SetCurrentTopic(retro.ID, retro.CreatedBy, models.NoteSubjectGroup, group.ID)
+	require.NoError(t, err)
+	queue, err = service.ClearCurrentTopic(retro.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Nil(t, queue.Current)
+	assert.Nil(t, mockRepo.currentTopics[retro.ID].GroupID)
+
+	_, err = service.SetCurrentTopic(retro.ID, retro.CreatedBy, models.NoteSubjectItem, items[2].ID)
+	assert.EqualError(t, err, "topic is not in the discussion queue")
+	_, err = service.SetCurrentTopic(retro.ID, retro.CreatedBy, models.NoteSubjectGroup, items[1].ID)
+	assert.EqualError(t, err, "topic is not in the discussion queue")
+	_, err = service.SetCurrentTopic(retro.ID, retro.CreatedBy, "card", items[1].ID)
+	assert.EqualError(t, err, "invalid topic type")
+
+	// A topic grouped while being discussed goes on as its group
+	_, err = service.SetCurrentTopic(retro.ID, retro.CreatedBy, models.NoteSubjectItem, items[1].ID)
+	require.NoError(t, err)
+	details.Groups[0].ItemIDs = append(details.Groups[0].ItemIDs, items[1].ID)
+	queue, err = service.NextTopic(retro.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Equal(t, items[0].ID, queue.Current.ID)
+
+	// and one merged while being discussed as the item it was merged into
+	mockRepo.currentTopics[retro.ID] = &models.CurrentTopic{ItemID: &merged.ID, StartedAt: &start}
+	queue, err = service.NextTopic(retro.ID, retro.CreatedBy)
+	require.NoError(t, err)
+	assert.Nil(t, queue.Current)
+
+	// Only the facilitators lead the discussion, while the retrospective is active
+	member := uuid.New()
+	mockRepo.teamRoles[[2]uuid.UUID{retro.TeamID, member}] = "member"
+	_, err = service.NextTopic(retro.ID, member)
+	assert.EqualError(t, err, "access denied")
+	owner := uuid.New()
+	mockRepo.teamRoles[[2]uuid.UUID{retro.TeamID, owner}] = "owner"
+	_, err = service.NextTopic(retro.ID, owner)
+	assert.NoError(t, err)
+
+	details.Status = models.RetroStatusClosed
+	_, err = service.NextTopic(retro.ID, retro.CreatedBy)
+	assert.EqualError(t, err, "can only change the topic of active retrospectives")
+	_, err = service.GetDiscussionQueue(retro.ID, member)
+	assert.NoError(t, err)
+	_, err = service.GetDiscussionQueue(uuid.New(), member)
+	assert.EqualError(t, err, "retrospective not found")
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	scheduleMaxIntervalWeeks = 12
+	scheduleBatchSize        = 50
+)
+
+// ScheduleService manages the recurring retrospectives of teams and runs
+// the scheduler: it creates the retrospective of each schedule leadTime
+// before its time, and starts planned retrospectives when their scheduled
+// time comes, the same way as a manual start.
+type ScheduleService struct {
+	scheduleRepo    repositories.ScheduleRepositoryInterface
+	retroRepo       repositories.RetrospectiveRepositoryInterface
+	realtimeService *RealtimeService
+	templates       *TemplateService
+	// leadTime is how long before its time a scheduled retrospective is
+	// created, so the team can see it and add items beforehand
+	leadTime time.Duration
+	now      func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	runMu    sync.Mutex
+}
+
+func NewScheduleService(scheduleRepo repositories.ScheduleRepositoryInterface, retroRepo repositories.RetrospectiveRepositoryInterface, realtimeService *RealtimeService, leadTime time.Duration) *ScheduleService {
+	return &ScheduleService{
+		scheduleRepo:    scheduleRepo,
+		retroRepo:       retroRepo,
+		realtimeService: realtimeService,
+		templates:       NewTemplateService(),
+		leadTime:        leadTime,
+		now:             time.Now,
+		stop:            make(chan struct{}),
+	}
+}
+
+// requireTeamEditor checks that the user owns or is a member of the team;
+// viewers cannot manage schedules
+func (s *ScheduleService) requireTeamEditor(teamID, userID uuid.UUID) error {
+	role, err := s.retroRepo.GetTeamRole(teamID, userID)
+	if err == sql.ErrNoRows || (err == nil && role != "owner" && role != "member") {
+		return errors.New("access denied")
+	}
+	return err
+}
+
+// getTeamSchedule returns the schedule if it belongs to the team
+func (s *ScheduleService) getTeamSchedule(teamID, scheduleID uuid.UUID) (*models.RetrospectiveSchedule, error) {
+	schedule, err := s.scheduleRepo.GetByID(scheduleID)
+	if err == sql.ErrNoRows || (err == nil && schedule.TeamID != teamID) {
+		return nil, errors.New("schedule not found")
+	}
+	return schedule, err
+}
+
+func (s *ScheduleService) validateSchedule(schedule *models.RetrospectiveSchedule) error {
+	if schedule.Title == "" {
+		return errors.New("title is required")
+	}
+	if !s.templates.ValidateTemplate(string(schedule.Template)) {
+		return errors.New("invalid template")
+	}
+	if schedule.IntervalWeeks < 1 || schedule.IntervalWeeks > scheduleMaxIntervalWeeks {
+		return errors.New("interval_weeks must be between 1 and 12")
+	}
+	return nil
+}
+
+// CreateSchedule creates a recurring retrospective for the team. The first
+// one happens at StartsAt and the next ones every IntervalWeeks weeks after.
+func (s *ScheduleService) CreateSchedule(teamID, userID uuid.UUID, req *models.RetrospectiveScheduleCreateRequest) (*models.RetrospectiveSchedule, error) {
+	if err := s.requireTeamEditor(teamID, userID); err != nil {
+		return nil, err
+	}
+
+	schedule := &models.RetrospectiveSchedule{
+		TeamID:        teamID,
+		Title:         req.Title,
+		Template:      req.Template,
+		IntervalWeeks: req.IntervalWeeks,
+		NextRunAt:     req.StartsAt,
+		Active:        req.Active == nil || *req.Active,
+		CreatedBy:     &userID,
+	}
+	if req.Description != "" {
+		schedule.Description = &req.Description
+	}
+
+	if err := s.validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	if !schedule.NextRunAt.After(s.now()) {
+		return nil, errors.New("starts_at must be in the future")
+	}
+
+	if err := s.scheduleRepo.Create(schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (s *ScheduleService) ListSchedules(teamID, userID uuid.UUID) ([]models.RetrospectiveSchedule, error) {
+	if _, err := s.retroRepo.GetTeamRole(teamID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("access denied")
+		}
+		return nil, err
+	}
+
+	return s.scheduleRepo.ListByTeam(teamID)
+}
+
+func (s *ScheduleService) UpdateSchedule(teamID, scheduleID, userID uuid.UUID, req *models.RetrospectiveScheduleUpdateRequest) (*models.RetrospectiveSchedule, error) {
+	if err := s.requireTeamEditor(teamID, userID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.getTeamSchedule(teamID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		schedule.Title = *req.Title
+	}
+	if req.Description != nil {
+		schedule.Description = req.Description
+		if *req.Description == "" {
+			schedule.Description = nil
+		}
+	}
+	if req.Template != nil {
+		schedule.Template = *req.Template
+	}
+	if req.IntervalWeeks != nil {
+		schedule.IntervalWeeks = *req.IntervalWeeks
+	}
+	if req.NextRunAt != nil {
+		if !req.NextRunAt.After(s.now()) {
+			return nil, errors.New("next_run_at must be in the future")
+		}
+		schedule.NextRunAt = *req.NextRunAt
+	}
+	if req.Active != nil {
+		schedule.Active = *req.Active
+	}
+
+	if err := s.validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// DeleteSchedule stops the recurrence. Retrospectives it already created are kept.
+func (s *ScheduleService) DeleteSchedule(teamID, scheduleID, userID uuid.UUID) error {
+	if err := s.requireTeamEditor(teamID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.getTeamSchedule(teamID, scheduleID); err != nil {
+		return err
+	}
+
+	return s.scheduleRepo.Delete(scheduleID)
+}
+
+// Start runs the scheduler immediately and then every interval until Stop is called
+func (s *ScheduleService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if created, started, err := s.RunOnce(); err != nil {
+				log.Printf("Failed to run retrospective scheduler: %v", err)
+			} else if created > 0 || started > 0 {
+				log.Printf("Scheduler created %d and started %d retrospectives", created, started)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *ScheduleService) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// RunOnce creates the retrospectives of the schedules that are due within
+// the lead time and starts the planned retrospectives whose time has come.
+// It returns how many retrospectives were created and started.
+func (s *ScheduleService) RunOnce() (int, int, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	now := s.now()
+
+	created, err := s.createScheduledRetrospectives(now)
+	if err != nil {
+		return created, 0, err
+	}
+
+	started, err := s.startDueRetrospectives(now)
+	return created, started, err
+}
+
+func (s *ScheduleService) createScheduledRetrospectives(now time.Time) (int, error) {
+	schedules, err := s.scheduleRepo.GetDueSchedules(now.Add(s.leadTime), scheduleBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, schedule := range schedules {
+		// Occurrences missed while the server was down are skipped rather
+		// than created all at once in the past: the first one still ahead is
+		// created instead
+		occurrence := schedule.NextRunAt
+		for occurrence.Before(now) {
+			occurrence = occurrence.AddDate(0, 0, 7*schedule.IntervalWeeks)
+		}
+		next := occurrence.AddDate(0, 0, 7*schedule.IntervalWeeks)
+
+		retrospective := &models.Retrospective{
+			TeamID:      schedule.TeamID,
+			Title:       schedule.Title + " - " + occurrence.Format("02/01/2006"),
+			Description: schedule.Description,
+			Template:    schedule.Template,
+			Status:      models.RetroStatusPlanned,
+			ScheduledAt: &occurrence,
+		}
+
+		// Claiming the occurrence and creating its retrospective together
+		// keeps concurrent runs from creating it twice, and a failed creation
+		// from skipping it
+		if err := s.scheduleRepo.CreateOccurrence(&schedule, next, retrospective); err != nil {
+			if err != sql.ErrNoRows {
+				log.Printf("Failed to create retrospective of schedule %s for %s: %v", schedule.ID, occurrence, err)
+			}
+			continue
+		}
+		created++
+	}
+
+	return created, nil
+}
+
+func (s *ScheduleService) startDueRetrospectives(now time.Time) (int, error) {
+	retrospectiveIDs, err := s.retroRepo.GetDueRetrospectives(now)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, retrospectiveID := range retrospectiveIDs {
+		// Same as a manual start, carrying over the unfinished action items
+		// of the previous retrospective; a concurrent run or a join may have
+		// started it already
+		started, _, err := startRetrospective(s.retroRepo, s.realtimeService, retrospectiveID)
+		if err != nil {
+			log.Printf("Failed to start scheduled retrospective %s: %v", retrospectiveID, err)
+			continue
+		}
+		if !started {
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockScheduleRepository keeps schedules in memory and creates their
+// retrospectives in retroRepo
+type MockScheduleRepository struct {
+	schedules map[uuid.UUID]*models.RetrospectiveSchedule
+	retroRepo *MockRetrospectiveRepository
+}
+
+func NewMockScheduleRepository(retroRepo *MockRetrospectiveRepository) *MockScheduleRepository {
+	return &MockScheduleRepository{schedules: make(map[uuid.UUID]*models.RetrospectiveSchedule), retroRepo: retroRepo}
+}
+
+func (m *MockScheduleRepository) Create(schedule *models.RetrospectiveSchedule) error {
+	schedule.ID = uuid.New()
+	scheduleCopy := *schedule
+	m.schedules[schedule.ID] = &scheduleCopy
+	return nil
+}
+
+func (m *MockScheduleRepository) GetByID(id uuid.UUID) (*models.RetrospectiveSchedule, error) {
+	schedule, exists := m.schedules[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	scheduleCopy := *schedule
+	return &scheduleCopy, nil
+}
+
+func (m *MockScheduleRepository) ListByTeam(teamID uuid.UUID) ([]models.RetrospectiveSchedule, error) {
+	schedules := []models.RetrospectiveSchedule{}
+	for _, schedule := range m.schedules {
+		if schedule.TeamID == teamID {
+			schedules = append(schedules, *schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (m *MockScheduleRepository) GetDueSchedules(until time.Time, limit int) ([]models.RetrospectiveSchedule, error) {
+	schedules := []models.RetrospectiveSchedule{}
+	for _, schedule := range m.schedules {
+		if schedule.Active && !schedule.NextRunAt.After(until) && len(schedules) < limit {
+			schedules = append(schedules, *schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (m *MockScheduleRepository) Update(schedule *models.RetrospectiveSchedule) error {
+	if _, exists := m.schedules[schedule.ID]; !exists {
+		return sql.ErrNoRows
+	}
+	scheduleCopy := *schedule
+	m.schedules[schedule.ID] = &scheduleCopy
+	return nil
+}
+
+func (m *MockScheduleRepository) CreateOccurrence(schedule *models.RetrospectiveSchedule, next time.Time, retrospective *models.Retrospective) error {
+	stored, exists := m.schedules[schedule.ID]
+	if !exists || !stored.NextRunAt.Equal(schedule.NextRunAt) {
+		return sql.ErrNoRows
+	}
+	if schedule.CreatedBy != nil {
+		retrospective.CreatedBy = *schedule.CreatedBy
+	}
+	if err := m.retroRepo.Create(retrospective); err != nil {
+		return err
+	}
+	stored.NextRunAt = next
+	return nil
+}
+
+func (m *MockScheduleRepository) Delete(id uuid.UUID) error {
+	if _, exists := m.schedules[id]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(m.schedules, id)
+	return nil
+}
+
+func TestScheduleService_CreateSchedule(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewScheduleService(NewMockScheduleRepository(mockRetroRepo), mockRetroRepo, nil, 72*time.Hour)
+	service.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	teamID := uuid.New()
+	userID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, userID}] = "member"
+	startsAt := time.Date(2024, 5, 10, 14, 0, 0, 0, time.UTC)
+
+	req := &models.RetrospectiveScheduleCreateRequest{
+		Title:         "Retro quinzenal",
+		Template:      models.TemplateStartStopContinue,
+		IntervalWeeks: 2,
+		StartsAt:      startsAt,
+	}
+	schedule, err := service.CreateSchedule(teamID, userID, req)
+	require.NoError(t, err)
+	assert.True(t, schedule.Active)
+	assert.Equal(t, startsAt, schedule.NextRunAt)
+
+	req.IntervalWeeks = 13
+	_, err = service.CreateSchedule(teamID, userID, req)
+	assert.EqualError(t, err, "interval_weeks must be between 1 and 12")
+
+	req.IntervalWeeks = 2
+	req.StartsAt = time.Date(2024, 4, 30, 14, 0, 0, 0, time.UTC)
+	_, err = service.CreateSchedule(teamID, userID, req)
+	assert.EqualError(t, err, "starts_at must be in the future")
+
+	viewerID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, viewerID}] = "viewer"
+	_, err = service.CreateSchedule(teamID, viewerID, req)
+	assert.EqualError(t, err, "access denied")
+}
+
+func TestScheduleService_RunOnce_CreatesAhead(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockScheduleRepo := NewMockScheduleRepository(mockRetroRepo)
+	service := NewScheduleService(mockScheduleRepo, mockRetroRepo, nil, 72*time.Hour)
+	service.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	teamID := uuid.New()
+	userID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, userID}] = "member"
+
+	// Within the lead time: created now, started later
+	schedule, err := service.CreateSchedule(teamID, userID, &models.RetrospectiveScheduleCreateRequest{
+		Title:         "Retro quinzenal",
+		Template:      models.TemplateSailboat,
+		IntervalWeeks: 2,
+		StartsAt:      time.Date(2024, 5, 3, 14, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	created, started, err := service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 0, started)
+	assert.Equal(t, time.Date(2024, 5, 17, 14, 0, 0, 0, time.UTC), mockScheduleRepo.schedules[schedule.ID].NextRunAt)
+
+	require.Len(t, mockRetroRepo.retrospectives, 1)
+	for _, retro := range mockRetroRepo.retrospectives {
+		assert.Equal(t, "Retro quinzenal - 03/05/2024", retro.Title)
+		assert.Equal(t, teamID, retro.TeamID)
+		assert.Equal(t, userID, retro.CreatedBy)
+		assert.Equal(t, models.RetroStatusPlanned, retro.Status)
+		assert.Equal(t, time.Date(2024, 5, 3, 14, 0, 0, 0, time.UTC), *retro.ScheduledAt)
+	}
+
+	// Nothing new on the next run
+	created, _, err = service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+}
+
+func TestScheduleService_RunOnce_SkipsMissedOccurrences(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockScheduleRepo := NewMockScheduleRepository(mockRetroRepo)
+	service := NewScheduleService(mockScheduleRepo, mockRetroRepo, nil, 72*time.Hour)
+	service.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	teamID := uuid.New()
+	userID := uuid.New()
+
+	// The server was down for weeks: the next run is long past
+	schedule := &models.RetrospectiveSchedule{
+		TeamID:        teamID,
+		Title:         "Retro semanal",
+		Template:      models.TemplateStartStopContinue,
+		IntervalWeeks: 1,
+		NextRunAt:     time.Date(2024, 4, 3, 14, 0, 0, 0, time.UTC),
+		Active:        true,
+		CreatedBy:     &userID,
+	}
+	require.NoError(t, mockScheduleRepo.Create(schedule))
+
+	created, started, err := service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 0, started)
+	assert.Equal(t, time.Date(2024, 5, 8, 14, 0, 0, 0, time.UTC), mockScheduleRepo.schedules[schedule.ID].NextRunAt)
+
+	require.Len(t, mockRetroRepo.retrospectives, 1)
+	for _, retro := range mockRetroRepo.retrospectives {
+		assert.Equal(t, "Retro semanal - 01/05/2024", retro.Title)
+		assert.Equal(t, models.RetroStatusPlanned, retro.Status)
+		assert.Equal(t, time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC), *retro.ScheduledAt)
+	}
+}
+
+func TestScheduleService_RunOnce_StartsDue(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	realtimeService := NewRealtimeService()
+	service := NewScheduleService(NewMockScheduleRepository(mockRetroRepo), mockRetroRepo, realtimeService, time.Hour)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	var mu sync.Mutex
+	started := []uuid.UUID{}
+	realtimeService.AddListener(func(retrospectiveID uuid.UUID, eventType string, data interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		if eventType == "retrospective_started" {
+			started = append(started, retrospectiveID)
+		}
+	})
+
+	due := now.Add(-time.Minute)
+	later := now.Add(time.Hour)
+	dueRetro := &models.Retrospective{ID: uuid.New(), Status: models.RetroStatusPlanned, ScheduledAt: &due, CreatedBy: uuid.New()}
+	laterRetro := &models.Retrospective{ID: uuid.New(), Status: models.RetroStatusPlanned, ScheduledAt: &later, CreatedBy: uuid.New()}
+	mockRetroRepo.retrospectives[dueRetro.ID] = dueRetro
+	mockRetroRepo.retrospectives[laterRetro.ID] = laterRetro
+
+	_, count, err := service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, models.RetroStatusActive, dueRetro.Status)
+	assert.Equal(t, models.RetroStatusPlanned, laterRetro.Status)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []uuid.UUID{dueRetro.ID}, started)
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"educ-retro/internal/export"
+	"educ-retro/internal/models"
+	"educ-retro/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	shareLinkDefaultDays = 7
+	shareLinkMaxDays     = 90
+)
+
+// ShareService manages who can export a retrospective and the public
+// read-only links to its summary
+type ShareService struct {
+	retroRepo repositories.RetrospectiveRepositoryInterface
+	shareRepo repositories.ShareLinkRepositoryInterface
+	// appURL is the base URL of the web app, for the URL of the summary page
+	appURL string
+	now    func() time.Time
+}
+
+func NewShareService(retroRepo repositories.RetrospectiveRepositoryInterface, shareRepo repositories.ShareLinkRepositoryInterface, appURL string) *ShareService {
+	return &ShareService{
+		retroRepo: retroRepo,
+		shareRepo: shareRepo,
+		appURL:    strings.TrimSuffix(appURL, "/"),
+		now:       time.Now,
+	}
+}
+
+func (s *ShareService) getRetrospective(retrospectiveID uuid.UUID) (*models.Retrospective, error) {
+	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("retrospective not found")
+	}
+	return retrospective, err
+}
+
+// GetSharing returns the export policy of the retrospective, what the user
+// can do and, if they can export, the links that have not expired
+func (s *ShareService) GetSharing(retrospectiveID, userID uuid.UUID) (*models.RetrospectiveSharing, error) {
+	retrospective, err := s.getRetrospective(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.retroRepo.GetExportPolicy(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	sharing := &models.RetrospectiveSharing{ExportPolicy: policy, ShareLinks: []models.ShareLink{}}
+	if sharing.CanExport, err = canExport(s.retroRepo, retrospective, userID); err != nil {
+		return nil, err
+	}
+	if sharing.CanManage, err = isFacilitator(s.retroRepo, retrospective, userID); err != nil {
+		return nil, err
+	}
+
+	if sharing.CanExport {
+		links, err := s.shareRepo.ListByRetrospective(retrospectiveID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range links {
+			links[i].URL = s.linkURL(links[i].Token)
+		}
+		sharing.ShareLinks = links
+	}
+
+	return sharing, nil
+}
+
+// SetExportPolicy changes who can export the retrospective. Only its
+// facilitators can.
+func (s *ShareService) SetExportPolicy(retrospectiveID, userID uuid.UUID, policy models.ExportPolicy) error {
+	switch policy {
+	case models.ExportPolicyCreator, models.ExportPolicyFacilitators, models.ExportPolicyParticipants, models.ExportPolicyTeam:
+	default:
+		return errors.New("invalid export_policy")
+	}
+
+	retrospective, err := s.getRetrospective(retrospectiveID)
+	if err != nil {
+		return err
+	}
+
+	facilitator, err := isFacilitator(s.retroRepo, retrospective, userID)
+	if err != nil {
+		return err
+	}
+	if !facilitator {
+		return errors.New("access denied")
+	}
+
+	return s.retroRepo.SetExportPolicy(retrospectiveID, policy)
+}
+
+// CreateShareLink creates a public link to the summary of the retrospective,
+// for whoever can export it
+func (s *ShareService) CreateShareLink(retrospectiveID, userID uuid.UUID, req *models.ShareLinkCreateRequest) (*models.ShareLink, error) {
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = shareLinkDefaultDays
+	}
+	if days < 1 || days > shareLinkMaxDays {
+		return nil, fmt.Errorf("expires_in_days must be between 1 and %d", shareLinkMaxDays)
+	}
+
+	retrospective, err := s.getRetrospective(retrospectiveID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := canExport(s.retroRepo, retrospective, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("export not allowed")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	link := &models.ShareLink{
+		RetrospectiveID: retrospectiveID,
+		Token:           hex.EncodeToString(secret),
+		CreatedBy:       userID,
+		ExpiresAt:       s.now().AddDate(0, 0, days),
+	}
+	if err := s.shareRepo.Create(link); err != nil {
+		return nil, err
+	}
+
+	link.URL = s.linkURL(link.Token)
+	return link, nil
+}
+
+// DeleteShareLink revokes a link. Its creator and the facilitators of the
+// retrospective can.
+func (s *ShareService) DeleteShareLink(retrospectiveID, linkID, userID uuid.UUID) error {
+	link, err := s.shareRepo.GetByID(linkID)
+	if err == sql.ErrNoRows || (err == nil && link.RetrospectiveID != retrospectiveID) {
+		return errors.New("share link not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if link.CreatedBy != userID {
+		retrospective, err := s.getRetrospective(retrospectiveID)
+		if err != nil {
+			return err
+		}
+		facilitator, err := isFacilitator(s.retroRepo, retrospective, userID)
+		if err != nil {
+			return err
+		}
+		if !facilitator {
+			return errors.New("access denied")
+		}
+	}
+
+	return s.shareRepo.Delete(linkID)
+}
+
+// GetSharedReport returns the report of the retrospective of a public link,
+// and the link. Expired and unknown links are not found alike.
+func (s *ShareService) GetSharedReport(token string) (*export.Report, *models.ShareLink, error) {
+	link, err := s.shareRepo.GetByToken(token)
+	if err == sql.ErrNoRows || (err == nil && !link.ExpiresAt.After(s.now())) {
+		return nil, nil, errors.New("share link not found")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := buildExportReport(s.retroRepo, link.RetrospectiveID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return report, link, nil
+}
+
+// linkURL is the page of the summary in the web app, or "" when the app URL
+// is not configured
+func (s *ShareService) linkURL(token string) string {
+	if s.appURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/share/%s", s.appURL, token)
+}
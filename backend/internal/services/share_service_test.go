@@ -0,0 +1,213 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MockShareLinkRepository struct {
+	links map[uuid.UUID]*models.ShareLink
+}
+
+func NewMockShareLinkRepository() *MockShareLinkRepository {
+	return &MockShareLinkRepository{links: make(map[uuid.UUID]*models.ShareLink)}
+}
+
+func (m *MockShareLinkRepository) Create(link *models.ShareLink) error {
+	link.ID = uuid.New()
+	link.CreatedAt = time.Now()
+	linkCopy := *link
+	m.links[link.ID] = &linkCopy
+	return nil
+}
+func (m *MockShareLinkRepository) GetByID(id uuid.UUID) (*models.ShareLink, error) {
+	link, exists := m.links[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	linkCopy := *link
+	return &linkCopy, nil
+}
+func (m *MockShareLinkRepository) GetByToken(token string) (*models.ShareLink, error) {
+	for _, link := range m.links {
+		if link.Token == token {
+			linkCopy := *link
+			return &linkCopy, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+func (m *MockShareLinkRepository) ListByRetrospective(retrospectiveID uuid.UUID) ([]models.ShareLink, error) {
+	links := []models.ShareLink{}
+	for _, link := range m.links {
+		if link.RetrospectiveID == retrospectiveID && link.ExpiresAt.After(time.Now()) {
+			links = append(links, *link)
+		}
+	}
+	return links, nil
+}
+func (m *MockShareLinkRepository) Delete(id uuid.UUID) error {
+	if _, exists := m.links[id]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(m.links, id)
+	return nil
+}
+
+func TestShareService_ExportPolicy(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewShareService(mockRetroRepo, NewMockShareLinkRepository(), "http://app.test/")
+
+	retro := &models.Retrospective{ID: uuid.New(), Title: "Sprint 12", Template: models.TemplateStartStopContinue, Status: models.RetroStatusClosed, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRetroRepo.retrospectives[retro.ID] = retro
+	mockRetroRepo.details[retro.ID] = &models.RetrospectiveWithDetails{Retrospective: *retro}
+
+	owner, member, viewer, participant, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{retro.TeamID, owner}] = "owner"
+	mockRetroRepo.teamRoles[[2]uuid.UUID{retro.TeamID, member}] = "member"
+	mockRetroRepo.teamRoles[[2]uuid.UUID{retro.TeamID, viewer}] = "viewer"
+	mockRetroRepo.members[retro.ID] = []uuid.UUID{participant}
+
+	tests := []struct {
+		policy  models.ExportPolicy
+		allowed []uuid.UUID
+		denied  []uuid.UUID
+	}{
+		{models.ExportPolicyCreator, []uuid.UUID{retro.CreatedBy}, []uuid.UUID{owner, member, viewer, participant, outsider}},
+		{models.ExportPolicyFacilitators, []uuid.UUID{retro.CreatedBy, owner}, []uuid.UUID{member, viewer, participant, outsider}},
+		{models.ExportPolicyParticipants, []uuid.UUID{retro.CreatedBy, owner, participant}, []uuid.UUID{member, viewer, outsider}},
+		{models.ExportPolicyTeam, []uuid.UUID{retro.CreatedBy, owner, participant, member, viewer}, []uuid.UUID{outsider}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			require.NoError(t, service.SetExportPolicy(retro.ID, retro.CreatedBy, tt.policy))
+
+			for _, userID := range tt.allowed {
+				sharing, err := service.GetSharing(retro.ID, userID)
+				require.NoError(t, err)
+				assert.Equal(t, tt.policy, sharing.ExportPolicy)
+				assert.True(t, sharing.CanExport)
+			}
+			for _, userID := range tt.denied {
+				sharing, err := service.GetSharing(retro.ID, userID)
+				require.NoError(t, err)
+				assert.False(t, sharing.CanExport)
+			}
+		})
+	}
+
+	// Only the facilitators change the policy
+	assert.EqualError(t, service.SetExportPolicy(retro.ID, member, models.ExportPolicyTeam), "access denied")
+	assert.NoError(t, service.SetExportPolicy(retro.ID, owner, models.ExportPolicyCreator))
+	assert.EqualError(t, service.SetExportPolicy(retro.ID, owner, "everyone"), "invalid export_policy")
+	assert.EqualError(t, service.SetExportPolicy(uuid.New(), owner, models.ExportPolicyTeam), "retrospective not found")
+
+	sharing, err := service.GetSharing(retro.ID, owner)
+	require.NoError(t, err)
+	assert.True(t, sharing.CanManage)
+	sharing, err = service.GetSharing(retro.ID, member)
+	require.NoError(t, err)
+	assert.False(t, sharing.CanManage)
+}
+
+func TestShareService_CreateShareLink(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewShareService(mockRetroRepo, NewMockShareLinkRepository(), "http://app.test/")
+
+	retro := &models.Retrospective{ID: uuid.New(), Title: "Sprint 12", Template: models.TemplateStartStopContinue, Status: models.RetroStatusClosed, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRetroRepo.retrospectives[retro.ID] = retro
+	mockRetroRepo.details[retro.ID] = &models.RetrospectiveWithDetails{Retrospective: *retro}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	link, err := service.CreateShareLink(retro.ID, retro.CreatedBy, &models.ShareLinkCreateRequest{})
+	require.NoError(t, err)
+	assert.Len(t, link.Token, 64)
+	assert.Equal(t, now.AddDate(0, 0, 7), link.ExpiresAt)
+	assert.Equal(t, "http://app.test/share/"+link.Token, link.URL)
+
+	link, err = service.CreateShareLink(retro.ID, retro.CreatedBy, &models.ShareLinkCreateRequest{ExpiresInDays: 30})
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, 30), link.ExpiresAt)
+
+	_, err = service.CreateShareLink(retro.ID, retro.CreatedBy, &models.ShareLinkCreateRequest{ExpiresInDays: 91})
+	assert.EqualError(t, err, "expires_in_days must be between 1 and 90")
+	_, err = service.CreateShareLink(retro.ID, retro.CreatedBy, &models.ShareLinkCreateRequest{ExpiresInDays: -1})
+	assert.EqualError(t, err, "expires_in_days must be between 1 and 90")
+
+	// Members create links once the policy lets them export
+	member := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{retro.TeamID, member}] = "member"
+	_, err = service.CreateShareLink(retro.ID, member, &models.ShareLinkCreateRequest{})
+	assert.EqualError(t, err, "export not allowed")
+
+	mockRetroRepo.exportPolicies[retro.ID] = models.ExportPolicyTeam
+	_, err = service.CreateShareLink(retro.ID, member, &models.ShareLinkCreateRequest{})
+	assert.NoError(t, err)
+}
+
+func TestShareService_DeleteShareLink(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockShareRepo := NewMockShareLinkRepository()
+	service := NewShareService(mockRetroRepo, mockShareRepo, "http://app.test/")
+
+	retro := &models.Retrospective{ID: uuid.New(), Title: "Sprint 12", Template: models.TemplateStartStopContinue, Status: models.RetroStatusClosed, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRetroRepo.retrospectives[retro.ID] = retro
+	mockRetroRepo.details[retro.ID] = &models.RetrospectiveWithDetails{Retrospective: *retro}
+	mockRetroRepo.exportPolicies[retro.ID] = models.ExportPolicyTeam
+
+	owner, member, other := uuid.New(), uuid.New(), uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{retro.TeamID, owner}] = "owner"
+	mockRetroRepo.teamRoles[[2]uuid.UUID{retro.TeamID, member}] = "member"
+	mockRetroRepo.teamRoles[[2]uuid.UUID{retro.TeamID, other}] = "member"
+
+	link, err := service.CreateShareLink(retro.ID, member, &models.ShareLinkCreateRequest{})
+	require.NoError(t, err)
+
+	assert.EqualError(t, service.DeleteShareLink(retro.ID, link.ID, other), "access denied")
+	assert.EqualError(t, service.DeleteShareLink(uuid.New(), link.ID, member), "share link not found")
+	assert.NoError(t, service.DeleteShareLink(retro.ID, link.ID, member))
+	assert.Empty(t, mockShareRepo.links)
+
+	// Facilitators revoke the links of others
+	link, err = service.CreateShareLink(retro.ID, member, &models.ShareLinkCreateRequest{})
+	require.NoError(t, err)
+	assert.NoError(t, service.DeleteShareLink(retro.ID, link.ID, owner))
+	assert.EqualError(t, service.DeleteShareLink(retro.ID, link.ID, owner), "share link not found")
+}
+
+func TestShareService_GetSharedReport(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewShareService(mockRetroRepo, NewMockShareLinkRepository(), "http://app.test/")
+
+	retro := &models.Retrospective{ID: uuid.New(), Title: "Sprint 12", Template: models.TemplateStartStopContinue, Status: models.RetroStatusClosed, CreatedBy: uuid.New(), TeamID: uuid.New()}
+	mockRetroRepo.retrospectives[retro.ID] = retro
+	mockRetroRepo.details[retro.ID] = &models.RetrospectiveWithDetails{Retrospective: *retro}
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	link, err := service.CreateShareLink(retro.ID, retro.CreatedBy, &models.ShareLinkCreateRequest{ExpiresInDays: 1})
+	require.NoError(t, err)
+
+	report, sharedLink, err := service.GetSharedReport(link.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint 12", report.Retrospective.Title)
+	assert.Equal(t, "Start, Stop, Continue", report.TemplateName)
+	assert.Equal(t, link.ID, sharedLink.ID)
+
+	_, _, err = service.GetSharedReport("unknown")
+	assert.EqualError(t, err, "share link not found")
+
+	// Expired links are not found alike
+	service.now = func() time.Time { return now.AddDate(0, 0, 2) }
+	_, _, err = service.GetSharedReport(link.Token)
+	assert.EqualError(t, err, "share link not found")
+}
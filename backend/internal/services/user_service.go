@@ -1,7 +1,10 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
+	"math"
+	"time"
 
 	"educ-retro/internal/auth"
 	"educ-retro/internal/models"
@@ -56,6 +59,7 @@ func (s *UserService) Register(req *models.UserCreateRequest) (*models.UserRespo
 		Email:     user.Email,
 		Name:      user.Name,
 		Avatar:    user.Avatar,
+		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -74,6 +78,10 @@ func (s *UserService) Login(req *models.UserLoginRequest) (*models.UserResponse,
 		return nil, "", errors.New("invalid credentials")
 	}
 
+	if user.SuspendedAt != nil {
+		return nil, "", errors.New("account suspended")
+	}
+
 	// Generate JWT token
 	token, err := auth.GenerateToken(user.ID, user.Email, user.Name)
 	if err != nil {
@@ -86,6 +94,7 @@ func (s *UserService) Login(req *models.UserLoginRequest) (*models.UserResponse,
 		Email:     user.Email,
 		Name:      user.Name,
 		Avatar:    user.Avatar,
+		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -103,6 +112,7 @@ func (s *UserService) GetProfile(userID uuid.UUID) (*models.UserResponse, error)
 		Email:     user.Email,
 		Name:      user.Name,
 		Avatar:    user.Avatar,
+		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -130,8 +140,186 @@ func (s *UserService) UpdateProfile(userID uuid.UUID, name, avatar string) (*mod
 		Email:     user.Email,
 		Name:      user.Name,
 		Avatar:    user.Avatar,
+		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 	}
 
 	return userResponse, nil
 }
+
+// LoginWithOIDC signs in a user authenticated by an external identity provider.
+// The identity is matched by provider and subject first, then linked to an
+// existing account with the same email, and otherwise a new user is provisioned.
+func (s *UserService) LoginWithOIDC(identity *auth.OIDCIdentity) (*models.UserResponse, string, error) {
+	user, err := s.userRepo.GetByIdentity(identity.Issuer, identity.Subject)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, "", err
+	}
+	if err == sql.ErrNoRows {
+		if identity.Email == "" {
+			return nil, "", errors.New("identity provider did not return an email")
+		}
+		if !identity.EmailVerified {
+			return nil, "", errors.New("email not verified by identity provider")
+		}
+
+		user, err = s.userRepo.GetByEmail(identity.Email)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, "", err
+		}
+		if err == sql.ErrNoRows {
+			// Provision a new account; SSO users have no local password
+			user = &models.User{
+				Email: identity.Email,
+				Name:  identity.Name,
+			}
+
+			err = s.userRepo.Create(user)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+
+		err = s.userRepo.CreateIdentity(&models.UserIdentity{
+			UserID:   user.ID,
+			Provider: identity.Issuer,
+			Subject:  identity.Subject,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if user.SuspendedAt != nil {
+		return nil, "", errors.New("account suspended")
+	}
+
+	// Generate JWT token
+	token, err := auth.GenerateToken(user.ID, user.Email, user.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	userResponse := &models.UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Avatar:    user.Avatar,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+	}
+
+	return userResponse, token, nil
+}
+
+// GetAccountStatus returns the system role of the user and whether the account
+// is suspended. It is used by the authentication middleware on every request.
+func (s *UserService) GetAccountStatus(userID uuid.UUID) (string, bool, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return "", false, errors.New("user not found")
+	}
+
+	return string(user.Role), user.SuspendedAt != nil, nil
+}
+
+// DeleteAccount permanently removes the user (LGPD right to erasure)
+func (s *UserService) DeleteAccount(userID uuid.UUID, mode models.AccountDeletionMode) error {
+	if mode == "" {
+		mode = models.AccountDeletionAnonymize
+	}
+	if mode != models.AccountDeletionAnonymize && mode != models.AccountDeletionPurge {
+		return errors.New("invalid deletion mode. Must be one of: anonymize, purge")
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	return s.userRepo.DeleteAccount(userID, mode)
+}
+
+// ExportData returns every record stored about the user (LGPD data portability)
+func (s *UserService) ExportData(userID uuid.UUID) (*models.UserDataExport, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.userRepo.GetDataExport(userID)
+}
+
+// GetAnalytics returns the user's activity across retrospectives with the
+// action item completion rate for each of the last months (current month included)
+func (s *UserService) GetAnalytics(userID uuid.UUID, months int) (*models.UserAnalytics, error) {
+	if months < 1 || months > 24 {
+		return nil, errors.New("months must be between 1 and 24")
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -(months - 1), 0)
+
+	analytics, err := s.userRepo.GetAnalytics(userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics.ActionItems.CompletionRate = completionRate(analytics.ActionItems.Completed, analytics.ActionItems.Assigned)
+	analytics.CompletionHistory = fillCompletionHistory(analytics.CompletionHistory, since, months)
+
+	return analytics, nil
+}
+
+func (s *UserService) GetNotificationPreferences(userID uuid.UUID) (*models.NotificationPreferences, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.userRepo.GetNotificationPreferences(userID)
+}
+
+func (s *UserService) UpdateNotificationPreferences(userID uuid.UUID, preferences *models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if err := s.userRepo.UpdateNotificationPreferences(userID, preferences); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// fillCompletionHistory returns one point per month starting at since, using
+// zeroes for months without assigned action items
+func fillCompletionHistory(points []models.CompletionRatePoint, since time.Time, months int) []models.CompletionRatePoint {
+	byPeriod := make(map[string]models.CompletionRatePoint, len(points))
+	for _, point := range points {
+		byPeriod[point.Period] = point
+	}
+
+	history := make([]models.CompletionRatePoint, 0, months)
+	for i := 0; i < months; i++ {
+		period := since.AddDate(0, i, 0).Format("2006-01")
+		point, ok := byPeriod[period]
+		if !ok {
+			point = models.CompletionRatePoint{Period: period}
+		}
+		point.CompletionRate = completionRate(point.Completed, point.Assigned)
+		history = append(history, point)
+	}
+
+	return history
+}
+
+// completionRate returns completed/total as a percentage with one decimal, 0
+// when there is nothing to complete
+func completionRate(completed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(completed)/float64(total)*1000) / 10
+}
@@ -3,9 +3,11 @@ package services
 import (
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"educ-retro/internal/auth"
 	"educ-retro/internal/models"
 	"educ-retro/internal/utils"
 
@@ -15,14 +17,23 @@ import (
 
 // MockUserRepository é um mock simples do UserRepository
 type MockUserRepository struct {
-	users  map[uuid.UUID]*models.User
-	emails map[string]*models.User
+	users       map[uuid.UUID]*models.User
+	emails      map[string]*models.User
+	identities  map[string]uuid.UUID
+	preferences map[uuid.UUID]*models.NotificationPreferences
+	// calendarTokens maps a user to the token of their calendar feed
+	calendarTokens map[uuid.UUID]string
+	// lookupErr is returned by the lookups of users by identity and email
+	lookupErr error
 }
 
 func NewMockUserRepository() *MockUserRepository {
 	return &MockUserRepository{
-		users:  make(map[uuid.UUID]*models.User),
-		emails: make(map[string]*models.User),
+		users:          make(map[uuid.UUID]*models.User),
+		emails:         make(map[string]*models.User),
+		identities:     make(map[string]uuid.UUID),
+		preferences:    make(map[uuid.UUID]*models.NotificationPreferences),
+		calendarTokens: make(map[uuid.UUID]string),
 	}
 }
 
@@ -53,6 +64,9 @@ func (m *MockUserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 }
 
 func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
+	if m.lookupErr != nil {
+		return nil, m.lookupErr
+	}
 	user, exists := m.emails[email]
 	if !exists {
 		return nil, sql.ErrNoRows
@@ -91,6 +105,143 @@ func (m *MockUserRepository) GetUsersByIDs(ids []uuid.UUID) ([]models.User, erro
 	return users, nil
 }
 
+func (m *MockUserRepository) GetByIdentity(provider, subject string) (*models.User, error) {
+	if m.lookupErr != nil {
+		return nil, m.lookupErr
+	}
+	userID, exists := m.identities[provider+"|"+subject]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	return m.GetByID(userID)
+}
+
+func (m *MockUserRepository) CreateIdentity(identity *models.UserIdentity) error {
+	identity.ID = uuid.New()
+	identity.CreatedAt = time.Now()
+	m.identities[identity.Provider+"|"+identity.Subject] = identity.UserID
+	return nil
+}
+
+func (m *MockUserRepository) DeleteAccount(id uuid.UUID, mode models.AccountDeletionMode) error {
+	return m.Delete(id)
+}
+
+func (m *MockUserRepository) GetDataExport(id uuid.UUID) (*models.UserDataExport, error) {
+	user, exists := m.users[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	return &models.UserDataExport{
+		ExportedAt: time.Now(),
+		Profile:    models.UserResponse{ID: user.ID, Email: user.Email, Name: user.Name},
+	}, nil
+}
+
+func (m *MockUserRepository) GetAnalytics(id uuid.UUID, since time.Time) (*models.UserAnalytics, error) {
+	return &models.UserAnalytics{
+		ItemsByCategory: map[string]int{"start": 2},
+		ItemsAuthored:   2,
+		ActionItems:     models.ActionItemAnalytics{Assigned: 3, Completed: 1, Overdue: 1},
+		CompletionHistory: []models.CompletionRatePoint{
+			{Period: since.Format("2006-01"), Assigned: 3, Completed: 1},
+		},
+	}, nil
+}
+
+func (m *MockUserRepository) List(search string, limit, offset int) ([]models.User, int, error) {
+	users := []models.User{}
+	for _, user := range m.users {
+		if search == "" || strings.Contains(user.Email, search) || strings.Contains(user.Name, search) {
+			users = append(users, *user)
+		}
+	}
+	total := len(users)
+	if offset >= total {
+		return []models.User{}, total, nil
+	}
+	if offset+limit < total {
+		users = users[offset : offset+limit]
+	} else {
+		users = users[offset:]
+	}
+	return users, total, nil
+}
+
+func (m *MockUserRepository) SetSuspended(id uuid.UUID, suspended bool) error {
+	user, exists := m.users[id]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	user.SuspendedAt = nil
+	if suspended {
+		now := time.Now()
+		user.SuspendedAt = &now
+	}
+	return nil
+}
+
+func (m *MockUserRepository) UpdateRole(id uuid.UUID, role models.UserRole) error {
+	user, exists := m.users[id]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	user.Role = role
+	return nil
+}
+
+func (m *MockUserRepository) GetNotificationPreferences(id uuid.UUID) (*models.NotificationPreferences, error) {
+	if preferences, exists := m.preferences[id]; exists {
+		preferencesCopy := *preferences
+		return &preferencesCopy, nil
+	}
+	return &models.NotificationPreferences{ActionItemReminders: true}, nil
+}
+
+func (m *MockUserRepository) UpdateNotificationPreferences(id uuid.UUID, preferences *models.NotificationPreferences) error {
+	preferencesCopy := *preferences
+	m.preferences[id] = &preferencesCopy
+	return nil
+}
+
+func (m *MockUserRepository) GetCalendarToken(id uuid.UUID) (string, error) {
+	if _, exists := m.users[id]; !exists {
+		return "", sql.ErrNoRows
+	}
+	return m.calendarTokens[id], nil
+}
+
+func (m *MockUserRepository) SetCalendarToken(id uuid.UUID, token string) error {
+	if _, exists := m.users[id]; !exists {
+		return sql.ErrNoRows
+	}
+	m.calendarTokens[id] = token
+	return nil
+}
+
+func (m *MockUserRepository) GetUserIDByCalendarToken(token string) (uuid.UUID, error) {
+	for id, userToken := range m.calendarTokens {
+		if userToken == token && m.users[id].SuspendedAt == nil {
+			return id, nil
+		}
+	}
+	return uuid.Nil, sql.ErrNoRows
+}
+
+func (m *MockUserRepository) GetSystemStats() (*models.SystemStats, error) {
+	stats := &models.SystemStats{RetrospectivesByStatus: map[string]int{}}
+	for _, user := range m.users {
+		stats.Users++
+		if user.Role == models.UserRoleAdmin {
+			stats.Admins++
+		}
+		if user.SuspendedAt != nil {
+			stats.SuspendedUsers++
+		}
+	}
+	return stats, nil
+}
+
 func TestNewUserService(t *testing.T) {
 	mockRepo := NewMockUserRepository()
 	service := NewUserService(mockRepo)
@@ -218,6 +369,27 @@ func TestUserService_Login(t *testing.T) {
 			},
 			expectedError: "invalid credentials",
 		},
+		{
+			name: "suspended account",
+			request: &models.UserLoginRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			setupMock: func(m *MockUserRepository) {
+				hashedPassword, _ := utils.HashPassword("password123")
+				suspendedAt := time.Now()
+				user := &models.User{
+					ID:          uuid.New(),
+					Email:       "test@example.com",
+					Name:        "Test User",
+					Password:    hashedPassword,
+					SuspendedAt: &suspendedAt,
+					CreatedAt:   time.Now(),
+				}
+				m.emails["test@example.com"] = user
+			},
+			expectedError: "account suspended",
+		},
 	}
 
 	for _, tt := range tests {
@@ -413,3 +585,228 @@ func TestUserService_EdgeCases(t *testing.T) {
 		assert.Empty(t, token)
 	})
 }
+
+func TestUserService_LoginWithOIDC(t *testing.T) {
+	issuer := "https://idp.example.com"
+
+	t.Run("provisions a new user", func(t *testing.T) {
+		mockRepo := NewMockUserRepository()
+		service := NewUserService(mockRepo)
+
+		identity := &auth.OIDCIdentity{
+			Issuer:        issuer,
+			Subject:       "sub-1",
+			Email:         "new@example.com",
+			EmailVerified: true,
+			Name:          "New User",
+		}
+
+		user, token, err := service.LoginWithOIDC(identity)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		assert.Equal(t, identity.Email, user.Email)
+		assert.Equal(t, identity.Name, user.Name)
+		assert.Len(t, mockRepo.users, 1)
+		assert.Equal(t, user.ID, mockRepo.identities[issuer+"|sub-1"])
+	})
+
+	t.Run("links an existing user by email", func(t *testing.T) {
+		mockRepo := NewMockUserRepository()
+		service := NewUserService(mockRepo)
+
+		existing := &models.User{Email: "existing@example.com", Name: "Existing", Password: "hash"}
+		assert.NoError(t, mockRepo.Create(existing))
+
+		user, _, err := service.LoginWithOIDC(&auth.OIDCIdentity{
+			Issuer:        issuer,
+			Subject:       "sub-2",
+			Email:         existing.Email,
+			EmailVerified: true,
+			Name:          "Other Name",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing.ID, user.ID)
+		assert.Equal(t, "Existing", user.Name)
+		assert.Len(t, mockRepo.users, 1)
+		assert.Equal(t, existing.ID, mockRepo.identities[issuer+"|sub-2"])
+	})
+
+	t.Run("uses the linked identity even if the email changed", func(t *testing.T) {
+		mockRepo := NewMockUserRepository()
+		service := NewUserService(mockRepo)
+
+		existing := &models.User{Email: "old@example.com", Name: "Existing"}
+		assert.NoError(t, mockRepo.Create(existing))
+		mockRepo.identities[issuer+"|sub-3"] = existing.ID
+
+		user, _, err := service.LoginWithOIDC(&auth.OIDCIdentity{
+			Issuer:  issuer,
+			Subject: "sub-3",
+			Email:   "renamed@example.com",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing.ID, user.ID)
+	})
+
+	t.Run("rejects unverified email", func(t *testing.T) {
+		mockRepo := NewMockUserRepository()
+		service := NewUserService(mockRepo)
+
+		existing := &models.User{Email: "victim@example.com", Name: "Victim"}
+		assert.NoError(t, mockRepo.Create(existing))
+
+		user, token, err := service.LoginWithOIDC(&auth.OIDCIdentity{
+			Issuer:        issuer,
+			Subject:       "sub-4",
+			Email:         existing.Email,
+			EmailVerified: false,
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, "email not verified by identity provider", err.Error())
+		assert.Nil(t, user)
+		assert.Empty(t, token)
+		assert.Empty(t, mockRepo.identities)
+	})
+
+	t.Run("does not provision on database errors", func(t *testing.T) {
+		mockRepo := NewMockUserRepository()
+		service := NewUserService(mockRepo)
+		mockRepo.lookupErr = errors.New("connection refused")
+
+		user, _, err := service.LoginWithOIDC(&auth.OIDCIdentity{
+			Issuer:        issuer,
+			Subject:       "sub-6",
+			Email:         "sso@example.com",
+			EmailVerified: true,
+		})
+
+		assert.EqualError(t, err, "connection refused")
+		assert.Nil(t, user)
+		assert.Empty(t, mockRepo.users)
+		assert.Empty(t, mockRepo.identities)
+	})
+
+	t.Run("SSO user cannot login with empty password", func(t *testing.T) {
+		mockRepo := NewMockUserRepository()
+		service := NewUserService(mockRepo)
+
+		_, _, err := service.LoginWithOIDC(&auth.OIDCIdentity{
+			Issuer:        issuer,
+			Subject:       "sub-5",
+			Email:         "sso@example.com",
+			EmailVerified: true,
+			Name:          "SSO",
+		})
+		assert.NoError(t, err)
+
+		_, _, err = service.Login(&models.UserLoginRequest{Email: "sso@example.com", Password: ""})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid credentials", err.Error())
+	})
+}
+
+func TestUserService_DeleteAccount(t *testing.T) {
+	mockRepo := NewMockUserRepository()
+	service := NewUserService(mockRepo)
+
+	user := &models.User{Email: "delete@example.com", Name: "Delete Me"}
+	assert.NoError(t, mockRepo.Create(user))
+
+	err := service.DeleteAccount(user.ID, "everything")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid deletion mode")
+
+	err = service.DeleteAccount(user.ID, "")
+	assert.NoError(t, err)
+
+	_, err = mockRepo.GetByID(user.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	err = service.DeleteAccount(user.ID, models.AccountDeletionPurge)
+	assert.Error(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestUserService_ExportData(t *testing.T) {
+	mockRepo := NewMockUserRepository()
+	service := NewUserService(mockRepo)
+
+	user := &models.User{Email: "export@example.com", Name: "Export Me", Password: "secret-hash"}
+	assert.NoError(t, mockRepo.Create(user))
+
+	export, err := service.ExportData(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.Email, export.Profile.Email)
+
+	_, err = service.ExportData(uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestUserService_GetAnalytics(t *testing.T) {
+	mockRepo := NewMockUserRepository()
+	service := NewUserService(mockRepo)
+
+	user := &models.User{Email: "analytics@example.com", Name: "Analytics"}
+	assert.NoError(t, mockRepo.Create(user))
+
+	analytics, err := service.GetAnalytics(user.ID, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 33.3, analytics.ActionItems.CompletionRate)
+	assert.Len(t, analytics.CompletionHistory, 3)
+	assert.Equal(t, 3, analytics.CompletionHistory[0].Assigned)
+	assert.Equal(t, 33.3, analytics.CompletionHistory[0].CompletionRate)
+	assert.Equal(t, time.Now().Format("2006-01"), analytics.CompletionHistory[2].Period)
+	assert.Equal(t, 0, analytics.CompletionHistory[2].Assigned)
+
+	_, err = service.GetAnalytics(user.ID, 0)
+	assert.Error(t, err)
+
+	_, err = service.GetAnalytics(uuid.New(), 6)
+	assert.Error(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestFillCompletionHistory(t *testing.T) {
+	since := time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)
+	points := []models.CompletionRatePoint{
+		{Period: "2025-12", Assigned: 4, Completed: 4},
+		{Period: "2026-02", Assigned: 2, Completed: 1},
+	}
+
+	history := fillCompletionHistory(points, since, 4)
+
+	assert.Equal(t, []models.CompletionRatePoint{
+		{Period: "2025-11"},
+		{Period: "2025-12", Assigned: 4, Completed: 4, CompletionRate: 100},
+		{Period: "2026-01"},
+		{Period: "2026-02", Assigned: 2, Completed: 1, CompletionRate: 50},
+	}, history)
+}
+
+func TestUserService_NotificationPreferences(t *testing.T) {
+	mockRepo := NewMockUserRepository()
+	service := NewUserService(mockRepo)
+
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Name: "Test User"}
+	mockRepo.users[user.ID] = user
+
+	preferences, err := service.GetNotificationPreferences(user.ID)
+	assert.NoError(t, err)
+	assert.True(t, preferences.ActionItemReminders)
+
+	_, err = service.UpdateNotificationPreferences(user.ID, &models.NotificationPreferences{ActionItemReminders: false})
+	assert.NoError(t, err)
+
+	preferences, err = service.GetNotificationPreferences(user.ID)
+	assert.NoError(t, err)
+	assert.False(t, preferences.ActionItemReminders)
+
+	_, err = service.GetNotificationPreferences(uuid.New())
+	assert.EqualError(t, err, "user not found")
+}
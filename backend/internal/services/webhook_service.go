@@ -0,0 +1,500 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"educ-retro/internal/models"
+	"educ-retro/internal/notifications"
+	"educ-retro/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// webhookEvents are the retrospective events teams can subscribe to
+var webhookEvents = map[string]bool{
+	models.WebhookEventItemAdded:            true,
+	models.WebhookEventActionItemAdded:      true,
+	models.WebhookEventActionItemUpdated:    true,
+	models.WebhookEventRetrospectiveStarted: true,
+	models.WebhookEventRetrospectiveEnded:   true,
+}
+
+const (
+	webhookMaxAttempts = 6
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = time.Hour
+	// webhookLease is how long a claimed delivery is hidden from other runs
+	webhookLease     = 5 * time.Minute
+	webhookBatchSize = 50
+)
+
+// WebhookService manages the webhooks of teams and delivers the events of
+// their retrospectives. Deliveries are signed with HMAC-SHA256 and retried
+// with exponential backoff; every attempt is recorded in the delivery log.
+type WebhookService struct {
+	webhookRepo repositories.WebhookRepositoryInterface
+	retroRepo   repositories.RetrospectiveRepositoryInterface
+	httpClient  *http.Client
+	now         func() time.Time
+
+	wake     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	runMu    sync.Mutex
+}
+
+func NewWebhookService(webhookRepo repositories.WebhookRepositoryInterface, retroRepo repositories.RetrospectiveRepositoryInterface) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		retroRepo:   retroRepo,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+	}
+}
+
+// requireTeamOwner checks that the user owns the team
+func (s *WebhookService) requireTeamOwner(teamID, userID uuid.UUID) error {
+	role, err := s.retroRepo.GetTeamRole(teamID, userID)
+	if err == sql.ErrNoRows || (err == nil && role != "owner") {
+		return errors.New("access denied")
+	}
+	return err
+}
+
+// getTeamWebhook returns the webhook if it belongs to the team
+func (s *WebhookService) getTeamWebhook(teamID, webhookID uuid.UUID) (*models.TeamWebhook, error) {
+	webhook, err := s.webhookRepo.GetByID(webhookID)
+	if err == sql.ErrNoRows || (err == nil && webhook.TeamID != teamID) {
+		return nil, errors.New("webhook not found")
+	}
+	return webhook, err
+}
+
+func validateWebhook(rawURL string, events []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("invalid url")
+	}
+
+	if len(events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	for _, event := range events {
+		if !webhookEvents[event] {
+			return fmt.Errorf("invalid event: %s", event)
+		}
+	}
+
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// CreateWebhook subscribes a URL to events of the team's retrospectives. The
+// returned webhook carries the signing secret, which is not shown again.
+func (s *WebhookService) CreateWebhook(teamID, userID uuid.UUID, req *models.TeamWebhookCreateRequest) (*models.TeamWebhook, error) {
+	if err := s.requireTeamOwner(teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := validateWebhook(req.URL, req.Events); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := &models.TeamWebhook{
+		TeamID:    teamID,
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    req.Events,
+		Active:    req.Active == nil || *req.Active,
+		CreatedBy: &userID,
+	}
+
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookService) ListWebhooks(teamID, userID uuid.UUID) ([]models.TeamWebhook, error) {
+	if err := s.requireTeamOwner(teamID, userID); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.webhookRepo.ListByTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+func (s *WebhookService) UpdateWebhook(teamID, webhookID, userID uuid.UUID, req *models.TeamWebhookUpdateRequest) (*models.TeamWebhook, error) {
+	if err := s.requireTeamOwner(teamID, userID); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.getTeamWebhook(teamID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		webhook.Events = req.Events
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := validateWebhook(webhook.URL, webhook.Events); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.Update(webhook); err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+func (s *WebhookService) DeleteWebhook(teamID, webhookID, userID uuid.UUID) error {
+	if err := s.requireTeamOwner(teamID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.getTeamWebhook(teamID, webhookID); err != nil {
+		return err
+	}
+
+	return s.webhookRepo.Delete(webhookID)
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first
+func (s *WebhookService) ListDeliveries(teamID, webhookID, userID uuid.UUID, limit, offset int) (*models.WebhookDeliveryList, error) {
+	if limit < 1 || limit > 100 {
+		return nil, errors.New("limit must be between 1 and 100")
+	}
+	if offset < 0 {
+		return nil, errors.New("offset must not be negative")
+	}
+
+	if err := s.requireTeamOwner(teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getTeamWebhook(teamID, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := s.webhookRepo.ListDeliveries(webhookID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhookDeliveryList{
+		Deliveries: deliveries,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	}, nil
+}
+
+// GetChatWebhook returns the chat channel of the team
+func (s *WebhookService) GetChatWebhook(teamID, userID uuid.UUID) (*models.TeamChatWebhook, error) {
+	if err := s.requireTeamOwner(teamID, userID); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.webhookRepo.GetChatWebhook(teamID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("chat webhook not found")
+	}
+	return webhook, err
+}
+
+// SetChatWebhook sets the chat channel the milestones of the team's
+// retrospectives are posted to, replacing the previous one
+func (s *WebhookService) SetChatWebhook(teamID, userID uuid.UUID, req *models.TeamChatWebhookRequest) (*models.TeamChatWebhook, error) {
+	if err := s.requireTeamOwner(teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := notifications.NewChatNotifier(req.Provider, req.URL); err != nil {
+		return nil, errors.New("invalid provider. Must be one of: slack, teams, mattermost")
+	}
+	parsed, err := url.Parse(req.URL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, errors.New("invalid url")
+	}
+
+	webhook := &models.TeamChatWebhook{
+		TeamID:    teamID,
+		Provider:  req.Provider,
+		URL:       req.URL,
+		CreatedBy: &userID,
+	}
+	if err := s.webhookRepo.SetChatWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookService) DeleteChatWebhook(teamID, userID uuid.UUID) error {
+	if err := s.requireTeamOwner(teamID, userID); err != nil {
+		return err
+	}
+
+	err := s.webhookRepo.DeleteChatWebhook(teamID)
+	if err == sql.ErrNoRows {
+		return errors.New("chat webhook not found")
+	}
+	return err
+}
+
+// HandleEvent queues a delivery of the event for every webhook of the
+// retrospective's team subscribed to it. It is registered as a listener of
+// the realtime service, so it receives the events sent to browsers.
+func (s *WebhookService) HandleEvent(retrospectiveID uuid.UUID, eventType string, data interface{}) {
+	if !webhookEvents[eventType] {
+		return
+	}
+
+	if _, err := s.Dispatch(retrospectiveID, eventType, data); err != nil {
+		log.Printf("Failed to queue %s webhooks for retrospective %s: %v", eventType, retrospectiveID, err)
+	}
+}
+
+// Dispatch queues the event for the subscribed webhooks and returns how many
+// deliveries were queued
+func (s *WebhookService) Dispatch(retrospectiveID uuid.UUID, eventType string, data interface{}) (int, error) {
+	retrospective, err := s.retroRepo.GetByID(retrospectiveID)
+	if err != nil {
+		return 0, err
+	}
+	if retrospective.TeamID == uuid.Nil {
+		return 0, nil
+	}
+
+	webhooks, err := s.webhookRepo.GetActiveForEvent(retrospective.TeamID, eventType)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	queued := 0
+	for _, webhook := range webhooks {
+		deliveryID := uuid.New()
+		payload, err := json.Marshal(map[string]interface{}{
+			"id":               deliveryID,
+			"event":            eventType,
+			"team_id":          retrospective.TeamID,
+			"retrospective_id": retrospectiveID,
+			"occurred_at":      now.UTC(),
+			"data":             data,
+		})
+		if err != nil {
+			return queued, err
+		}
+
+		delivery := &models.WebhookDelivery{
+			ID:            deliveryID,
+			WebhookID:     webhook.ID,
+			Event:         eventType,
+			Payload:       payload,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		}
+		if err := s.webhookRepo.CreateDelivery(delivery); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		// Deliver right away instead of waiting for the next tick
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return queued, nil
+}
+
+// Start delivers the due deliveries every interval, and as soon as new ones
+// are queued, until Stop is called
+func (s *WebhookService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.RunOnce(); err != nil {
+				log.Printf("Failed to deliver webhooks: %v", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.wake:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *WebhookService) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// RunOnce attempts the due deliveries and returns how many succeeded
+func (s *WebhookService) RunOnce() (int, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	now := s.now()
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(now, now.Add(webhookLease), webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		s.attempt(delivery)
+
+		if err := s.webhookRepo.RecordDeliveryAttempt(&delivery.WebhookDelivery); err != nil {
+			return succeeded, err
+		}
+		if delivery.Status == models.WebhookDeliverySucceeded {
+			succeeded++
+		}
+	}
+
+	return succeeded, nil
+}
+
+// attempt sends the delivery once and updates its status, attempts, error and next attempt
+func (s *WebhookService) attempt(delivery *models.PendingWebhookDelivery) {
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	delivery.LastError = nil
+
+	statusCode, err := s.send(delivery)
+	if statusCode != 0 {
+		delivery.ResponseStatus = &statusCode
+	}
+
+	now := s.now()
+	if err == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	message := err.Error()
+	delivery.LastError = &message
+
+	if delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	next := now.Add(webhookBackoff(delivery.Attempts))
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = &next
+}
+
+// webhookBackoff is the wait after the given number of failed attempts: 30s,
+// 1m, 2m, 4m... up to an hour
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return backoff
+}
+
+func (s *WebhookService) send(delivery *models.PendingWebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "educ-retro-webhooks")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "timestamp.payload" with
+// the webhook secret, as sent in the X-Webhook-Signature header
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"educ-retro/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockWebhookRepository keeps webhooks and deliveries in memory
+type MockWebhookRepository struct {
+	webhooks     map[uuid.UUID]*models.TeamWebhook
+	deliveries   []*models.WebhookDelivery
+	chatWebhooks map[uuid.UUID]*models.TeamChatWebhook
+}
+
+func NewMockWebhookRepository() *MockWebhookRepository {
+	return &MockWebhookRepository{
+		webhooks:     make(map[uuid.UUID]*models.TeamWebhook),
+		chatWebhooks: make(map[uuid.UUID]*models.TeamChatWebhook),
+	}
+}
+
+func (m *MockWebhookRepository) Create(webhook *models.TeamWebhook) error {
+	webhook.ID = uuid.New()
+	webhookCopy := *webhook
+	m.webhooks[webhook.ID] = &webhookCopy
+	return nil
+}
+
+func (m *MockWebhookRepository) GetByID(id uuid.UUID) (*models.TeamWebhook, error) {
+	webhook, exists := m.webhooks[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	webhookCopy := *webhook
+	return &webhookCopy, nil
+}
+
+func (m *MockWebhookRepository) ListByTeam(teamID uuid.UUID) ([]models.TeamWebhook, error) {
+	webhooks := []models.TeamWebhook{}
+	for _, webhook := range m.webhooks {
+		if webhook.TeamID == teamID {
+			webhooks = append(webhooks, *webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *MockWebhookRepository) Update(webhook *models.TeamWebhook) error {
+	if _, exists := m.webhooks[webhook.ID]; !exists {
+		return sql.ErrNoRows
+	}
+	webhookCopy := *webhook
+	webhookCopy.Secret = m.webhooks[webhook.ID].Secret
+	m.webhooks[webhook.ID] = &webhookCopy
+	return nil
+}
+
+func (m *MockWebhookRepository) Delete(id uuid.UUID) error {
+	if _, exists := m.webhooks[id]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *MockWebhookRepository) GetActiveForEvent(teamID uuid.UUID, event string) ([]models.TeamWebhook, error) {
+	webhooks := []models.TeamWebhook{}
+	for _, webhook := range m.webhooks {
+		if webhook.TeamID != teamID || !webhook.Active {
+			continue
+		}
+		for _, subscribed := range webhook.Events {
+			if subscribed == event {
+				webhooks = append(webhooks, *webhook)
+				break
+			}
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *MockWebhookRepository) GetChatWebhook(teamID uuid.UUID) (*models.TeamChatWebhook, error) {
+	webhook, exists := m.chatWebhooks[teamID]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	webhookCopy := *webhook
+	return &webhookCopy, nil
+}
+
+func (m *MockWebhookRepository) SetChatWebhook(webhook *models.TeamChatWebhook) error {
+	webhookCopy := *webhook
+	m.chatWebhooks[webhook.TeamID] = &webhookCopy
+	return nil
+}
+
+func (m *MockWebhookRepository) DeleteChatWebhook(teamID uuid.UUID) error {
+	if _, exists := m.chatWebhooks[teamID]; !exists {
+		return sql.ErrNoRows
+	}
+	delete(m.chatWebhooks, teamID)
+	return nil
+}
+
+func (m *MockWebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	deliveryCopy := *delivery
+	m.deliveries = append(m.deliveries, &deliveryCopy)
+	return nil
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(now, leaseUntil time.Time, limit int) ([]models.PendingWebhookDelivery, error) {
+	claimed := []models.PendingWebhookDelivery{}
+	for _, delivery := range m.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		if delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		lease := leaseUntil
+		delivery.NextAttemptAt = &lease
+		webhook := m.webhooks[delivery.WebhookID]
+		claimed = append(claimed, models.PendingWebhookDelivery{WebhookDelivery: *delivery, URL: webhook.URL, Secret: webhook.Secret})
+	}
+	return claimed, nil
+}
+
+func (m *MockWebhookRepository) RecordDeliveryAttempt(delivery *models.WebhookDelivery) error {
+	for i, existing := range m.deliveries {
+		if existing.ID == delivery.ID {
+			deliveryCopy := *delivery
+			m.deliveries[i] = &deliveryCopy
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (m *MockWebhookRepository) ListDeliveries(webhookID uuid.UUID, limit, offset int) ([]models.WebhookDelivery, int, error) {
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	total := len(deliveries)
+	if offset > total {
+		offset = total
+	}
+	deliveries = deliveries[offset:]
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, total, nil
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewWebhookService(NewMockWebhookRepository(), mockRetroRepo)
+
+	ownerID := uuid.New()
+	teamID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, ownerID}] = "owner"
+
+	webhook, err := service.CreateWebhook(teamID, ownerID, &models.TeamWebhookCreateRequest{
+		URL:    "https://example.com/hooks/retro",
+		Events: []string{models.WebhookEventItemAdded},
+	})
+	require.NoError(t, err)
+	assert.True(t, webhook.Active)
+	assert.Len(t, webhook.Secret, 64)
+
+	webhooks, err := service.ListWebhooks(teamID, ownerID)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Empty(t, webhooks[0].Secret)
+
+	_, err = service.CreateWebhook(teamID, ownerID, &models.TeamWebhookCreateRequest{
+		URL:    "ftp://example.com",
+		Events: []string{models.WebhookEventItemAdded},
+	})
+	assert.EqualError(t, err, "invalid url")
+
+	_, err = service.CreateWebhook(teamID, ownerID, &models.TeamWebhookCreateRequest{
+		URL:    "https://example.com",
+		Events: []string{"vote_added"},
+	})
+	assert.EqualError(t, err, "invalid event: vote_added")
+
+	// Only the owner manages the team's webhooks
+	memberID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, memberID}] = "member"
+	_, err = service.ListWebhooks(teamID, memberID)
+	assert.EqualError(t, err, "access denied")
+	_, err = service.ListWebhooks(teamID, uuid.New())
+	assert.EqualError(t, err, "access denied")
+}
+
+func TestWebhookService_UpdateWebhook_OtherTeam(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	service := NewWebhookService(NewMockWebhookRepository(), mockRetroRepo)
+
+	ownerID := uuid.New()
+	teamID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, ownerID}] = "owner"
+
+	webhook, err := service.CreateWebhook(teamID, ownerID, &models.TeamWebhookCreateRequest{
+		URL:    "https://example.com",
+		Events: []string{models.WebhookEventItemAdded},
+	})
+	require.NoError(t, err)
+
+	otherTeamID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{otherTeamID, ownerID}] = "owner"
+	active := false
+	_, err = service.UpdateWebhook(otherTeamID, webhook.ID, ownerID, &models.TeamWebhookUpdateRequest{Active: &active})
+	assert.EqualError(t, err, "webhook not found")
+
+	updated, err := service.UpdateWebhook(teamID, webhook.ID, ownerID, &models.TeamWebhookUpdateRequest{Active: &active})
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+}
+
+func TestWebhookService_DeliversSignedPayload(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockWebhookRepo := NewMockWebhookRepository()
+	service := NewWebhookService(mockWebhookRepo, mockRetroRepo)
+
+	ownerID := uuid.New()
+	teamID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, ownerID}] = "owner"
+	retrospectiveID := uuid.New()
+	mockRetroRepo.retrospectives[retrospectiveID] = &models.Retrospective{ID: retrospectiveID, Title: "Sprint 1", TeamID: teamID, CreatedBy: ownerID}
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	var mu sync.Mutex
+	var received []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := service.CreateWebhook(teamID, ownerID, &models.TeamWebhookCreateRequest{
+		URL:    server.URL,
+		Events: []string{models.WebhookEventItemAdded},
+		Secret: "s3cret",
+	})
+	require.NoError(t, err)
+
+	// Events the webhook is not subscribed to are not delivered
+	service.HandleEvent(retrospectiveID, models.WebhookEventRetrospectiveEnded, map[string]interface{}{})
+	service.HandleEvent(retrospectiveID, "vote_added", map[string]interface{}{})
+	service.HandleEvent(retrospectiveID, models.WebhookEventItemAdded, map[string]interface{}{"content": "More pairing"})
+	require.Len(t, mockWebhookRepo.deliveries, 1)
+
+	delivered, err := service.RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+
+	require.Len(t, received, 1)
+	req, body := received[0], bodies[0]
+	timestamp := req.Header.Get("X-Webhook-Timestamp")
+	assert.Equal(t, "1715355000", timestamp)
+	assert.Equal(t, models.WebhookEventItemAdded, req.Header.Get("X-Webhook-Event"))
+	assert.Equal(t, mockWebhookRepo.deliveries[0].ID.String(), req.Header.Get("X-Webhook-Delivery"))
+	assert.Equal(t, "sha256="+SignWebhookPayload("s3cret", timestamp, body), req.Header.Get("X-Webhook-Signature"))
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, retrospectiveID.String(), payload["retrospective_id"])
+	assert.Equal(t, teamID.String(), payload["team_id"])
+	assert.Equal(t, "More pairing", payload["data"].(map[string]interface{})["content"])
+
+	delivery := mockWebhookRepo.deliveries[0]
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, *delivery.ResponseStatus)
+}
+
+func TestWebhookService_RetriesWithBackoff(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockWebhookRepo := NewMockWebhookRepository()
+	service := NewWebhookService(mockWebhookRepo, mockRetroRepo)
+
+	ownerID := uuid.New()
+	teamID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, ownerID}] = "owner"
+	retrospectiveID := uuid.New()
+	mockRetroRepo.retrospectives[retrospectiveID] = &models.Retrospective{ID: retrospectiveID, Title: "Sprint 1", TeamID: teamID, CreatedBy: ownerID}
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := service.CreateWebhook(teamID, ownerID, &models.TeamWebhookCreateRequest{
+		URL:    server.URL,
+		Events: []string{models.WebhookEventRetrospectiveEnded},
+	})
+	require.NoError(t, err)
+
+	_, err = service.Dispatch(retrospectiveID, models.WebhookEventRetrospectiveEnded, map[string]interface{}{})
+	require.NoError(t, err)
+
+	backoffs := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute}
+	for attempt, backoff := range backoffs {
+		_, err := service.RunOnce()
+		require.NoError(t, err)
+
+		delivery := mockWebhookRepo.deliveries[0]
+		assert.Equal(t, attempt+1, delivery.Attempts)
+		assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, "endpoint responded with status 503", *delivery.LastError)
+		assert.Equal(t, now.Add(backoff), *delivery.NextAttemptAt)
+
+		// Nothing is attempted before the backoff elapses
+		_, err = service.RunOnce()
+		require.NoError(t, err)
+		assert.Equal(t, attempt+1, calls)
+
+		now = now.Add(backoff)
+	}
+
+	_, err = service.RunOnce()
+	require.NoError(t, err)
+
+	delivery := mockWebhookRepo.deliveries[0]
+	assert.Equal(t, webhookMaxAttempts, delivery.Attempts)
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.Equal(t, webhookMaxAttempts, calls)
+
+	deliveries, err := service.ListDeliveries(teamID, delivery.WebhookID, ownerID, 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deliveries.Total)
+}
+
+func TestWebhookService_IgnoresRetrospectivesWithoutTeam(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockWebhookRepo := NewMockWebhookRepository()
+	service := NewWebhookService(mockWebhookRepo, mockRetroRepo)
+
+	retrospectiveID := uuid.New()
+	mockRetroRepo.retrospectives[retrospectiveID] = &models.Retrospective{ID: retrospectiveID, CreatedBy: uuid.New()}
+
+	queued, err := service.Dispatch(retrospectiveID, models.WebhookEventItemAdded, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, queued)
+	assert.Empty(t, mockWebhookRepo.deliveries)
+}
+
+func TestWebhookService_OneDeliveryForCarriedActionItems(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockWebhookRepo := NewMockWebhookRepository()
+	service := NewWebhookService(mockWebhookRepo, mockRetroRepo)
+	realtimeService := NewRealtimeService()
+	realtimeService.AddListener(service.HandleEvent)
+
+	teamID := uuid.New()
+	retrospectiveID := uuid.New()
+	mockRetroRepo.retrospectives[retrospectiveID] = &models.Retrospective{ID: retrospectiveID, TeamID: teamID, CreatedBy: uuid.New()}
+	mockWebhookRepo.webhooks[uuid.New()] = &models.TeamWebhook{TeamID: teamID, URL: "https://example.com/hook", Events: []string{models.WebhookEventActionItemUpdated}, Active: true}
+
+	// Shown in two later retrospectives, the update is still one event
+	carriedInto := []uuid.UUID{uuid.New(), uuid.New()}
+	realtimeService.BroadcastToRetrospectives(retrospectiveID, carriedInto, models.WebhookEventActionItemUpdated, map[string]interface{}{})
+
+	assert.Len(t, mockWebhookRepo.deliveries, 1)
+}
+
+func TestWebhookService_SetChatWebhook(t *testing.T) {
+	mockRetroRepo := NewMockRetrospectiveRepository()
+	mockWebhookRepo := NewMockWebhookRepository()
+	service := NewWebhookService(mockWebhookRepo, mockRetroRepo)
+
+	ownerID := uuid.New()
+	memberID := uuid.New()
+	teamID := uuid.New()
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, ownerID}] = "owner"
+	mockRetroRepo.teamRoles[[2]uuid.UUID{teamID, memberID}] = "member"
+
+	req := &models.TeamChatWebhookRequest{Provider: "slack", URL: "https://hooks.slack.test/T000/B000"}
+	_, err := service.SetChatWebhook(teamID, memberID, req)
+	assert.EqualError(t, err, "access denied")
+
+	webhook, err := service.SetChatWebhook(teamID, ownerID, req)
+	require.NoError(t, err)
+	assert.Equal(t, "slack", mockWebhookRepo.chatWebhooks[teamID].Provider)
+	assert.Equal(t, &ownerID, webhook.CreatedBy)
+
+	_, err = service.SetChatWebhook(teamID, ownerID, &models.TeamChatWebhookRequest{Provider: "discord", URL: req.URL})
+	assert.EqualError(t, err, "invalid provider. Must be one of: slack, teams, mattermost")
+	_, err = service.SetChatWebhook(teamID, ownerID, &models.TeamChatWebhookRequest{Provider: "teams", URL: "http://teams.test/webhook"})
+	assert.EqualError(t, err, "invalid url")
+
+	require.NoError(t, service.DeleteChatWebhook(teamID, ownerID))
+	_, err = service.GetChatWebhook(teamID, ownerID)
+	assert.EqualError(t, err, "chat webhook not found")
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookBackoff(1))
+	assert.Equal(t, 4*time.Minute, webhookBackoff(4))
+	assert.Equal(t, time.Hour, webhookBackoff(10))
+	assert.Equal(t, time.Hour, webhookBackoff(100))
+}
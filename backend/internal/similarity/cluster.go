@@ -0,0 +1,214 @@
+package similarity
+
+import (
+	"math"
+	"sort"
+)
+
+// Cluster is a set of similar texts, by their indexes in the texts given to
+// Clusters
+type Cluster struct {
+	Members []int
+	// Terms are the words the texts share, the most relevant first
+	Terms []string
+	// Similarity is the average similarity of the pairs of texts, from 0 to 1
+	Similarity float64
+}
+
+// maxTerms is the number of terms of a cluster
+const maxTerms = 3
+
+// Clusters groups the texts that are similar: two groups are joined, most
+// similar first, while the average similarity of their texts is at least the
+// threshold. Only the clusters of two or more texts are returned, the largest
+// and then the most similar first.
+func Clusters(texts []string, threshold float64) []Cluster {
+	documents := make([][]token, len(texts))
+	for i, text := range texts {
+		documents[i] = tokenize(text)
+	}
+	vectors := tfidf(documents)
+
+	similarities := make([][]float64, len(texts))
+	for i := range similarities {
+		similarities[i] = make([]float64, len(texts))
+		for j := 0; j < i; j++ {
+			similarities[i][j] = cosine(vectors[i], vectors[j])
+			similarities[j][i] = similarities[i][j]
+		}
+	}
+
+	// Average linkage agglomerative clustering
+	groups := make([][]int, len(texts))
+	for i := range groups {
+		groups[i] = []int{i}
+	}
+	for {
+		bestI, bestJ, best := -1, -1, threshold
+		for i := range groups {
+			for j := i + 1; j < len(groups); j++ {
+				if linkage := averageSimilarity(similarities, groups[i], groups[j]); linkage >= best && linkage > 0 {
+					bestI, bestJ, best = i, j, linkage
+				}
+			}
+		}
+		if bestI < 0 {
+			break
+		}
+		groups[bestI] = append(groups[bestI], groups[bestJ]...)
+		groups = append(groups[:bestJ], groups[bestJ+1:]...)
+	}
+
+	clusters := []Cluster{}
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Ints(members)
+		clusters = append(clusters, Cluster{
+			Members:    members,
+			Terms:      sharedTerms(documents, vectors, members),
+			Similarity: cohesion(similarities, members),
+		})
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if len(clusters[i].Members) != len(clusters[j].Members) {
+			return len(clusters[i].Members) > len(clusters[j].Members)
+		}
+		return clusters[i].Similarity > clusters[j].Similarity
+	})
+
+	return clusters
+}
+
+// tfidf returns the TF-IDF vectors of the documents by stem, normalized to
+// unit length. Stems found in few documents weigh more.
+func tfidf(documents [][]token) []map[string]float64 {
+	documentFrequency := map[string]int{}
+	for _, document := range documents {
+		seen := map[string]bool{}
+		for _, t := range document {
+			if !seen[t.stem] {
+				seen[t.stem] = true
+				documentFrequency[t.stem]++
+			}
+		}
+	}
+
+	vectors := make([]map[string]float64, len(documents))
+	for i, document := range documents {
+		vector := map[string]float64{}
+		for _, t := range document {
+			vector[t.stem]++
+		}
+
+		var norm float64
+		for stem, frequency := range vector {
+			idf := math.Log(float64(1+len(documents))/float64(1+documentFrequency[stem])) + 1
+			vector[stem] = frequency * idf
+			norm += vector[stem] * vector[stem]
+		}
+		norm = math.Sqrt(norm)
+		for stem := range vector {
+			vector[stem] /= norm
+		}
+		vectors[i] = vector
+	}
+
+	return vectors
+}
+
+// cosine returns the cosine of two unit vectors
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var dot float64
+	for stem, weight := range a {
+		dot += weight * b[stem]
+	}
+	return dot
+}
+
+func averageSimilarity(similarities [][]float64, a, b []int) float64 {
+	var sum float64
+	for _, i := range a {
+		for _, j := range b {
+			sum += similarities[i][j]
+		}
+	}
+	return sum / float64(len(a)*len(b))
+}
+
+// cohesion returns the average similarity of the pairs of members
+func cohesion(similarities [][]float64, members []int) float64 {
+	var sum float64
+	pairs := 0
+	for i, a := range members {
+		for _, b := range members[i+1:] {
+			sum += similarities[a][b]
+			pairs++
+		}
+	}
+	return sum / float64(pairs)
+}
+
+// sharedTerms returns the words of the stems found in two or more members,
+// by their weight in the members. Each stem is named by its most frequent
+// word.
+func sharedTerms(documents [][]token, vectors []map[string]float64, members []int) []string {
+	weights := map[string]float64{}
+	counts := map[string]int{}
+	words := map[string]map[string]int{}
+	for _, member := range members {
+		for stem, weight := range vectors[member] {
+			weights[stem] += weight
+			counts[stem]++
+		}
+		for _, t := range documents[member] {
+			if words[t.stem] == nil {
+				words[t.stem] = map[string]int{}
+			}
+			words[t.stem][t.word]++
+		}
+	}
+
+	stems := []string{}
+	for stem, count := range counts {
+		if count >= 2 {
+			stems = append(stems, stem)
+		}
+	}
+	sort.Slice(stems, func(i, j int) bool {
+		if weights[stems[i]] != weights[stems[j]] {
+			return weights[stems[i]] > weights[stems[j]]
+		}
+		return stems[i] < stems[j]
+	})
+	if len(stems) > maxTerms {
+		stems = stems[:maxTerms]
+	}
+
+	terms := make([]string, len(stems))
+	for i, stem := range stems {
+		terms[i] = mostFrequent(words[stem])
+	}
+	return terms
+}
+
+// mostFrequent returns the most frequent word, the shortest and then the
+// first in alphabetical order on ties
+func mostFrequent(words map[string]int) string {
+	best := ""
+	for word, count := range words {
+		switch {
+		case best == "",
+			count > words[best],
+			count == words[best] && len(word) < len(best),
+			count == words[best] && len(word) == len(best) && word < best:
+			best = word
+		}
+	}
+	return best
+}
@@ -0,0 +1,42 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusters(t *testing.T) {
+	texts := []string{
+		"Deploy manual demora muito",
+		"Boa comunicação no time",
+		"Automatizar o deploy",
+		"Os deploys manuais quebram",
+		"Comunicação com o cliente foi boa",
+		"Café da manhã",
+	}
+
+	clusters := Clusters(texts, 0.2)
+
+	require.Len(t, clusters, 2)
+	assert.Equal(t, []int{0, 2, 3}, clusters[0].Members)
+	assert.Equal(t, "deploy", clusters[0].Terms[0])
+	assert.Contains(t, clusters[0].Terms, "manual")
+	assert.Equal(t, []int{1, 4}, clusters[1].Members)
+	assert.ElementsMatch(t, []string{"boa", "comunicação"}, clusters[1].Terms)
+	for _, cluster := range clusters {
+		assert.Greater(t, cluster.Similarity, 0.2)
+		assert.LessOrEqual(t, cluster.Similarity, 1.0)
+	}
+}
+
+func TestClusters_Threshold(t *testing.T) {
+	texts := []string{"Deploy manual", "Deploy automatizado", "Pipeline lento"}
+
+	assert.Len(t, Clusters(texts, 0.2), 1)
+	assert.Empty(t, Clusters(texts, 0.9))
+	assert.Empty(t, Clusters(nil, 0.2))
+	// Texts without words are similar to none
+	assert.Empty(t, Clusters([]string{"!!", "e o", "42"}, 0))
+}
@@ -0,0 +1,135 @@
+// Package similarity clusters short texts, such as the items of a
+// retrospective, by the words they share. Texts in Portuguese and English are
+// tokenized and stemmed, and compared by the cosine of their TF-IDF vectors,
+// without any external service.
+package similarity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token is a word of a text and its stem
+type token struct {
+	word string
+	stem string
+}
+
+// accents maps the accented letters of Portuguese to the plain ones
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// stopWords are the Portuguese and English words that say nothing about the
+// subject of a text, without accents
+var stopWords = toSet(`
+	a o e as os um uma uns umas de da do das dos em na no nas nos num numa
+	ao aos pra pro para por pelo pela pelos pelas com sem sob sobre entre ate
+	que se mas ou nem como quando onde porque pois ja nao sim so muito muita
+	muitos muitas mais menos pouco pouca bem mal tao tambem ainda sempre nunca
+	eu tu ele ela nos vos eles elas voce voces me te lhe seu sua seus suas
+	meu minha nosso nossa nossos nossas isso isto esse essa esses essas este
+	esta estes estas aquele aquela aqui ali la foi era sao ser ter tem tinha
+	tiveram teve estar estava estao fica ficou vai vamos fazer feito cada todo
+	toda todos todas outro outra outros outras qual quais algum alguma
+	the an and or of to in on at by for from with without about into over
+	is are was were be been being am it its this that these those there here
+	we our us you your they their them he she his her i my me not no nor so
+	but if then than too very more most less can could should would will
+	shall may might must do does did done have has had get got just also
+	all any some each every other such only own same what which who whom
+	when where why how again once
+`)
+
+func toSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// tokenize splits the text into its words, lowercased, leaving out the stop
+// words, the single letters and the numbers
+func tokenize(text string) []token {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := []token{}
+	for _, word := range fields {
+		folded := accents.Replace(word)
+		if len([]rune(folded)) < 2 || stopWords[folded] || isNumber(folded) {
+			continue
+		}
+		tokens = append(tokens, token{word: word, stem: stem(folded)})
+	}
+
+	return tokens
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// minStemLength is the shortest stem a suffix is removed down to
+const minStemLength = 3
+
+type suffixRule struct {
+	suffix      string
+	replacement string
+}
+
+// pluralRules turn plurals into singulars
+var pluralRules = []suffixRule{
+	{"oes", "ao"}, {"aes", "ao"}, {"ais", "al"}, {"eis", "el"}, {"ies", "y"},
+	{"es", ""}, {"s", ""},
+}
+
+// derivationRules remove the suffixes of adverbs, nouns and verbs, longest
+// first
+var derivationRules = []suffixRule{
+	{"amento", ""}, {"imento", ""}, {"mente", ""}, {"ation", ""},
+	{"acao", ""}, {"icao", ""}, {"ando", ""}, {"endo", ""}, {"indo", ""},
+	{"ment", ""}, {"ness", ""}, {"ing", ""},
+	{"ada", ""}, {"ado", ""}, {"ida", ""}, {"ido", ""},
+	{"ed", ""}, {"ar", ""}, {"er", ""}, {"ir", ""},
+}
+
+// stem reduces a word without accents to a stem shared by its inflections in
+// Portuguese and English, like "deploys", "deployed" and "deploy", or
+// "comunicação" and "comunicar". It is a light stemmer: a plural rule, a
+// derivation rule and a final vowel are removed, each at most once.
+func stem(word string) string {
+	if !strings.HasSuffix(word, "ss") {
+		word = applyRule(word, pluralRules)
+	}
+	word = applyRule(word, derivationRules)
+	for _, vowel := range []string{"a", "e", "o"} {
+		if strings.HasSuffix(word, vowel) && len(word)-1 >= minStemLength {
+			return word[:len(word)-1]
+		}
+	}
+	return word
+}
+
+// applyRule applies the first rule with the suffix of the word that leaves a
+// long enough stem
+func applyRule(word string, rules []suffixRule) string {
+	for _, rule := range rules {
+		if strings.HasSuffix(word, rule.suffix) && len(word)-len(rule.suffix) >= minStemLength {
+			return word[:len(word)-len(rule.suffix)] + rule.replacement
+		}
+	}
+	return word
+}
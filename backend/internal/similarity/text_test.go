@@ -0,0 +1,41 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		words []string
+		stem  string
+	}{
+		{[]string{"deploy", "deploys", "deployed", "deploying", "deployment"}, "deploy"},
+		{[]string{"teste", "testes", "testar", "testing", "tests"}, "test"},
+		{[]string{"comunicacao", "comunicar"}, "comunic"},
+		{[]string{"reuniao", "reunioes"}, "reunia"},
+		{[]string{"manual", "manuais"}, "manual"},
+		{[]string{"lento", "lenta", "lentos"}, "lent"},
+		{[]string{"process", "processes"}, "process"},
+	}
+
+	for _, tt := range tests {
+		for _, word := range tt.words {
+			assert.Equal(t, tt.stem, stem(word), word)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens := tokenize("O deploy é MANUAL e demora 2 horas; the builds are slow!")
+
+	words := []string{}
+	stems := []string{}
+	for _, token := range tokens {
+		words = append(words, token.word)
+		stems = append(stems, token.stem)
+	}
+	assert.Equal(t, []string{"deploy", "manual", "demora", "horas", "builds", "slow"}, words)
+	assert.Equal(t, []string{"deploy", "manual", "demor", "hor", "build", "slow"}, stems)
+}
@@ -0,0 +1,11 @@
+-- External identities (OpenID Connect) linked to local users
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(255) NOT NULL, -- Issuer URL of the identity provider
+    subject VARCHAR(255) NOT NULL, -- "sub" claim issued by the provider
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
@@ -0,0 +1,18 @@
+-- Rows orphaned by deleted users cannot satisfy NOT NULL again, remove them first
+DELETE FROM action_items WHERE created_by IS NULL;
+ALTER TABLE action_items DROP CONSTRAINT IF EXISTS action_items_created_by_fkey;
+ALTER TABLE action_items ADD CONSTRAINT action_items_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE action_items ALTER COLUMN created_by SET NOT NULL;
+
+DELETE FROM retrospective_groups WHERE created_by IS NULL;
+ALTER TABLE retrospective_groups DROP CONSTRAINT IF EXISTS retrospective_groups_created_by_fkey;
+ALTER TABLE retrospective_groups ADD CONSTRAINT retrospective_groups_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE retrospective_groups ALTER COLUMN created_by SET NOT NULL;
+
+DELETE FROM retrospectives WHERE created_by IS NULL;
+ALTER TABLE retrospectives DROP CONSTRAINT IF EXISTS retrospectives_created_by_fkey;
+ALTER TABLE retrospectives ADD CONSTRAINT retrospectives_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE retrospectives ALTER COLUMN created_by SET NOT NULL;
@@ -0,0 +1,16 @@
+-- Deleting a user must not delete the retrospectives, groups and action items
+-- they created for the whole team. Keep the rows and clear the reference instead.
+ALTER TABLE retrospectives ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE retrospectives DROP CONSTRAINT IF EXISTS retrospectives_created_by_fkey;
+ALTER TABLE retrospectives ADD CONSTRAINT retrospectives_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE retrospective_groups ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE retrospective_groups DROP CONSTRAINT IF EXISTS retrospective_groups_created_by_fkey;
+ALTER TABLE retrospective_groups ADD CONSTRAINT retrospective_groups_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE action_items ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE action_items DROP CONSTRAINT IF EXISTS action_items_created_by_fkey;
+ALTER TABLE action_items ADD CONSTRAINT action_items_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+-- System-wide roles and account suspension
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('admin', 'user')),
+    ADD COLUMN suspended_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_role ON users(role);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_action_item_carryovers_action_item_id;
+DROP TABLE IF EXISTS action_item_carryovers;
@@ -0,0 +1,12 @@
+-- Unfinished action items from previous retrospectives reviewed in a new one.
+-- The action item stays in its original retrospective; this only links it.
+CREATE TABLE action_item_carryovers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    retrospective_id UUID NOT NULL REFERENCES retrospectives(id) ON DELETE CASCADE,
+    action_item_id UUID NOT NULL REFERENCES action_items(id) ON DELETE CASCADE,
+    carry_forward BOOLEAN NOT NULL DEFAULT TRUE, -- carry it again to the next retrospective if still unfinished
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(retrospective_id, action_item_id)
+);
+
+CREATE INDEX idx_action_item_carryovers_action_item_id ON action_item_carryovers(action_item_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_action_item_reminders_user_id;
+DROP TABLE IF EXISTS action_item_reminders;
+DROP TABLE IF EXISTS user_notification_preferences;
@@ -0,0 +1,22 @@
+-- Per-user notification settings; users without a row get the defaults
+CREATE TABLE user_notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    action_item_reminders BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Due date reminders sent to assignees. A reminder is sent once per kind and
+-- due date, so moving the due date arms the reminders again.
+CREATE TABLE action_item_reminders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    action_item_id UUID NOT NULL REFERENCES action_items(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('due_soon', 'overdue')),
+    due_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    channel VARCHAR(50) NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE, -- null while the delivery is in progress
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(action_item_id, user_id, kind, due_date)
+);
+
+CREATE INDEX idx_action_item_reminders_user_id ON action_item_reminders(user_id);
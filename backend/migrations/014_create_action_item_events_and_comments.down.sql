@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_action_item_comments_action_item_id;
+DROP TABLE IF EXISTS action_item_comments;
+DROP INDEX IF EXISTS idx_action_item_events_action_item_id;
+DROP TABLE IF EXISTS action_item_events;
@@ -0,0 +1,27 @@
+-- Change history of action items: who changed the status, assignee or due
+-- date, from what to what and, optionally, why
+CREATE TABLE action_item_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    action_item_id UUID NOT NULL REFERENCES action_items(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(30) NOT NULL CHECK (event_type IN ('created', 'status_changed', 'reassigned', 'due_date_changed')),
+    old_value TEXT,
+    new_value TEXT,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_action_item_events_action_item_id ON action_item_events(action_item_id, created_at);
+
+-- Threaded comments on action items; replies point to their parent comment
+CREATE TABLE action_item_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    action_item_id UUID NOT NULL REFERENCES action_items(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES action_item_comments(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_action_item_comments_action_item_id ON action_item_comments(action_item_id, created_at);
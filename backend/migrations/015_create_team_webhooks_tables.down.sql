@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_pending;
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook_id;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP INDEX IF EXISTS idx_team_webhooks_team_id;
+DROP TABLE IF EXISTS team_webhooks;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_action_items_external;
+
+ALTER TABLE action_items
+    DROP COLUMN IF EXISTS external_synced_at,
+    DROP COLUMN IF EXISTS external_url,
+    DROP COLUMN IF EXISTS external_id,
+    DROP COLUMN IF EXISTS external_provider;
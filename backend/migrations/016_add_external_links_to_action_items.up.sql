@@ -0,0 +1,8 @@
+-- Link of an action item exported to an issue tracker (GitHub Issues, Jira)
+ALTER TABLE action_items
+    ADD COLUMN external_provider VARCHAR(20),
+    ADD COLUMN external_id VARCHAR(255),
+    ADD COLUMN external_url TEXT,
+    ADD COLUMN external_synced_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX idx_action_items_external ON action_items(external_provider, external_id) WHERE external_id IS NOT NULL;
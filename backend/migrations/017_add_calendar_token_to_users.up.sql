@@ -0,0 +1,5 @@
+-- Secret token of the user's iCalendar feed; calendar apps cannot send the
+-- bearer token, so the feed URL carries this one
+ALTER TABLE users ADD COLUMN calendar_token VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_users_calendar_token ON users(calendar_token) WHERE calendar_token IS NOT NULL;
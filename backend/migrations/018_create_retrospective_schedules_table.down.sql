@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_retrospectives_scheduled_planned;
+DROP TABLE IF EXISTS retrospective_schedules;
@@ -0,0 +1,21 @@
+-- Recurring retrospectives of a team. The scheduler creates a planned
+-- retrospective for next_run_at ahead of time and moves next_run_at forward
+-- by interval_weeks; planned retrospectives start on their own at scheduled_at.
+CREATE TABLE retrospective_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    template VARCHAR(50) NOT NULL CHECK (template IN ('start_stop_continue', '4ls', 'mad_sad_glad', 'sailboat', 'went_well_to_improve', 'custom')),
+    interval_weeks INTEGER NOT NULL CHECK (interval_weeks BETWEEN 1 AND 12),
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_retrospective_schedules_team_id ON retrospective_schedules(team_id);
+CREATE INDEX idx_retrospective_schedules_next_run ON retrospective_schedules(next_run_at) WHERE active;
+
+CREATE INDEX idx_retrospectives_scheduled_planned ON retrospectives(scheduled_at) WHERE status = 'planned';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS retrospective_share_links;
+
+ALTER TABLE retrospectives DROP COLUMN IF EXISTS export_policy;
@@ -0,0 +1,18 @@
+-- Who can export the retrospective and create public links to its summary:
+-- the creator, the facilitators (the creator and the owners of its team),
+-- its participants too, or every member of its team too
+ALTER TABLE retrospectives ADD COLUMN export_policy VARCHAR(20) NOT NULL DEFAULT 'creator'
+    CHECK (export_policy IN ('creator', 'facilitators', 'participants', 'team'));
+
+-- Public read-only links to the summary of a retrospective; anyone with the
+-- token can read it until the link expires or is revoked
+CREATE TABLE retrospective_share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    retrospective_id UUID NOT NULL REFERENCES retrospectives(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_retrospective_share_links_retrospective_id ON retrospective_share_links(retrospective_id);
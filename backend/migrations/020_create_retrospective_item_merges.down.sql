@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS retrospective_item_merges;
+
+-- Merged items were deleted before merges could be undone
+DELETE FROM retrospective_items WHERE merged_into_id IS NOT NULL;
+ALTER TABLE retrospective_items DROP COLUMN IF EXISTS merged_into_id;
@@ -0,0 +1,23 @@
+-- Items merged into another item are kept, hidden, so that the merge can be
+-- undone; they are deleted along with the item they were merged into
+ALTER TABLE retrospective_items ADD COLUMN merged_into_id UUID REFERENCES retrospective_items(id) ON DELETE CASCADE;
+
+-- The merges that can be undone, with the content and votes of the target
+-- item before the merge
+CREATE TABLE retrospective_item_merges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    retrospective_id UUID NOT NULL REFERENCES retrospectives(id) ON DELETE CASCADE,
+    target_item_id UUID NOT NULL REFERENCES retrospective_items(id) ON DELETE CASCADE,
+    source_item_id UUID NOT NULL UNIQUE REFERENCES retrospective_items(id) ON DELETE CASCADE,
+    vote_mode VARCHAR(10) NOT NULL CHECK (vote_mode IN ('sum', 'max', 'reset')),
+    target_content TEXT NOT NULL,
+    target_votes INTEGER NOT NULL,
+    -- votes of the target right after the merge, so that votes cast later are kept on undo
+    merged_votes INTEGER NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_retrospective_items_merged_into_id ON retrospective_items(merged_into_id);
+CREATE INDEX idx_retrospective_item_merges_retrospective_id ON retrospective_item_merges(retrospective_id);
+CREATE INDEX idx_retrospective_item_merges_target_item_id ON retrospective_item_merges(target_item_id);
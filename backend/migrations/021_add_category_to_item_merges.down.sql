@@ -0,0 +1,2 @@
+ALTER TABLE retrospective_item_merges DROP COLUMN IF EXISTS merged_category;
+ALTER TABLE retrospective_item_merges DROP COLUMN IF EXISTS target_category;
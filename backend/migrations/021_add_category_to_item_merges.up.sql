@@ -0,0 +1,13 @@
+-- Items of different categories can be merged into a category of choice. The
+-- merge keeps the category of the target item before and right after it, so
+-- that undo puts the target back unless it was moved since.
+ALTER TABLE retrospective_item_merges ADD COLUMN target_category VARCHAR(100);
+ALTER TABLE retrospective_item_merges ADD COLUMN merged_category VARCHAR(100);
+
+UPDATE retrospective_item_merges m
+SET target_category = i.category, merged_category = i.category
+FROM retrospective_items i
+WHERE i.id = m.target_item_id;
+
+ALTER TABLE retrospective_item_merges ALTER COLUMN target_category SET NOT NULL;
+ALTER TABLE retrospective_item_merges ALTER COLUMN merged_category SET NOT NULL;
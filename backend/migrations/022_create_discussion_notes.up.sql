@@ -0,0 +1,18 @@
+-- What was said while discussing an item or a group, and its outcome. There
+-- is one note per item or group, edited by everyone; version changes on every
+-- save so that saving over a newer version is refused.
+CREATE TABLE discussion_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    retrospective_id UUID NOT NULL REFERENCES retrospectives(id) ON DELETE CASCADE,
+    item_id UUID UNIQUE REFERENCES retrospective_items(id) ON DELETE CASCADE,
+    group_id UUID UNIQUE REFERENCES retrospective_groups(id) ON DELETE CASCADE,
+    content TEXT NOT NULL DEFAULT '',
+    outcome TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK ((item_id IS NULL) <> (group_id IS NULL))
+);
+
+CREATE INDEX idx_discussion_notes_retrospective_id ON discussion_notes(retrospective_id);
@@ -0,0 +1,5 @@
+ALTER TABLE retrospectives
+    DROP CONSTRAINT IF EXISTS retrospectives_current_topic_check,
+    DROP COLUMN IF EXISTS current_topic_started_at,
+    DROP COLUMN IF EXISTS current_topic_group_id,
+    DROP COLUMN IF EXISTS current_topic_item_id;
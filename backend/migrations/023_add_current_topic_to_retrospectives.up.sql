@@ -0,0 +1,8 @@
+-- The item or group being discussed, set by a facilitator as the discussion
+-- goes through the queue of topics, and when its discussion started
+ALTER TABLE retrospectives
+    ADD COLUMN current_topic_item_id UUID REFERENCES retrospective_items(id) ON DELETE SET NULL,
+    ADD COLUMN current_topic_group_id UUID REFERENCES retrospective_groups(id) ON DELETE SET NULL,
+    ADD COLUMN current_topic_started_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT retrospectives_current_topic_check
+        CHECK (current_topic_item_id IS NULL OR current_topic_group_id IS NULL);
@@ -0,0 +1,6 @@
+-- Teams orphaned by deleted owners cannot satisfy NOT NULL again, remove them first
+DELETE FROM teams WHERE owner_id IS NULL;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_owner_id_fkey;
+ALTER TABLE teams ADD CONSTRAINT teams_owner_id_fkey
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE teams ALTER COLUMN owner_id SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Deleting the owner of a team must not delete the team with all of its
+-- retrospectives. Ownership is handed to another member when the account is
+-- deleted; a team left with no members is kept without an owner.
+ALTER TABLE teams ALTER COLUMN owner_id DROP NOT NULL;
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_owner_id_fkey;
+ALTER TABLE teams ADD CONSTRAINT teams_owner_id_fkey
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL;
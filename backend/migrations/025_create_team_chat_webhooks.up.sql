@@ -0,0 +1,10 @@
+-- Chat channel of a team: retrospective start, end and new action items are
+-- posted to this Slack, Teams or Mattermost incoming webhook
+CREATE TABLE team_chat_webhooks (
+    team_id UUID PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('slack', 'teams', 'mattermost')),
+    url TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE action_items DROP COLUMN IF EXISTS external_status;
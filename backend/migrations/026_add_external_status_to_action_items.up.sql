@@ -0,0 +1,9 @@
+-- State of the linked issue at the last sync (the status it maps to, empty for
+-- an open GitHub issue). Only a change of this state is applied to the action
+-- item, so status changes made in the app are not overwritten by the next sync.
+ALTER TABLE action_items ADD COLUMN external_status VARCHAR(20);
+
+-- Linked action items were in the state of their issue after the last sync
+UPDATE action_items
+SET external_status = CASE WHEN external_provider = 'github' AND status <> 'done' THEN '' ELSE status END
+WHERE external_id IS NOT NULL;
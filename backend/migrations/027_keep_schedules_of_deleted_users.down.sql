@@ -0,0 +1,6 @@
+-- Schedules of deleted users cannot satisfy NOT NULL again, remove them first
+DELETE FROM retrospective_schedules WHERE created_by IS NULL;
+ALTER TABLE retrospective_schedules DROP CONSTRAINT IF EXISTS retrospective_schedules_created_by_fkey;
+ALTER TABLE retrospective_schedules ADD CONSTRAINT retrospective_schedules_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE retrospective_schedules ALTER COLUMN created_by SET NOT NULL;
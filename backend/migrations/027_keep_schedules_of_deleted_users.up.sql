@@ -0,0 +1,7 @@
+-- Deleting the account that created a schedule must not stop the team's
+-- recurring retrospectives. The scheduler creates them on behalf of the team
+-- owner once the creator is gone.
+ALTER TABLE retrospective_schedules ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE retrospective_schedules DROP CONSTRAINT IF EXISTS retrospective_schedules_created_by_fkey;
+ALTER TABLE retrospective_schedules ADD CONSTRAINT retrospective_schedules_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
@@ -15,6 +15,7 @@ import RetrospectivesPage from './pages/RetrospectivesPage';
 import CreateRetrospectivePage from './pages/CreateRetrospectivePage';
 import RetrospectiveDetailPage from './pages/RetrospectiveDetailPage';
 import ActionItemsPage from './pages/ActionItemsPage';
+import PublicSummaryPage from './pages/PublicSummaryPage';
 
 const queryClient = new QueryClient({
   defaultOptions: {
@@ -35,6 +36,7 @@ function App() {
               {/* Public routes */}
               <Route path="/login" element={<LoginPage />} />
               <Route path="/register" element={<RegisterPage />} />
+              <Route path="/share/:token" element={<PublicSummaryPage />} />
               
               {/* Protected routes */}
               <Route path="/" element={
@@ -0,0 +1,251 @@
+import React, { useState } from 'react';
+import { useQuery, useMutation, useQueryClient } from 'react-query';
+import { X, MessageSquare, History, CornerDownRight, Edit3, Trash2 } from 'lucide-react';
+import toast from 'react-hot-toast';
+import { useAuth } from '../services/AuthContext';
+import { actionItemsAPI } from '../services/api';
+import LoadingSpinner from './LoadingSpinner';
+
+const statusLabels = {
+  todo: 'A fazer',
+  in_progress: 'Em andamento',
+  done: 'Concluído',
+};
+
+const formatDateTime = (value) => new Date(value).toLocaleString('pt-BR');
+
+const formatDate = (value) => (value ? value.split('-').reverse().join('/') : 'sem data');
+
+const describeEvent = (event, userNames) => {
+  switch (event.event_type) {
+    case 'created':
+      return 'criou o action item';
+    case 'status_changed':
+      return `mudou o status de "${statusLabels[event.old_value] || event.old_value}" para "${statusLabels[event.new_value] || event.new_value}"`;
+    case 'reassigned':
+      if (!event.new_value) return 'removeu o responsável';
+      return `atribuiu a ${userNames[event.new_value] || 'outro usuário'}`;
+    case 'due_date_changed':
+      return `mudou o prazo de ${formatDate(event.old_value)} para ${formatDate(event.new_value)}`;
+    default:
+      return event.event_type;
+  }
+};
+
+const ActionItemActivityModal = ({ actionItem, isOpen, onClose, userNames = {} }) => {
+  const { user } = useAuth();
+  const queryClient = useQueryClient();
+  const [content, setContent] = useState('');
+  const [replyingTo, setReplyingTo] = useState(null);
+  const [replyContent, setReplyContent] = useState('');
+  const [editingComment, setEditingComment] = useState(null);
+  const [editContent, setEditContent] = useState('');
+
+  const actionItemId = actionItem?.id;
+
+  const { data: history = [], isLoading: historyLoading } = useQuery(
+    ['actionItemHistory', actionItemId],
+    () => actionItemsAPI.getHistory(actionItemId),
+    { enabled: isOpen && !!actionItemId, select: (response) => response.data }
+  );
+
+  const { data: comments = [], isLoading: commentsLoading } = useQuery(
+    ['actionItemComments', actionItemId],
+    () => actionItemsAPI.getComments(actionItemId),
+    { enabled: isOpen && !!actionItemId, select: (response) => response.data }
+  );
+
+  const onError = (error) => {
+    toast.error('Erro ao salvar comentário: ' + (error.response?.data?.error || error.message));
+  };
+
+  const addCommentMutation = useMutation(
+    (data) => actionItemsAPI.addComment(actionItemId, data),
+    {
+      onSuccess: () => {
+        queryClient.invalidateQueries(['actionItemComments', actionItemId]);
+        setContent('');
+        setReplyingTo(null);
+        setReplyContent('');
+      },
+      onError,
+    }
+  );
+
+  const updateCommentMutation = useMutation(
+    ({ commentId, data }) => actionItemsAPI.updateComment(actionItemId, commentId, data),
+    {
+      onSuccess: () => {
+        queryClient.invalidateQueries(['actionItemComments', actionItemId]);
+        setEditingComment(null);
+      },
+      onError,
+    }
+  );
+
+  const deleteCommentMutation = useMutation(
+    (commentId) => actionItemsAPI.deleteComment(actionItemId, commentId),
+    {
+      onSuccess: () => {
+        queryClient.invalidateQueries(['actionItemComments', actionItemId]);
+        toast.success('Comentário excluído');
+      },
+      onError,
+    }
+  );
+
+  if (!isOpen || !actionItem) return null;
+
+  const handleSubmit = (e) => {
+    e.preventDefault();
+    if (!content.trim()) return;
+    addCommentMutation.mutate({ content });
+  };
+
+  const handleReply = (e, parentId) => {
+    e.preventDefault();
+    if (!replyContent.trim()) return;
+    addCommentMutation.mutate({ content: replyContent, parent_id: parentId });
+  };
+
+  const renderComment = (comment, depth = 0) => (
+    <div key={comment.id} className={depth > 0 ? 'ml-6 mt-3' : 'mt-4'}>
+      <div className="flex items-start space-x-2">
+        {depth > 0 && <CornerDownRight className="h-4 w-4 text-gray-300 mt-1" />}
+        <div className="flex-1 bg-gray-50 rounded-md p-3">
+          <div className="flex items-center justify-between">
+            <span className="text-sm font-medium text-gray-900">{comment.user_name || 'Usuário removido'}</span>
+            <span className="text-xs text-gray-400">{formatDateTime(comment.created_at)}</span>
+          </div>
+          {editingComment === comment.id ? (
+            <form
+              onSubmit={(e) => {
+                e.preventDefault();
+                updateCommentMutation.mutate({ commentId: comment.id, data: { content: editContent } });
+              }}
+              className="mt-2 space-y-2"
+            >
+              <textarea
+                value={editContent}
+                onChange={(e) => setEditContent(e.target.value)}
+                className="input w-full"
+                rows={2}
+              />
+              <div className="flex justify-end space-x-2">
+                <button type="button" onClick={() => setEditingComment(null)} className="btn btn-secondary">Cancelar</button>
+                <button type="submit" className="btn btn-primary" disabled={updateCommentMutation.isLoading}>Salvar</button>
+              </div>
+            </form>
+          ) : (
+            <p className="mt-1 text-sm text-gray-700 whitespace-pre-wrap">{comment.content}</p>
+          )}
+          <div className="mt-2 flex items-center space-x-3 text-xs text-gray-500">
+            <button onClick={() => { setReplyingTo(comment.id); setReplyContent(''); }} className="hover:text-gray-800">
+              Responder
+            </button>
+            {comment.user_id === user?.id && (
+              <>
+                <button
+                  onClick={() => { setEditingComment(comment.id); setEditContent(comment.content); }}
+                  className="flex items-center hover:text-gray-800"
+                >
+                  <Edit3 className="h-3 w-3 mr-1" /> Editar
+                </button>
+                <button
+                  onClick={() => deleteCommentMutation.mutate(comment.id)}
+                  className="flex items-center hover:text-red-600"
+                >
+                  <Trash2 className="h-3 w-3 mr-1" /> Excluir
+                </button>
+              </>
+            )}
+          </div>
+          {replyingTo === comment.id && (
+            <form onSubmit={(e) => handleReply(e, comment.id)} className="mt-2 space-y-2">
+              <textarea
+                value={replyContent}
+                onChange={(e) => setReplyContent(e.target.value)}
+                className="input w-full"
+                rows={2}
+                placeholder="Escreva uma resposta..."
+              />
+              <div className="flex justify-end space-x-2">
+                <button type="button" onClick={() => setReplyingTo(null)} className="btn btn-secondary">Cancelar</button>
+                <button type="submit" className="btn btn-primary" disabled={addCommentMutation.isLoading}>Responder</button>
+              </div>
+            </form>
+          )}
+        </div>
+      </div>
+      {comment.replies?.map((reply) => renderComment(reply, depth + 1))}
+    </div>
+  );
+
+  return (
+    <div className="fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full z-50">
+      <div className="relative top-20 mx-auto p-6 w-11/12 md:w-2/3 lg:w-1/2 bg-white rounded-lg shadow-xl">
+        {/* Header */}
+        <div className="flex items-center justify-between mb-6">
+          <h3 className="text-lg font-medium text-gray-900">{actionItem.title}</h3>
+          <button onClick={onClose} className="text-gray-400 hover:text-gray-600">
+            <X className="h-6 w-6" />
+          </button>
+        </div>
+
+        {/* History */}
+        <div className="mb-6">
+          <h4 className="flex items-center text-sm font-medium text-gray-700 mb-2">
+            <History className="h-4 w-4 mr-2" /> Histórico
+          </h4>
+          {historyLoading ? (
+            <LoadingSpinner size="sm" />
+          ) : history.length === 0 ? (
+            <p className="text-sm text-gray-500">Nenhuma alteração registrada.</p>
+          ) : (
+            <ul className="space-y-2">
+              {history.map((event) => (
+                <li key={event.id} className="text-sm text-gray-600">
+                  <span className="font-medium text-gray-900">{event.user_name || 'Usuário removido'}</span>{' '}
+                  {describeEvent(event, userNames)}
+                  <span className="text-xs text-gray-400"> · {formatDateTime(event.created_at)}</span>
+                  {event.note && <p className="ml-4 text-xs italic text-gray-500">“{event.note}”</p>}
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+
+        {/* Comments */}
+        <div>
+          <h4 className="flex items-center text-sm font-medium text-gray-700">
+            <MessageSquare className="h-4 w-4 mr-2" /> Comentários
+          </h4>
+          {commentsLoading ? (
+            <LoadingSpinner size="sm" />
+          ) : comments.length === 0 ? (
+            <p className="mt-2 text-sm text-gray-500">Nenhum comentário ainda.</p>
+          ) : (
+            comments.map((comment) => renderComment(comment))
+          )}
+
+          <form onSubmit={handleSubmit} className="mt-4 space-y-2">
+            <textarea
+              value={content}
+              onChange={(e) => setContent(e.target.value)}
+              className="input w-full"
+              rows={3}
+              placeholder="Adicione um comentário..."
+            />
+            <div className="flex justify-end">
+              <button type="submit" className="btn btn-primary" disabled={addCommentMutation.isLoading || !content.trim()}>
+                {addCommentMutation.isLoading ? 'Enviando...' : 'Comentar'}
+              </button>
+            </div>
+          </form>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ActionItemActivityModal;
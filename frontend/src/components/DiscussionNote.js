@@ -0,0 +1,107 @@
+import React, { useState } from 'react';
+import { MessageSquare } from 'lucide-react';
+import toast from 'react-hot-toast';
+
+// Discussion note of an item or group: what was said and the outcome.
+// Everyone edits the same note; a save over a newer version is refused by
+// the API, and the editor then continues from the current note.
+const DiscussionNote = ({ note, editable, onSave }) => {
+  const [isEditing, setIsEditing] = useState(false);
+  const [content, setContent] = useState('');
+  const [outcome, setOutcome] = useState('');
+  const [version, setVersion] = useState(0);
+  const [isSaving, setIsSaving] = useState(false);
+
+  const startEditing = () => {
+    setContent(note?.content || '');
+    setOutcome(note?.outcome || '');
+    setVersion(note?.version || 0);
+    setIsEditing(true);
+  };
+
+  const handleSave = async () => {
+    setIsSaving(true);
+    try {
+      await onSave({ content, outcome, version });
+      setIsEditing(false);
+    } catch (error) {
+      const current = error.response?.status === 409 ? error.response.data?.note : null;
+      if (current) {
+        // Keep the text being edited, saving next over the current version
+        setVersion(current.version);
+        toast.error(
+          `Outra pessoa alterou a nota: "${current.content || current.outcome}". Revise e salve novamente.`,
+          { duration: 8000 }
+        );
+      } else {
+        toast.error('Erro ao salvar nota: ' + (error.response?.data?.error || error.message));
+      }
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  if (isEditing) {
+    return (
+      <div className="mt-2 space-y-2">
+        <textarea
+          value={content}
+          onChange={(e) => setContent(e.target.value)}
+          placeholder="O que foi discutido"
+          rows={2}
+          className="w-full text-xs border border-gray-300 rounded-md p-2"
+        />
+        <textarea
+          value={outcome}
+          onChange={(e) => setOutcome(e.target.value)}
+          placeholder="Resultado"
+          rows={1}
+          className="w-full text-xs border border-gray-300 rounded-md p-2"
+        />
+        <div className="flex justify-end space-x-2">
+          <button
+            onClick={() => setIsEditing(false)}
+            className="px-2 py-1 text-xs text-gray-600 hover:text-gray-800"
+          >
+            Cancelar
+          </button>
+          <button
+            onClick={handleSave}
+            disabled={isSaving}
+            className="px-2 py-1 text-xs bg-purple-500 text-white rounded hover:bg-purple-600 disabled:opacity-50"
+          >
+            {isSaving ? 'Salvando...' : 'Salvar nota'}
+          </button>
+        </div>
+      </div>
+    );
+  }
+
+  const hasNote = note && (note.content || note.outcome);
+
+  return (
+    <div className="mt-2">
+      {hasNote && (
+        <div className="text-xs text-gray-600 bg-gray-50 rounded-md p-2 space-y-1">
+          {note.content && <p className="whitespace-pre-wrap break-words">{note.content}</p>}
+          {note.outcome && (
+            <p className="whitespace-pre-wrap break-words">
+              <span className="font-medium">Resultado:</span> {note.outcome}
+            </p>
+          )}
+        </div>
+      )}
+      {editable && (
+        <button
+          onClick={startEditing}
+          className="mt-1 flex items-center space-x-1 text-xs text-gray-400 hover:text-purple-500 transition-colors"
+        >
+          <MessageSquare className="h-3 w-3" />
+          <span>{hasNote ? 'Editar nota' : 'Adicionar nota'}</span>
+        </button>
+      )}
+    </div>
+  );
+};
+
+export default DiscussionNote;
@@ -0,0 +1,90 @@
+import React from 'react';
+import { Heart, SkipForward, Square, Users } from 'lucide-react';
+
+// Discussion queue of a retrospective: the groups and items in no group, the
+// most voted first, and the topic being discussed. Facilitators lead the
+// discussion; everyone else follows the current topic.
+const DiscussionQueue = ({ queue, canLead, onSelect, onNext, onClear, isChanging }) => {
+  if (!queue || queue.topics.length === 0) {
+    return null;
+  }
+
+  const current = queue.current;
+
+  return (
+    <div className="bg-white border border-gray-200 rounded-lg p-4 mb-6">
+      <div className="flex items-center justify-between mb-3">
+        <h3 className="text-sm font-medium text-gray-900">Fila de discussão</h3>
+        {canLead && (
+          <div className="flex items-center space-x-2">
+            <button
+              onClick={onNext}
+              disabled={isChanging}
+              className="flex items-center space-x-1 px-3 py-1 bg-purple-500 text-white rounded-md text-xs font-medium hover:bg-purple-600 transition-colors disabled:opacity-50"
+            >
+              <SkipForward className="h-3 w-3" />
+              <span>{current ? 'Próximo tópico' : 'Iniciar discussão'}</span>
+            </button>
+            {current && (
+              <button
+                onClick={onClear}
+                disabled={isChanging}
+                className="flex items-center space-x-1 px-3 py-1 bg-white border border-gray-300 text-gray-700 rounded-md text-xs font-medium hover:bg-gray-50 transition-colors disabled:opacity-50"
+              >
+                <Square className="h-3 w-3" />
+                <span>Encerrar</span>
+              </button>
+            )}
+          </div>
+        )}
+      </div>
+
+      {current && (
+        <div className="mb-3 p-3 rounded-md bg-purple-50 border border-purple-200">
+          <p className="text-xs text-purple-600 font-medium">
+            Discutindo agora
+            {queue.current_started_at && ` desde ${new Date(queue.current_started_at).toLocaleTimeString('pt-BR', { hour: '2-digit', minute: '2-digit' })}`}
+          </p>
+          <p className="text-sm text-gray-900 font-medium break-words">{current.title}</p>
+        </div>
+      )}
+
+      <ol className="space-y-1">
+        {queue.topics.map((topic, index) => {
+          const isCurrent = current?.type === topic.type && current?.id === topic.id;
+          return (
+            <li
+              key={`${topic.type}-${topic.id}`}
+              className={`flex items-center justify-between px-2 py-1 rounded text-sm ${
+                isCurrent ? 'bg-purple-100 text-purple-900' : 'text-gray-700'
+              }`}
+            >
+              <div className="flex items-center space-x-2 min-w-0">
+                <span className="text-xs text-gray-400 w-5 text-right">{index + 1}.</span>
+                {topic.type === 'group' && <Users className="h-3 w-3 text-gray-400 flex-shrink-0" />}
+                <span className="truncate">{topic.title}</span>
+              </div>
+              <div className="flex items-center space-x-3 ml-2 flex-shrink-0">
+                <span className="flex items-center space-x-1 text-xs text-gray-500">
+                  <Heart className="h-3 w-3" />
+                  <span>{topic.votes}</span>
+                </span>
+                {canLead && !isCurrent && (
+                  <button
+                    onClick={() => onSelect(topic)}
+                    disabled={isChanging}
+                    className="text-xs text-purple-500 hover:text-purple-700 disabled:opacity-50"
+                  >
+                    Discutir
+                  </button>
+                )}
+              </div>
+            </li>
+          );
+        })}
+      </ol>
+    </div>
+  );
+};
+
+export default DiscussionQueue;
@@ -6,3 +6,6 @@ export { default as Card } from './Card';
 export { default as ConfirmModal } from './ConfirmModal';
 export { default as Layout } from './Layout';
 export { default as ProtectedRoute } from './ProtectedRoute';
+export { default as ActionItemActivityModal } from './ActionItemActivityModal';
+export { default as DiscussionNote } from './DiscussionNote';
+export { default as DiscussionQueue } from './DiscussionQueue';
@@ -45,6 +45,14 @@ const useSSE = (url, retrospectiveId) => {
           case 'action_item_deleted':
             // Toast is handled by the mutation onSuccess
             break;
+          case 'carried_action_item_updated':
+            // Toast is handled by the mutation onSuccess
+            break;
+          case 'action_item_comment_added':
+          case 'action_item_comment_updated':
+          case 'action_item_comment_deleted':
+            // Toast is handled by the mutation onSuccess
+            break;
           case 'group_created':
             // Toast is handled by the mutation onSuccess
             break;
@@ -54,9 +62,18 @@ const useSSE = (url, retrospectiveId) => {
           case 'group_deleted':
             // Toast is handled by the mutation onSuccess
             break;
+          case 'group_updated':
+            // Toast is handled by the mutation onSuccess
+            break;
           case 'items_merged':
+          case 'items_unmerged':
+          case 'item_moved':
+          case 'note_updated':
             // Toast is handled by the mutation onSuccess
             break;
+          case 'topic_changed':
+            // The page follows the current topic
+            break;
           case 'connected':
             console.log('Connected to retrospective:', data.data);
             break;
@@ -1,4 +1,4 @@
-import React, { useState } from 'react';
+import React, { useState, useEffect } from 'react';
 import { Link } from 'react-router-dom';
 import { useQuery, useMutation, useQueryClient } from 'react-query';
 import { useAuth } from '../services/AuthContext';
@@ -19,35 +19,115 @@ import {
   FileText,
   CheckSquare,
   Play,
-  Pause
+  Pause,
+  History,
+  ExternalLink,
+  Upload,
+  RefreshCw
 } from 'lucide-react';
-import { retrospectivesAPI } from '../services/api';
+import { retrospectivesAPI, actionItemsAPI } from '../services/api';
 import toast from 'react-hot-toast';
 import ConfirmModal from '../components/ConfirmModal';
+import ActionItemActivityModal from '../components/ActionItemActivityModal';
+
+// Action items per page of the list
+const PAGE_SIZE = 50;
 
 const ActionItemsPage = () => {
   const { user } = useAuth();
   const [statusFilter, setStatusFilter] = useState('all');
   const [retrospectiveFilter, setRetrospectiveFilter] = useState('all');
   const [searchTerm, setSearchTerm] = useState('');
+  const [debouncedSearch, setDebouncedSearch] = useState('');
+  const [page, setPage] = useState(0);
   const [showCompleteModal, setShowCompleteModal] = useState(false);
   const [showFeedbackModal, setShowFeedbackModal] = useState(false);
   const [showDeleteModal, setShowDeleteModal] = useState(false);
   const [completingActionItem, setCompletingActionItem] = useState(null);
   const [viewingActionItem, setViewingActionItem] = useState(null);
   const [deletingActionItem, setDeletingActionItem] = useState(null);
+  const [activityActionItem, setActivityActionItem] = useState(null);
   const [completionForm, setCompletionForm] = useState({
     feedback: ''
   });
 
   const queryClient = useQueryClient();
 
-  // Fetch all retrospectives to get action items
-  const { data: retrospectives, isLoading } = useQuery(
+  // Search as the user stops typing, not on every key
+  useEffect(() => {
+    const timeout = setTimeout(() => setDebouncedSearch(searchTerm.trim()), 300);
+    return () => clearTimeout(timeout);
+  }, [searchTerm]);
+
+  // Back to the first page whenever the filters change
+  useEffect(() => {
+    setPage(0);
+  }, [statusFilter, retrospectiveFilter, debouncedSearch]);
+
+  // Fetch a page of the action items across the user's retrospectives, filtered by the API
+  const actionItemParams = {
+    sort: 'due_date',
+    limit: PAGE_SIZE,
+    offset: page * PAGE_SIZE,
+    ...(statusFilter !== 'all' && { status: statusFilter }),
+    ...(retrospectiveFilter !== 'all' && { retrospective_id: retrospectiveFilter }),
+    ...(debouncedSearch && { search: debouncedSearch }),
+  };
+  const { data: actionItemList, isLoading } = useQuery(
+    ['actionItems', actionItemParams],
+    () => actionItemsAPI.getActionItems(actionItemParams),
+    {
+      select: (response) => response.data,
+      keepPreviousData: true,
+    }
+  );
+
+  // Retrospectives of the user, for the filter
+  const { data: retrospectives = [] } = useQuery(
     'userRetrospectives',
     retrospectivesAPI.getRetrospectives,
+    {
+      select: (response) => response.data || [],
+    }
+  );
+
+  // Issue tracker the action items can be exported to, if any
+  const { data: issueTracker } = useQuery(
+    'issueTracker',
+    () => actionItemsAPI.getIssueTracker(),
     {
       select: (response) => response.data,
+      staleTime: Infinity,
+    }
+  );
+
+  const issueTrackerName = issueTracker?.provider === 'jira' ? 'Jira' : 'GitHub';
+
+  // Export to / sync with the issue tracker mutations
+  const exportActionItemMutation = useMutation(
+    (actionItemId) => actionItemsAPI.exportToIssueTracker(actionItemId),
+    {
+      onSuccess: () => {
+        queryClient.invalidateQueries('actionItems');
+        toast.success(`Action Item exportado para o ${issueTrackerName}!`);
+      },
+      onError: (error) => {
+        toast.error('Erro ao exportar Action Item: ' + (error.response?.data?.error || error.message));
+      }
+    }
+  );
+
+  const syncActionItemMutation = useMutation(
+    (actionItemId) => actionItemsAPI.syncWithIssueTracker(actionItemId),
+    {
+      onSuccess: (response) => {
+        queryClient.invalidateQueries('actionItems');
+        queryClient.invalidateQueries(['actionItemHistory', response.data?.action_item?.id]);
+        toast.success(response.data?.events?.length ? 'Status atualizado a partir da issue' : 'Action Item já está sincronizado');
+      },
+      onError: (error) => {
+        toast.error('Erro ao sincronizar Action Item: ' + (error.response?.data?.error || error.message));
+      }
     }
   );
 
@@ -56,6 +136,7 @@ const ActionItemsPage = () => {
     ({ actionItemId, data }) => retrospectivesAPI.updateActionItem(actionItemId, data),
     {
       onSuccess: () => {
+        queryClient.invalidateQueries('actionItems');
         queryClient.invalidateQueries('userRetrospectives');
         queryClient.invalidateQueries(['retrospective']);
         //toast.success('Action Item atualizado com sucesso!');
@@ -71,6 +152,7 @@ const ActionItemsPage = () => {
     (actionItemId) => retrospectivesAPI.deleteActionItem(actionItemId),
     {
       onSuccess: () => {
+        queryClient.invalidateQueries('actionItems');
         queryClient.invalidateQueries('userRetrospectives');
         toast.success('Action Item excluído com sucesso!');
       },
@@ -80,32 +162,16 @@ const ActionItemsPage = () => {
     }
   );
 
-  // Flatten all action items from all retrospectives
-  const allActionItems = (retrospectives && Array.isArray(retrospectives)) 
-    ? retrospectives.flatMap(retro => 
-        retro.action_items?.map(actionItem => ({
-          ...actionItem,
-          retrospective: {
-            id: retro.id,
-            title: retro.title,
-            template: retro.template,
-            status: retro.status
-          }
-        })) || []
-      )
-    : [];
-
-  // Filter action items
-  const filteredActionItems = allActionItems.filter(actionItem => {
-    const matchesStatus = statusFilter === 'all' || actionItem.status === statusFilter;
-    const matchesRetrospective = retrospectiveFilter === 'all' || actionItem.retrospective.id === retrospectiveFilter;
-    const matchesSearch = searchTerm === '' || 
-      actionItem.title.toLowerCase().includes(searchTerm.toLowerCase()) ||
-      actionItem.description?.toLowerCase().includes(searchTerm.toLowerCase()) ||
-      actionItem.retrospective.title.toLowerCase().includes(searchTerm.toLowerCase());
-    
-    return matchesStatus && matchesRetrospective && matchesSearch;
-  });
+  const actionItems = actionItemList?.action_items || [];
+  const total = actionItemList?.total || 0;
+  const pageCount = Math.max(1, Math.ceil(total / PAGE_SIZE));
+
+  // Deleting the last action items of the last page goes back a page
+  useEffect(() => {
+    if (actionItemList && page >= pageCount) {
+      setPage(pageCount - 1);
+    }
+  }, [actionItemList, page, pageCount]);
 
   const getStatusColor = (status) => {
     switch (status) {
@@ -332,14 +398,14 @@ ${completionForm.feedback}
           </div>
         </div>
         <div className="mt-4 text-sm text-gray-500">
-          {filteredActionItems.length} de {allActionItems.length} action items
+          {total} action items
         </div>
       </div>
 
       {/* Action Items List */}
-      {filteredActionItems.length > 0 ? (
+      {actionItems.length > 0 ? (
         <div className="space-y-3">
-          {filteredActionItems.map((actionItem) => (
+          {actionItems.map((actionItem) => (
             <div key={actionItem.id} className="card">
               <div className="flex items-center justify-between">
                 <div className="flex items-center space-x-4">
@@ -434,6 +500,55 @@ ${completionForm.feedback}
                           </button>
                         )}
 
+                  {/* Issue tracker: export, or open and sync the linked issue */}
+                  {actionItem.external_url ? (
+                    <>
+                      <a
+                        href={actionItem.external_url}
+                        target="_blank"
+                        rel="noopener noreferrer"
+                        className="flex flex-col items-center space-y-1 text-gray-600 hover:text-gray-900 p-2 rounded-md hover:bg-gray-50 transition-colors"
+                        title="Abrir issue"
+                      >
+                        <ExternalLink className="h-4 w-4" />
+                        <span className="text-xs text-gray-400">Issue</span>
+                      </a>
+                      {issueTracker?.provider === actionItem.external_provider && canEditActionItem(actionItem) && (
+                        <button
+                          onClick={() => syncActionItemMutation.mutate(actionItem.id)}
+                          disabled={syncActionItemMutation.isLoading}
+                          className="flex flex-col items-center space-y-1 text-gray-600 hover:text-gray-900 p-2 rounded-md hover:bg-gray-50 transition-colors disabled:opacity-50"
+                          title="Sincronizar status com a issue"
+                        >
+                          <RefreshCw className="h-4 w-4" />
+                          <span className="text-xs text-gray-400">Sincronizar</span>
+                        </button>
+                      )}
+                    </>
+                  ) : (
+                    issueTracker?.enabled && canEditActionItem(actionItem) && (
+                      <button
+                        onClick={() => exportActionItemMutation.mutate(actionItem.id)}
+                        disabled={exportActionItemMutation.isLoading}
+                        className="flex flex-col items-center space-y-1 text-gray-600 hover:text-gray-900 p-2 rounded-md hover:bg-gray-50 transition-colors disabled:opacity-50"
+                        title={`Exportar para o ${issueTrackerName}`}
+                      >
+                        <Upload className="h-4 w-4" />
+                        <span className="text-xs text-gray-400">Exportar</span>
+                      </button>
+                    )
+                  )}
+
+                  {/* History and comments */}
+                  <button
+                    onClick={() => setActivityActionItem(actionItem)}
+                    className="flex flex-col items-center space-y-1 text-gray-600 hover:text-gray-900 p-2 rounded-md hover:bg-gray-50 transition-colors"
+                    title="Histórico e comentários"
+                  >
+                    <History className="h-4 w-4" />
+                    <span className="text-xs text-gray-400">Histórico</span>
+                  </button>
+
                   {/* View Retrospective Link */}
                   <Link
                     to={`/retrospectives/${actionItem.retrospective.id}`}
@@ -447,6 +562,29 @@ ${completionForm.feedback}
               </div>
             </div>
           ))}
+
+          {/* Pagination */}
+          {pageCount > 1 && (
+            <div className="flex items-center justify-between pt-2">
+              <button
+                onClick={() => setPage(page - 1)}
+                disabled={page === 0}
+                className="btn btn-secondary disabled:opacity-50"
+              >
+                Anterior
+              </button>
+              <span className="text-sm text-gray-500">
+                Página {page + 1} de {pageCount}
+              </span>
+              <button
+                onClick={() => setPage(page + 1)}
+                disabled={page + 1 >= pageCount}
+                className="btn btn-secondary disabled:opacity-50"
+              >
+                Próxima
+              </button>
+            </div>
+          )}
         </div>
       ) : (
         <div className="text-center py-12">
@@ -460,6 +598,14 @@ ${completionForm.feedback}
         </div>
       )}
 
+      {/* History and comments */}
+      <ActionItemActivityModal
+        actionItem={activityActionItem}
+        isOpen={!!activityActionItem}
+        onClose={() => setActivityActionItem(null)}
+        userNames={user ? { [user.id]: user.name } : {}}
+      />
+
       {/* Complete Action Item Modal */}
       {showCompleteModal && (
         <div className="fixed inset-0 bg-gray-600 bg-opacity-50 overflow-y-auto h-full w-full z-50">
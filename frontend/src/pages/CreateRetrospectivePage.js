@@ -11,7 +11,8 @@ const CreateRetrospectivePage = () => {
   const [formData, setFormData] = useState({
     title: '',
     description: '',
-    template: ''
+    template: '',
+    scheduled_at: ''
   });
 
   const { data: templates, isLoading: templatesLoading } = useQuery('templates', templatesAPI.getTemplates);
@@ -50,7 +51,12 @@ const CreateRetrospectivePage = () => {
       return;
     }
 
-    createRetrospectiveMutation.mutate(formData);
+    const { scheduled_at, ...data } = formData;
+    if (scheduled_at) {
+      data.scheduled_at = new Date(scheduled_at).toISOString();
+    }
+
+    createRetrospectiveMutation.mutate(data);
   };
 
   const getTemplateIcon = (templateId) => {
@@ -133,6 +139,23 @@ const CreateRetrospectivePage = () => {
               />
             </div>
 
+            <div>
+              <label htmlFor="scheduled_at" className="block text-sm font-medium text-gray-700">
+                Agendar para (opcional)
+              </label>
+              <input
+                type="datetime-local"
+                id="scheduled_at"
+                name="scheduled_at"
+                value={formData.scheduled_at}
+                onChange={handleChange}
+                className="input mt-1"
+              />
+              <p className="mt-1 text-xs text-gray-500">
+                A retrospectiva começa automaticamente no horário agendado.
+              </p>
+            </div>
+
             <div>
               <label htmlFor="template" className="block text-sm font-medium text-gray-700">
                 Template *
@@ -1,12 +1,14 @@
-import React, { useState } from 'react';
+import React, { useState, useEffect } from 'react';
 import { Link, useNavigate, useLocation } from 'react-router-dom';
 import { MessageSquare, Mail, Lock } from 'lucide-react';
 import { useAuth } from '../services/AuthContext';
+import { authAPI } from '../services/api';
+import toast from 'react-hot-toast';
 import InputField from '../components/InputField';
 import LoadingSpinner from '../components/LoadingSpinner';
 
 const LoginPage = () => {
-  const { login } = useAuth();
+  const { login, loginWithToken } = useAuth();
   const navigate = useNavigate();
   const location = useLocation();
 
@@ -19,6 +21,32 @@ const LoginPage = () => {
   const [loading, setLoading] = useState(false);
   const [errors, setErrors] = useState({});
 
+  // Single sign-on ends back here with the token or the error in the fragment
+  useEffect(() => {
+    const params = new URLSearchParams(window.location.hash.slice(1));
+    const ssoToken = params.get('token');
+    const ssoError = params.get('error');
+    if (!ssoToken && !ssoError) {
+      return;
+    }
+
+    // Keep the token out of the browser history
+    window.history.replaceState(null, '', window.location.pathname + window.location.search);
+
+    if (ssoError) {
+      toast.error('Erro no login com SSO: ' + ssoError);
+      return;
+    }
+
+    setLoading(true);
+    loginWithToken(ssoToken).then((result) => {
+      setLoading(false);
+      if (result.success) {
+        navigate(from, { replace: true });
+      }
+    });
+  }, []); // eslint-disable-line react-hooks/exhaustive-deps
+
   const handleChange = (e) => {
     const { name, value } = e.target;
     setFormData(prev => ({
@@ -116,6 +144,17 @@ const LoginPage = () => {
               </button>
             </div>
           </form>
+
+          {process.env.REACT_APP_OIDC_ENABLED === 'true' && (
+            <div className="mt-6">
+              <a
+                href={authAPI.oidcLoginURL}
+                className="btn btn-secondary btn-lg w-full text-center"
+              >
+                Entrar com SSO
+              </a>
+            </div>
+          )}
         </div>
       </div>
     </div>
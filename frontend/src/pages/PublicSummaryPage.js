@@ -0,0 +1,109 @@
+import React from 'react';
+import { useParams } from 'react-router-dom';
+import { useQuery } from 'react-query';
+import { Users, CheckCircle, AlertCircle } from 'lucide-react';
+import { publicAPI } from '../services/api';
+
+// Read-only summary of a retrospective opened from a public link
+const PublicSummaryPage = () => {
+  const { token } = useParams();
+
+  const { data, isLoading, isError } = useQuery(
+    ['publicSummary', token],
+    () => publicAPI.getSummary(token),
+    {
+      select: (response) => response.data,
+    }
+  );
+
+  if (isLoading) {
+    return (
+      <div className="flex items-center justify-center min-h-screen">
+        <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-primary-600"></div>
+      </div>
+    );
+  }
+
+  if (isError || !data) {
+    return (
+      <div className="flex flex-col items-center justify-center min-h-screen text-gray-600">
+        <AlertCircle className="h-12 w-12 mb-4 text-gray-400" />
+        <p>Este link não existe ou expirou.</p>
+      </div>
+    );
+  }
+
+  const { summary, expires_at: expiresAt } = data;
+
+  return (
+    <div className="max-w-4xl mx-auto py-8 px-4 space-y-6">
+      <div>
+        <h1 className="text-2xl font-bold text-gray-900">{summary.title}</h1>
+        {summary.description && <p className="mt-1 text-gray-600">{summary.description}</p>}
+        <div className="mt-2 flex items-center space-x-4 text-sm text-gray-500">
+          <span>{summary.template}</span>
+          <span>{summary.status}</span>
+          <span className="flex items-center">
+            <Users className="h-4 w-4 mr-1" />
+            {summary.participants} participantes
+          </span>
+        </div>
+      </div>
+
+      <div className="grid gap-4 md:grid-cols-2">
+        {summary.categories.map((category) => (
+          <div key={category.name} className="card p-4">
+            <h2 className="font-semibold mb-2" style={{ color: category.color }}>{category.name}</h2>
+            <ul className="space-y-1 text-sm text-gray-700">
+              {category.items.map((item, index) => (
+                <li key={index} className="flex justify-between">
+                  <span>{item.content}</span>
+                  <span className="text-gray-400 ml-2">{item.votes} votos</span>
+                </li>
+              ))}
+            </ul>
+          </div>
+        ))}
+      </div>
+
+      {summary.groups.length > 0 && (
+        <div className="card p-4">
+          <h2 className="font-semibold mb-2">Grupos</h2>
+          {summary.groups.map((group) => (
+            <div key={group.name} className="mb-2 text-sm">
+              <p className="font-medium">{group.name} ({group.votes} votos)</p>
+              <ul className="list-disc list-inside text-gray-600">
+                {group.items.map((content, index) => <li key={index}>{content}</li>)}
+              </ul>
+            </div>
+          ))}
+        </div>
+      )}
+
+      {summary.action_items.length > 0 && (
+        <div className="card p-4">
+          <h2 className="font-semibold mb-2">Action items</h2>
+          <ul className="space-y-1 text-sm text-gray-700">
+            {summary.action_items.map((actionItem, index) => (
+              <li key={index} className="flex items-center">
+                <CheckCircle className="h-4 w-4 mr-2 text-gray-400" />
+                <span>{actionItem.title}</span>
+                <span className="ml-2 text-gray-400">
+                  {actionItem.status}
+                  {actionItem.assignee && ` · ${actionItem.assignee}`}
+                  {actionItem.due_date && ` · ${new Date(actionItem.due_date).toLocaleDateString('pt-BR')}`}
+                </span>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
+      <p className="text-xs text-gray-400">
+        Link válido até {new Date(expiresAt).toLocaleString('pt-BR')}
+      </p>
+    </div>
+  );
+};
+
+export default PublicSummaryPage;
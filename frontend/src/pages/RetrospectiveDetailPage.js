@@ -1,13 +1,15 @@
 import React, { useState, useEffect } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
 import { useQuery, useMutation, useQueryClient } from 'react-query';
-import { Users, Plus, Heart, MessageSquare, CheckCircle, AlertCircle, Trash2, Edit3, Filter, Calendar, X, Star, Eye, EyeOff, Clock, Play, Pause, Square, Download } from 'lucide-react';
-import { retrospectivesAPI, templatesAPI } from '../services/api';
+import { Users, Plus, Heart, MessageSquare, CheckCircle, AlertCircle, Trash2, Edit3, Filter, Calendar, X, Star, Eye, EyeOff, Clock, Play, Pause, Square, Download, Share2 } from 'lucide-react';
+import { retrospectivesAPI, templatesAPI, sharingAPI } from '../services/api';
 import { useAuth } from '../services/AuthContext';
 import useSSE from '../hooks/useSSE';
 import toast from 'react-hot-toast';
 import ConfirmModal from '../components/ConfirmModal';
 import Timer from '../components/Timer';
+import DiscussionNote from '../components/DiscussionNote';
+import DiscussionQueue from '../components/DiscussionQueue';
 
 const RetrospectiveDetailPage = () => {
   const { id } = useParams();
@@ -18,11 +20,13 @@ const RetrospectiveDetailPage = () => {
   const [showAddActionItemModal, setShowAddActionItemModal] = useState(false);
   const [showEditActionItemModal, setShowEditActionItemModal] = useState(false);
   const [selectedCategory, setSelectedCategory] = useState('');
+  const [groupSuggestions, setGroupSuggestions] = useState(null);
+  const [exportFormat, setExportFormat] = useState('pdf');
   const [newItemContent, setNewItemContent] = useState('');
   const [editingCategory, setEditingCategory] = useState(null); // Para edição inline
   const [editingItem, setEditingItem] = useState(null); // Para editar item existente
   const [editItemContent, setEditItemContent] = useState(''); // Conteúdo sendo editado
-  const [newActionItem, setNewActionItem] = useState({ title: '', description: '', dueDate: '' });
+  const [newActionItem, setNewActionItem] = useState({ title: '', description: '', dueDate: '', assignedTo: '' });
   const [editingActionItem, setEditingActionItem] = useState(null);
   
   // Function to extract feedback from description
@@ -58,6 +62,34 @@ const RetrospectiveDetailPage = () => {
     }
   );
 
+  // Export policy of the retrospective and whether the user can export it
+  const { data: sharing } = useQuery(
+    ['sharing', id],
+    () => sharingAPI.getSharing(id),
+    {
+      select: (response) => response.data,
+    }
+  );
+
+  // Topics in the order they are discussed, and the topic being discussed
+  const { data: discussionQueue } = useQuery(
+    ['discussionQueue', id],
+    () => retrospectivesAPI.getDiscussionQueue(id),
+    {
+      select: (response) => response.data,
+    }
+  );
+
+  // Users who can be assigned action items of this retrospective
+  const { data: assignableUsers = [] } = useQuery(
+    ['assignableUsers', id],
+    () => retrospectivesAPI.getAssignableUsers(id),
+    {
+      enabled: showAddActionItemModal,
+      select: (response) => response.data,
+    }
+  );
+
   // Fetch template information
   const { data: templateData } = useQuery(
     ['template', retrospective?.template],
@@ -116,7 +148,7 @@ const RetrospectiveDetailPage = () => {
       onSuccess: () => {
         queryClient.invalidateQueries(['retrospective', id]);
         setShowAddActionItemModal(false);
-        setNewActionItem({ title: '', description: '', dueDate: '' });
+        setNewActionItem({ title: '', description: '', dueDate: '', assignedTo: '' });
         toast.success('Action item adicionado com sucesso!');
       },
       onError: (error) => {
@@ -153,6 +185,18 @@ const RetrospectiveDetailPage = () => {
     }
   );
 
+  const carryForwardMutation = useMutation(
+    ({ actionItemId, carryForward }) => retrospectivesAPI.setCarryForward(id, actionItemId, carryForward),
+    {
+      onSuccess: () => {
+        queryClient.invalidateQueries(['retrospective', id]);
+      },
+      onError: (error) => {
+        toast.error('Erro ao atualizar action item: ' + (error.response?.data?.error || error.message));
+      },
+    }
+  );
+
   const startRetrospectiveMutation = useMutation(
     () => retrospectivesAPI.startRetrospective(id),
     {
@@ -181,14 +225,68 @@ const RetrospectiveDetailPage = () => {
 
 
 
+  const unmergeItemsMutation = useMutation(
+    (mergeId) => retrospectivesAPI.unmergeItems(id, mergeId),
+    {
+      onSuccess: () => {
+        queryClient.invalidateQueries(['retrospective', id]);
+        toast.success('Mesclagem desfeita!');
+      },
+      onError: (error) => {
+        toast.error('Erro ao desfazer mesclagem: ' + (error.response?.data?.error || error.message));
+      },
+    }
+  );
+
+  const moveItemMutation = useMutation(
+    ({ itemId, category }) => retrospectivesAPI.moveItem(itemId, category),
+    {
+      onSuccess: () => {
+        queryClient.invalidateQueries(['retrospective', id]);
+        toast.success('Item movido!');
+      },
+      onError: (error) => {
+        toast.error('Erro ao mover item: ' + (error.response?.data?.error || error.message));
+      },
+    }
+  );
+
+  const changeTopicMutation = useMutation(
+    (change) => change(),
+    {
+      onSuccess: (response) => {
+        queryClient.setQueryData(['discussionQueue', id], response);
+      },
+      onError: (error) => {
+        toast.error('Erro ao mudar o tópico: ' + (error.response?.data?.error || error.message));
+      },
+    }
+  );
+
   const mergeItemsMutation = useMutation(
     (data) => retrospectivesAPI.mergeItems(id, data),
     {
-      onSuccess: () => {
+      onSuccess: (response) => {
         queryClient.invalidateQueries(['retrospective', id]);
         setDraggedItem(null);
         setDragOverItem(null);
-        toast.success('Itens mesclados com sucesso!');
+        const mergeId = response.data.merge?.id;
+        toast.success((t) => (
+          <span className="flex items-center space-x-2">
+            <span>Itens mesclados com sucesso!</span>
+            {mergeId && (
+              <button
+                onClick={() => {
+                  toast.dismiss(t.id);
+                  unmergeItemsMutation.mutate(mergeId);
+                }}
+                className="underline font-medium"
+              >
+                Desfazer
+              </button>
+            )}
+          </span>
+        ), { duration: 8000 });
       },
       onError: (error) => {
         toast.error('Erro ao mesclar itens: ' + (error.response?.data?.error || error.message));
@@ -220,9 +318,28 @@ const RetrospectiveDetailPage = () => {
         console.log('Blur state synchronized:', blurData.blurred);
       } else if (lastMessage.type === 'item_added' || lastMessage.type === 'item_voted' || 
                  lastMessage.type === 'action_item_added' || lastMessage.type === 'action_item_updated' || 
-                 lastMessage.type === 'action_item_deleted' || lastMessage.type === 'items_merged') {
+                 lastMessage.type === 'action_item_deleted' || lastMessage.type === 'items_merged' || lastMessage.type === 'items_unmerged' ||
+                 lastMessage.type === 'item_moved' || lastMessage.type === 'note_updated' ||
+                 lastMessage.type === 'carried_action_item_updated' || lastMessage.type === 'group_created' ||
+                 lastMessage.type === 'group_updated' || lastMessage.type === 'group_deleted') {
         // Invalidate and refetch retrospective data for other updates
         queryClient.invalidateQueries(['retrospective', id]);
+        queryClient.invalidateQueries(['discussionQueue', id]);
+        if (lastMessage.type === 'action_item_updated') {
+          queryClient.invalidateQueries(['actionItemHistory', lastMessage.data?.action_item?.id]);
+        }
+      } else if (lastMessage.type === 'topic_changed') {
+        // Follow the topic the facilitator moved the discussion to
+        queryClient.invalidateQueries(['discussionQueue', id]);
+        const topic = lastMessage.data?.topic;
+        const itemId = topic?.type === 'group' ? topic.item_ids?.[0] : topic?.id;
+        if (itemId) {
+          document.getElementById(`item-${itemId}`)?.scrollIntoView({ behavior: 'smooth', block: 'center' });
+        }
+      } else if (lastMessage.type === 'action_item_comment_added' || lastMessage.type === 'action_item_comment_updated') {
+        queryClient.invalidateQueries(['actionItemComments', lastMessage.data?.comment?.action_item_id]);
+      } else if (lastMessage.type === 'action_item_comment_deleted') {
+        queryClient.invalidateQueries(['actionItemComments', lastMessage.data?.action_item_id]);
       }
     }
   }, [lastMessage, queryClient, id]);
@@ -356,10 +473,38 @@ const RetrospectiveDetailPage = () => {
     setSelectedCategory('');
   };
 
+  const handleSelectTopic = (topic) => {
+    changeTopicMutation.mutate(() => retrospectivesAPI.setCurrentTopic(id, { type: topic.type, id: topic.id }));
+  };
+
+  const handleNextTopic = () => {
+    changeTopicMutation.mutate(() => retrospectivesAPI.nextTopic(id));
+  };
+
+  const handleClearTopic = () => {
+    changeTopicMutation.mutate(() => retrospectivesAPI.clearCurrentTopic(id));
+  };
+
+  // Whether the item is the topic being discussed or in the group that is
+  const isCurrentTopicItem = (item) => {
+    const current = discussionQueue?.current;
+    if (!current) {
+      return false;
+    }
+    return current.type === 'group' ? current.item_ids?.includes(item.id) : current.id === item.id;
+  };
+
   const handleVoteItem = (itemId) => {
     voteItemMutation.mutate(itemId);
   };
 
+  // Errors are handled by the note editor, which keeps the text on conflicts
+  const handleSaveItemNote = async (itemId, data) => {
+    await retrospectivesAPI.saveItemNote(itemId, data);
+    queryClient.invalidateQueries(['retrospective', id]);
+    toast.success('Nota salva!');
+  };
+
   const handleDeleteItem = (item) => {
     setDeletingItem(item);
     setShowDeleteItemModal(true);
@@ -399,6 +544,7 @@ const RetrospectiveDetailPage = () => {
 
   const handleDrop = (e, targetItem) => {
     e.preventDefault();
+    e.stopPropagation();
     setDragOverItem(null);
     
     if (!draggedItem || draggedItem.id === targetItem.id) {
@@ -406,13 +552,6 @@ const RetrospectiveDetailPage = () => {
       return;
     }
 
-    // Check if items are in the same category
-    if (draggedItem.category !== targetItem.category) {
-      toast.error('Só é possível mesclar itens da mesma categoria');
-      setDraggedItem(null);
-      return;
-    }
-
     // Check if items are kudos (kudos cannot be merged)
     if (draggedItem.category === 'kudos' || targetItem.category === 'kudos') {
       toast.error('Kudos não podem ser mesclados');
@@ -420,13 +559,26 @@ const RetrospectiveDetailPage = () => {
       return;
     }
 
-    // Merge items
+    // Merge items; items of another column are merged into the column of the target
     mergeItemsMutation.mutate({
       source_item_id: draggedItem.id,
       target_item_id: targetItem.id,
+      vote_mode: 'sum',
+      target_category: targetItem.category,
     });
   };
 
+  // Dropping an item on an empty spot of another column moves it there
+  const handleDropOnCategory = (e, categoryKey) => {
+    e.preventDefault();
+    setDragOverItem(null);
+
+    if (draggedItem && draggedItem.category !== categoryKey) {
+      moveItemMutation.mutate({ itemId: draggedItem.id, category: categoryKey });
+    }
+    setDraggedItem(null);
+  };
+
   const handleDragEnd = () => {
     setDraggedItem(null);
     setDragOverItem(null);
@@ -442,6 +594,7 @@ const RetrospectiveDetailPage = () => {
       title: newActionItem.title,
       description: newActionItem.description,
       due_date: newActionItem.dueDate || null,
+      assigned_to: newActionItem.assignedTo || null,
     });
   };
 
@@ -578,14 +731,15 @@ ${editingActionItem.feedback}
 
   const handleExportRetrospective = async () => {
     try {
-      const response = await retrospectivesAPI.exportRetrospective(id);
+      const response = await retrospectivesAPI.exportRetrospective(id, exportFormat);
+      const extension = exportFormat === 'markdown' ? 'md' : exportFormat;
       
       // Create blob and download
-      const blob = new Blob([response.data], { type: 'application/pdf' });
+      const blob = new Blob([response.data], { type: response.headers['content-type'] });
       const url = window.URL.createObjectURL(blob);
       const link = document.createElement('a');
       link.href = url;
-      link.download = `retrospective_${retrospective.title}_${new Date().toISOString().split('T')[0]}.pdf`;
+      link.download = `retrospective_${retrospective.title}_${new Date().toISOString().split('T')[0]}.${extension}`;
       document.body.appendChild(link);
       link.click();
       document.body.removeChild(link);
@@ -598,6 +752,70 @@ ${editingActionItem.feedback}
     }
   };
 
+  const handleExportPolicyChange = async (exportPolicy) => {
+    try {
+      await sharingAPI.setExportPolicy(id, exportPolicy);
+      queryClient.invalidateQueries(['sharing', id]);
+      toast.success('Permissão de exportação atualizada!');
+    } catch (error) {
+      toast.error(error.response?.data?.error || 'Erro ao atualizar permissão de exportação');
+    }
+  };
+
+  const handleCreateShareLink = async () => {
+    try {
+      const response = await sharingAPI.createShareLink(id, {});
+      const url = response.data.url || `${window.location.origin}/share/${response.data.token}`;
+      await navigator.clipboard?.writeText(url);
+      queryClient.invalidateQueries(['sharing', id]);
+      toast.success('Link do resumo copiado! Ele expira em 7 dias.');
+    } catch (error) {
+      toast.error(error.response?.data?.error || 'Erro ao criar link do resumo');
+    }
+  };
+
+  const handleSuggestGroups = async () => {
+    try {
+      const response = await retrospectivesAPI.getGroupSuggestions(id);
+      setGroupSuggestions(response.data.suggestions);
+      if (response.data.suggestions.length === 0) {
+        toast('Nenhum grupo sugerido: não há itens semelhantes fora de grupos.');
+      }
+    } catch (error) {
+      toast.error(error.response?.data?.error || 'Erro ao sugerir grupos');
+    }
+  };
+
+  const handleAcceptSuggestion = async (suggestion) => {
+    try {
+      await retrospectivesAPI.createGroup(id, suggestion);
+      setGroupSuggestions((suggestions) => suggestions.filter((other) => other !== suggestion));
+      queryClient.invalidateQueries(['retrospective', id]);
+      toast.success(`Grupo "${suggestion.name}" criado!`);
+    } catch (error) {
+      toast.error(error.response?.data?.error || 'Erro ao criar grupo');
+    }
+  };
+
+  const handleDownloadCalendar = async () => {
+    try {
+      const response = await retrospectivesAPI.downloadCalendar(id);
+
+      const blob = new Blob([response.data], { type: 'text/calendar' });
+      const url = window.URL.createObjectURL(blob);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = `retrospective_${id}.ics`;
+      document.body.appendChild(link);
+      link.click();
+      document.body.removeChild(link);
+      window.URL.revokeObjectURL(url);
+    } catch (error) {
+      console.error('Error downloading calendar:', error);
+      toast.error('Erro ao baixar calendário');
+    }
+  };
+
   // Toggle comments blur
   const toggleCommentsBlur = () => {
     const newBlurState = !isCommentsBlurred;
@@ -648,6 +866,14 @@ ${editingActionItem.feedback}
     return acc;
   }, {}) || {};
 
+  // Discussion notes by item
+  const itemNotes = (retrospective.notes || []).reduce((acc, note) => {
+    if (note.item_id) {
+      acc[note.item_id] = note;
+    }
+    return acc;
+  }, {});
+
   // Get categories from template data
   const categories = templateData?.categories?.map(cat => cat.id) || [];
 
@@ -704,8 +930,48 @@ ${editingActionItem.feedback}
               {/* Timer Component - Only for retrospective owner */}
               {isRetrospectiveOwner() && <Timer />}
               
-              {/* Export Button - Only for retrospective owner */}
-              {isRetrospectiveOwner() && (
+              {/* Export policy - Only for facilitators */}
+              {sharing?.can_manage && (
+                <select
+                  value={sharing.export_policy}
+                  onChange={(e) => handleExportPolicyChange(e.target.value)}
+                  className="px-2 py-2 bg-white border border-gray-300 text-gray-700 rounded-md text-sm"
+                  title="Quem pode exportar"
+                >
+                  <option value="creator">Exportação: criador</option>
+                  <option value="facilitators">Exportação: facilitadores</option>
+                  <option value="participants">Exportação: participantes</option>
+                  <option value="team">Exportação: time</option>
+                </select>
+              )}
+
+              {/* Group suggestions - Only for facilitators */}
+              {sharing?.can_manage && retrospective?.status === 'active' && (
+                <button
+                  onClick={handleSuggestGroups}
+                  className="flex items-center space-x-2 px-3 py-2 bg-white border border-gray-300 text-gray-700 rounded-md text-sm font-medium hover:bg-gray-50 transition-colors"
+                  title="Sugerir grupos de itens semelhantes"
+                >
+                  <Users className="h-4 w-4" />
+                  <span>Sugerir grupos</span>
+                </button>
+              )}
+
+              {/* Export Button - Whoever the export policy allows */}
+              {sharing?.can_export && (
+                <select
+                  value={exportFormat}
+                  onChange={(e) => setExportFormat(e.target.value)}
+                  className="px-2 py-2 bg-white border border-gray-300 text-gray-700 rounded-md text-sm"
+                  title="Formato da exportação"
+                >
+                  <option value="pdf">PDF</option>
+                  <option value="markdown">Markdown</option>
+                  <option value="csv">CSV</option>
+                  <option value="json">JSON</option>
+                </select>
+              )}
+              {sharing?.can_export && (
                 <button
                   onClick={handleExportRetrospective}
                   className="flex items-center space-x-2 px-3 py-2 bg-white border border-gray-300 text-gray-700 rounded-md text-sm font-medium hover:bg-gray-50 transition-colors"
@@ -715,6 +981,25 @@ ${editingActionItem.feedback}
                   <span>Exportar</span>
                 </button>
               )}
+              {sharing?.can_export && (
+                <button
+                  onClick={handleCreateShareLink}
+                  className="flex items-center space-x-2 px-3 py-2 bg-white border border-gray-300 text-gray-700 rounded-md text-sm font-medium hover:bg-gray-50 transition-colors"
+                  title="Copiar link público do resumo"
+                >
+                  <Share2 className="h-4 w-4" />
+                  <span>Compartilhar</span>
+                </button>
+              )}
+              
+              <button
+                onClick={handleDownloadCalendar}
+                className="flex items-center space-x-2 px-3 py-2 bg-white border border-gray-300 text-gray-700 rounded-md text-sm font-medium hover:bg-gray-50 transition-colors"
+                title="Adicionar ao calendário (.ics)"
+              >
+                <Calendar className="h-4 w-4" />
+                <span>Calendário</span>
+              </button>
               
               {canStart && (
                 <button
@@ -737,13 +1022,63 @@ ${editingActionItem.feedback}
                 <div>
                   <p className="text-sm text-blue-800 font-medium">Dica: Drag and Drop</p>
                   <p className="text-xs text-blue-600">
-                    Arraste um item sobre outro da mesma categoria para mesclá-los automaticamente!
+                    Arraste um item sobre outro para mesclá-los, ou para outra coluna para movê-lo!
                   </p>
                 </div>
               </div>
             </div>
           )}
 
+          {/* Group Suggestions */}
+          {groupSuggestions?.length > 0 && (
+            <div className="bg-white border border-gray-200 rounded-lg p-4 mb-6">
+              <div className="flex items-center justify-between mb-3">
+                <h3 className="text-sm font-medium text-gray-900">Grupos sugeridos</h3>
+                <button
+                  onClick={() => setGroupSuggestions(null)}
+                  className="p-1 text-gray-400 hover:text-gray-600 transition-colors"
+                  title="Fechar"
+                >
+                  <X className="h-4 w-4" />
+                </button>
+              </div>
+              <div className="space-y-3">
+                {groupSuggestions.map((suggestion) => (
+                  <div key={suggestion.item_ids.join()} className="flex items-start justify-between border rounded-md p-3">
+                    <div className="min-w-0">
+                      <p className="text-sm font-medium text-gray-900">{suggestion.name}</p>
+                      <ul className="mt-1 text-xs text-gray-600 list-disc list-inside">
+                        {suggestion.item_ids.map((itemId) => (
+                          <li key={itemId} className="truncate">
+                            {retrospective.items?.find((item) => item.id === itemId)?.content}
+                          </li>
+                        ))}
+                      </ul>
+                    </div>
+                    <button
+                      onClick={() => handleAcceptSuggestion(suggestion)}
+                      className="ml-3 px-3 py-1 bg-purple-500 text-white rounded-md text-xs font-medium hover:bg-purple-600 transition-colors"
+                    >
+                      Aceitar
+                    </button>
+                  </div>
+                ))}
+              </div>
+            </div>
+          )}
+
+          {/* Discussion Queue */}
+          {retrospective?.status === 'active' && (
+            <DiscussionQueue
+              queue={discussionQueue}
+              canLead={sharing?.can_manage}
+              onSelect={handleSelectTopic}
+              onNext={handleNextTopic}
+              onClear={handleClearTopic}
+              isChanging={changeTopicMutation.isLoading}
+            />
+          )}
+
           {/* Retrospective Items */}
           <div className={`grid grid-cols-1 gap-6 ${
             retrospective?.template === 'went_well_to_improve' 
@@ -755,7 +1090,12 @@ ${editingActionItem.feedback}
           const items = itemsByCategory[categoryKey] || [];
           
           return (
-            <div key={categoryKey} className={`border-2 border-dashed rounded-lg p-6 w-full min-w-0 min-h-[400px] flex flex-col ${getCategoryColor(categoryKey)}`}>
+            <div
+              key={categoryKey}
+              className={`border-2 border-dashed rounded-lg p-6 w-full min-w-0 min-h-[400px] flex flex-col ${getCategoryColor(categoryKey)}`}
+              onDragOver={(e) => canEdit && draggedItem && e.preventDefault()}
+              onDrop={(e) => handleDropOnCategory(e, categoryKey)}
+            >
               <div className="mb-4">
                 <h3 className={`text-lg font-medium ${categoryInfo.color}`}>{categoryInfo.name}</h3>
                 <p className="text-sm text-gray-500">{categoryInfo.description}</p>
@@ -812,8 +1152,11 @@ ${editingActionItem.feedback}
                     ) : (
                       // Modo de visualização normal
                       <div 
+                        id={`item-${item.id}`}
                         className={`p-4 border rounded-lg bg-white hover:shadow-sm transition-all duration-200 w-full max-w-full ${
                           draggedItem?.id === item.id ? 'opacity-50 scale-95' : ''
+                        } ${
+                          isCurrentTopicItem(item) ? 'border-purple-500 ring-2 ring-purple-200' : ''
                         } ${
                           dragOverItem?.id === item.id ? 'border-green-500 bg-green-50 ring-2 ring-green-200' : ''
                         } ${
@@ -840,6 +1183,11 @@ ${editingActionItem.feedback}
                                 </div>
                               )}
                             </div>
+                            <DiscussionNote
+                              note={itemNotes[item.id]}
+                              editable={retrospective?.status === 'active'}
+                              onSave={(data) => handleSaveItemNote(item.id, data)}
+                            />
                             <div className="flex items-center justify-end">
                               <div className="flex items-center space-x-2">
                                 <button
@@ -979,6 +1327,58 @@ ${editingActionItem.feedback}
             </div>
           </div>
         
+        {/* Unfinished action items from the previous retrospective */}
+        {retrospective.carried_action_items?.length > 0 && (
+          <div className="mb-4 p-3 bg-gray-50 rounded-lg">
+            <h4 className="text-sm font-medium text-gray-700 mb-2">Ações anteriores</h4>
+            <div className="space-y-2">
+              {retrospective.carried_action_items.map((actionItem) => (
+                <div key={actionItem.id} className="p-3 bg-white border border-gray-200 rounded-md">
+                  <div className="flex items-start justify-between">
+                    <div className="flex-1">
+                      <p className="text-sm font-medium text-gray-900">{actionItem.title}</p>
+                      <p className="text-xs text-gray-500">{actionItem.origin_retrospective_title}</p>
+                    </div>
+                    <span className={`px-2 py-1 rounded-full text-xs font-medium ${getActionItemStatusColor(actionItem.status)}`}>
+                      {getActionItemStatusText(actionItem.status)}
+                    </span>
+                  </div>
+                  {isRetrospectiveOwner() && (
+                    <div className="flex items-center justify-between mt-2">
+                      <label className="flex items-center text-xs text-gray-600">
+                        <input
+                          type="checkbox"
+                          checked={actionItem.carry_forward}
+                          onChange={(e) => carryForwardMutation.mutate({ actionItemId: actionItem.id, carryForward: e.target.checked })}
+                          className="mr-1"
+                        />
+                        Levar para a próxima retrospectiva
+                      </label>
+                      <div className="flex items-center space-x-2">
+                        {actionItem.status !== 'done' && (
+                          <button
+                            onClick={() => updateActionItemMutation.mutate({ actionItemId: actionItem.id, data: { status: 'done' } })}
+                            className="text-xs text-green-600 hover:text-green-800"
+                          >
+                            Concluir
+                          </button>
+                        )}
+                        <button
+                          onClick={() => handleEditActionItem(actionItem)}
+                          className="p-1 text-gray-400 hover:text-blue-600 hover:bg-blue-50 rounded transition-colors"
+                          title="Editar Action Item"
+                        >
+                          <Edit3 className="h-4 w-4" />
+                        </button>
+                      </div>
+                    </div>
+                  )}
+                </div>
+              ))}
+            </div>
+          </div>
+        )}
+
         <div className="space-y-3 mb-4 flex-grow">
           {retrospective.action_items?.filter(actionItem => 
             actionItemFilter === 'all' || actionItem.status === actionItemFilter
@@ -1310,6 +1710,21 @@ ${editingActionItem.feedback}
                     placeholder="Descrição do action item..."
                   />
                 </div>
+                <div>
+                  <label className="block text-sm font-medium text-gray-700 mb-1">Responsável</label>
+                  <select
+                    value={newActionItem.assignedTo}
+                    onChange={(e) => setNewActionItem({ ...newActionItem, assignedTo: e.target.value })}
+                    className="w-full p-3 border border-gray-300 rounded-md focus:ring-blue-500 focus:border-blue-500"
+                  >
+                    <option value="">Sem responsável</option>
+                    {assignableUsers.map((assignableUser) => (
+                      <option key={assignableUser.id} value={assignableUser.id}>
+                        {assignableUser.name}
+                      </option>
+                    ))}
+                  </select>
+                </div>
                 <div>
                   <label className="block text-sm font-medium text-gray-700 mb-1">Prazo</label>
                   <input
@@ -1324,7 +1739,7 @@ ${editingActionItem.feedback}
                 <button
                   onClick={() => {
                     setShowAddActionItemModal(false);
-                    setNewActionItem({ title: '', description: '', dueDate: '' });
+                    setNewActionItem({ title: '', description: '', dueDate: '', assignedTo: '' });
                   }}
                   className="btn btn-secondary"
                 >
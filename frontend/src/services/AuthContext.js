@@ -61,6 +61,28 @@ export const AuthProvider = ({ children }) => {
     }
   };
 
+  // Completes a single sign-on: the backend redirects back with the token in
+  // the URL fragment, and the profile is loaded with it
+  const loginWithToken = async (authToken) => {
+    try {
+      localStorage.setItem('token', authToken);
+      const response = await usersAPI.getProfile();
+      const userData = response.data;
+
+      setUser(userData);
+      setToken(authToken);
+      localStorage.setItem('user', JSON.stringify(userData));
+
+      toast.success(`Bem-vindo, ${userData.name}!`);
+      return { success: true };
+    } catch (error) {
+      localStorage.removeItem('token');
+      const message = error.response?.data?.error || 'Erro ao fazer login com SSO';
+      toast.error(message);
+      return { success: false, error: message };
+    }
+  };
+
   const register = async (userData) => {
     try {
       const response = await authAPI.register(userData);
@@ -116,6 +138,7 @@ export const AuthProvider = ({ children }) => {
     token,
     loading,
     login,
+    loginWithToken,
     register,
     logout,
     updateProfile,
@@ -44,12 +44,16 @@ api.interceptors.response.use(
 export const authAPI = {
   login: (credentials) => api.post('/auth/login', credentials),
   register: (userData) => api.post('/auth/register', userData),
+  // Single sign-on is a browser redirect, not an API call
+  oidcLoginURL: `${API_BASE_URL}/auth/oidc/login`,
 };
 
 // Users API
 export const usersAPI = {
   getProfile: () => api.get('/users/profile'),
   updateProfile: (data) => api.put('/users/profile', data),
+  getCalendarFeed: () => api.get('/users/profile/calendar'),
+  resetCalendarFeed: () => api.post('/users/profile/calendar/reset'),
   getAnalytics: () => api.get('/users/analytics'),
 };
 
@@ -65,25 +69,78 @@ export const retrospectivesAPI = {
   getRetrospectives: () => api.get('/retrospectives'),
   getRetrospective: (id) => api.get(`/retrospectives/${id}`),
   createRetrospective: (data) => api.post('/retrospectives', data),
+  importRetrospective: (file, params) => api.post('/retrospectives/import', file, {
+    params,
+    headers: { 'Content-Type': file.name.endsWith('.csv') ? 'text/csv' : 'application/json' },
+  }),
   updateRetrospective: (id, data) => api.put(`/retrospectives/${id}`, data),
   deleteRetrospective: (id) => api.delete(`/retrospectives/${id}`),
   startRetrospective: (id) => api.post(`/retrospectives/${id}/start`),
   endRetrospective: (id) => api.post(`/retrospectives/${id}/end`),
   addItem: (id, data) => api.post(`/retrospectives/${id}/items`, data),
   voteItem: (itemId) => api.post(`/retrospectives/items/${itemId}/vote`),
+  moveItem: (itemId, category) => api.post(`/retrospectives/items/${itemId}/move`, { category }),
+  saveItemNote: (itemId, data) => api.put(`/retrospectives/items/${itemId}/note`, data),
   addActionItem: (id, data) => api.post(`/retrospectives/${id}/action-items`, data),
+  getAssignableUsers: (id) => api.get(`/retrospectives/${id}/assignable-users`),
   updateActionItem: (actionItemId, data) => api.put(`/retrospectives/action-items/${actionItemId}`, data),
+  setCarryForward: (id, actionItemId, carryForward) => api.put(`/retrospectives/${id}/carried-action-items/${actionItemId}`, { carry_forward: carryForward }),
   deleteActionItem: (actionItemId) => api.delete(`/retrospectives/action-items/${actionItemId}`),
   joinRetrospective: (id) => api.post(`/retrospectives/${id}/join`),
   getParticipants: (id) => api.get(`/retrospectives/${id}/participants`),
   deleteItem: (itemId) => api.delete(`/retrospectives/items/${itemId}`),
   reopenRetrospective: (id) => api.post(`/retrospectives/${id}/reopen`),
   createGroup: (id, data) => api.post(`/retrospectives/${id}/groups`, data),
+  getDiscussionQueue: (id) => api.get(`/retrospectives/${id}/discussion-queue`),
+  setCurrentTopic: (id, topic) => api.put(`/retrospectives/${id}/current-topic`, topic),
+  nextTopic: (id) => api.post(`/retrospectives/${id}/current-topic/next`),
+  clearCurrentTopic: (id) => api.delete(`/retrospectives/${id}/current-topic`),
+  getGroupSuggestions: (id, params) => api.get(`/retrospectives/${id}/group-suggestions`, { params }),
+  saveGroupNote: (groupId, data) => api.put(`/retrospectives/groups/${groupId}/note`, data),
   voteGroup: (groupId) => api.post(`/retrospectives/groups/${groupId}/vote`),
   deleteGroup: (groupId) => api.delete(`/retrospectives/groups/${groupId}`),
+  updateGroup: (groupId, data) => api.put(`/retrospectives/groups/${groupId}`, data),
+  addGroupItems: (groupId, itemIds) => api.post(`/retrospectives/groups/${groupId}/items`, { item_ids: itemIds }),
+  removeGroupItem: (groupId, itemId) => api.delete(`/retrospectives/groups/${groupId}/items/${itemId}`),
   mergeItems: (id, data) => api.post(`/retrospectives/${id}/merge-items`, data),
+  unmergeItems: (id, mergeId) => api.post(`/retrospectives/${id}/unmerge-items`, { merge_id: mergeId }),
   toggleBlur: (id, blurred) => api.put(`/retrospectives/${id}/blur`, { blurred }),
-  exportRetrospective: (id) => api.get(`/retrospectives/${id}/export`, { responseType: 'blob' }),
+  exportRetrospective: (id, format = 'pdf') => api.get(`/retrospectives/${id}/export`, { params: { format }, responseType: 'blob' }),
+  downloadCalendar: (id) => api.get(`/retrospectives/${id}/calendar.ics`, { responseType: 'blob' }),
+};
+
+// Sharing API
+export const sharingAPI = {
+  getSharing: (id) => api.get(`/retrospectives/${id}/sharing`),
+  setExportPolicy: (id, exportPolicy) => api.put(`/retrospectives/${id}/export-policy`, { export_policy: exportPolicy }),
+  createShareLink: (id, data) => api.post(`/retrospectives/${id}/share-links`, data),
+  deleteShareLink: (id, linkId) => api.delete(`/retrospectives/${id}/share-links/${linkId}`),
+};
+
+// Public summaries, read without logging in
+export const publicAPI = {
+  getSummary: (token) => api.get(`/public/summaries/${token}`),
+};
+
+// Schedules API
+export const schedulesAPI = {
+  getSchedules: (teamId) => api.get(`/teams/${teamId}/schedules`),
+  createSchedule: (teamId, data) => api.post(`/teams/${teamId}/schedules`, data),
+  updateSchedule: (teamId, scheduleId, data) => api.put(`/teams/${teamId}/schedules/${scheduleId}`, data),
+  deleteSchedule: (teamId, scheduleId) => api.delete(`/teams/${teamId}/schedules/${scheduleId}`),
+};
+
+// Action Items API
+export const actionItemsAPI = {
+  getActionItems: (params) => api.get('/action-items', { params }),
+  getHistory: (actionItemId) => api.get(`/action-items/${actionItemId}/history`),
+  getComments: (actionItemId) => api.get(`/action-items/${actionItemId}/comments`),
+  addComment: (actionItemId, data) => api.post(`/action-items/${actionItemId}/comments`, data),
+  updateComment: (actionItemId, commentId, data) => api.put(`/action-items/${actionItemId}/comments/${commentId}`, data),
+  deleteComment: (actionItemId, commentId) => api.delete(`/action-items/${actionItemId}/comments/${commentId}`),
+  getIssueTracker: () => api.get('/issue-tracker'),
+  exportToIssueTracker: (actionItemId) => api.post(`/action-items/${actionItemId}/export`),
+  syncWithIssueTracker: (actionItemId) => api.post(`/action-items/${actionItemId}/sync`),
 };
 
 